@@ -0,0 +1,379 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup <file>",
+	Short: "Archive all plum-managed state to a tar.gz",
+	Long: `Archive all plum-managed state into a single tar.gz file.
+
+The archive holds a literal copy of:
+  - settings.json for the user, project, and local scopes
+  - known_marketplaces.json
+  - the install registry (installed_plugins.json)
+  - the plugin cache
+
+Unlike 'plum freeze', which records which plugins to install, a backup is a
+snapshot that 'plum restore' puts back byte for byte. Managed settings are
+not included.
+
+Examples:
+  plum backup plum-state.tar.gz
+  plum backup plum-state.tar.gz --project ~/code/app`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackup,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore plum-managed state from a backup archive",
+	Long: `Restore settings, the install registry, known marketplaces, and the plugin
+cache from an archive written by 'plum backup'.
+
+Files in the archive overwrite the current ones. If the archive holds a
+plugin cache, the current cache is replaced entirely. Every path is checked
+before anything is written, and archives with entries outside plum's state
+are rejected. Restore asks for confirmation; use --yes to skip the prompt.
+
+Examples:
+  plum restore plum-state.tar.gz
+  plum restore plum-state.tar.gz --project ~/code/app --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+var (
+	backupProject  string
+	restoreProject string
+	restoreYes     bool
+)
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	backupCmd.Flags().StringVar(&backupProject, "project", "", "Project path for project/local settings (default: current directory)")
+	restoreCmd.Flags().StringVar(&restoreProject, "project", "", "Project path for project/local settings (default: current directory)")
+	restoreCmd.Flags().BoolVarP(&restoreYes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+// Archive layout. Settings are stored per scope so a backup taken in one
+// project can be restored into another.
+const (
+	archiveSettingsDir = "settings"
+	archivePluginsDir  = "plugins"
+	archiveCacheDir    = archivePluginsDir + "/cache"
+)
+
+// maxRestoreFileSize caps each extracted file to guard against archive bombs
+const maxRestoreFileSize = 100 << 20
+
+// stateRoots maps the archive's top-level layout to locations on disk
+type stateRoots struct {
+	pluginsDir string
+	settings   map[string]string // archive name -> settings path
+}
+
+func resolveStateRoots(projectPath string) (stateRoots, error) {
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		return stateRoots{}, err
+	}
+
+	roots := stateRoots{pluginsDir: pluginsDir, settings: make(map[string]string)}
+	for _, scope := range settings.WritableScopes() {
+		p, err := settings.ScopePath(scope, projectPath)
+		if err != nil {
+			return stateRoots{}, err
+		}
+		roots.settings[archiveSettingsDir+"/"+scope.String()+".json"] = p
+	}
+	return roots, nil
+}
+
+// target returns where an archive entry is restored to. Entries outside the
+// known layout, or that would escape it, are rejected.
+func (r stateRoots) target(name string) (string, error) {
+	if name == "" || strings.Contains(name, "\\") || path.IsAbs(name) {
+		return "", fmt.Errorf("invalid archive entry %q", name)
+	}
+	clean := path.Clean(name)
+	if clean != strings.TrimSuffix(name, "/") || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("archive entry contains invalid path traversal: %s", name)
+	}
+
+	if p, ok := r.settings[clean]; ok {
+		return p, nil
+	}
+
+	switch {
+	case clean == archivePluginsDir+"/known_marketplaces.json",
+		clean == archivePluginsDir+"/installed_plugins.json",
+		clean == archiveCacheDir,
+		strings.HasPrefix(clean, archiveCacheDir+"/"):
+		rel := strings.TrimPrefix(clean, archivePluginsDir+"/")
+		return filepath.Join(r.pluginsDir, filepath.FromSlash(rel)), nil
+	}
+	return "", fmt.Errorf("unexpected archive entry %q", name)
+}
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	roots, err := resolveStateRoots(backupProject)
+	if err != nil {
+		return err
+	}
+
+	count, err := writeBackup(args[0], roots)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backed up %d file(s) to %s\n", count, args[0])
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	roots, err := resolveStateRoots(restoreProject)
+	if err != nil {
+		return err
+	}
+
+	// Validate every entry before touching anything on disk
+	names, err := listBackup(args[0], roots)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s holds %d file(s). Restoring overwrites current settings, the install registry, and the plugin cache.\n", args[0], len(names))
+	if !restoreYes && !confirm(cmd.InOrStdin(), cmd.OutOrStdout(), "Restore backup?") {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	count, err := extractBackup(args[0], roots)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored %d file(s) from %s\n", count, args[0])
+	return nil
+}
+
+// writeBackup archives every state file that exists and returns how many
+// files were written
+func writeBackup(archivePath string, roots stateRoots) (int, error) {
+	// #nosec G304 -- archivePath is given by the user
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create backup: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	count := 0
+	add := func(name, src string) error {
+		info, err := os.Stat(src)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := addFileToArchive(tw, name, src, info); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+
+	settingsNames := make([]string, 0, len(roots.settings))
+	for name := range roots.settings {
+		settingsNames = append(settingsNames, name)
+	}
+	sort.Strings(settingsNames)
+	for _, name := range settingsNames {
+		if err := add(name, roots.settings[name]); err != nil {
+			return 0, fmt.Errorf("failed to archive %s: %w", name, err)
+		}
+	}
+
+	for _, file := range []string{"known_marketplaces.json", "installed_plugins.json"} {
+		if err := add(archivePluginsDir+"/"+file, filepath.Join(roots.pluginsDir, file)); err != nil {
+			return 0, fmt.Errorf("failed to archive %s: %w", file, err)
+		}
+	}
+
+	cacheDir := filepath.Join(roots.pluginsDir, "cache")
+	err = filepath.WalkDir(cacheDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == cacheDir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		// Only regular files and directories are archived
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(cacheDir, p)
+		if err != nil {
+			return err
+		}
+		return add(archiveCacheDir+"/"+filepath.ToSlash(rel), p)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive plugin cache: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	return count, nil
+}
+
+func addFileToArchive(tw *tar.Writer, name, src string, info os.FileInfo) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(info.Mode().Perm()),
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	// #nosec G304 -- src is a file under plum's own state directories
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	_, err = io.Copy(tw, in)
+	return err
+}
+
+// walkBackup calls fn for each regular file in the archive after checking
+// its path. Directory entries are skipped; anything else is rejected.
+func walkBackup(archivePath string, roots stateRoots, fn func(hdr *tar.Header, target string, r io.Reader) error) error {
+	// #nosec G304 -- archivePath is given by the user
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("invalid backup %s: %w", archivePath, err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid backup %s: %w", archivePath, err)
+		}
+
+		target, err := roots.target(hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return fmt.Errorf("unsupported archive entry type for %s", hdr.Name)
+		}
+		if hdr.Size > maxRestoreFileSize {
+			return fmt.Errorf("archive entry %s exceeds %d bytes", hdr.Name, maxRestoreFileSize)
+		}
+
+		if err := fn(hdr, target, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// listBackup validates an archive and returns its file entries
+func listBackup(archivePath string, roots stateRoots) ([]string, error) {
+	var names []string
+	err := walkBackup(archivePath, roots, func(hdr *tar.Header, _ string, _ io.Reader) error {
+		names = append(names, hdr.Name)
+		return nil
+	})
+	return names, err
+}
+
+// extractBackup writes an archive's files to their state locations. When the
+// archive includes the plugin cache, the current cache is removed first so
+// the restored cache matches the snapshot.
+func extractBackup(archivePath string, roots stateRoots) (int, error) {
+	names, err := listBackup(archivePath, roots)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		if strings.HasPrefix(name, archiveCacheDir+"/") {
+			if err := os.RemoveAll(filepath.Join(roots.pluginsDir, "cache")); err != nil {
+				return 0, fmt.Errorf("failed to clear plugin cache: %w", err)
+			}
+			break
+		}
+	}
+
+	count := 0
+	err = walkBackup(archivePath, roots, func(hdr *tar.Header, target string, r io.Reader) error {
+		// #nosec G301 -- Settings and plugin cache need to be readable by Claude Code
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", hdr.Name, err)
+		}
+
+		perm := os.FileMode(hdr.Mode).Perm() & 0755
+		// #nosec G304 -- target was validated against plum's state directories
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+		}
+		if _, err := io.Copy(out, io.LimitReader(r, maxRestoreFileSize)); err != nil {
+			_ = out.Close()
+			return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+		}
+		if err := out.Close(); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
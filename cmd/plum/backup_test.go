@@ -0,0 +1,190 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupCommandsRegistered(t *testing.T) {
+	for _, want := range []string{"backup", "restore"} {
+		cmd, _, err := rootCmd.Find([]string{want})
+		if err != nil || cmd.Name() != want {
+			t.Errorf("%s command not registered (err = %v)", want, err)
+		}
+	}
+	if restoreCmd.Flags().Lookup("yes") == nil {
+		t.Error("restore command should have --yes flag")
+	}
+}
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	project := t.TempDir()
+	pluginsDir := filepath.Join(configDir, "plugins")
+
+	fixture := map[string]string{
+		filepath.Join(configDir, "settings.json"):                                 `{"enabledPlugins": {"alpha@mkt": true}}`,
+		filepath.Join(project, ".claude", "settings.json"):                        `{"enabledPlugins": {"beta@mkt": true}}`,
+		filepath.Join(project, ".claude", "settings.local.json"):                  `{"enabledPlugins": {"beta@mkt": false}}`,
+		filepath.Join(pluginsDir, "known_marketplaces.json"):                      `{"mkt": {}}`,
+		filepath.Join(pluginsDir, "installed_plugins.json"):                       `{"version": 2, "plugins": {}}`,
+		filepath.Join(pluginsDir, "cache", "mkt", "alpha", "commands", "a.md"):    "# a",
+		filepath.Join(pluginsDir, "cache", "mkt", "alpha", ".claude-plugin", "x"): "x",
+	}
+	for path, content := range fixture {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archive := filepath.Join(t.TempDir(), "state.tar.gz")
+	backupProject = project
+	defer func() { backupProject = "" }()
+	output, err := captureStdout(t, func() error { return runBackup(backupCmd, []string{archive}) })
+	if err != nil {
+		t.Fatalf("runBackup failed: %v", err)
+	}
+	if !strings.Contains(output, "Backed up 7 file(s)") {
+		t.Errorf("unexpected backup output: %s", output)
+	}
+
+	// Change everything: edit, delete, and add files
+	if err := os.WriteFile(filepath.Join(configDir, "settings.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(project, ".claude", "settings.local.json")); err != nil {
+		t.Fatal(err)
+	}
+	stray := filepath.Join(pluginsDir, "cache", "mkt", "stray", "file")
+	writeFixturePlugin(t, filepath.Dir(stray), `{"name": "stray"}`)
+	if err := os.WriteFile(stray, []byte("stray"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	restoreProject = project
+	restoreYes = true
+	defer func() { restoreProject = ""; restoreYes = false }()
+	output, err = captureStdout(t, func() error { return runRestore(restoreCmd, []string{archive}) })
+	if err != nil {
+		t.Fatalf("runRestore failed: %v", err)
+	}
+	if !strings.Contains(output, "Restored 7 file(s)") {
+		t.Errorf("unexpected restore output: %s", output)
+	}
+
+	for path, want := range fixture {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%s not restored: %v", path, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Dir(stray)); !os.IsNotExist(err) {
+		t.Errorf("restore should replace the plugin cache, but stray plugin remains (stat err = %v)", err)
+	}
+}
+
+func TestRestore_Declined(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	settingsPath := filepath.Join(configDir, "settings.json")
+	if err := os.WriteFile(settingsPath, []byte(`{"enabledPlugins": {"alpha@mkt": true}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "state.tar.gz")
+	if _, err := captureStdout(t, func() error { return runBackup(backupCmd, []string{archive}) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	restoreCmd.SetIn(strings.NewReader("n\n"))
+	defer restoreCmd.SetIn(nil)
+	output, err := captureStdout(t, func() error { return runRestore(restoreCmd, []string{archive}) })
+	if err != nil {
+		t.Fatalf("runRestore failed: %v", err)
+	}
+	if !strings.Contains(output, "Aborted") {
+		t.Errorf("expected abort, got: %s", output)
+	}
+	if data, _ := os.ReadFile(settingsPath); string(data) != `{}` {
+		t.Errorf("declined restore should leave settings alone, got %s", data)
+	}
+}
+
+func TestRestore_RejectsUnsafeEntries(t *testing.T) {
+	tests := map[string]string{
+		"traversal":        "plugins/cache/../../../evil",
+		"absolute":         "/etc/passwd",
+		"outside layout":   "plugins/settings.json",
+		"unknown scope":    "settings/managed.json",
+		"backslash":        `plugins\cache\x`,
+		"leading dot-dot":  "../settings/user.json",
+		"redundant prefix": "./plugins/cache/x",
+	}
+
+	for name, entry := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+			t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+			archive := filepath.Join(t.TempDir(), "evil.tar.gz")
+			writeTestArchive(t, archive, map[string]string{
+				"plugins/installed_plugins.json": `{}`,
+				entry:                            "evil",
+			})
+
+			restoreYes = true
+			defer func() { restoreYes = false }()
+			_, err := captureStdout(t, func() error { return runRestore(restoreCmd, []string{archive}) })
+			if err == nil {
+				t.Fatalf("expected restore of %q to fail", entry)
+			}
+			// Nothing is written when any entry is invalid
+			if _, err := os.Stat(filepath.Join(configDir, "plugins", "installed_plugins.json")); !os.IsNotExist(err) {
+				t.Errorf("restore wrote files before rejecting the archive (stat err = %v)", err)
+			}
+		})
+	}
+}
+
+// writeTestArchive writes a tar.gz with the given regular file entries
+func writeTestArchive(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		hdr := &tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644, Size: int64(len(content))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -16,4 +16,5 @@ Claude Code plugins from the marketplace and local sources.`,
 
 func init() {
 	rootCmd.AddCommand(browseCmd)
+	browseCmd.Flags().BoolVar(&plainTUI, "plain", false, "Render without colors or box drawing (also enabled by NO_COLOR)")
 }
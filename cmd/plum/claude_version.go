@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// minClaudeVersion is the oldest Claude Code release plum has been tested
+// against: plugin marketplaces and installed_plugins_v2.json
+const minClaudeVersion = "2.0.0"
+
+// installedPluginsFormat is the installed_plugins_v2.json version plum writes
+const installedPluginsFormat = 2
+
+// claudeVersionRe matches the first semantic version in `claude --version`
+// output, e.g. "2.0.14 (Claude Code)"
+var claudeVersionRe = regexp.MustCompile(`\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?`)
+
+// claudeVersionOutput runs `claude --version` (variable for testing)
+var claudeVersionOutput = func() ([]byte, error) {
+	path, err := exec.LookPath("claude")
+	if err != nil {
+		return nil, fmt.Errorf("claude CLI not found in PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// #nosec G204 -- path is the resolved claude binary, args are fixed
+	return exec.CommandContext(ctx, path, "--version").Output()
+}
+
+// detectClaudeVersion returns the installed Claude Code version, best effort
+func detectClaudeVersion() (string, error) {
+	out, err := claudeVersionOutput()
+	if err != nil {
+		return "", err
+	}
+	return parseClaudeVersion(string(out))
+}
+
+// parseClaudeVersion extracts the version from `claude --version` output
+func parseClaudeVersion(output string) (string, error) {
+	version := claudeVersionRe.FindString(output)
+	if version == "" {
+		return "", fmt.Errorf("unrecognized version output %q", output)
+	}
+	return version, nil
+}
+
+// checkClaudeCompatibility reports info issues when the Claude Code version
+// can't be detected or looks older than what plum supports, and when the
+// installed plugins registry uses a format plum doesn't write
+func checkClaudeCompatibility(registryFormat int) []DoctorIssue {
+	var issues []DoctorIssue
+
+	version, err := detectClaudeVersion()
+	switch {
+	case err != nil:
+		issues = append(issues, DoctorIssue{
+			Type:        "claude_version_unknown",
+			Severity:    "info",
+			Description: fmt.Sprintf("Could not detect Claude Code version: %v", err),
+		})
+	case !isCompatibleClaudeVersion(version):
+		issues = append(issues, DoctorIssue{
+			Type:        "claude_version_incompatible",
+			Severity:    "info",
+			Description: fmt.Sprintf("Claude Code %s is older than %s; plugins installed by plum may not load", version, minClaudeVersion),
+		})
+	}
+
+	// A zero format means the registry doesn't exist yet
+	if registryFormat != 0 && registryFormat != installedPluginsFormat {
+		issues = append(issues, DoctorIssue{
+			Type:        "registry_format_mismatch",
+			Severity:    "info",
+			Description: fmt.Sprintf("installed_plugins_v2.json has version %d; plum writes version %d", registryFormat, installedPluginsFormat),
+		})
+	}
+
+	return issues
+}
+
+// isCompatibleClaudeVersion reports whether version is at least minClaudeVersion.
+// Unparseable versions are given the benefit of the doubt.
+func isCompatibleClaudeVersion(version string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return true
+	}
+	return !v.LessThan(semver.MustParse(minClaudeVersion))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseClaudeVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{"cli output", "2.0.14 (Claude Code)\n", "2.0.14", false},
+		{"bare version", "1.0.120", "1.0.120", false},
+		{"prerelease", "2.1.0-beta.1 (Claude Code)", "2.1.0-beta.1", false},
+		{"prefixed", "claude v2.0.5", "2.0.5", false},
+		{"garbage", "command not found", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseClaudeVersion(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseClaudeVersion(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseClaudeVersion(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckClaudeCompatibility(t *testing.T) {
+	original := claudeVersionOutput
+	defer func() { claudeVersionOutput = original }()
+
+	tests := []struct {
+		name           string
+		output         string
+		err            error
+		registryFormat int
+		wantTypes      []string
+	}{
+		{"compatible", "2.0.14 (Claude Code)", nil, 2, nil},
+		{"missing registry", "2.0.14 (Claude Code)", nil, 0, nil},
+		{"old version", "1.0.88 (Claude Code)", nil, 2, []string{"claude_version_incompatible"}},
+		{"cli missing", "", errors.New("claude CLI not found in PATH"), 2, []string{"claude_version_unknown"}},
+		{"registry format mismatch", "2.0.14 (Claude Code)", nil, 3, []string{"registry_format_mismatch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claudeVersionOutput = func() ([]byte, error) {
+				return []byte(tt.output), tt.err
+			}
+
+			issues := checkClaudeCompatibility(tt.registryFormat)
+			if len(issues) != len(tt.wantTypes) {
+				t.Fatalf("expected %d issues, got %+v", len(tt.wantTypes), issues)
+			}
+			for i, issue := range issues {
+				if issue.Type != tt.wantTypes[i] {
+					t.Errorf("issue %d type = %s, want %s", i, issue.Type, tt.wantTypes[i])
+				}
+				if issue.Severity != "info" {
+					t.Errorf("issue %d severity = %s, want info", i, issue.Severity)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+)
+
+// commandNameFromFile derives the slash command a command file registers,
+// e.g. "commands/deploy.md" -> "/deploy". Returns "" for non-markdown files.
+func commandNameFromFile(file string) string {
+	file = filepath.ToSlash(file)
+	if !strings.EqualFold(path.Ext(file), ".md") {
+		return ""
+	}
+	name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+	if name == "" {
+		return ""
+	}
+	return "/" + name
+}
+
+// pluginCommandNames returns the sorted slash commands defined by a cached
+// plugin: files listed under "commands" in plugin.json plus any markdown
+// files in its commands/ directory
+func pluginCommandNames(installPath string) []string {
+	seen := make(map[string]bool)
+
+	// #nosec G304 -- path is built from the install registry's cache path
+	if data, err := os.ReadFile(filepath.Join(installPath, ".claude-plugin", "plugin.json")); err == nil {
+		var manifest install.ManifestFiles
+		if json.Unmarshal(data, &manifest) == nil {
+			for _, file := range manifest.Commands {
+				if name := commandNameFromFile(file); name != "" {
+					seen[name] = true
+				}
+			}
+		}
+	}
+
+	commandsDir := filepath.Join(installPath, "commands")
+	_ = filepath.WalkDir(commandsDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if !d.IsDir() {
+			if name := commandNameFromFile(p); name != "" {
+				seen[name] = true
+			}
+		}
+		return nil
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkCommandConflicts reports slash commands defined by more than one
+// installed plugin, where Claude Code's choice between them is ambiguous
+func checkCommandConflicts(installed *config.InstalledPluginsV2) []DoctorIssue {
+	definedBy := make(map[string][]string) // command -> plugin full names
+
+	for fullName, installs := range installed.Plugins {
+		// Scopes share one cache directory, so the first path is enough
+		for _, install := range installs {
+			if install.InstallPath == "" {
+				continue
+			}
+			for _, name := range pluginCommandNames(install.InstallPath) {
+				definedBy[name] = append(definedBy[name], fullName)
+			}
+			break
+		}
+	}
+
+	var commands []string
+	for name, plugins := range definedBy {
+		if len(plugins) > 1 {
+			commands = append(commands, name)
+		}
+	}
+	sort.Strings(commands)
+
+	issues := make([]DoctorIssue, 0, len(commands))
+	for _, name := range commands {
+		plugins := definedBy[name]
+		sort.Strings(plugins)
+		issues = append(issues, DoctorIssue{
+			Type:        "command_conflict",
+			Severity:    "warning",
+			Description: fmt.Sprintf("Command %s is defined by multiple plugins: %s", name, strings.Join(plugins, ", ")),
+		})
+	}
+	return issues
+}
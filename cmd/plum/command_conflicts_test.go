@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+)
+
+func TestCommandNameFromFile(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"commands/deploy.md", "/deploy"},
+		{"./commands/deploy.md", "/deploy"},
+		{"commands/ops/deploy.MD", "/deploy"},
+		{"deploy.md", "/deploy"},
+		{"commands/README.txt", ""},
+		{"commands/.md", ""},
+	}
+
+	for _, tt := range tests {
+		if got := commandNameFromFile(tt.file); got != tt.want {
+			t.Errorf("commandNameFromFile(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+// writeFixturePlugin creates a cached plugin with the given command files
+func writeFixturePlugin(t *testing.T, dir, pluginJSON string, commands ...string) {
+	t.Helper()
+	files := map[string]string{filepath.Join(dir, ".claude-plugin", "plugin.json"): pluginJSON}
+	for _, c := range commands {
+		files[filepath.Join(dir, filepath.FromSlash(c))] = "# command\n"
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckCommandConflicts(t *testing.T) {
+	cacheDir := t.TempDir()
+	alphaDir := filepath.Join(cacheDir, "mkt", "alpha")
+	betaDir := filepath.Join(cacheDir, "mkt", "beta")
+	gammaDir := filepath.Join(cacheDir, "other", "gamma")
+
+	writeFixturePlugin(t, alphaDir, `{"name": "alpha", "commands": ["commands/deploy.md"]}`, "commands/deploy.md", "commands/lint.md")
+	writeFixturePlugin(t, betaDir, `{"name": "beta"}`, "commands/release/deploy.md")
+	writeFixturePlugin(t, gammaDir, `{"name": "gamma", "commands": ["commands/test.md"]}`)
+
+	installed := &config.InstalledPluginsV2{
+		Version: 2,
+		Plugins: map[string][]config.PluginInstall{
+			"alpha@mkt": {
+				{Scope: "user", InstallPath: alphaDir},
+				{Scope: "project", InstallPath: alphaDir},
+			},
+			"beta@mkt":    {{Scope: "user", InstallPath: betaDir}},
+			"gamma@other": {{Scope: "user", InstallPath: gammaDir}},
+			"missing@mkt": {{Scope: "user", InstallPath: filepath.Join(cacheDir, "gone")}},
+			"no-path@mkt": {{Scope: "user"}},
+		},
+	}
+
+	issues := checkCommandConflicts(installed)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 conflict, got %d: %+v", len(issues), issues)
+	}
+
+	issue := issues[0]
+	if issue.Type != "command_conflict" {
+		t.Errorf("expected type command_conflict, got %s", issue.Type)
+	}
+	if issue.Severity != "warning" {
+		t.Errorf("expected severity warning, got %s", issue.Severity)
+	}
+	if !strings.Contains(issue.Description, "/deploy") ||
+		!strings.Contains(issue.Description, "alpha@mkt, beta@mkt") {
+		t.Errorf("description should name the command and both plugins, got %q", issue.Description)
+	}
+}
@@ -19,17 +19,27 @@ The plugin can be specified as:
   - plugin-name (uses first matching installed plugin)
   - plugin-name@marketplace (specific marketplace)
 
+Use --all instead of a plugin name to disable every enabled plugin in the
+scope without uninstalling anything. Settings are written once, and the
+original settings.json is kept as settings.json.backup-plum. Re-enable
+everything later with "plum enable --all". Use --marketplace instead to
+disable every installed plugin from one marketplace.
+
 Examples:
   plum disable ralph-wiggum
   plum disable ralph-wiggum@claude-code-plugins
-  plum disable memory --scope=project`,
-	Args: cobra.ExactArgs(1),
+  plum disable memory --scope=project
+  plum disable --all
+  plum disable --marketplace=claude-code-plugins`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runDisable,
 }
 
 var (
 	disableScope   string
 	disableProject string
+	disableAll     bool
+	disableMarket  string
 )
 
 func init() {
@@ -37,13 +47,17 @@ func init() {
 
 	disableCmd.Flags().StringVarP(&disableScope, "scope", "s", "user", "Target scope (user, project, local)")
 	disableCmd.Flags().StringVar(&disableProject, "project", "", "Project path (default: current directory)")
+	disableCmd.Flags().BoolVar(&disableAll, "all", false, "Disable every plugin in the target scope")
+	disableCmd.Flags().StringVar(&disableMarket, "marketplace", "", "Disable every installed plugin from this marketplace")
 }
 
 func runDisable(cmd *cobra.Command, args []string) error {
-	pluginArg := args[0]
+	if err := checkPluginOrAll(args, disableAll, disableMarket); err != nil {
+		return err
+	}
 
 	// Parse scope
-	scope, err := settings.ParseScope(disableScope)
+	scope, err := parseScopeFlag(cmd, disableScope, disableProject)
 	if err != nil {
 		return err
 	}
@@ -53,6 +67,15 @@ func runDisable(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot write to %s scope (read-only)", scope)
 	}
 
+	if disableAll {
+		return setAllPluginsEnabled(false, scope, disableProject)
+	}
+	if disableMarket != "" {
+		return setMarketplacePluginsEnabled(false, disableMarket, scope, disableProject)
+	}
+
+	pluginArg := args[0]
+
 	// Resolve plugin full name (reuse from enable.go)
 	fullName, err := resolvePluginFullName(pluginArg, disableProject)
 	if err != nil {
@@ -2,8 +2,13 @@ package main
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/settings"
 )
 
 func TestDisableCommandRegistered(t *testing.T) {
@@ -83,3 +88,148 @@ func TestDisableCommandHelp(t *testing.T) {
 		}
 	}
 }
+
+func TestDisableCommand_All(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	userSettings := `{
+  "model": "opus",
+  "enabledPlugins": {"a@market": true, "b@other": true}
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "settings.json"), []byte(userSettings), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	disableScope = "user"
+	disableProject = tmpDir
+	disableAll = true
+	defer func() { disableAll = false; disableProject = "" }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runDisable(disableCmd, nil)
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("runDisable --all failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	if !strings.Contains(buf.String(), "Disabled 2 plugin(s) in user scope") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	s, err := settings.LoadSettings(settings.ScopeUser, tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.EnabledPlugins["a@market"] || s.EnabledPlugins["b@other"] {
+		t.Errorf("all plugins should be disabled, got %v", s.EnabledPlugins)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"model": "opus"`) {
+		t.Errorf("other settings should be preserved, got %s", data)
+	}
+}
+
+func TestDisableCommand_AllArgValidation(t *testing.T) {
+	defer func() { disableAll = false }()
+
+	disableAll = true
+	if err := runDisable(disableCmd, []string{"a@market"}); err == nil {
+		t.Error("expected error combining plugin name with --all")
+	}
+
+	disableAll = false
+	if err := runDisable(disableCmd, nil); err == nil {
+		t.Error("expected error without plugin name or --all")
+	}
+}
+
+func TestDisableCommand_Marketplace(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	userSettings := `{
+  "model": "opus",
+  "enabledPlugins": {"a@market": true, "b@market": true, "c@other": true}
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "settings.json"), []byte(userSettings), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, fullName := range []string{"a@market", "b@market", "c@other"} {
+		if err := install.Register(fullName, filepath.Join(tmpDir, "cache", fullName), "1.0.0", "", "", settings.ScopeUser, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	disableScope = "user"
+	disableProject = tmpDir
+	disableMarket = "market"
+	defer func() { disableMarket = ""; disableProject = "" }()
+
+	output, err := captureStdout(t, func() error { return runDisable(disableCmd, nil) })
+	if err != nil {
+		t.Fatalf("runDisable --marketplace failed: %v", err)
+	}
+	if !strings.Contains(output, "Disabled 2 plugin(s) from market in user scope") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	s, err := settings.LoadSettings(settings.ScopeUser, tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.EnabledPlugins["a@market"] || s.EnabledPlugins["b@market"] {
+		t.Errorf("market plugins should be disabled, got %v", s.EnabledPlugins)
+	}
+	if !s.EnabledPlugins["c@other"] {
+		t.Errorf("plugins from other marketplaces should be untouched, got %v", s.EnabledPlugins)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"model": "opus"`) {
+		t.Errorf("other settings should be preserved, got %s", data)
+	}
+
+	disableMarket = "missing"
+	if err := runDisable(disableCmd, nil); err == nil {
+		t.Error("expected error for a marketplace with no installed plugins")
+	}
+}
+
+func TestDisableCommand_MarketplaceArgValidation(t *testing.T) {
+	defer func() { disableAll = false; disableMarket = "" }()
+
+	disableMarket = "market"
+	if err := runDisable(disableCmd, []string{"a@market"}); err == nil {
+		t.Error("expected error combining plugin name with --marketplace")
+	}
+
+	disableAll = true
+	if err := runDisable(disableCmd, nil); err == nil {
+		t.Error("expected error combining --all with --marketplace")
+	}
+
+	disableAll = false
+	if err := disableCmd.Flags().Set("scope", "managed"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { disableScope = "user"; disableCmd.Flags().Lookup("scope").Changed = false }()
+	if err := runDisable(disableCmd, nil); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected read-only error for managed scope, got %v", err)
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/settings"
 	"github.com/spf13/cobra"
 )
@@ -22,10 +25,21 @@ Performs the following checks:
   - Invalid JSON in plugin manifests
   - Orphaned cache entries (cache files with no registry entry)
   - Missing cache files for registered plugins
+  - Registry versions that differ from the cached plugin.json version
   - Enabled plugins that aren't installed
+  - Registered plugins their marketplace no longer lists (removed upstream)
+  - Plugin keys that differ only by case within a settings scope
+  - Plugins enabled in one settings scope and disabled in another
+  - Slash commands defined by more than one installed plugin
+  - Claude Code version and registry format compatibility (informational)
+
+With --fix, plugins missing from the cache are downloaded again and orphaned
+cache directories are removed after confirmation. The checks then run again so
+the report reflects the repaired state.
 
 Examples:
   plum doctor
+  plum doctor --fix
   plum doctor --json`,
 	RunE: runDoctor,
 }
@@ -33,6 +47,7 @@ Examples:
 var (
 	doctorJSON    bool
 	doctorProject string
+	doctorFix     bool
 )
 
 func init() {
@@ -40,6 +55,7 @@ func init() {
 
 	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output as JSON")
 	doctorCmd.Flags().StringVar(&doctorProject, "project", "", "Project path (default: current directory)")
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair missing cache files and offer to remove orphaned caches")
 }
 
 // DoctorIssue represents a health check issue
@@ -49,6 +65,10 @@ type DoctorIssue struct {
 	Plugin      string `json:"plugin,omitempty"`
 	Path        string `json:"path,omitempty"`
 	Description string `json:"description"`
+
+	// Set for version_mismatch issues
+	RegistryVersion string `json:"registryVersion,omitempty"`
+	CachedVersion   string `json:"cachedVersion,omitempty"`
 }
 
 // DoctorResult holds the results of the health check
@@ -56,6 +76,7 @@ type DoctorResult struct {
 	Healthy bool          `json:"healthy"`
 	Issues  []DoctorIssue `json:"issues"`
 	Summary DoctorSummary `json:"summary"`
+	Fixes   []DoctorFix   `json:"fixes,omitempty"` // Repairs attempted by --fix
 }
 
 // DoctorSummary provides counts of different issue types
@@ -65,9 +86,42 @@ type DoctorSummary struct {
 	EnabledPlugins    int `json:"enabledPlugins"`
 	Errors            int `json:"errors"`
 	Warnings          int `json:"warnings"`
+	VersionMismatches int `json:"versionMismatches"`
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	result, err := checkHealth(cmd)
+	if err != nil {
+		return err
+	}
+
+	if doctorFix {
+		fixes := fixDoctorIssues(cmd, result.Issues)
+
+		// Re-run the checks so the report reflects the repaired state
+		result, err = checkHealth(cmd)
+		if err != nil {
+			return err
+		}
+		result.Fixes = fixes
+
+		if !doctorJSON {
+			outputDoctorFixes(fixes)
+		}
+	}
+
+	// Output
+	if doctorJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	return outputDoctorResult(result)
+}
+
+// checkHealth runs every doctor check and returns the collected issues
+func checkHealth(cmd *cobra.Command) (DoctorResult, error) {
 	result := DoctorResult{
 		Healthy: true,
 		Issues:  make([]DoctorIssue, 0),
@@ -76,7 +130,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Get plugins directory
 	pluginsDir, err := config.ClaudePluginsDir()
 	if err != nil {
-		return fmt.Errorf("failed to get plugins directory: %w", err)
+		return result, fmt.Errorf("failed to get plugins directory: %w", err)
 	}
 	cacheDir := filepath.Join(pluginsDir, "cache")
 
@@ -101,70 +155,51 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build set of registered plugins for lookup
-	registeredPaths := make(map[string]string) // path -> fullName
-	for fullName, installs := range installed.Plugins {
-		for _, install := range installs {
-			if install.InstallPath != "" {
-				registeredPaths[install.InstallPath] = fullName
-			}
-		}
-	}
+	registeredPaths := registeredInstallPaths(installed)
 
 	// Check 1: Scan cache directory for plugin directories
-	cachedPlugins := make(map[string]bool) // path -> exists
-	if _, err := os.Stat(cacheDir); err == nil {
-		err := filepath.WalkDir(cacheDir, func(path string, d os.DirEntry, err error) error {
-			if err != nil {
-				return nil // Skip errors
+	pluginDirs, err := scanCachedPlugins(cacheDir)
+	if err != nil {
+		// Log but don't fail
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: error scanning cache: %v\n", err)
+	}
+	for _, pluginDir := range pluginDirs {
+		result.Summary.CachedPlugins++
+
+		// Check for plugin.json
+		pluginJSONPath := filepath.Join(pluginDir, ".claude-plugin", "plugin.json")
+		if _, statErr := os.Stat(pluginJSONPath); os.IsNotExist(statErr) {
+			result.Issues = append(result.Issues, DoctorIssue{
+				Type:        "missing_plugin_json",
+				Severity:    "error",
+				Path:        pluginDir,
+				Description: "Missing plugin.json file",
+			})
+			result.Summary.Errors++
+		} else if statErr == nil {
+			// Validate JSON
+			if jsonErr := validatePluginJSON(pluginJSONPath); jsonErr != nil {
+				result.Issues = append(result.Issues, DoctorIssue{
+					Type:        "invalid_json",
+					Severity:    "error",
+					Path:        pluginJSONPath,
+					Description: fmt.Sprintf("Invalid plugin.json: %v", jsonErr),
+				})
+				result.Summary.Errors++
 			}
+		}
 
-			// Look for .claude-plugin directories
-			if d.IsDir() && d.Name() == ".claude-plugin" {
-				pluginDir := filepath.Dir(path)
-				cachedPlugins[pluginDir] = true
-				result.Summary.CachedPlugins++
-
-				// Check for plugin.json
-				pluginJSONPath := filepath.Join(path, "plugin.json")
-				if _, statErr := os.Stat(pluginJSONPath); os.IsNotExist(statErr) {
-					result.Issues = append(result.Issues, DoctorIssue{
-						Type:        "missing_plugin_json",
-						Severity:    "error",
-						Path:        pluginDir,
-						Description: "Missing plugin.json file",
-					})
-					result.Summary.Errors++
-				} else if statErr == nil {
-					// Validate JSON
-					if jsonErr := validatePluginJSON(pluginJSONPath); jsonErr != nil {
-						result.Issues = append(result.Issues, DoctorIssue{
-							Type:        "invalid_json",
-							Severity:    "error",
-							Path:        pluginJSONPath,
-							Description: fmt.Sprintf("Invalid plugin.json: %v", jsonErr),
-						})
-						result.Summary.Errors++
-					}
-				}
-
-				// Check if this cached plugin is registered
-				if _, registered := registeredPaths[pluginDir]; !registered {
-					// Extract plugin name from path for the message
-					relPath, _ := filepath.Rel(cacheDir, pluginDir)
-					result.Issues = append(result.Issues, DoctorIssue{
-						Type:        "orphaned_cache",
-						Severity:    "warning",
-						Path:        pluginDir,
-						Description: fmt.Sprintf("Cached plugin '%s' not in registry", relPath),
-					})
-					result.Summary.Warnings++
-				}
-			}
-			return nil
-		})
-		if err != nil {
-			// Log but don't fail
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: error scanning cache: %v\n", err)
+		// Check if this cached plugin is registered
+		if _, registered := registeredPaths[pluginDir]; !registered {
+			// Extract plugin name from path for the message
+			relPath, _ := filepath.Rel(cacheDir, pluginDir)
+			result.Issues = append(result.Issues, DoctorIssue{
+				Type:        "orphaned_cache",
+				Severity:    "warning",
+				Path:        pluginDir,
+				Description: fmt.Sprintf("Cached plugin '%s' not in registry", relPath),
+			})
+			result.Summary.Warnings++
 		}
 	}
 
@@ -182,6 +217,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 						Description: "Registered plugin missing from cache",
 					})
 					result.Summary.Errors++
+				} else if issue, drifted := checkVersionDrift(fullName, install, pluginJSONPath); drifted {
+					result.Issues = append(result.Issues, issue)
+					result.Summary.Warnings++
+					result.Summary.VersionMismatches++
 				}
 			}
 		}
@@ -202,17 +241,225 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Check 4: Detect case-variant duplicate plugin keys within each scope
+	for _, issue := range checkCaseVariantKeys(doctorProject) {
+		result.Issues = append(result.Issues, issue)
+		result.Summary.Warnings++
+	}
+
+	// Check 5: Detect slash commands defined by more than one installed plugin
+	for _, issue := range checkCommandConflicts(installed) {
+		result.Issues = append(result.Issues, issue)
+		result.Summary.Warnings++
+	}
+
+	// Check 6: Detect registered plugins their marketplace no longer lists
+	for _, issue := range checkRemovedUpstream(installed) {
+		result.Issues = append(result.Issues, issue)
+		result.Summary.Warnings++
+	}
+
+	// Check 7: Detect plugins whose scopes disagree on whether they're enabled
+	for _, issue := range checkScopeConflicts(doctorProject) {
+		result.Issues = append(result.Issues, issue)
+		result.Summary.Warnings++
+	}
+
+	// Check 8: Claude Code version and registry format (informational only)
+	result.Issues = append(result.Issues, checkClaudeCompatibility(installed.Version)...)
+
 	// Determine overall health
 	result.Healthy = result.Summary.Errors == 0
 
-	// Output
-	if doctorJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(result)
+	return result, nil
+}
+
+// registeredInstallPaths maps each install path in the registry to its plugin
+func registeredInstallPaths(installed *config.InstalledPluginsV2) map[string]string {
+	paths := make(map[string]string) // path -> fullName
+	for fullName, installs := range installed.Plugins {
+		for _, install := range installs {
+			if install.InstallPath != "" {
+				paths[install.InstallPath] = fullName
+			}
+		}
 	}
+	return paths
+}
 
-	return outputDoctorResult(result)
+// scanCachedPlugins returns every plugin directory (one containing a
+// .claude-plugin directory) under cacheDir. A missing cacheDir yields none.
+func scanCachedPlugins(cacheDir string) ([]string, error) {
+	if _, err := os.Stat(cacheDir); err != nil {
+		return nil, nil
+	}
+
+	var pluginDirs []string
+	err := filepath.WalkDir(cacheDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip errors
+		}
+
+		// Update backups aren't installs; 'plum rollback' manages them
+		if d.IsDir() && path == filepath.Join(cacheDir, backupDirName) {
+			return filepath.SkipDir
+		}
+
+		// A directory with a .claude-plugin directory is a plugin. Don't
+		// descend into it: vendored or example plugins nested inside belong
+		// to this one and aren't installs of their own.
+		if !d.IsDir() {
+			return nil
+		}
+		if info, err := os.Stat(filepath.Join(path, ".claude-plugin")); err == nil && info.IsDir() {
+			pluginDirs = append(pluginDirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return pluginDirs, err
+}
+
+// checkScopeConflicts reports plugins enabled in one scope and disabled in
+// another, naming the scope that wins and the values it overrides
+func checkScopeConflicts(projectPath string) []DoctorIssue {
+	conflicts, err := settings.DetectConflicts(projectPath)
+	if err != nil {
+		return nil
+	}
+
+	issues := make([]DoctorIssue, 0, len(conflicts))
+	for _, c := range conflicts {
+		winner := c.Values[0]
+		state := "Disabled"
+		if winner.Enabled {
+			state = "Enabled"
+		}
+		overridden := make([]string, 0, len(c.Values)-1)
+		for _, v := range c.Values[1:] {
+			overridden = append(overridden, fmt.Sprintf("%s: %s", v.Scope, enabledLabel(v.Enabled)))
+		}
+		issues = append(issues, DoctorIssue{
+			Type:        "scope_conflict",
+			Severity:    "warning",
+			Plugin:      c.FullName,
+			Description: fmt.Sprintf("%s in %s scope, overriding %s", state, winner.Scope, strings.Join(overridden, ", ")),
+		})
+	}
+	return issues
+}
+
+// checkCaseVariantKeys reports enabledPlugins keys that differ only by
+// marketplace case within the same scope (e.g. foo@Market and foo@market)
+func checkCaseVariantKeys(projectPath string) []DoctorIssue {
+	var issues []DoctorIssue
+	for _, scope := range settings.AllScopes() {
+		s, err := settings.LoadSettings(scope, projectPath)
+		if err != nil || s == nil {
+			continue
+		}
+		for _, keys := range settings.CaseVariantDuplicates(s.EnabledPlugins) {
+			issues = append(issues, DoctorIssue{
+				Type:        "case_variant_duplicate",
+				Severity:    "warning",
+				Plugin:      keys[0],
+				Description: fmt.Sprintf("Keys differ only by case in %s scope: %s", scope, strings.Join(keys, ", ")),
+			})
+		}
+	}
+	return issues
+}
+
+// checkRemovedUpstream reports registered plugins missing from their
+// marketplace's locally available manifest, since they will never receive
+// updates. Marketplaces without a local manifest are skipped; nothing is
+// fetched from the network.
+func checkRemovedUpstream(installed *config.InstalledPluginsV2) []DoctorIssue {
+	known, _ := config.LoadKnownMarketplaces() // Not fatal - plum's cache may still have the manifest
+
+	listed := make(map[string]map[string]bool) // marketplace -> plugin names; nil if unknown
+	var issues []DoctorIssue
+	for fullName := range installed.Plugins {
+		pluginName, marketplaceName, ok := plugin.ParseFullName(fullName)
+		if !ok {
+			continue
+		}
+
+		names, loaded := listed[marketplaceName]
+		if !loaded {
+			names = cachedMarketplacePlugins(marketplaceName, known)
+			listed[marketplaceName] = names
+		}
+		if names == nil || names[pluginName] {
+			continue
+		}
+
+		issues = append(issues, DoctorIssue{
+			Type:        "removed_upstream",
+			Severity:    "warning",
+			Plugin:      fullName,
+			Description: fmt.Sprintf("No longer listed by marketplace '%s' - updates will not be available", marketplaceName),
+		})
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Plugin < issues[j].Plugin })
+	return issues
+}
+
+// cachedMarketplacePlugins returns the plugin names in a marketplace's local
+// manifest, preferring Claude Code's clone over plum's cache. Returns nil when
+// neither is available.
+func cachedMarketplacePlugins(marketplaceName string, known config.KnownMarketplaces) map[string]bool {
+	var manifest *marketplace.MarketplaceManifest
+	if entry, ok := known[marketplaceName]; ok {
+		manifest, _ = config.LoadMarketplaceManifest(entry.InstallLocation)
+	}
+	if manifest == nil {
+		manifest, _ = marketplace.LoadFromCache(marketplaceName)
+	}
+	if manifest == nil {
+		return nil
+	}
+
+	names := make(map[string]bool, len(manifest.Plugins))
+	for _, p := range manifest.Plugins {
+		names[p.Name] = true
+	}
+	return names
+}
+
+// checkVersionDrift compares a registry entry's version with the version in
+// its cached plugin.json. Missing or unreadable versions are not reported.
+func checkVersionDrift(fullName string, install config.PluginInstall, pluginJSONPath string) (DoctorIssue, bool) {
+	if install.Version == "" {
+		return DoctorIssue{}, false
+	}
+
+	// #nosec G304 -- path is built from the install registry's cache path
+	data, err := os.ReadFile(pluginJSONPath)
+	if err != nil {
+		return DoctorIssue{}, false
+	}
+	var manifest struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.Version == "" {
+		return DoctorIssue{}, false
+	}
+
+	if strings.TrimPrefix(manifest.Version, "v") == strings.TrimPrefix(install.Version, "v") {
+		return DoctorIssue{}, false
+	}
+
+	return DoctorIssue{
+		Type:            "version_mismatch",
+		Severity:        "warning",
+		Plugin:          fullName,
+		Path:            install.InstallPath,
+		Description:     fmt.Sprintf("Registry lists version %s but cached plugin.json is %s", install.Version, manifest.Version),
+		RegistryVersion: install.Version,
+		CachedVersion:   manifest.Version,
+	}, true
 }
 
 func validatePluginJSON(path string) error {
@@ -257,13 +504,15 @@ func outputDoctorResult(result DoctorResult) error {
 	}
 
 	// Group issues by severity
-	var errors, warnings []DoctorIssue
+	var errors, warnings, infos []DoctorIssue
 	for _, issue := range result.Issues {
 		switch issue.Severity {
 		case "error":
 			errors = append(errors, issue)
 		case "warning":
 			warnings = append(warnings, issue)
+		case "info":
+			infos = append(infos, issue)
 		}
 	}
 
@@ -285,10 +534,25 @@ func outputDoctorResult(result DoctorResult) error {
 		fmt.Println()
 	}
 
+	// Then informational notes
+	if len(infos) > 0 {
+		fmt.Printf("Info (%d):\n", len(infos))
+		for _, issue := range infos {
+			printIssue(issue)
+		}
+		fmt.Println()
+	}
+
 	// Suggestions
 	if result.Summary.Errors > 0 {
 		fmt.Println("Run 'plum install <plugin>' to reinstall missing plugins")
 	}
+	for _, issue := range result.Issues {
+		if issue.Type == "orphaned_cache" {
+			fmt.Println("Run 'plum prune' to delete orphaned cache directories")
+			break
+		}
+	}
 
 	return nil
 }
@@ -300,6 +564,8 @@ func printIssue(issue DoctorIssue) {
 		prefix = "  ✗"
 	case "warning":
 		prefix = "  !"
+	case "info":
+		prefix = "  i"
 	}
 
 	desc := issue.Description
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+// DoctorFix records the outcome of one --fix repair
+type DoctorFix struct {
+	Type    string `json:"type"` // Issue type that was addressed
+	Plugin  string `json:"plugin,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Fixed   bool   `json:"fixed"`
+	Message string `json:"message"`
+}
+
+// fixDoctorIssues repairs what it can: missing_cache errors are downloaded
+// again, orphaned_cache warnings are removed after confirmation. Other issue
+// types are left alone.
+func fixDoctorIssues(cmd *cobra.Command, issues []DoctorIssue) []DoctorFix {
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		installed = &config.InstalledPluginsV2{Plugins: make(map[string][]config.PluginInstall)}
+	}
+
+	// Keep prompts off stdout when it carries JSON
+	prompts := cmd.OutOrStdout()
+	if doctorJSON {
+		prompts = cmd.ErrOrStderr()
+	}
+	// One shared reader so answers aren't lost between prompts
+	in := bufio.NewReader(cmd.InOrStdin())
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var fixes []DoctorFix
+	for _, issue := range issues {
+		switch issue.Type {
+		case "missing_cache":
+			fixes = append(fixes, repairMissingCache(ctx, issue.Plugin, issue.Path, installed))
+		case "orphaned_cache":
+			fix := DoctorFix{Type: issue.Type, Path: issue.Path}
+			if !confirm(in, prompts, fmt.Sprintf("Remove orphaned cache %s?", shortenPath(issue.Path))) {
+				fix.Message = "kept (not confirmed)"
+			} else if err := removeOrphanedCache(issue.Path); err != nil {
+				fix.Message = err.Error()
+			} else {
+				fix.Fixed = true
+				fix.Message = "removed"
+			}
+			fixes = append(fixes, fix)
+		}
+	}
+	return fixes
+}
+
+// repairMissingCache downloads a registered plugin into its recorded install
+// path at the recorded version: from the recorded commit when there is one,
+// otherwise from a pinned install's tag. Unpinned installs with no commit can
+// only get the marketplace's current version, so their registry entry is
+// updated to match what was downloaded.
+func repairMissingCache(ctx context.Context, fullName, installPath string, installed *config.InstalledPluginsV2) DoctorFix {
+	fix := DoctorFix{Type: "missing_cache", Plugin: fullName, Path: installPath}
+
+	pluginName, marketplaceName, _, err := parsePluginArg(fullName)
+	if err != nil || marketplaceName == "" {
+		fix.Message = fmt.Sprintf("invalid plugin name %q", fullName)
+		return fix
+	}
+
+	var recorded config.PluginInstall
+	for _, install := range installed.Plugins[fullName] {
+		if install.InstallPath == installPath {
+			recorded = install
+			break
+		}
+	}
+	commit := ""
+	if marketplace.IsCommitSHA(recorded.GitCommitSha) {
+		commit = recorded.GitCommitSha
+	}
+
+	// A commit is fetched directly, so the version needn't still be listed
+	lookupVersion := ""
+	if commit == "" && recorded.Ref != "" {
+		lookupVersion = recorded.Version
+	}
+	pluginInfo, err := findPluginInMarketplaces(pluginName, marketplaceName, lookupVersion, false)
+	if err != nil {
+		fix.Message = err.Error()
+		return fix
+	}
+	if !pluginInfo.Installable {
+		fix.Message = fmt.Sprintf("cannot be installed by plum: %s", pluginInfo.InstallabilityReason)
+		return fix
+	}
+	if commit != "" {
+		pluginInfo.Commit = commit
+		pluginInfo.Pinned = true
+		if recorded.Version != "" {
+			pluginInfo.Version = recorded.Version
+		}
+	}
+
+	ref, err := install.DownloadToCache(ctx, pluginInfo, installPath, true, os.Stderr)
+	if err != nil {
+		fix.Message = err.Error()
+		return fix
+	}
+
+	if commit == "" && recorded.Ref == "" {
+		scope, err := settings.ParseScope(recorded.Scope)
+		if err == nil {
+			err = install.Register(fullName, installPath, pluginInfo.Version, "", install.ResolveCommit(pluginInfo, ref), scope, recorded.ProjectPath)
+		}
+		if err != nil {
+			fix.Message = fmt.Sprintf("downloaded again, but failed to update install registry: %v", err)
+			return fix
+		}
+	}
+
+	fix.Fixed = true
+	fix.Message = "downloaded again"
+	if pluginInfo.Version != "" {
+		fix.Message = fmt.Sprintf("downloaded again at %s", pluginInfo.Version)
+	}
+	return fix
+}
+
+// removeOrphanedCache deletes an orphaned plugin directory, refusing
+// anything outside the plugin cache
+func removeOrphanedCache(path string) error {
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		return err
+	}
+	cacheDir := filepath.Join(pluginsDir, "cache")
+	if !isInsideDir(path, cacheDir) {
+		return fmt.Errorf("refusing to remove %s: outside cache directory %s", path, cacheDir)
+	}
+	return os.RemoveAll(path)
+}
+
+func outputDoctorFixes(fixes []DoctorFix) {
+	if len(fixes) == 0 {
+		fmt.Println("Nothing to fix")
+		fmt.Println()
+		return
+	}
+
+	var fixed, skipped []string
+	for _, f := range fixes {
+		target := f.Plugin
+		if target == "" {
+			target = shortenPath(f.Path)
+		}
+		line := fmt.Sprintf("%s: %s", target, f.Message)
+		if f.Fixed {
+			fixed = append(fixed, line)
+		} else {
+			skipped = append(skipped, line)
+		}
+	}
+
+	fmt.Printf("Fixed %d, skipped %d\n", len(fixed), len(skipped))
+	if len(fixed) > 0 {
+		fmt.Println("  ✓ " + strings.Join(fixed, "\n  ✓ "))
+	}
+	if len(skipped) > 0 {
+		fmt.Println("  - " + strings.Join(skipped, "\n  - "))
+	}
+	fmt.Println()
+}
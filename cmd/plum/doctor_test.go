@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+func TestDoctorCommand_Structure(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"doctor"})
+	if err != nil {
+		t.Fatalf("doctor command not found: %v", err)
+	}
+
+	flags := []string{"json", "project", "fix"}
+	for _, flag := range flags {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag --%s to exist", flag)
+		}
+	}
+}
+
+func TestCheckScopeConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	claudeDir := filepath.Join(tmpDir, ".claude")
+	projectDir := filepath.Join(tmpDir, "project")
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+
+	files := map[string]string{
+		filepath.Join(claudeDir, "settings.json"):             `{"enabledPlugins": {"foo@market": true, "bar@market": true}}`,
+		filepath.Join(projectDir, ".claude", "settings.json"): `{"enabledPlugins": {"foo@market": false, "bar@market": true}}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues := checkScopeConflicts(projectDir)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d: %+v", len(issues), issues)
+	}
+
+	issue := issues[0]
+	if issue.Type != "scope_conflict" || issue.Severity != "warning" || issue.Plugin != "foo@market" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if want := "Disabled in project scope, overriding user: enabled"; issue.Description != want {
+		t.Errorf("description = %q, want %q", issue.Description, want)
+	}
+}
+
+func TestCheckCaseVariantKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	claudeDir := filepath.Join(tmpDir, ".claude")
+	if err := os.MkdirAll(claudeDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+
+	userSettings := `{
+		"enabledPlugins": {
+			"foo@Market": true,
+			"foo@market": false,
+			"bar@market": true
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(claudeDir, "settings.json"), []byte(userSettings), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := checkCaseVariantKeys(t.TempDir())
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d: %+v", len(issues), issues)
+	}
+
+	issue := issues[0]
+	if issue.Type != "case_variant_duplicate" {
+		t.Errorf("expected type case_variant_duplicate, got %s", issue.Type)
+	}
+	if issue.Severity != "warning" {
+		t.Errorf("expected severity warning, got %s", issue.Severity)
+	}
+	if !strings.Contains(issue.Description, "foo@Market") || !strings.Contains(issue.Description, "foo@market") {
+		t.Errorf("description should list both keys, got %q", issue.Description)
+	}
+}
+
+func TestDoctorFix_RepopulatesMissingCache(t *testing.T) {
+	configDir := setupInstallFixture(t)
+
+	cacheRoot := filepath.Join(configDir, "plugins", "cache")
+	installPath := filepath.Join(cacheRoot, "claude-code-marketplace", "alpha")
+	// The marketplace now lists 1.0.0 and no commit was recorded
+	if err := install.Register("alpha@claude-code-marketplace", installPath, "0.9.0", "", "", settings.ScopeUser, ""); err != nil {
+		t.Fatalf("install.Register failed: %v", err)
+	}
+
+	orphanDir := filepath.Join(cacheRoot, "claude-code-marketplace", "orphan")
+	writeFixturePlugin(t, orphanDir, `{"name": "orphan"}`)
+
+	doctorFix = true
+	doctorJSON = true
+	doctorProject = t.TempDir()
+	defer func() { doctorFix = false; doctorJSON = false; doctorProject = "" }()
+
+	doctorCmd.SetIn(strings.NewReader("y\n"))
+	doctorCmd.SetErr(io.Discard)
+	defer func() { doctorCmd.SetIn(nil); doctorCmd.SetErr(nil) }()
+
+	output, err := captureStdout(t, func() error { return runDoctor(doctorCmd, nil) })
+	if err != nil {
+		t.Fatalf("runDoctor --fix failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(installPath, ".claude-plugin", "plugin.json")); err != nil {
+		t.Errorf("--fix should repopulate the cache: %v", err)
+	}
+	if _, err := os.Stat(orphanDir); !os.IsNotExist(err) {
+		t.Errorf("--fix should remove the confirmed orphan (stat err = %v)", err)
+	}
+
+	var result DoctorResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	if len(result.Fixes) != 2 {
+		t.Fatalf("expected 2 fixes, got %+v", result.Fixes)
+	}
+	for _, fix := range result.Fixes {
+		if !fix.Fixed {
+			t.Errorf("expected %s to be fixed: %+v", fix.Type, fix)
+		}
+	}
+
+	// The final report reflects the repaired state
+	for _, issue := range result.Issues {
+		if issue.Type == "missing_cache" || issue.Type == "orphaned_cache" || issue.Type == "version_mismatch" {
+			t.Errorf("issue should be resolved after --fix: %+v", issue)
+		}
+	}
+
+	// The registry records what was downloaded
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := installed.Plugins["alpha@claude-code-marketplace"][0]
+	if got.Version != "1.0.0" || got.GitCommitSha != fixtureCommit {
+		t.Errorf("registry entry = %s at %s, want 1.0.0 at %s", got.Version, got.GitCommitSha, fixtureCommit)
+	}
+}
+
+func TestDoctorFix_DownloadsRecordedCommit(t *testing.T) {
+	configDir := setupInstallFixture(t)
+	const fullName = "alpha@claude-code-marketplace"
+
+	// 0.9.0 is no longer listed, but its commit can still be fetched
+	installPath := filepath.Join(configDir, "plugins", "cache", "claude-code-marketplace", "alpha")
+	if err := install.Register(fullName, installPath, "0.9.0", "", fixtureCommit, settings.ScopeUser, ""); err != nil {
+		t.Fatalf("install.Register failed: %v", err)
+	}
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fix := repairMissingCache(context.Background(), fullName, installPath, installed)
+	if !fix.Fixed || fix.Message != "downloaded again at 0.9.0" {
+		t.Fatalf("unexpected fix: %+v", fix)
+	}
+
+	// The commit's plugin.json, not the default branch's
+	data, err := os.ReadFile(filepath.Join(installPath, ".claude-plugin", "plugin.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"name": "alpha"}` {
+		t.Errorf("plugin.json = %s, want the recorded commit's", data)
+	}
+	installed, err = config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := installed.Plugins[fullName][0]; got.Version != "0.9.0" || got.GitCommitSha != fixtureCommit {
+		t.Errorf("registry entry changed to %s at %s", got.Version, got.GitCommitSha)
+	}
+}
+
+func TestDoctorFix_DeclinedOrphanIsKept(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orphanDir := filepath.Join(pluginsDir, "cache", "mkt", "orphan")
+	writeFixturePlugin(t, orphanDir, `{"name": "orphan"}`)
+
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetOut(io.Discard)
+
+	fixes := fixDoctorIssues(cmd, []DoctorIssue{{Type: "orphaned_cache", Severity: "warning", Path: orphanDir}})
+	if len(fixes) != 1 || fixes[0].Fixed {
+		t.Errorf("declined orphan should be skipped, got %+v", fixes)
+	}
+	if _, err := os.Stat(orphanDir); err != nil {
+		t.Errorf("declined orphan should be kept: %v", err)
+	}
+}
+
+func TestCheckVersionDrift(t *testing.T) {
+	dir := t.TempDir()
+	pluginJSONPath := filepath.Join(dir, ".claude-plugin", "plugin.json")
+	writeFixturePlugin(t, dir, `{"name": "alpha", "version": "1.2.0"}`)
+
+	tests := []struct {
+		name            string
+		registryVersion string
+		wantDrift       bool
+	}{
+		{"matching version", "1.2.0", false},
+		{"matching with v prefix", "v1.2.0", false},
+		{"mismatched version", "1.0.0", true},
+		{"no registry version", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			install := config.PluginInstall{Scope: "user", InstallPath: dir, Version: tt.registryVersion}
+			issue, drifted := checkVersionDrift("alpha@mkt", install, pluginJSONPath)
+			if drifted != tt.wantDrift {
+				t.Fatalf("drifted = %v, want %v (%+v)", drifted, tt.wantDrift, issue)
+			}
+			if !drifted {
+				return
+			}
+			if issue.Type != "version_mismatch" || issue.Severity != "warning" {
+				t.Errorf("unexpected issue type/severity: %+v", issue)
+			}
+			if !strings.Contains(issue.Description, "1.0.0") || !strings.Contains(issue.Description, "1.2.0") {
+				t.Errorf("description should include both versions, got %q", issue.Description)
+			}
+			if issue.RegistryVersion != "1.0.0" || issue.CachedVersion != "1.2.0" {
+				t.Errorf("unexpected versions: registry %q, cached %q", issue.RegistryVersion, issue.CachedVersion)
+			}
+		})
+	}
+}
+
+func TestDoctor_ReportsVersionMismatch(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installPath := filepath.Join(pluginsDir, "cache", "mkt", "alpha")
+	writeFixturePlugin(t, installPath, `{"name": "alpha", "version": "2.0.0"}`)
+	if err := install.Register("alpha@mkt", installPath, "1.0.0", "", "", settings.ScopeUser, ""); err != nil {
+		t.Fatalf("install.Register failed: %v", err)
+	}
+
+	doctorProject = t.TempDir()
+	defer func() { doctorProject = "" }()
+
+	result, err := checkHealth(doctorCmd)
+	if err != nil {
+		t.Fatalf("checkHealth failed: %v", err)
+	}
+	if result.Summary.VersionMismatches != 1 {
+		t.Errorf("expected 1 version mismatch, got %d: %+v", result.Summary.VersionMismatches, result.Issues)
+	}
+}
+
+func TestDoctor_ReportsRemovedUpstream(t *testing.T) {
+	configDir := setupInstallFixture(t)
+	cacheDir := filepath.Join(configDir, "plugins", "cache")
+
+	// alpha is still listed, gamma was removed upstream, and other-mkt has
+	// no local manifest so it can't be judged
+	for _, fullName := range []string{"alpha@claude-code-marketplace", "gamma@claude-code-marketplace", "delta@other-mkt"} {
+		installPath := filepath.Join(cacheDir, strings.ReplaceAll(fullName, "@", "-"))
+		writeFixturePlugin(t, installPath, `{"name": "x"}`)
+		if err := install.Register(fullName, installPath, "", "", "", settings.ScopeUser, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	doctorProject = t.TempDir()
+	defer func() { doctorProject = "" }()
+
+	result, err := checkHealth(doctorCmd)
+	if err != nil {
+		t.Fatalf("checkHealth failed: %v", err)
+	}
+
+	var removed []string
+	for _, issue := range result.Issues {
+		if issue.Type == "removed_upstream" {
+			removed = append(removed, issue.Plugin)
+			if issue.Severity != "warning" {
+				t.Errorf("removed_upstream severity = %q, want warning", issue.Severity)
+			}
+		}
+	}
+	if len(removed) != 1 || removed[0] != "gamma@claude-code-marketplace" {
+		t.Errorf("removed_upstream issues = %v, want only gamma@claude-code-marketplace", removed)
+	}
+}
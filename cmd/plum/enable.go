@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/settings"
 	"github.com/spf13/cobra"
 )
@@ -21,17 +23,25 @@ The plugin can be specified as:
   - plugin-name (uses first matching installed plugin)
   - plugin-name@marketplace (specific marketplace)
 
+Use --all instead of a plugin name to enable every plugin in the scope,
+for example to undo "plum disable --all". Use --marketplace instead to
+enable every installed plugin from one marketplace.
+
 Examples:
   plum enable ralph-wiggum
   plum enable ralph-wiggum@claude-code-plugins
-  plum enable memory --scope=project`,
-	Args: cobra.ExactArgs(1),
+  plum enable memory --scope=project
+  plum enable --all
+  plum enable --marketplace=claude-code-plugins`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runEnable,
 }
 
 var (
 	enableScope   string
 	enableProject string
+	enableAll     bool
+	enableMarket  string
 )
 
 func init() {
@@ -39,13 +49,17 @@ func init() {
 
 	enableCmd.Flags().StringVarP(&enableScope, "scope", "s", "user", "Target scope (user, project, local)")
 	enableCmd.Flags().StringVar(&enableProject, "project", "", "Project path (default: current directory)")
+	enableCmd.Flags().BoolVar(&enableAll, "all", false, "Enable every plugin in the target scope")
+	enableCmd.Flags().StringVar(&enableMarket, "marketplace", "", "Enable every installed plugin from this marketplace")
 }
 
 func runEnable(cmd *cobra.Command, args []string) error {
-	pluginArg := args[0]
+	if err := checkPluginOrAll(args, enableAll, enableMarket); err != nil {
+		return err
+	}
 
 	// Parse scope
-	scope, err := settings.ParseScope(enableScope)
+	scope, err := parseScopeFlag(cmd, enableScope, enableProject)
 	if err != nil {
 		return err
 	}
@@ -54,6 +68,18 @@ func runEnable(cmd *cobra.Command, args []string) error {
 	if !scope.IsWritable() {
 		return fmt.Errorf("cannot write to %s scope (read-only)", scope)
 	}
+	if err := settings.CheckWritable(scope, enableProject); err != nil {
+		return err
+	}
+
+	if enableAll {
+		return setAllPluginsEnabled(true, scope, enableProject)
+	}
+	if enableMarket != "" {
+		return setMarketplacePluginsEnabled(true, enableMarket, scope, enableProject)
+	}
+
+	pluginArg := args[0]
 
 	// Resolve plugin full name
 	fullName, err := resolvePluginFullName(pluginArg, enableProject)
@@ -70,6 +96,76 @@ func runEnable(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkPluginOrAll validates that exactly one of a plugin argument, --all,
+// or --marketplace was given
+func checkPluginOrAll(args []string, all bool, marketplace string) error {
+	switch {
+	case all && marketplace != "":
+		return fmt.Errorf("cannot combine --all with --marketplace")
+	case all && len(args) > 0:
+		return fmt.Errorf("cannot combine a plugin name with --all")
+	case marketplace != "" && len(args) > 0:
+		return fmt.Errorf("cannot combine a plugin name with --marketplace")
+	case !all && marketplace == "" && len(args) == 0:
+		return fmt.Errorf("requires a plugin name, --all, or --marketplace")
+	}
+	return nil
+}
+
+// setAllPluginsEnabled flips every plugin in scope in a single settings write
+// and reports how many changed
+func setAllPluginsEnabled(enabled bool, scope settings.Scope, projectPath string) error {
+	verb := "Disabled"
+	if enabled {
+		verb = "Enabled"
+	}
+
+	changed, err := settings.SetAllPluginsEnabled(enabled, scope, projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to update plugins: %w", err)
+	}
+
+	for _, fullName := range changed {
+		fmt.Printf("  %s\n", fullName)
+	}
+	fmt.Printf("%s %d plugin(s) in %s scope\n", verb, len(changed), scope)
+	return nil
+}
+
+// setMarketplacePluginsEnabled sets every installed plugin from a marketplace
+// in scope and reports how many were toggled
+func setMarketplacePluginsEnabled(enabled bool, marketplace string, scope settings.Scope, projectPath string) error {
+	verb := "Disabled"
+	if enabled {
+		verb = "Enabled"
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to load installed plugins: %w", err)
+	}
+
+	var names []string
+	for fullName := range installed.Plugins {
+		if _, m, ok := plugin.ParseFullName(fullName); ok && m == marketplace {
+			names = append(names, fullName)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no installed plugins from marketplace '%s'", marketplace)
+	}
+	sort.Strings(names)
+
+	for _, fullName := range names {
+		if err := settings.SetPluginEnabled(fullName, enabled, scope, projectPath); err != nil {
+			return fmt.Errorf("failed to update %s: %w", fullName, err)
+		}
+		fmt.Printf("  %s\n", fullName)
+	}
+	fmt.Printf("%s %d plugin(s) from %s in %s scope\n", verb, len(names), marketplace, scope)
+	return nil
+}
+
 // resolvePluginFullName resolves a plugin argument to its full name (plugin@marketplace)
 // If the argument already contains @, it's returned as-is after validation
 // Otherwise, it searches installed plugins and settings for a match
@@ -77,8 +173,7 @@ func resolvePluginFullName(pluginArg string, projectPath string) (string, error)
 	// If already has @marketplace, validate and return
 	if strings.Contains(pluginArg, "@") {
 		// Validate format
-		parts := strings.SplitN(pluginArg, "@", 2)
-		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		if _, _, ok := plugin.ParseFullName(pluginArg); !ok {
 			return "", fmt.Errorf("invalid plugin format: %s (expected: plugin-name@marketplace)", pluginArg)
 		}
 		return pluginArg, nil
@@ -93,8 +188,7 @@ func resolvePluginFullName(pluginArg string, projectPath string) (string, error)
 	// Look for exact name match
 	var matches []string
 	for fullName := range installed.Plugins {
-		name := strings.SplitN(fullName, "@", 2)[0]
-		if name == pluginArg {
+		if name, _, ok := plugin.ParseFullName(fullName); ok && name == pluginArg {
 			matches = append(matches, fullName)
 		}
 	}
@@ -103,8 +197,7 @@ func resolvePluginFullName(pluginArg string, projectPath string) (string, error)
 	states, err := settings.MergedPluginStates(projectPath)
 	if err == nil {
 		for _, state := range states {
-			name := strings.SplitN(state.FullName, "@", 2)[0]
-			if name == pluginArg {
+			if name, _, ok := plugin.ParseFullName(state.FullName); ok && name == pluginArg {
 				// Check if we already have this match
 				found := false
 				for _, m := range matches {
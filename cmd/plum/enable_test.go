@@ -2,8 +2,12 @@ package main
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/settings"
 )
 
 func TestEnableCommandRegistered(t *testing.T) {
@@ -54,6 +58,14 @@ func TestEnableCommandFlags(t *testing.T) {
 	if projectFlag == nil {
 		t.Error("enable command should have --project flag")
 	}
+
+	// Check all flag exists
+	allFlag := enableCmd.Flags().Lookup("all")
+	if allFlag == nil {
+		t.Error("enable command should have --all flag")
+	} else if allFlag.DefValue != "false" {
+		t.Errorf("--all default = %q, want %q", allFlag.DefValue, "false")
+	}
 }
 
 func TestEnableCommandHelp(t *testing.T) {
@@ -94,3 +106,55 @@ func TestEnableCommandRequiresArg(t *testing.T) {
 		t.Error("enableCmd.Args should not be nil")
 	}
 }
+
+func TestEnableCommand_ScopeFromProjectConfig(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	projectDir := t.TempDir()
+	t.Chdir(projectDir)
+	if err := os.WriteFile(filepath.Join(projectDir, ".plum.json"), []byte(`{"defaultScope": "project"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	scopeFlag := enableCmd.Flags().Lookup("scope")
+	enableProject = projectDir
+	defer func() {
+		enableProject = ""
+		enableScope = "user"
+		scopeFlag.Changed = false
+	}()
+
+	// Without --scope the project default applies
+	if _, err := captureStdout(t, func() error { return runEnable(enableCmd, []string{"a@market"}) }); err != nil {
+		t.Fatalf("runEnable failed: %v", err)
+	}
+	project, err := settings.LoadSettings(settings.ScopeProject, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !project.EnabledPlugins["a@market"] {
+		t.Errorf("plugin should be enabled in project scope, got %v", project.EnabledPlugins)
+	}
+
+	// An explicit --scope wins over .plum.json
+	if err := enableCmd.Flags().Set("scope", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := captureStdout(t, func() error { return runEnable(enableCmd, []string{"b@market"}) }); err != nil {
+		t.Fatalf("runEnable failed: %v", err)
+	}
+	user, err := settings.LoadSettings(settings.ScopeUser, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.EnabledPlugins["b@market"] {
+		t.Errorf("plugin should be enabled in user scope, got %v", user.EnabledPlugins)
+	}
+	project, err = settings.LoadSettings(settings.ScopeProject, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := project.EnabledPlugins["b@market"]; ok {
+		t.Errorf("explicit --scope=user should not touch project settings, got %v", project.EnabledPlugins)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+// parseScopeFlag parses a command's --scope value. When the flag wasn't
+// passed, the defaultScope from the nearest .plum.json at or above
+// projectPath (the command's --project, or the current directory) is used,
+// falling back to user.
+func parseScopeFlag(cmd *cobra.Command, value, projectPath string) (settings.Scope, error) {
+	if cmd.Flags().Changed("scope") {
+		return settings.ParseScope(value)
+	}
+	return defaultScope(projectPath)
+}
+
+// defaultScope returns the defaultScope configured for projectPath, or
+// ScopeUser when none is set
+func defaultScope(projectPath string) (settings.Scope, error) {
+	dir := projectPath
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return settings.ScopeUser, nil
+		}
+		dir = cwd
+	}
+
+	cfg, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil || cfg.DefaultScope == "" {
+		return settings.ScopeUser, nil
+	}
+
+	scope, err := settings.ParseScope(cfg.DefaultScope)
+	if err != nil {
+		return "", fmt.Errorf("invalid defaultScope %q in %s: %w", cfg.DefaultScope, config.ProjectConfigFile, err)
+	}
+	return scope, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+func TestDefaultScope(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(nested, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	// No .plum.json anywhere: user scope
+	if got, err := defaultScope(nested); err != nil || got != settings.ScopeUser {
+		t.Errorf("defaultScope() without config = %v, %v; want user", got, err)
+	}
+
+	configPath := filepath.Join(root, ".plum.json")
+	if err := os.WriteFile(configPath, []byte(`{"defaultScope": "project"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := defaultScope(nested); err != nil || got != settings.ScopeProject {
+		t.Errorf("defaultScope() with config = %v, %v; want project", got, err)
+	}
+
+	// An empty project path searches from the current directory
+	t.Chdir(nested)
+	if got, err := defaultScope(""); err != nil || got != settings.ScopeProject {
+		t.Errorf("defaultScope(\"\") = %v, %v; want project", got, err)
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"defaultScope": "everywhere"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := defaultScope(nested); !errors.Is(err, settings.ErrInvalidScope) {
+		t.Errorf("expected ErrInvalidScope for a bad defaultScope, got %v", err)
+	}
+}
+
+func TestParseScopeFlag_UsesProjectPath(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, ".plum.json"), []byte(`{"defaultScope": "local"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The current directory has no .plum.json; --project decides
+	t.Chdir(t.TempDir())
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("scope", "user", "")
+		return cmd
+	}
+
+	if got, err := parseScopeFlag(newCmd(), "user", projectDir); err != nil || got != settings.ScopeLocal {
+		t.Errorf("parseScopeFlag() = %v, %v; want local from --project", got, err)
+	}
+	if got, err := parseScopeFlag(newCmd(), "user", ""); err != nil || got != settings.ScopeUser {
+		t.Errorf("parseScopeFlag() without --project = %v, %v; want user", got, err)
+	}
+
+	// An explicit --scope wins over .plum.json
+	cmd := newCmd()
+	if err := cmd.Flags().Set("scope", "project"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := parseScopeFlag(cmd, "project", projectDir); err != nil || got != settings.ScopeProject {
+		t.Errorf("parseScopeFlag() with --scope = %v, %v; want project", got, err)
+	}
+}
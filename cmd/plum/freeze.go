@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Write installed plugins to a lockfile",
+	Long: `Write every plugin in the install registry to a lockfile.
+
+The lockfile is a JSON array of {name, marketplace, version, commit} entries
+that 'plum install --from' installs at the recorded versions, so teammates can
+reproduce the same plugin set. When the commit the plugin was downloaded from
+is known, it is recorded too and installs fetch exactly that commit.
+
+Examples:
+  plum freeze                  # Write plum.lock
+  plum freeze -o team.lock     # Write a different file
+  plum install --from plum.lock`,
+	Args: cobra.NoArgs,
+	RunE: runFreeze,
+}
+
+var freezeOutput string
+
+func init() {
+	rootCmd.AddCommand(freezeCmd)
+
+	freezeCmd.Flags().StringVarP(&freezeOutput, "output", "o", "plum.lock", "Lockfile to write")
+}
+
+// LockEntry is one plugin in a plum lockfile
+type LockEntry struct {
+	Name        string `json:"name"`
+	Marketplace string `json:"marketplace"`
+	Version     string `json:"version"`
+	Commit      string `json:"commit,omitempty"` // Commit the files were downloaded from, if known
+}
+
+// FullName returns the entry as plugin@marketplace
+func (e LockEntry) FullName() string {
+	return e.Name + "@" + e.Marketplace
+}
+
+func runFreeze(cmd *cobra.Command, args []string) error {
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to load install registry: %w", err)
+	}
+
+	entries := lockEntries(installed)
+	if err := writeLockfile(freezeOutput, entries); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d plugin(s) to %s\n", len(entries), freezeOutput)
+	return nil
+}
+
+// lockEntries lists each registered plugin once, sorted by full name. Scopes
+// share one cache, so the first install's version and commit are used.
+func lockEntries(installed *config.InstalledPluginsV2) []LockEntry {
+	entries := make([]LockEntry, 0, len(installed.Plugins))
+	for fullName, installs := range installed.Plugins {
+		name, marketplace, ok := plugin.ParseFullName(fullName)
+		if !ok || len(installs) == 0 {
+			continue
+		}
+		entries = append(entries, LockEntry{
+			Name:        name,
+			Marketplace: marketplace,
+			Version:     installs[0].Version,
+			Commit:      installs[0].GitCommitSha,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FullName() < entries[j].FullName()
+	})
+	return entries
+}
+
+func writeLockfile(path string, entries []LockEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	// #nosec G306 -- Lockfiles are meant to be committed and shared
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// readLockfile loads and validates a lockfile written by freeze
+func readLockfile(path string) ([]LockEntry, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is given by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var entries []LockEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid lockfile %s: %w", path, err)
+	}
+	for i, e := range entries {
+		if e.Name == "" || e.Marketplace == "" {
+			return nil, fmt.Errorf("invalid lockfile %s: entry %d needs a name and marketplace", path, i+1)
+		}
+		if e.Commit != "" && !marketplace.IsCommitSHA(e.Commit) {
+			return nil, fmt.Errorf("invalid lockfile %s: entry %d has an invalid commit %q", path, i+1, e.Commit)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+)
+
+func TestFreezeCommandRegistered(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"freeze"})
+	if err != nil || cmd != freezeCmd {
+		t.Fatalf("freeze command not registered (err = %v)", err)
+	}
+	if installCmd.Flags().Lookup("from") == nil {
+		t.Error("install command should have --from flag")
+	}
+}
+
+func TestFreezeAndInstallFromLockfile(t *testing.T) {
+	configDir := setupInstallFixture(t)
+
+	installScope = "user"
+	installProject = t.TempDir()
+	defer func() { installProject = ""; installFrom = "" }()
+
+	if _, err := captureStdout(t, func() error {
+		return runInstall(installCmd, []string{"alpha@claude-code-marketplace", "beta@claude-code-marketplace"})
+	}); err != nil {
+		t.Fatalf("runInstall failed: %v", err)
+	}
+	want := registryVersions(t)
+
+	lockPath := filepath.Join(t.TempDir(), "plum.lock")
+	freezeOutput = lockPath
+	defer func() { freezeOutput = "plum.lock" }()
+	output, err := captureStdout(t, func() error { return runFreeze(freezeCmd, nil) })
+	if err != nil {
+		t.Fatalf("runFreeze failed: %v", err)
+	}
+	if !strings.Contains(output, "Wrote 2 plugin(s)") {
+		t.Errorf("unexpected freeze output: %s", output)
+	}
+
+	entries, err := readLockfile(lockPath)
+	if err != nil {
+		t.Fatalf("readLockfile failed: %v", err)
+	}
+	wantEntries := []LockEntry{
+		{Name: "alpha", Marketplace: "claude-code-marketplace", Version: "1.0.0", Commit: fixtureCommit},
+		{Name: "beta", Marketplace: "claude-code-marketplace", Version: "2.0.0", Commit: fixtureCommit},
+	}
+	if len(entries) != len(wantEntries) {
+		t.Fatalf("lockfile entries = %+v, want %+v", entries, wantEntries)
+	}
+	for i := range wantEntries {
+		if entries[i] != wantEntries[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], wantEntries[i])
+		}
+	}
+
+	// Wipe everything install created
+	for _, path := range []string{
+		filepath.Join(configDir, "settings.json"),
+		filepath.Join(configDir, "plugins", "installed_plugins.json"),
+		filepath.Join(configDir, "plugins", "cache"),
+	} {
+		if err := os.RemoveAll(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	installFrom = lockPath
+	output, err = captureStdout(t, func() error { return runInstall(installCmd, nil) })
+	if err != nil {
+		t.Fatalf("install --from failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "Installed 2 plugins") {
+		t.Errorf("unexpected install output: %s", output)
+	}
+
+	got := registryVersions(t)
+	if len(got) != len(want) {
+		t.Fatalf("registry after install --from = %v, want %v", got, want)
+	}
+	for name, version := range want {
+		if got[name] != version {
+			t.Errorf("%s version = %q, want %q", name, got[name], version)
+		}
+	}
+
+	// Lockfile installs download from the recorded commit, not a guessed tag
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, installs := range installed.Plugins {
+		if installs[0].GitCommitSha != fixtureCommit || installs[0].Ref != fixtureCommit {
+			t.Errorf("%s commit = %q, ref = %q, want both %q", name, installs[0].GitCommitSha, installs[0].Ref, fixtureCommit)
+		}
+	}
+}
+
+func TestInstallFromLockfile_CommitOfOlderVersion(t *testing.T) {
+	setupInstallFixture(t)
+
+	// 0.9.0 is no longer listed in the marketplace and has no release tag, but
+	// its commit can still be fetched
+	lockPath := filepath.Join(t.TempDir(), "plum.lock")
+	if err := writeLockfile(lockPath, []LockEntry{
+		{Name: "alpha", Marketplace: "claude-code-marketplace", Version: "0.9.0", Commit: fixtureCommit},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	installScope = "user"
+	installProject = t.TempDir()
+	installFrom = lockPath
+	defer func() { installProject = ""; installFrom = "" }()
+
+	output, err := captureStdout(t, func() error { return runInstall(installCmd, nil) })
+	if err != nil {
+		t.Fatalf("install --from failed: %v\n%s", err, output)
+	}
+	if v := registryVersions(t)["alpha@claude-code-marketplace"]; v != "0.9.0" {
+		t.Errorf("alpha version = %q, want 0.9.0", v)
+	}
+}
+
+func TestInstallFromLockfile_ContinuesPastFailures(t *testing.T) {
+	setupInstallFixture(t)
+
+	lockPath := filepath.Join(t.TempDir(), "plum.lock")
+	if err := writeLockfile(lockPath, []LockEntry{
+		{Name: "missing", Marketplace: "claude-code-marketplace", Version: "1.0.0"},
+		{Name: "alpha", Marketplace: "claude-code-marketplace", Version: "1.0.0"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	installScope = "user"
+	installProject = t.TempDir()
+	installFrom = lockPath
+	defer func() { installProject = ""; installFrom = "" }()
+
+	output, err := captureStdout(t, func() error { return runInstall(installCmd, nil) })
+	if err == nil || !strings.Contains(err.Error(), "missing@claude-code-marketplace@1.0.0") {
+		t.Errorf("expected failure naming the missing plugin, got %v", err)
+	}
+	if !strings.Contains(output, "Installed 1 plugin (1 failed)") {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if !strings.Contains(output, "✗ plugin missing@claude-code-marketplace@1.0.0") {
+		t.Errorf("output should list the failed plugin: %s", output)
+	}
+	if v := registryVersions(t)["alpha@claude-code-marketplace"]; v != "1.0.0" {
+		t.Errorf("alpha should still be installed after an earlier failure, got version %q", v)
+	}
+
+	// Installing again skips alpha; --ignore-errors turns the failure into a
+	// successful exit
+	installIgnoreErrors = true
+	defer func() { installIgnoreErrors = false }()
+	output, err = captureStdout(t, func() error { return runInstall(installCmd, nil) })
+	if err != nil {
+		t.Errorf("expected no error with --ignore-errors, got %v", err)
+	}
+	if !strings.Contains(output, "Installed 0 plugins (1 failed, 1 skipped)") {
+		t.Errorf("unexpected output: %s", output)
+	}
+}
+
+func TestInstallIgnoreErrorsRequiresFrom(t *testing.T) {
+	installIgnoreErrors = true
+	defer func() { installIgnoreErrors = false }()
+
+	if err := installCmd.Args(installCmd, []string{"alpha"}); err == nil {
+		t.Error("expected --ignore-errors without --from to be rejected")
+	}
+}
+
+func TestReadLockfile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"not json":        `{`,
+		"missing name":    `[{"marketplace": "mkt", "version": "1.0.0"}]`,
+		"missing market":  `[{"name": "alpha"}]`,
+		"bad commit":      `[{"name": "alpha", "marketplace": "mkt", "commit": "main"}]`,
+		"object not list": `{"name": "alpha", "marketplace": "mkt"}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-")+".lock")
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := readLockfile(path); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// registryVersions maps each registered plugin to its first install's version
+func registryVersions(t *testing.T) map[string]string {
+	t.Helper()
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := make(map[string]string)
+	for name, installs := range installed.Plugins {
+		if len(installs) > 0 {
+			versions[name] = installs[0].Version
+		}
+	}
+	return versions
+}
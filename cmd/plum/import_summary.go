@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// importOutcome is the result of applying a single import item
+type importOutcome int
+
+const (
+	importApplied importOutcome = iota // Item was added/installed
+	importSkipped                      // Item was already present
+	importFailed                       // Item could not be applied
+)
+
+// Import item kinds
+const (
+	importKindMarketplace = "marketplace"
+	importKindPlugin      = "plugin"
+)
+
+// importItemResult records what happened to one marketplace or plugin
+type importItemResult struct {
+	Kind    string
+	Name    string
+	Outcome importOutcome
+	Err     error
+}
+
+// importSummary collects per-item outcomes during an import run
+type importSummary struct {
+	Results []importItemResult
+}
+
+// importPlan lists what an import should apply, in order
+type importPlan struct {
+	Marketplaces []importMarketplace
+	Plugins      []string // plugin@marketplace
+}
+
+// importMarketplace is a marketplace entry in an import plan
+type importMarketplace struct {
+	Name string
+	Repo string
+}
+
+// marketplaceAdder adds a marketplace, reporting skipped=true if it already exists
+type marketplaceAdder func(m importMarketplace) (skipped bool, err error)
+
+// pluginInstaller installs a plugin, reporting skipped=true if it is already installed
+type pluginInstaller func(fullName string) (skipped bool, err error)
+
+// applyImport applies plan using the given adder and installer. Marketplaces
+// are added first so plugins can resolve against them. Every item is attempted;
+// failures are recorded rather than aborting the run.
+func applyImport(plan importPlan, addMarketplace marketplaceAdder, install pluginInstaller) *importSummary {
+	summary := &importSummary{}
+
+	for _, m := range plan.Marketplaces {
+		skipped, err := addMarketplace(m)
+		summary.record(importKindMarketplace, m.Name, skipped, err)
+	}
+
+	for _, fullName := range plan.Plugins {
+		skipped, err := install(fullName)
+		summary.record(importKindPlugin, fullName, skipped, err)
+	}
+
+	return summary
+}
+
+// record stores the outcome for one item
+func (s *importSummary) record(kind, name string, skipped bool, err error) {
+	outcome := importApplied
+	switch {
+	case err != nil:
+		outcome = importFailed
+	case skipped:
+		outcome = importSkipped
+	}
+	s.Results = append(s.Results, importItemResult{Kind: kind, Name: name, Outcome: outcome, Err: err})
+}
+
+// tally counts outcomes for one item kind
+func (s *importSummary) tally(kind string) (applied, skipped, failed int) {
+	for _, r := range s.Results {
+		if r.Kind != kind {
+			continue
+		}
+		switch r.Outcome {
+		case importApplied:
+			applied++
+		case importSkipped:
+			skipped++
+		case importFailed:
+			failed++
+		}
+	}
+	return applied, skipped, failed
+}
+
+// Failed returns the results that failed, in the order they were applied
+func (s *importSummary) Failed() []importItemResult {
+	var failed []importItemResult
+	for _, r := range s.Results {
+		if r.Outcome == importFailed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// String returns the one-line end-of-run summary, e.g.
+// "Added 3 marketplaces (1 skipped, already present), installed 10 plugins (2 failed, 1 skipped)".
+// Kinds the run had no items of are left out, e.g. "Installed 10 plugins".
+func (s *importSummary) String() string {
+	var clauses []string
+
+	if s.has(importKindMarketplace) {
+		applied, skipped, failed := s.tally(importKindMarketplace)
+		var details []string
+		if failed > 0 {
+			details = append(details, fmt.Sprintf("%d failed", failed))
+		}
+		if skipped > 0 {
+			details = append(details, fmt.Sprintf("%d skipped, already present", skipped))
+		}
+		clauses = append(clauses, "added "+pluralize(applied, "marketplace")+formatDetails(details))
+	}
+
+	if s.has(importKindPlugin) || len(clauses) == 0 {
+		applied, skipped, failed := s.tally(importKindPlugin)
+		var details []string
+		if failed > 0 {
+			details = append(details, fmt.Sprintf("%d failed", failed))
+		}
+		if skipped > 0 {
+			details = append(details, fmt.Sprintf("%d skipped", skipped))
+		}
+		clauses = append(clauses, "installed "+pluralize(applied, "plugin")+formatDetails(details))
+	}
+
+	line := strings.Join(clauses, ", ")
+	return strings.ToUpper(line[:1]) + line[1:]
+}
+
+// has reports whether the run included any item of kind
+func (s *importSummary) has(kind string) bool {
+	for _, r := range s.Results {
+		if r.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Print writes failed items followed by the summary line
+func (s *importSummary) Print(w io.Writer) {
+	for _, r := range s.Failed() {
+		_, _ = fmt.Fprintf(w, "  ✗ %s %s: %v\n", r.Kind, r.Name, r.Err)
+	}
+	_, _ = fmt.Fprintln(w, s.String())
+}
+
+// Err returns an error naming the failed items when any failed, unless
+// ignoreErrors is set
+func (s *importSummary) Err(ignoreErrors bool) error {
+	failed := s.Failed()
+	if len(failed) == 0 || ignoreErrors {
+		return nil
+	}
+	names := make([]string, len(failed))
+	for i, r := range failed {
+		names[i] = r.Name
+	}
+	return fmt.Errorf("import finished with %d failed item(s): %s", len(failed), strings.Join(names, ", "))
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func formatDetails(details []string) string {
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
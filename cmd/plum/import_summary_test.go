@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyImport_MixedOutcomes(t *testing.T) {
+	plan := importPlan{
+		Marketplaces: []importMarketplace{
+			{Name: "alpha", Repo: "owner/alpha"},
+			{Name: "beta", Repo: "owner/beta"},
+			{Name: "gamma", Repo: "owner/gamma"},
+			{Name: "present", Repo: "owner/present"},
+		},
+		Plugins: []string{
+			"one@alpha",
+			"two@alpha",
+			"three@beta",
+			"broken@beta",
+			"missing@gamma",
+			"already@present",
+		},
+	}
+
+	addMarketplace := func(m importMarketplace) (bool, error) {
+		return m.Name == "present", nil
+	}
+	install := func(fullName string) (bool, error) {
+		switch fullName {
+		case "broken@beta", "missing@gamma":
+			return false, errors.New("download failed")
+		case "already@present":
+			return true, nil
+		}
+		return false, nil
+	}
+
+	summary := applyImport(plan, addMarketplace, install)
+
+	applied, skipped, failed := summary.tally(importKindMarketplace)
+	if applied != 3 || skipped != 1 || failed != 0 {
+		t.Errorf("marketplace tally = %d/%d/%d, want 3/1/0", applied, skipped, failed)
+	}
+
+	applied, skipped, failed = summary.tally(importKindPlugin)
+	if applied != 3 || skipped != 1 || failed != 2 {
+		t.Errorf("plugin tally = %d/%d/%d, want 3/1/2", applied, skipped, failed)
+	}
+
+	want := "Added 3 marketplaces (1 skipped, already present), installed 3 plugins (2 failed, 1 skipped)"
+	if got := summary.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if err := summary.Err(false); err == nil || !strings.Contains(err.Error(), "broken@beta, missing@gamma") {
+		t.Errorf("expected error naming the failed items, got %v", err)
+	}
+	if err := summary.Err(true); err != nil {
+		t.Errorf("expected no error with ignoreErrors, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	summary.Print(&buf)
+	out := buf.String()
+	for _, name := range []string{"broken@beta", "missing@gamma"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("expected failed item %s in output:\n%s", name, out)
+		}
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out), want) {
+		t.Errorf("expected summary line last, got:\n%s", out)
+	}
+}
+
+func TestImportSummary_AllApplied(t *testing.T) {
+	summary := applyImport(
+		importPlan{
+			Marketplaces: []importMarketplace{{Name: "alpha"}},
+			Plugins:      []string{"one@alpha"},
+		},
+		func(importMarketplace) (bool, error) { return false, nil },
+		func(string) (bool, error) { return false, nil },
+	)
+
+	want := "Added 1 marketplace, installed 1 plugin"
+	if got := summary.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if err := summary.Err(false); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestImportSummary_PluginsOnly(t *testing.T) {
+	summary := applyImport(
+		importPlan{Plugins: []string{"one@alpha", "two@alpha", "three@alpha"}},
+		nil,
+		func(fullName string) (bool, error) {
+			if fullName == "three@alpha" {
+				return true, nil
+			}
+			return false, nil
+		},
+	)
+
+	want := "Installed 2 plugins (1 skipped)"
+	if got := summary.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -62,48 +62,32 @@ type PluginInfo struct {
 	InstalledVersion string   `json:"installedVersion,omitempty"`
 	InstalledAt      string   `json:"installedAt,omitempty"`
 	IsLocal          bool     `json:"isLocal,omitempty"`
+	Enabled          bool     `json:"enabled"`
 	GitHubURL        string   `json:"githubUrl,omitempty"`
+	InstallCommand   string   `json:"installCommand"`
+
+	Plugin plugin.Plugin `json:"plugin"` // Marketplace entry as loaded
 }
 
 func runInfo(cmd *cobra.Command, args []string) error {
-	query := args[0]
-
-	// Parse plugin@marketplace format
-	pluginName := query
-	marketplaceFilter := ""
-	if idx := strings.LastIndex(query, "@"); idx > 0 {
-		pluginName = query[:idx]
-		marketplaceFilter = query[idx+1:]
-	}
-
-	// Load all plugins
-	plugins, err := config.LoadAllPlugins()
+	pluginName, marketplaceFilter, version, err := parsePluginArg(args[0])
 	if err != nil {
-		return fmt.Errorf("failed to load plugins: %w", err)
-	}
-
-	// Find matching plugin
-	var found *PluginInfo
-	for _, p := range plugins {
-		if p.Name == pluginName {
-			// If marketplace filter specified, must match
-			if marketplaceFilter != "" && p.Marketplace != marketplaceFilter {
-				continue
-			}
-			found = buildPluginInfo(p)
-			break
-		}
+		return err
 	}
 
-	if found == nil {
-		return fmt.Errorf("plugin '%s' not found", query)
+	// Resolve the same way install does, so ambiguous names fail the same way
+	result, err := findPluginInMarketplaces(pluginName, marketplaceFilter, version, false)
+	if err != nil {
+		return err
 	}
+	found := buildPluginInfo(result.Details)
 
 	// Get additional state from settings
 	fullName := found.Name + "@" + found.Marketplace
 	state, err := settings.GetPluginState(fullName, infoProject)
 	if err == nil && state != nil {
 		found.Scope = state.Scope.String()
+		found.Enabled = state.Enabled
 		if state.Enabled {
 			found.Status = "enabled"
 		} else {
@@ -145,7 +129,9 @@ func buildPluginInfo(p plugin.Plugin) *PluginInfo {
 		Tags:            p.Tags,
 		Installed:       p.Installed,
 		InstallPath:     p.InstallPath,
-		GitHubURL:       p.GitHubURL(),
+		GitHubURL:       p.SourceURL(),
+		InstallCommand:  p.InstallCommand(),
+		Plugin:          p,
 	}
 }
 
@@ -197,6 +183,8 @@ func outputInfoFormatted(info *PluginInfo) error {
 		fmt.Println("Installed:   No")
 	}
 
+	fmt.Printf("Install:     %s\n", info.InstallCommand)
+
 	if info.GitHubURL != "" {
 		fmt.Printf("\nSource:      %s\n", info.GitHubURL)
 	}
@@ -1,9 +1,16 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
 	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/settings"
 )
 
 func TestInfoCommand_Structure(t *testing.T) {
@@ -114,3 +121,110 @@ func TestPluginInfo_JSONFields(t *testing.T) {
 		t.Error("Installed field missing")
 	}
 }
+
+// useMarketplacePlugins makes marketplace lookups return plugins and isolates the
+// config directory
+func useMarketplacePlugins(t *testing.T, plugins []plugin.Plugin) string {
+	t.Helper()
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	original := loadMarketplacePlugins
+	loadMarketplacePlugins = func(config.LoadOptions) ([]plugin.Plugin, error) { return plugins, nil }
+	t.Cleanup(func() { loadMarketplacePlugins = original })
+
+	return configDir
+}
+
+func TestInfoCommand_Ambiguous(t *testing.T) {
+	useMarketplacePlugins(t, []plugin.Plugin{
+		{Name: "shared", Marketplace: "mkt-one", Version: "1.0.0"},
+		{Name: "shared", Marketplace: "mkt-two", Version: "1.1.0"},
+	})
+	infoJSON = false
+	infoProject = t.TempDir()
+	defer func() { infoProject = "" }()
+
+	_, err := captureStdout(t, func() error { return runInfo(infoCmd, []string{"shared"}) })
+	if err == nil {
+		t.Fatal("expected an error for a plugin in several marketplaces")
+	}
+	for _, want := range []string{"multiple marketplaces", "shared@mkt-one", "shared@mkt-two"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+
+	// Qualifying the name resolves it
+	output, err := captureStdout(t, func() error { return runInfo(infoCmd, []string{"shared@mkt-two"}) })
+	if err != nil {
+		t.Fatalf("runInfo failed: %v", err)
+	}
+	if !strings.Contains(output, "Marketplace: mkt-two") {
+		t.Errorf("expected mkt-two in output:\n%s", output)
+	}
+}
+
+func TestInfoCommand_SingleMatch(t *testing.T) {
+	configDir := useMarketplacePlugins(t, []plugin.Plugin{
+		{Name: "shared", Marketplace: "mkt-two", Version: "1.1.0"},
+		{
+			Name:        "only-mkt-one",
+			Marketplace: "mkt-one",
+			Version:     "2.1.0",
+			Description: "Just here",
+			Category:    "testing",
+			Keywords:    []string{"alpha", "beta"},
+			Author:      plugin.Author{Name: "Jane"},
+		},
+	})
+	settingsJSON := `{"enabledPlugins": {"only-mkt-one@mkt-one": true}}`
+	if err := os.WriteFile(filepath.Join(configDir, "settings.json"), []byte(settingsJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := install.Register("only-mkt-one@mkt-one", "/cache/only", "2.0.0", "", "", settings.ScopeUser, ""); err != nil {
+		t.Fatal(err)
+	}
+	infoProject = t.TempDir()
+	defer func() { infoProject = ""; infoJSON = false }()
+
+	infoJSON = false
+	output, err := captureStdout(t, func() error { return runInfo(infoCmd, []string{"only-mkt-one"}) })
+	if err != nil {
+		t.Fatalf("runInfo failed: %v", err)
+	}
+	for _, want := range []string{
+		"Name:        only-mkt-one",
+		"Version:     2.1.0",
+		"Author:      Jane",
+		"Marketplace: mkt-one",
+		"Category:    testing",
+		"Keywords:    alpha, beta",
+		"Installed:   Yes (user scope)",
+		"Status:      enabled",
+		"Install:     /plugin install only-mkt-one@mkt-one",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	infoJSON = true
+	output, err = captureStdout(t, func() error { return runInfo(infoCmd, []string{"only-mkt-one"}) })
+	if err != nil {
+		t.Fatalf("runInfo --json failed: %v", err)
+	}
+	var info PluginInfo
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	if info.Plugin.Name != "only-mkt-one" || info.Plugin.Version != "2.1.0" || info.Plugin.Category != "testing" {
+		t.Errorf("plugin struct not emitted: %+v", info.Plugin)
+	}
+	if !info.Installed || !info.Enabled || info.Scope != "user" || info.InstalledVersion != "2.0.0" {
+		t.Errorf("resolved state wrong: %+v", info)
+	}
+	if info.InstallCommand != "/plugin install only-mkt-one@mkt-one" {
+		t.Errorf("installCommand = %q", info.InstallCommand)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Scaffold new plum projects",
+	Long: `Scaffold new plum projects.
+
+Available subcommands:
+  marketplace   Create a new marketplace repository`,
+}
+
+var initMarketplaceCmd = &cobra.Command{
+	Use:   "marketplace <dir>",
+	Short: "Scaffold a new marketplace repository",
+	Long: `Create the files for a new plugin marketplace in <dir>.
+
+The scaffold contains:
+  .claude-plugin/marketplace.json              Marketplace manifest
+  plugins/<plugin>/.claude-plugin/plugin.json  Sample plugin manifest
+  plugins/<plugin>/commands/hello.md           Sample slash command
+  README.md                                    Publishing instructions
+
+The directory is created if it does not exist. Existing marketplaces are never
+overwritten. Owner name and email are prompted for when not passed as flags.
+
+Examples:
+  plum init marketplace ./my-marketplace
+  plum init marketplace ./my-marketplace --owner "Jane Doe" --email jane@example.com
+  plum init marketplace ./my-marketplace --name team-plugins --plugin lint-helper`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInitMarketplace,
+}
+
+var (
+	initMarketplaceName   string
+	initMarketplaceOwner  string
+	initMarketplaceEmail  string
+	initMarketplacePlugin string
+)
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.AddCommand(initMarketplaceCmd)
+
+	initMarketplaceCmd.Flags().StringVar(&initMarketplaceName, "name", "", "Marketplace name (default: directory name)")
+	initMarketplaceCmd.Flags().StringVar(&initMarketplaceOwner, "owner", "", "Owner name")
+	initMarketplaceCmd.Flags().StringVar(&initMarketplaceEmail, "email", "", "Owner email")
+	initMarketplaceCmd.Flags().StringVar(&initMarketplacePlugin, "plugin", "hello-plugin", "Name of the sample plugin")
+}
+
+// marketplaceScaffold describes the marketplace files to generate
+type marketplaceScaffold struct {
+	Dir        string
+	Name       string
+	OwnerName  string
+	OwnerEmail string
+	PluginName string
+}
+
+func runInitMarketplace(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+
+	name := initMarketplaceName
+	if name == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve directory: %w", err)
+		}
+		name = filepath.Base(abs)
+	}
+
+	owner := initMarketplaceOwner
+	email := initMarketplaceEmail
+	if owner == "" || email == "" {
+		reader := bufio.NewReader(cmd.InOrStdin())
+		if owner == "" {
+			owner = promptLine(reader, cmd.OutOrStdout(), "Owner name")
+		}
+		if email == "" {
+			email = promptLine(reader, cmd.OutOrStdout(), "Owner email")
+		}
+	}
+
+	scaffold := marketplaceScaffold{
+		Dir:        dir,
+		Name:       name,
+		OwnerName:  owner,
+		OwnerEmail: email,
+		PluginName: initMarketplacePlugin,
+	}
+	if err := writeMarketplaceScaffold(scaffold); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created marketplace '%s' in %s\n", name, dir)
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Println("  1. Push the directory to a GitHub repository")
+	fmt.Println("  2. plum marketplace add <owner>/<repo>")
+	fmt.Printf("  3. plum install %s@%s\n", scaffold.PluginName, name)
+
+	return nil
+}
+
+// promptLine asks for a single line of input, returning it trimmed
+func promptLine(reader *bufio.Reader, out io.Writer, label string) string {
+	_, _ = fmt.Fprintf(out, "%s: ", label)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// writeMarketplaceScaffold creates the marketplace manifest, sample plugin and README
+func writeMarketplaceScaffold(s marketplaceScaffold) error {
+	if err := install.ValidatePathComponent(s.Name, "marketplace name"); err != nil {
+		return err
+	}
+	if err := install.ValidatePathComponent(s.PluginName, "plugin name"); err != nil {
+		return err
+	}
+
+	manifestPath := filepath.Join(s.Dir, ".claude-plugin", "marketplace.json")
+	if _, err := os.Stat(manifestPath); err == nil {
+		return fmt.Errorf("marketplace already exists: %s", manifestPath)
+	}
+
+	pluginDir := filepath.Join(s.Dir, "plugins", s.PluginName)
+	sourcePath := "./plugins/" + s.PluginName
+
+	manifest := marketplace.MarketplaceManifest{
+		Name: s.Name,
+		Owner: marketplace.MarketplaceOwner{
+			Name:  s.OwnerName,
+			Email: s.OwnerEmail,
+		},
+		Metadata: marketplace.MarketplaceMetadata{
+			Description: fmt.Sprintf("Claude Code plugins from %s", s.Name),
+			Version:     "1.0.0",
+		},
+		Plugins: []marketplace.MarketplacePlugin{
+			{
+				Name:        s.PluginName,
+				Source:      sourcePath,
+				Description: "A sample plugin with a single slash command",
+				Version:     "0.1.0",
+				Author: marketplace.Author{
+					Name:  s.OwnerName,
+					Email: s.OwnerEmail,
+				},
+				Category: "productivity",
+				License:  "MIT",
+				Keywords: []string{"sample"},
+			},
+		},
+	}
+
+	pluginManifest := struct {
+		Name        string             `json:"name"`
+		Version     string             `json:"version"`
+		Description string             `json:"description"`
+		Author      marketplace.Author `json:"author"`
+		Commands    []string           `json:"commands"`
+	}{
+		Name:        s.PluginName,
+		Version:     "0.1.0",
+		Description: "A sample plugin with a single slash command",
+		Author:      marketplace.Author{Name: s.OwnerName, Email: s.OwnerEmail},
+		Commands:    []string{"commands/hello.md"},
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode marketplace.json: %w", err)
+	}
+	pluginJSON, err := json.MarshalIndent(pluginManifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin.json: %w", err)
+	}
+
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{manifestPath, append(manifestJSON, '\n')},
+		{filepath.Join(pluginDir, ".claude-plugin", "plugin.json"), append(pluginJSON, '\n')},
+		{filepath.Join(pluginDir, "commands", "hello.md"), []byte(sampleCommand)},
+		{filepath.Join(s.Dir, "README.md"), []byte(marketplaceReadme(s))},
+	}
+
+	for _, f := range files {
+		// #nosec G301 -- Marketplace files are meant to be published
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		// #nosec G306 -- Marketplace files are meant to be published
+		if err := os.WriteFile(f.path, f.content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+	}
+
+	return nil
+}
+
+const sampleCommand = `---
+description: Say hello from the sample plugin
+---
+
+Greet the user and briefly describe what this plugin can do.
+`
+
+func marketplaceReadme(s marketplaceScaffold) string {
+	return fmt.Sprintf(`# %s
+
+A Claude Code plugin marketplace.
+
+## Layout
+
+- .claude-plugin/marketplace.json - lists the plugins in this marketplace
+- plugins/%s/ - a sample plugin; copy it to add more
+
+## Usage
+
+After pushing this repository to GitHub:
+
+    plum marketplace add <owner>/<repo>
+    plum install %s@%s
+`, s.Name, s.PluginName, s.PluginName, s.Name)
+}
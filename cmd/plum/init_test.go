@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+)
+
+func TestInitMarketplaceCommand_Structure(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"init", "marketplace"})
+	if err != nil {
+		t.Fatalf("init marketplace command not found: %v", err)
+	}
+
+	if cmd.Use != "marketplace <dir>" {
+		t.Errorf("expected Use 'marketplace <dir>', got %s", cmd.Use)
+	}
+
+	flags := []string{"name", "owner", "email", "plugin"}
+	for _, flag := range flags {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected flag --%s to exist", flag)
+		}
+	}
+}
+
+func TestWriteMarketplaceScaffold(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-marketplace")
+
+	err := writeMarketplaceScaffold(marketplaceScaffold{
+		Dir:        dir,
+		Name:       "my-marketplace",
+		OwnerName:  "Jane Doe",
+		OwnerEmail: "jane@example.com",
+		PluginName: "hello-plugin",
+	})
+	if err != nil {
+		t.Fatalf("writeMarketplaceScaffold failed: %v", err)
+	}
+
+	manifest, err := config.LoadMarketplaceManifest(dir)
+	if err != nil {
+		t.Fatalf("scaffolded marketplace.json does not parse: %v", err)
+	}
+
+	if manifest.Name != "my-marketplace" {
+		t.Errorf("expected name 'my-marketplace', got %s", manifest.Name)
+	}
+	if manifest.Owner.Name != "Jane Doe" || manifest.Owner.Email != "jane@example.com" {
+		t.Errorf("unexpected owner: %+v", manifest.Owner)
+	}
+	if len(manifest.Plugins) != 1 {
+		t.Fatalf("expected 1 plugin, got %d", len(manifest.Plugins))
+	}
+
+	p := manifest.Plugins[0]
+	if p.Name != "hello-plugin" || p.Source != "./plugins/hello-plugin" {
+		t.Errorf("unexpected plugin entry: %+v", p)
+	}
+	if !p.Installable() {
+		t.Errorf("scaffolded plugin should be installable: %s", p.InstallabilityReason())
+	}
+
+	// The plugin.json must exist where the installer looks for it
+	pluginDir := filepath.Join(dir, strings.TrimPrefix(p.Source, "./"))
+	if err := validatePluginJSON(filepath.Join(pluginDir, ".claude-plugin", "plugin.json")); err != nil {
+		t.Errorf("scaffolded plugin.json invalid: %v", err)
+	}
+
+	// Every command listed in plugin.json must exist
+	data, err := os.ReadFile(filepath.Join(pluginDir, ".claude-plugin", "plugin.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pluginManifest struct {
+		Commands []string `json:"commands"`
+	}
+	if err := json.Unmarshal(data, &pluginManifest); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range pluginManifest.Commands {
+		if _, err := os.Stat(filepath.Join(pluginDir, c)); err != nil {
+			t.Errorf("command %s listed but missing: %v", c, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); err != nil {
+		t.Errorf("README.md not created: %v", err)
+	}
+}
+
+func TestWriteMarketplaceScaffold_RefusesOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	s := marketplaceScaffold{Dir: dir, Name: "market", PluginName: "hello-plugin"}
+
+	if err := writeMarketplaceScaffold(s); err != nil {
+		t.Fatalf("first scaffold failed: %v", err)
+	}
+	if err := writeMarketplaceScaffold(s); err == nil {
+		t.Error("expected error when marketplace already exists")
+	}
+}
+
+func TestWriteMarketplaceScaffold_InvalidNames(t *testing.T) {
+	tests := []marketplaceScaffold{
+		{Dir: t.TempDir(), Name: "../escape", PluginName: "ok"},
+		{Dir: t.TempDir(), Name: "market", PluginName: "a/b"},
+		{Dir: t.TempDir(), Name: "", PluginName: "ok"},
+	}
+
+	for _, s := range tests {
+		if err := writeMarketplaceScaffold(s); err == nil {
+			t.Errorf("expected error for name=%q plugin=%q", s.Name, s.PluginName)
+		}
+	}
+}
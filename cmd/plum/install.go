@@ -2,73 +2,17 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/itsdevcoffee/plum/internal/config"
-	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/settings"
 	"github.com/spf13/cobra"
 )
 
-// validatePathComponent checks if a path component is safe (no path traversal)
-func validatePathComponent(name, componentType string) error {
-	if name == "" {
-		return fmt.Errorf("%s cannot be empty", componentType)
-	}
-	if strings.Contains(name, "..") {
-		return fmt.Errorf("%s contains invalid path traversal: %s", componentType, name)
-	}
-	if strings.ContainsAny(name, "/\\") {
-		return fmt.Errorf("%s contains invalid path separator: %s", componentType, name)
-	}
-	if name == "." {
-		return fmt.Errorf("%s cannot be current directory", componentType)
-	}
-	return nil
-}
-
-// validatePluginFilePath validates a file path from plugin manifest is safe
-// Returns cleaned path relative to cacheDir, or error if path escapes
-func validatePluginFilePath(filePath, cacheDir string) (string, error) {
-	// Reject absolute paths
-	if filepath.IsAbs(filePath) {
-		return "", fmt.Errorf("absolute paths not allowed: %s", filePath)
-	}
-
-	// Reject path traversal attempts
-	if strings.Contains(filePath, "..") {
-		return "", fmt.Errorf("path traversal not allowed: %s", filePath)
-	}
-
-	// Clean the path
-	cleanPath := filepath.Clean(filePath)
-
-	// Construct full path and verify it's under cacheDir
-	fullPath := filepath.Join(cacheDir, cleanPath)
-	absCache, err := filepath.Abs(cacheDir)
-	if err != nil {
-		return "", err
-	}
-	absPath, err := filepath.Abs(fullPath)
-	if err != nil {
-		return "", err
-	}
-
-	// Ensure the path is under the cache directory
-	if !strings.HasPrefix(absPath, absCache+string(filepath.Separator)) && absPath != absCache {
-		return "", fmt.Errorf("path escapes cache directory: %s", filePath)
-	}
-
-	return fullPath, nil
-}
-
 var installCmd = &cobra.Command{
 	Use:   "install <plugin>",
 	Short: "Install a plugin",
@@ -77,21 +21,61 @@ var installCmd = &cobra.Command{
 The plugin can be specified as:
   - plugin-name (searches all known marketplaces)
   - plugin-name@marketplace (specific marketplace)
+  - plugin-name@marketplace@version (pinned version)
+
+Plugin names may contain '@'. The last '@' segment is read as a version only
+when it starts with a digit (optionally after a "v"); otherwise it is the
+marketplace.
+
+A pinned version must match the version listed in the marketplace manifest.
+Its files are downloaded from the matching git tag (v<version> or <version>)
+instead of the default branch, and the tag is recorded as the install's ref.
 
 Installation downloads plugin files to the Claude Code cache and enables
-the plugin in the specified scope.
+the plugin in the specified scope. Without --scope, the "defaultScope" from
+the nearest .plum.json (searched upward from --project, or the current
+directory) is used, falling back to user.
+
+Downloads are verified against SHA-256 checksums when the plugin declares
+them: the marketplace entry's "sha256" covers plugin.json, and plugin.json's
+"sha256" map (path -> hash) covers the files it lists. A plugin.json mismatch
+aborts the install; other mismatched files are skipped with a warning. Use
+--no-verify to skip checksum verification.
 
 Examples:
   plum install ralph-wiggum
   plum install ralph-wiggum@claude-code-plugins
-  plum install memory --scope=project`,
-	Args: cobra.MinimumNArgs(1),
+  plum install memory --scope=project
+  plum install memory --no-verify
+  plum install memory ralph-wiggum --dry-run
+  plum install memory --no-cache
+  plum install ralph-wiggum@claude-code-plugins@1.0.0
+  plum install --from plum.lock
+  plum install --from plum.lock --ignore-errors`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if installIgnoreErrors && installFrom == "" {
+			return fmt.Errorf("--ignore-errors only applies with --from")
+		}
+		if installFrom != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("plugin arguments can't be combined with --from")
+			}
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runInstall,
 }
 
 var (
-	installScope   string
-	installProject string
+	installScope    string
+	installProject  string
+	installNoVerify bool
+	installDryRun   bool
+	installNoCache  bool
+	installFrom     string
+
+	installIgnoreErrors bool
 )
 
 func init() {
@@ -99,11 +83,16 @@ func init() {
 
 	installCmd.Flags().StringVarP(&installScope, "scope", "s", "user", "Installation scope (user, project, local)")
 	installCmd.Flags().StringVar(&installProject, "project", "", "Project path (default: current directory)")
+	installCmd.Flags().BoolVar(&installNoVerify, "no-verify", false, "Skip SHA-256 checksum verification of downloaded files")
+	installCmd.Flags().BoolVar(&installDryRun, "dry-run", false, "Show what would be installed without changing anything")
+	installCmd.Flags().BoolVar(&installNoCache, "no-cache", false, "Fetch marketplace data fresh from GitHub instead of using the cache")
+	installCmd.Flags().StringVar(&installFrom, "from", "", "Install every plugin in a lockfile written by 'plum freeze'")
+	installCmd.Flags().BoolVar(&installIgnoreErrors, "ignore-errors", false, "With --from, exit successfully even if some plugins fail to install")
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
 	// Parse scope
-	scope, err := settings.ParseScope(installScope)
+	scope, err := parseScopeFlag(cmd, installScope, installProject)
 	if err != nil {
 		return err
 	}
@@ -112,10 +101,19 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	if !scope.IsWritable() {
 		return fmt.Errorf("cannot write to %s scope (read-only)", scope)
 	}
+	if !installDryRun {
+		if err := install.CheckWritable(scope, installProject); err != nil {
+			return err
+		}
+	}
+
+	if installFrom != "" {
+		return installFromLockfile(cmd, installFrom, scope)
+	}
 
 	// Install each plugin
 	for _, pluginArg := range args {
-		if err := installPlugin(pluginArg, scope, installProject); err != nil {
+		if _, err := installPlugin(pluginArg, scope, installProject); err != nil {
 			return fmt.Errorf("failed to install %s: %w", pluginArg, err)
 		}
 	}
@@ -123,22 +121,97 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func installPlugin(pluginArg string, scope settings.Scope, projectPath string) error {
-	// Parse plugin name and marketplace filter
-	pluginName := pluginArg
-	marketplaceFilter := ""
-	if idx := strings.LastIndex(pluginArg, "@"); idx > 0 {
-		pluginName = pluginArg[:idx]
-		marketplaceFilter = pluginArg[idx+1:]
+// installFromLockfile installs each lockfile entry at its recorded version,
+// continuing past failures and summarizing the outcomes at the end
+func installFromLockfile(cmd *cobra.Command, path string, scope settings.Scope) error {
+	entries, err := readLockfile(path)
+	if err != nil {
+		return err
+	}
+
+	var plan importPlan
+	commits := make(map[string]string)
+	for _, e := range entries {
+		pluginArg := e.FullName()
+		if e.Version != "" {
+			pluginArg += "@" + e.Version
+		}
+		plan.Plugins = append(plan.Plugins, pluginArg)
+		commits[pluginArg] = e.Commit
+	}
+
+	summary := applyImport(plan, nil, func(pluginArg string) (bool, error) {
+		return installPluginAt(pluginArg, commits[pluginArg], scope, installProject)
+	})
+
+	fmt.Println()
+	summary.Print(cmd.OutOrStdout())
+	return summary.Err(installIgnoreErrors)
+}
+
+// parsePluginArg splits "name[@marketplace[@version]]" into its parts.
+// Plugin names may contain '@', so the last segment is only a version when it
+// looks like one (a digit, optionally after a "v"); the rest is then split at
+// its last '@' like any full name. A marketplace that also looks like a
+// version is ambiguous and rejected.
+func parsePluginArg(pluginArg string) (name, marketplaceFilter, version string, err error) {
+	idx := strings.LastIndex(pluginArg, "@")
+	if idx <= 0 {
+		return pluginArg, "", "", nil
+	}
+
+	rest := pluginArg
+	if last := pluginArg[idx+1:]; looksLikeVersion(last) {
+		rest, version = pluginArg[:idx], last
+	}
+	name, marketplaceFilter, ok := plugin.ParseFullName(rest)
+	if !ok || (version != "" && looksLikeVersion(marketplaceFilter)) {
+		return "", "", "", fmt.Errorf("invalid plugin format: %s (expected: plugin-name@marketplace[@version])", pluginArg)
+	}
+	return name, marketplaceFilter, version, nil
+}
+
+// looksLikeVersion reports whether s starts like a version: "1.2.0" or "v1.2.0"
+func looksLikeVersion(s string) bool {
+	s = strings.TrimPrefix(s, "v")
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// installPlugin installs one plugin argument. skipped reports that it was
+// already installed in scope, so nothing was done.
+func installPlugin(pluginArg string, scope settings.Scope, projectPath string) (skipped bool, err error) {
+	return installPluginAt(pluginArg, "", scope, projectPath)
+}
+
+// installPluginAt is installPlugin downloading from commit when it's set.
+// The marketplace may list a newer version by now, so the version in
+// pluginArg is recorded rather than looked up.
+func installPluginAt(pluginArg, commit string, scope settings.Scope, projectPath string) (skipped bool, err error) {
+	// Parse plugin name, marketplace filter and pinned version
+	pluginName, marketplaceFilter, version, err := parsePluginArg(pluginArg)
+	if err != nil {
+		return false, err
+	}
+
+	lookupVersion := version
+	if commit != "" {
+		lookupVersion = ""
 	}
 
 	// Find the plugin in marketplaces
-	pluginInfo, err := findPluginInMarketplaces(pluginName, marketplaceFilter)
+	pluginInfo, err := findPluginInMarketplaces(pluginName, marketplaceFilter, lookupVersion, installNoCache)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if commit != "" {
+		pluginInfo.Commit = commit
+		pluginInfo.Pinned = true
+		if version != "" {
+			pluginInfo.Version = version
+		}
 	}
 
-	fullName := pluginInfo.Name + "@" + pluginInfo.Marketplace
+	fullName := pluginInfo.FullName()
 
 	// Check if plugin is installable via plum
 	if !pluginInfo.Installable {
@@ -155,7 +228,7 @@ func installPlugin(pluginArg string, scope settings.Scope, projectPath string) e
 			fmt.Println("This plugin requires a different installation method.")
 			fmt.Println("Check the plugin's homepage for installation instructions.")
 		}
-		return fmt.Errorf("plugin not installable via plum")
+		return false, install.ErrNotInstallable
 	}
 
 	// Check if already installed in the requested scope
@@ -163,82 +236,87 @@ func installPlugin(pluginArg string, scope settings.Scope, projectPath string) e
 	if err == nil {
 		if _, exists := scopeSettings.EnabledPlugins[fullName]; exists {
 			fmt.Printf("%s is already installed in %s scope\n", fullName, scope)
-			return nil
+			return true, nil
 		}
 	}
 
+	if installDryRun {
+		cacheDir, err := install.CacheDir(pluginInfo.Marketplace, pluginInfo.Name)
+		if err != nil {
+			return false, fmt.Errorf("failed to get cache directory: %w", err)
+		}
+		return false, printInstallPlan(pluginInfo, cacheDir, scope)
+	}
+
 	fmt.Printf("Installing %s...\n", fullName)
 
-	// Get cache directory
-	cacheDir, err := pluginCacheDir(pluginInfo.Marketplace, pluginInfo.Name)
+	result, err := install.Install(context.Background(), pluginInfo, install.Options{
+		Scope:       scope,
+		ProjectPath: projectPath,
+		NoVerify:    installNoVerify,
+		Warnings:    os.Stderr,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get cache directory: %w", err)
+		return false, err
 	}
-
-	// Check if cache already exists with valid plugin.json
-	// This allows installation to succeed even if remote download fails
-	cacheValid := isValidPluginCache(cacheDir)
-
-	// Try to download plugin files to cache (skip if cache is valid)
-	if !cacheValid {
-		if err := downloadPluginToCache(pluginInfo, cacheDir); err != nil {
-			return fmt.Errorf("failed to download plugin: %w", err)
-		}
-	} else {
+	if result.FromCache {
 		fmt.Println("Using cached plugin files")
 	}
 
-	// Register in installed_plugins_v2.json
-	if err := registerInstalledPlugin(fullName, cacheDir, pluginInfo.Version, scope, projectPath); err != nil {
-		return fmt.Errorf("failed to register plugin: %w", err)
+	fmt.Printf("Installed %s (v%s) in %s scope\n", fullName, pluginInfo.Version, scope)
+	return false, nil
+}
+
+// printInstallPlan prints what installPlugin would do without downloading,
+// registering, or enabling anything
+func printInstallPlan(pluginInfo *install.Target, cacheDir string, scope settings.Scope) error {
+	fmt.Printf("Would install %s@%s in %s scope\n", pluginInfo.Name, pluginInfo.Marketplace, scope)
+	fmt.Printf("  Marketplace: %s", pluginInfo.Marketplace)
+	if pluginInfo.MarketplaceRepo != "" {
+		fmt.Printf(" (%s)", pluginInfo.MarketplaceRepo)
 	}
+	fmt.Println()
+	fmt.Printf("  Version:     %s\n", pluginInfo.Version)
+	fmt.Printf("  Cache:       %s\n", cacheDir)
 
-	// Enable in settings.json
-	if err := settings.SetPluginEnabled(fullName, true, scope, projectPath); err != nil {
-		return fmt.Errorf("failed to enable plugin: %w", err)
+	if install.IsValidCache(cacheDir) && !pluginInfo.Pinned {
+		fmt.Println("  Files:       none (using cached plugin files)")
+		return nil
 	}
 
-	fmt.Printf("Installed %s (v%s) in %s scope\n", fullName, pluginInfo.Version, scope)
+	ref, files, err := install.PlanDownload(pluginInfo)
+	if err != nil {
+		return fmt.Errorf("failed to resolve plugin files: %w", err)
+	}
+	fmt.Printf("  Ref:         %s\n", ref)
+	fmt.Printf("  Files (%d):\n", len(files))
+	for _, f := range files {
+		fmt.Printf("    %s\n", f)
+	}
 	return nil
 }
 
-// pluginSearchResult holds plugin info needed for installation
-type pluginSearchResult struct {
-	Name                 string
-	Marketplace          string
-	MarketplaceRepo      string
-	Version              string
-	Source               string // Path within marketplace
-	Installable          bool   // Whether plum can install this plugin
-	InstallabilityReason string // Human-readable reason if not installable
-	IsIncomplete         bool   // True if plugin is missing required files
-}
+// loadMarketplacePlugins loads every marketplace plugin (variable for testing)
+var loadMarketplacePlugins = config.LoadAllPluginsWithOptions
 
-// findPluginInMarketplaces searches for a plugin across all known marketplaces
-func findPluginInMarketplaces(pluginName, marketplaceFilter string) (*pluginSearchResult, error) {
+// findPluginInMarketplaces searches for a plugin across all known marketplaces.
+// A non-empty version pins the install and must match the manifest version.
+// noCache fetches marketplace manifests fresh instead of using plum's cache.
+func findPluginInMarketplaces(pluginName, marketplaceFilter, version string, noCache bool) (*install.Target, error) {
 	// Load all plugins
-	plugins, err := config.LoadAllPlugins()
+	plugins, err := loadMarketplacePlugins(config.LoadOptions{NoCache: noCache})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load plugins: %w", err)
 	}
 
-	var matches []*pluginSearchResult
+	var matches []*install.Target
 	for _, p := range plugins {
 		if p.Name == pluginName {
 			// If marketplace filter specified, must match
 			if marketplaceFilter != "" && p.Marketplace != marketplaceFilter {
 				continue
 			}
-			matches = append(matches, &pluginSearchResult{
-				Name:                 p.Name,
-				Marketplace:          p.Marketplace,
-				MarketplaceRepo:      p.MarketplaceRepo,
-				Version:              p.Version,
-				Source:               p.Source,
-				Installable:          p.Installable(),
-				InstallabilityReason: p.InstallabilityReason(),
-				IsIncomplete:         p.IsIncomplete,
-			})
+			matches = append(matches, install.NewTarget(p))
 		}
 	}
 
@@ -246,306 +324,40 @@ func findPluginInMarketplaces(pluginName, marketplaceFilter string) (*pluginSear
 		return nil, fmt.Errorf("plugin '%s' not found in any marketplace", pluginName)
 	}
 
+	if version != "" {
+		return selectPinnedVersion(matches, pluginName, version)
+	}
+
 	if len(matches) > 1 && marketplaceFilter == "" {
 		var names []string
 		for _, m := range matches {
 			names = append(names, m.Name+"@"+m.Marketplace)
 		}
-		return nil, fmt.Errorf("plugin '%s' found in multiple marketplaces:\n  %s\nSpecify with: plum install %s@<marketplace>",
+		return nil, fmt.Errorf("plugin '%s' found in multiple marketplaces:\n  %s\nSpecify it as %s@<marketplace>",
 			pluginName, strings.Join(names, "\n  "), pluginName)
 	}
 
 	return matches[0], nil
 }
 
-// isValidPluginCache checks if a cache directory contains a valid plugin.json
-func isValidPluginCache(cacheDir string) bool {
-	pluginJSONPath := filepath.Join(cacheDir, ".claude-plugin", "plugin.json")
-	info, err := os.Stat(pluginJSONPath)
-	if err != nil {
-		return false
-	}
-	// Ensure it's a file with non-zero size
-	return !info.IsDir() && info.Size() > 0
-}
-
-// pluginCacheDir returns the path to cache a plugin
-// Path: ~/.claude/plugins/cache/<marketplace>/<plugin>/
-func pluginCacheDir(marketplaceName, pluginName string) (string, error) {
-	// Validate marketplace and plugin names to prevent path traversal
-	if err := validatePathComponent(marketplaceName, "marketplace name"); err != nil {
-		return "", err
-	}
-	if err := validatePathComponent(pluginName, "plugin name"); err != nil {
-		return "", err
-	}
-
-	pluginsDir, err := config.ClaudePluginsDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(pluginsDir, "cache", marketplaceName, pluginName), nil
-}
-
-// maxTotalDownloadSize is the maximum total download size per plugin (50 MB)
-const maxTotalDownloadSize = 50 << 20
-
-// downloadPluginToCache downloads plugin files from GitHub to the cache directory
-func downloadPluginToCache(plugin *pluginSearchResult, cacheDir string) error {
-	// Extract owner/repo from marketplace repo URL
-	source, err := marketplace.DeriveSource(plugin.MarketplaceRepo)
-	if err != nil {
-		return fmt.Errorf("failed to derive source from repo: %w", err)
-	}
-
-	// Normalize source path (remove leading ./ if present)
-	sourcePath := strings.TrimPrefix(plugin.Source, "./")
-	if sourcePath == "" || sourcePath == "." {
-		sourcePath = "plugins/" + plugin.Name
-	}
-
-	// Create cache directory
-	// #nosec G301 -- Plugin cache needs to be readable by Claude Code
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
-	}
-
-	// Track total download size to prevent DoS
-	var totalDownloaded int64
-
-	// downloadWithLimit downloads a file and tracks total size
-	downloadWithLimit := func(url string) ([]byte, error) {
-		data, err := downloadFile(url)
-		if err != nil {
-			return nil, err
-		}
-		totalDownloaded += int64(len(data))
-		if totalDownloaded > maxTotalDownloadSize {
-			return nil, fmt.Errorf("plugin download size exceeded limit (%d MB)", maxTotalDownloadSize>>20)
-		}
-		return data, nil
-	}
-
-	// Download plugin.json to verify the plugin structure
-	pluginJSONURL := fmt.Sprintf("%s/%s/%s/%s/.claude-plugin/plugin.json",
-		marketplace.GitHubRawBase, source, marketplace.DefaultBranch, sourcePath)
-
-	pluginJSON, err := downloadWithLimit(pluginJSONURL)
-	if err != nil {
-		return fmt.Errorf("failed to download plugin.json: %w", err)
-	}
-
-	// Create .claude-plugin directory in cache
-	claudePluginDir := filepath.Join(cacheDir, ".claude-plugin")
-	// #nosec G301 -- Plugin directory needs to be readable by Claude Code
-	if err := os.MkdirAll(claudePluginDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .claude-plugin directory: %w", err)
-	}
-
-	// Write plugin.json
-	pluginJSONPath := filepath.Join(claudePluginDir, "plugin.json")
-	// #nosec G306 -- Plugin files need to be readable by Claude Code
-	if err := os.WriteFile(pluginJSONPath, pluginJSON, 0644); err != nil {
-		return fmt.Errorf("failed to write plugin.json: %w", err)
-	}
-
-	// Parse plugin.json to get file list
-	var pluginManifest struct {
-		Name        string   `json:"name"`
-		Version     string   `json:"version"`
-		Description string   `json:"description"`
-		Commands    []string `json:"commands"`
-		Hooks       []string `json:"hooks"`
-	}
-	if err := json.Unmarshal(pluginJSON, &pluginManifest); err != nil {
-		// Not a fatal error - we have the plugin.json at least
-		fmt.Fprintf(os.Stderr, "Warning: failed to parse plugin.json: %v\n", err)
-	}
-
-	// Download commands (non-executable)
-	downloadPluginFiles(pluginManifest.Commands, "command", cacheDir, source, sourcePath, downloadWithLimit, 0644)
-
-	// Download hooks (executable)
-	downloadPluginFiles(pluginManifest.Hooks, "hook", cacheDir, source, sourcePath, downloadWithLimit, 0755)
-
-	return nil
-}
-
-// downloadPluginFiles downloads a list of plugin files to the cache directory.
-// fileType is used for warning messages (e.g., "command" or "hook").
-// perm specifies the file permissions (e.g., 0644 for commands, 0755 for hooks).
-func downloadPluginFiles(
-	files []string,
-	fileType string,
-	cacheDir string,
-	source string,
-	sourcePath string,
-	downloadWithLimit func(string) ([]byte, error),
-	perm os.FileMode,
-) {
-	for _, file := range files {
-		// Validate path to prevent path traversal attacks
-		filePath, err := validatePluginFilePath(file, cacheDir)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Warning: skipping invalid %s path %s: %v\n", fileType, file, err)
-			continue
-		}
-
-		fileURL := fmt.Sprintf("%s/%s/%s/%s/%s",
-			marketplace.GitHubRawBase, source, marketplace.DefaultBranch, sourcePath, file)
-
-		content, err := downloadWithLimit(fileURL)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to download %s %s: %v\n", fileType, file, err)
-			continue
-		}
-
-		fileDir := filepath.Dir(filePath)
-		// #nosec G301 -- Plugin directory needs to be readable by Claude Code
-		if err := os.MkdirAll(fileDir, 0755); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to create directory for %s: %v\n", file, err)
-			continue
-		}
-
-		// #nosec G306 -- Plugin files need appropriate permissions
-		if err := os.WriteFile(filePath, content, perm); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to write %s: %v\n", file, err)
-		}
-	}
-}
-
-// downloadFile downloads a file from a URL
-func downloadFile(url string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", "plum/0.4.0")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, url)
-	}
-
-	// Limit response size
-	limitedBody := io.LimitReader(resp.Body, 10<<20) // 10 MB limit
-	return io.ReadAll(limitedBody)
-}
-
-// registerInstalledPlugin adds the plugin to installed_plugins_v2.json
-func registerInstalledPlugin(fullName, installPath, version string, scope settings.Scope, projectPath string) error {
-	// Get registry path for locking
-	registryPath, err := config.InstalledPluginsPath()
-	if err != nil {
-		return err
-	}
+// selectPinnedVersion picks the match whose manifest version equals version,
+// or returns an error listing the versions that are available
+func selectPinnedVersion(matches []*install.Target, pluginName, version string) (*install.Target, error) {
+	want := strings.TrimPrefix(version, "v")
 
-	// Use file locking to prevent race conditions
-	return settings.WithLock(registryPath, func() error {
-		installed, err := config.LoadInstalledPlugins()
-		if err != nil {
-			return err
+	var available []string
+	for _, m := range matches {
+		if strings.TrimPrefix(m.Version, "v") == want {
+			m.Pinned = true
+			return m, nil
 		}
-
-		// Create install entry
-		install := config.PluginInstall{
-			Scope:        scope.String(),
-			InstallPath:  installPath,
-			Version:      version,
-			InstalledAt:  time.Now().UTC().Format(time.RFC3339),
-			LastUpdated:  time.Now().UTC().Format(time.RFC3339),
-			GitCommitSha: "", // We don't track commit SHA for now
-			IsLocal:      false,
+		if m.Version != "" {
+			available = append(available, m.Version+" ("+m.Name+"@"+m.Marketplace+")")
 		}
-
-		// Add project path for project/local scopes
-		if scope == settings.ScopeProject || scope == settings.ScopeLocal {
-			if projectPath == "" {
-				cwd, err := os.Getwd()
-				if err != nil {
-					return err
-				}
-				projectPath = cwd
-			}
-			install.ProjectPath = projectPath
-		}
-
-		// Check if already installed
-		existing, ok := installed.Plugins[fullName]
-		if ok {
-			// Update existing entry for this scope
-			found := false
-			for i, e := range existing {
-				if e.Scope == scope.String() {
-					existing[i] = install
-					found = true
-					break
-				}
-			}
-			if !found {
-				existing = append(existing, install)
-			}
-			installed.Plugins[fullName] = existing
-		} else {
-			installed.Plugins[fullName] = []config.PluginInstall{install}
-		}
-
-		// Write back to file
-		return saveInstalledPlugins(installed)
-	})
-}
-
-// saveInstalledPlugins writes the installed plugins registry
-func saveInstalledPlugins(installed *config.InstalledPluginsV2) error {
-	path, err := config.InstalledPluginsPath()
-	if err != nil {
-		return err
-	}
-
-	// Ensure directory exists
-	dir := filepath.Dir(path)
-	// #nosec G301 -- Plugin directory needs to be readable by Claude Code
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
 	}
 
-	data, err := json.MarshalIndent(installed, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	// Write atomically
-	tmpFile, err := os.CreateTemp(dir, ".installed-*.json")
-	if err != nil {
-		return err
+	if len(available) == 0 {
+		return nil, fmt.Errorf("version %s of '%s' not found (no versions listed in marketplace manifest)", version, pluginName)
 	}
-	tmpPath := tmpFile.Name()
-	defer func() { _ = os.Remove(tmpPath) }()
-
-	if _, err := tmpFile.Write(data); err != nil {
-		_ = tmpFile.Close()
-		return err
-	}
-	if _, err := tmpFile.WriteString("\n"); err != nil {
-		_ = tmpFile.Close()
-		return err
-	}
-	if err := tmpFile.Close(); err != nil {
-		return err
-	}
-
-	// #nosec G302 -- Config files need to be readable by Claude Code
-	if err := os.Chmod(tmpPath, 0644); err != nil {
-		return err
-	}
-
-	return settings.AtomicRename(tmpPath, path)
+	return nil, fmt.Errorf("version %s of '%s' not found; available: %s", version, pluginName, strings.Join(available, ", "))
 }
@@ -2,8 +2,19 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
 )
 
 func TestInstallCommandRegistered(t *testing.T) {
@@ -85,54 +96,266 @@ func TestInstallCommandHelp(t *testing.T) {
 	}
 }
 
-func TestValidatePathComponent(t *testing.T) {
+// useFakeGitHub points raw content and API requests at url and isolates the
+// plum cache so resolved branches don't leak between tests
+func useFakeGitHub(t *testing.T, url string) {
+	t.Helper()
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+	originalRaw := marketplace.GitHubRawBase
+	originalAPI := marketplace.GitHubAPIBase
+	marketplace.GitHubRawBase = url
+	marketplace.GitHubAPIBase = url
+	t.Cleanup(func() {
+		marketplace.GitHubRawBase = originalRaw
+		marketplace.GitHubAPIBase = originalAPI
+	})
+}
+
+func TestInstallCommand_NoVerifyFlag(t *testing.T) {
+	flag := installCmd.Flags().Lookup("no-verify")
+	if flag == nil {
+		t.Fatal("expected --no-verify flag to exist")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("expected default false, got %s", flag.DefValue)
+	}
+}
+
+func TestParsePluginArg(t *testing.T) {
 	tests := []struct {
-		name      string
-		input     string
-		wantError bool
+		arg         string
+		name        string
+		marketplace string
+		version     string
+		wantErr     bool
 	}{
-		{"valid name", "my-plugin", false},
-		{"valid with numbers", "plugin123", false},
-		{"empty", "", true},
-		{"path traversal", "..", true},
-		{"path traversal in name", "foo/../bar", true},
-		{"forward slash", "foo/bar", true},
-		{"backslash", "foo\\bar", true},
-		{"current dir", ".", true},
+		{"memory", "memory", "", "", false},
+		{"memory@market", "memory", "market", "", false},
+		{"memory@market@1.2.0", "memory", "market", "1.2.0", false},
+		{"memory@market@v1.2.0", "memory", "market", "v1.2.0", false},
+		{"memory@@1.2.0", "", "", "", true},
+		{"memory@market@", "", "", "", true},
+		{"memory@market@1@2", "", "", "", true},
+		{"memory@", "", "", "", true},
+		{"memory@1.2.0", "", "", "", true},
+		{"scope@tool@market", "scope@tool", "market", "", false},
+		{"scope@tool@market@1.2.0", "scope@tool", "market", "1.2.0", false},
+		{"scope@tool@market@v2", "scope@tool", "market", "v2", false},
+		{"memory@market@latest", "memory@market", "latest", "", false},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := validatePathComponent(tt.input, "test")
-			if (err != nil) != tt.wantError {
-				t.Errorf("validatePathComponent(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+		t.Run(tt.arg, func(t *testing.T) {
+			name, mkt, version, err := parsePluginArg(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePluginArg(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if name != tt.name || mkt != tt.marketplace || version != tt.version {
+				t.Errorf("parsePluginArg(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.arg, name, mkt, version, tt.name, tt.marketplace, tt.version)
 			}
 		})
 	}
 }
 
-func TestValidatePluginFilePath(t *testing.T) {
-	cacheDir := "/tmp/plum-test-cache"
+func TestSelectPinnedVersion(t *testing.T) {
+	matches := []*install.Target{
+		{Name: "memory", Marketplace: "market", Version: "1.2.0"},
+	}
 
-	tests := []struct {
-		name      string
-		filePath  string
-		wantError bool
-	}{
-		{"valid simple", "script.js", false},
-		{"valid nested", "src/main.js", false},
-		{"path traversal", "../escape.js", true},
-		{"path traversal nested", "src/../../escape.js", true},
-		{"absolute path", "/etc/passwd", true},
-		{"double dot", "..hidden", true},
+	got, err := selectPinnedVersion(matches, "memory", "v1.2.0")
+	if err != nil {
+		t.Fatalf("selectPinnedVersion failed: %v", err)
+	}
+	if !got.Pinned {
+		t.Error("selected plugin should be marked pinned")
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := validatePluginFilePath(tt.filePath, cacheDir)
-			if (err != nil) != tt.wantError {
-				t.Errorf("validatePluginFilePath(%q) error = %v, wantError %v", tt.filePath, err, tt.wantError)
-			}
-		})
+	_, err = selectPinnedVersion(matches, "memory", "9.9.9")
+	if err == nil {
+		t.Fatal("expected error for missing version")
+	}
+	if !strings.Contains(err.Error(), "available: 1.2.0 (memory@market)") {
+		t.Errorf("error should list available versions, got %v", err)
+	}
+}
+
+// setupInstallFixture creates a known marketplace with plugins alpha and beta
+// and a fake GitHub serving their plugin.json files. Returns the config dir.
+// fixtureCommit is the commit the fixture's main branch resolves to
+const fixtureCommit = "0123456789abcdef0123456789abcdef01234567"
+
+func setupInstallFixture(t *testing.T) string {
+	t.Helper()
+
+	const (
+		marketplaceName = "claude-code-marketplace" // listed in PopularMarketplaces, so it has a repo
+		repoPath        = "/ananddtyagi/cc-marketplace"
+	)
+
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "claude")
+	mktDir := filepath.Join(tmpDir, "marketplace")
+
+	manifest := `{
+		"name": "` + marketplaceName + `",
+		"owner": {"name": "Test"},
+		"plugins": [
+			{"name": "alpha", "source": "./plugins/alpha", "version": "1.0.0"},
+			{"name": "beta", "source": "./plugins/beta", "version": "2.0.0"}
+		]
+	}`
+	known := `{"` + marketplaceName + `": {
+		"source": {"source": "github", "repo": "ananddtyagi/cc-marketplace"},
+		"installLocation": "` + filepath.ToSlash(mktDir) + `"
+	}}`
+
+	writeFiles := map[string]string{
+		filepath.Join(mktDir, ".claude-plugin", "marketplace.json"):                manifest,
+		filepath.Join(mktDir, "plugins", "alpha", ".claude-plugin", "plugin.json"): `{"name": "alpha"}`,
+		filepath.Join(mktDir, "plugins", "beta", ".claude-plugin", "plugin.json"):  `{"name": "beta"}`,
+		filepath.Join(configDir, "plugins", "known_marketplaces.json"):             known,
+	}
+	for path, content := range writeFiles {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remote := map[string]string{
+		repoPath + "/main/plugins/alpha/.claude-plugin/plugin.json": `{"name": "alpha", "commands": ["commands/a.md"]}`,
+		repoPath + "/main/plugins/beta/.claude-plugin/plugin.json":  `{"name": "beta", "hooks": ["hooks/b.sh"]}`,
+		// Release tags for pinned installs
+		repoPath + "/v1.0.0/plugins/alpha/.claude-plugin/plugin.json": `{"name": "alpha", "version": "1.0.0"}`,
+		repoPath + "/v2.0.0/plugins/beta/.claude-plugin/plugin.json":  `{"name": "beta", "version": "2.0.0"}`,
+		// The commit main points to, for lockfile installs
+		repoPath + "/" + fixtureCommit + "/plugins/alpha/.claude-plugin/plugin.json": `{"name": "alpha"}`,
+		repoPath + "/" + fixtureCommit + "/plugins/beta/.claude-plugin/plugin.json":  `{"name": "beta"}`,
+		"/repos" + repoPath:                   `{"default_branch": "main"}`,
+		"/repos" + repoPath + "/commits/main": fixtureCommit,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := remote[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	useFakeGitHub(t, server.URL)
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	return configDir
+}
+
+func TestInstallCommand_DryRun(t *testing.T) {
+	configDir := setupInstallFixture(t)
+
+	installScope = "user"
+	installProject = t.TempDir()
+	installDryRun = true
+	defer func() { installDryRun = false; installProject = "" }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runInstall(installCmd, []string{"alpha@claude-code-marketplace", "beta"})
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("runInstall --dry-run failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	output := buf.String()
+
+	for _, want := range []string{
+		"Would install alpha@claude-code-marketplace in user scope",
+		"Would install beta@claude-code-marketplace in user scope",
+		"Version:     2.0.0",
+		"Ref:         main",
+		"commands/a.md",
+		"hooks/b.sh",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	// Nothing may be written: no cache, registry, or settings
+	for _, path := range []string{
+		filepath.Join(configDir, "plugins", "cache"),
+		filepath.Join(configDir, "plugins", "installed_plugins.json"),
+		filepath.Join(configDir, "settings.json"),
+	} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("dry run should not create %s (stat err = %v)", path, err)
+		}
+	}
+}
+
+func TestInstallCommand_DryRunFlag(t *testing.T) {
+	flag := installCmd.Flags().Lookup("dry-run")
+	if flag == nil {
+		t.Fatal("expected --dry-run flag to exist")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("expected default false, got %s", flag.DefValue)
+	}
+}
+
+func TestNoCacheFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{installCmd, searchCmd, marketplaceListCmd} {
+		flag := cmd.Flags().Lookup("no-cache")
+		if flag == nil {
+			t.Errorf("expected --no-cache flag on %s", cmd.Name())
+			continue
+		}
+		if flag.DefValue != "false" {
+			t.Errorf("%s: expected default false, got %s", cmd.Name(), flag.DefValue)
+		}
+	}
+}
+
+func TestInstall_FailsFastWhenSettingsNotWritable(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced for this user")
+	}
+	configDir := setupInstallFixture(t)
+
+	project := t.TempDir()
+	if err := os.Chmod(project, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chmod(project, 0755) }()
+
+	installProject = project
+	defer func() { installProject = "" }()
+	if err := installCmd.Flags().Set("scope", "project"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		installScope = "user"
+		installCmd.Flags().Lookup("scope").Changed = false
+	}()
+
+	_, err := captureStdout(t, func() error {
+		return runInstall(installCmd, []string{"alpha@claude-code-marketplace"})
+	})
+	if !errors.Is(err, settings.ErrNotWritable) || !strings.Contains(err.Error(), "project settings") {
+		t.Fatalf("expected a not-writable error for project settings, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "plugins", "cache")); !os.IsNotExist(err) {
+		t.Errorf("nothing should be downloaded before the check fails (stat err = %v)", err)
 	}
 }
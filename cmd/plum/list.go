@@ -4,10 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
+	"sort"
 	"text/tabwriter"
 
 	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/settings"
 	"github.com/spf13/cobra"
 )
@@ -17,14 +18,17 @@ var listCmd = &cobra.Command{
 	Short: "List installed plugins",
 	Long: `List all installed plugins across all scopes.
 
-Shows plugin name, marketplace, scope, status (enabled/disabled), and version.
+Shows plugin name, marketplace, version, scope, and whether it is enabled.
+When a plugin is set in several scopes, the scope Claude Code uses wins
+(managed > local > project > user).
 
 Examples:
   plum list                  # List all plugins
   plum list --scope=user     # List only user-scoped plugins
-  plum list --enabled        # List only enabled plugins
+  plum list --enabled-only   # List only enabled plugins
   plum list --updates        # Show available updates inline
-  plum list --json           # Output as JSON`,
+  plum list --json           # Output as JSON
+  plum list --json --group-by=marketplace  # JSON object keyed by marketplace`,
 	RunE: runList,
 }
 
@@ -34,6 +38,7 @@ var (
 	listDisabled bool
 	listUpdates  bool
 	listJSON     bool
+	listGroupBy  string
 	listProject  string
 )
 
@@ -42,9 +47,11 @@ func init() {
 
 	listCmd.Flags().StringVarP(&listScope, "scope", "s", "", "Filter by scope (user, project, local)")
 	listCmd.Flags().BoolVar(&listEnabled, "enabled", false, "Show only enabled plugins")
+	listCmd.Flags().BoolVar(&listEnabled, "enabled-only", false, "Show only enabled plugins (same as --enabled)")
 	listCmd.Flags().BoolVar(&listDisabled, "disabled", false, "Show only disabled plugins")
 	listCmd.Flags().BoolVar(&listUpdates, "updates", false, "Show available updates inline")
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output as JSON")
+	listCmd.Flags().StringVar(&listGroupBy, "group-by", "", "Group JSON output (marketplace)")
 	listCmd.Flags().StringVar(&listProject, "project", "", "Project path (default: current directory)")
 }
 
@@ -61,6 +68,16 @@ type PluginListItem struct {
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	switch listGroupBy {
+	case "":
+	case "marketplace":
+		if !listJSON {
+			return fmt.Errorf("--group-by requires --json")
+		}
+	default:
+		return fmt.Errorf("invalid --group-by %q (must be marketplace)", listGroupBy)
+	}
+
 	// Load installed plugins from Claude Code's registry
 	installed, err := config.LoadInstalledPlugins()
 	if err != nil {
@@ -105,11 +122,9 @@ func runList(cmd *cobra.Command, args []string) error {
 	items := make([]PluginListItem, 0, len(states))
 	for _, state := range states {
 		// Parse plugin@marketplace
-		parts := strings.SplitN(state.FullName, "@", 2)
-		name := parts[0]
-		marketplace := ""
-		if len(parts) > 1 {
-			marketplace = parts[1]
+		name, marketplace, ok := plugin.ParseFullName(state.FullName)
+		if !ok {
+			name = state.FullName
 		}
 
 		// Get version from installed plugins registry
@@ -137,7 +152,7 @@ func runList(cmd *cobra.Command, args []string) error {
 		// Check for updates if --updates flag is set
 		if listUpdates && version != "" {
 			if latest, ok := latestVersions[state.FullName]; ok && latest != "" {
-				if isNewerVersion(latest, version) {
+				if plugin.CheckUpdate(version, latest) == plugin.UpdateAvailable {
 					item.LatestVersion = latest
 					item.UpdateAvail = true
 				}
@@ -147,8 +162,19 @@ func runList(cmd *cobra.Command, args []string) error {
 		items = append(items, item)
 	}
 
+	// Settings are maps, so give the output a stable order
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].Marketplace < items[j].Marketplace
+	})
+
 	// Output
 	if listJSON {
+		if listGroupBy == "marketplace" {
+			return outputGroupedJSON(groupByMarketplace(items))
+		}
 		return outputJSON(items)
 	}
 	return outputTable(items)
@@ -160,6 +186,21 @@ func outputJSON(items []PluginListItem) error {
 	return enc.Encode(items)
 }
 
+// groupByMarketplace buckets list items by marketplace, keeping their order
+func groupByMarketplace(items []PluginListItem) map[string][]PluginListItem {
+	groups := make(map[string][]PluginListItem)
+	for _, item := range items {
+		groups[item.Marketplace] = append(groups[item.Marketplace], item)
+	}
+	return groups
+}
+
+func outputGroupedJSON(groups map[string][]PluginListItem) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(groups)
+}
+
 func outputTable(items []PluginListItem) error {
 	if len(items) == 0 {
 		fmt.Println("No plugins found")
@@ -169,7 +210,7 @@ func outputTable(items []PluginListItem) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
 	// Header
-	_, _ = fmt.Fprintln(w, "NAME\tMARKETPLACE\tSCOPE\tSTATUS\tVERSION")
+	_, _ = fmt.Fprintln(w, "NAME\tMARKETPLACE\tVERSION\tSCOPE\tENABLED")
 
 	// Rows
 	for _, item := range items {
@@ -181,12 +222,16 @@ func outputTable(items []PluginListItem) error {
 		if item.UpdateAvail && item.LatestVersion != "" {
 			version = fmt.Sprintf("%s → %s available", version, item.LatestVersion)
 		}
+		enabled := "no"
+		if item.Status == "enabled" {
+			enabled = "yes"
+		}
 		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			item.Name,
 			item.Marketplace,
-			item.Scope,
-			item.Status,
 			version,
+			item.Scope,
+			enabled,
 		)
 	}
 
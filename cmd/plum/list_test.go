@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -20,7 +21,7 @@ func TestListCommand_Structure(t *testing.T) {
 	}
 
 	// Check flags exist
-	flags := []string{"scope", "enabled", "disabled", "json", "project"}
+	flags := []string{"scope", "enabled", "enabled-only", "disabled", "json", "group-by", "project"}
 	for _, flag := range flags {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("expected flag --%s to exist", flag)
@@ -152,3 +153,155 @@ func TestPluginListItem_JSONSerialization(t *testing.T) {
 		t.Errorf("version mismatch: %s != %s", parsed.Version, item.Version)
 	}
 }
+
+func TestListCommand_ScopePrecedence(t *testing.T) {
+	configDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	files := map[string]string{
+		// shared@mkt is enabled for the user but disabled in the project
+		filepath.Join(configDir, "settings.json"): `{"enabledPlugins": {
+			"shared@mkt": true,
+			"user-only@mkt": true,
+			"off@mkt": false
+		}}`,
+		filepath.Join(projectDir, ".claude", "settings.json"): `{"enabledPlugins": {
+			"shared@mkt": false,
+			"project-only@mkt": true
+		}}`,
+		filepath.Join(configDir, "plugins", "installed_plugins.json"): `{"version": 2, "plugins": {
+			"shared@mkt": [{"scope": "user", "version": "1.2.0"}]
+		}}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listScope = ""
+	listEnabled = false
+	listDisabled = false
+	listProject = projectDir
+	defer func() { listJSON = false; listScope = ""; listEnabled = false; listProject = "" }()
+
+	t.Run("json reflects precedence", func(t *testing.T) {
+		listJSON = true
+		output, err := captureStdout(t, func() error { return runList(listCmd, nil) })
+		if err != nil {
+			t.Fatalf("runList failed: %v", err)
+		}
+
+		var items []PluginListItem
+		if err := json.Unmarshal([]byte(output), &items); err != nil {
+			t.Fatalf("failed to parse JSON output: %v\nOutput: %s", err, output)
+		}
+
+		want := []PluginListItem{
+			{Name: "off", Marketplace: "mkt", Scope: "user", Status: "disabled"},
+			{Name: "project-only", Marketplace: "mkt", Scope: "project", Status: "enabled"},
+			{Name: "shared", Marketplace: "mkt", Scope: "project", Status: "disabled", Version: "1.2.0", Installed: true},
+			{Name: "user-only", Marketplace: "mkt", Scope: "user", Status: "enabled"},
+		}
+		if len(items) != len(want) {
+			t.Fatalf("expected %d items, got %+v", len(want), items)
+		}
+		for i := range want {
+			if items[i] != want[i] {
+				t.Errorf("item %d = %+v, want %+v", i, items[i], want[i])
+			}
+		}
+	})
+
+	t.Run("table with enabled-only and scope", func(t *testing.T) {
+		listJSON = false
+		listEnabled = true
+		listScope = "user"
+		output, err := captureStdout(t, func() error { return runList(listCmd, nil) })
+		if err != nil {
+			t.Fatalf("runList failed: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected header and one row, got:\n%s", output)
+		}
+		if got := strings.Fields(lines[0]); strings.Join(got, " ") != "NAME MARKETPLACE VERSION SCOPE ENABLED" {
+			t.Errorf("header = %v", got)
+		}
+		if got := strings.Fields(lines[1]); strings.Join(got, " ") != "user-only mkt - user yes" {
+			t.Errorf("row = %v", got)
+		}
+	})
+}
+
+func TestListCommand_GroupByMarketplace(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	userSettings := `{"enabledPlugins": {
+		"docker-build@docker-plugins": true,
+		"docker-run@docker-plugins": false,
+		"memory@claude-code-plugins": true
+	}}`
+	if err := os.WriteFile(filepath.Join(configDir, "settings.json"), []byte(userSettings), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	listJSON = true
+	listGroupBy = "marketplace"
+	listScope = ""
+	listEnabled = false
+	listDisabled = false
+	listProject = t.TempDir()
+	defer func() { listJSON = false; listGroupBy = ""; listProject = "" }()
+
+	output, err := captureStdout(t, func() error { return runList(listCmd, nil) })
+	if err != nil {
+		t.Fatalf("runList failed: %v", err)
+	}
+
+	var groups map[string][]PluginListItem
+	if err := json.Unmarshal([]byte(output), &groups); err != nil {
+		t.Fatalf("expected a JSON object keyed by marketplace: %v\nOutput: %s", err, output)
+	}
+
+	want := map[string][]string{
+		"docker-plugins":      {"docker-build", "docker-run"},
+		"claude-code-plugins": {"memory"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("expected %d marketplaces, got %v", len(want), groups)
+	}
+	for mkt, names := range want {
+		items := groups[mkt]
+		if len(items) != len(names) {
+			t.Errorf("%s: expected %d plugins, got %+v", mkt, len(names), items)
+			continue
+		}
+		for i, item := range items {
+			if item.Name != names[i] || item.Marketplace != mkt {
+				t.Errorf("%s[%d] = %s@%s, want %s@%s", mkt, i, item.Name, item.Marketplace, names[i], mkt)
+			}
+		}
+	}
+}
+
+func TestListCommand_GroupByValidation(t *testing.T) {
+	defer func() { listJSON = false; listGroupBy = "" }()
+
+	listJSON = false
+	listGroupBy = "marketplace"
+	if err := runList(listCmd, nil); err == nil || !strings.Contains(err.Error(), "--json") {
+		t.Errorf("expected --group-by without --json to fail, got %v", err)
+	}
+
+	listJSON = true
+	listGroupBy = "scope"
+	if err := runList(listCmd, nil); err == nil || !strings.Contains(err.Error(), "invalid --group-by") {
+		t.Errorf("expected unknown --group-by to fail, got %v", err)
+	}
+}
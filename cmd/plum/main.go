@@ -1,5 +1,53 @@
 package main
 
+import (
+	"os"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// terminalSupport describes how much of the TUI the current terminal can handle
+type terminalSupport int
+
+const (
+	terminalFull           terminalSupport = iota // Interactive with alt screen
+	terminalNoAltScreen                           // Interactive, but render inline
+	terminalNonInteractive                        // Not a usable terminal; use CLI subcommands
+)
+
 func main() {
 	Execute()
 }
+
+// noAltScreenTerms are terminal types without an alternate screen buffer
+var noAltScreenTerms = map[string]bool{
+	"vt52":  true,
+	"vt100": true,
+	"vt102": true,
+}
+
+// detectTerminalSupport decides how to run the TUI from TERM and whether
+// stdin/stdout are terminals. Pipes, CI logs and TERM=dumb can't drive the
+// TUI at all; terminal types known to lack an alternate screen get the
+// inline renderer. TERM is often unset on Windows consoles, so an empty TERM
+// on a real terminal is treated as fully capable.
+func detectTerminalSupport(termName string, stdinTTY, stdoutTTY bool) terminalSupport {
+	if !stdinTTY || !stdoutTTY || termName == "dumb" {
+		return terminalNonInteractive
+	}
+	if noAltScreenTerms[termName] {
+		return terminalNoAltScreen
+	}
+	return terminalFull
+}
+
+// currentTerminalSupport checks the process's own TERM and standard streams
+func currentTerminalSupport() terminalSupport {
+	return detectTerminalSupport(os.Getenv("TERM"), isTerminal(os.Stdin), isTerminal(os.Stdout))
+}
+
+// isTerminal reports whether f is a terminal. Unlike checking for a
+// character device, this is false for /dev/null.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(f.Fd())
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetectTerminalSupport(t *testing.T) {
+	tests := []struct {
+		name      string
+		term      string
+		stdinTTY  bool
+		stdoutTTY bool
+		want      terminalSupport
+	}{
+		{"xterm", "xterm-256color", true, true, terminalFull},
+		{"screen", "screen", true, true, terminalFull},
+		{"dumb terminal", "dumb", true, true, terminalNonInteractive},
+		{"unset TERM (Windows console)", "", true, true, terminalFull},
+		{"vt100", "vt100", true, true, terminalNoAltScreen},
+		{"piped stdin", "xterm-256color", false, true, terminalNonInteractive},
+		{"redirected stdout", "xterm-256color", true, false, terminalNonInteractive},
+		{"CI without TTY", "", false, false, terminalNonInteractive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectTerminalSupport(tt.term, tt.stdinTTY, tt.stdoutTTY); got != tt.want {
+				t.Errorf("detectTerminalSupport(%q, %v, %v) = %v, want %v",
+					tt.term, tt.stdinTTY, tt.stdoutTTY, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTerminal_Pipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close(); _ = w.Close() }()
+
+	if isTerminal(r) {
+		t.Error("pipe should not be reported as a terminal")
+	}
+}
+
+func TestIsTerminal_DevNull(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if isTerminal(f) {
+		t.Error("null device should not be reported as a terminal")
+	}
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
 	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/settings"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +29,7 @@ Marketplaces are sources of plugins that Plum can search and install from.
 
 Available subcommands:
   list     List all registered and discoverable marketplaces
+  info     Show details for one marketplace
   add      Add a custom marketplace
   remove   Remove a custom marketplace`,
 }
@@ -35,13 +43,15 @@ Shows marketplace name, source repository, plugin count, and installation status
 
 Examples:
   plum marketplace list
-  plum marketplace list --json`,
+  plum marketplace list --json
+  plum marketplace list --no-cache`,
 	RunE: runMarketplaceList,
 }
 
 var (
 	marketplaceListJSON    bool
 	marketplaceListProject string
+	marketplaceListNoCache bool
 )
 
 func init() {
@@ -50,6 +60,7 @@ func init() {
 
 	marketplaceListCmd.Flags().BoolVar(&marketplaceListJSON, "json", false, "Output as JSON")
 	marketplaceListCmd.Flags().StringVar(&marketplaceListProject, "project", "", "Project path (default: current directory)")
+	marketplaceListCmd.Flags().BoolVar(&marketplaceListNoCache, "no-cache", false, "Fetch plugin counts fresh from GitHub instead of using the cache")
 }
 
 // MarketplaceListItem represents a marketplace in the list output
@@ -79,6 +90,15 @@ func runMarketplaceList(cmd *cobra.Command, args []string) error {
 	items := make([]MarketplaceListItem, 0)
 	seenNames := make(map[string]bool)
 
+	// Fetch fresh manifests up front when the cache is bypassed
+	var fresh map[string]*marketplace.DiscoveredMarketplace
+	if marketplaceListNoCache {
+		fresh, err = marketplace.DiscoverPopularMarketplaces(context.Background(), true)
+		if err != nil {
+			return fmt.Errorf("failed to fetch marketplaces: %w", err)
+		}
+	}
+
 	// Add popular marketplaces
 	for _, pm := range marketplace.PopularMarketplaces {
 		_, isInstalled := known[pm.Name]
@@ -96,8 +116,12 @@ func runMarketplaceList(cmd *cobra.Command, args []string) error {
 			item.Stars = pm.StaticStats.Stars
 		}
 
-		// Count plugins from cached manifest
-		if cached, err := marketplace.LoadFromCache(pm.Name); err == nil && cached != nil {
+		// Count plugins from cached (or freshly fetched) manifest
+		if marketplaceListNoCache {
+			if disc, ok := fresh[pm.Name]; ok {
+				item.PluginCount = len(disc.Manifest.Plugins)
+			}
+		} else if cached, err := marketplace.LoadFromCache(pm.Name); err == nil && cached != nil {
 			item.PluginCount = len(cached.Plugins)
 		}
 
@@ -200,6 +224,168 @@ func outputMarketplaceListTable(items []MarketplaceListItem) error {
 	return w.Flush()
 }
 
+// marketplace info command
+var marketplaceInfoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "Show marketplace details",
+	Long: `Show details for one marketplace.
+
+Shows the marketplace's repository, description, plugin count, how many of its
+plugins you have installed, GitHub stats, and every plugin in its cached
+manifest. Run 'plum marketplace refresh' first if the cache is empty or stale.
+
+Examples:
+  plum marketplace info claude-code-plugins
+  plum marketplace info claude-code-plugins --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMarketplaceInfo,
+}
+
+var (
+	marketplaceInfoJSON    bool
+	marketplaceInfoProject string
+)
+
+func init() {
+	marketplaceCmd.AddCommand(marketplaceInfoCmd)
+
+	marketplaceInfoCmd.Flags().BoolVar(&marketplaceInfoJSON, "json", false, "Output as JSON")
+	marketplaceInfoCmd.Flags().StringVar(&marketplaceInfoProject, "project", "", "Project path (default: current directory)")
+}
+
+// MarketplaceInfo represents detailed marketplace information
+type MarketplaceInfo struct {
+	Name             string                   `json:"name"`
+	DisplayName      string                   `json:"displayName,omitempty"`
+	Repo             string                   `json:"repo"`
+	Description      string                   `json:"description,omitempty"`
+	Installed        bool                     `json:"installed"`
+	Cached           bool                     `json:"cached"`
+	PluginCount      int                      `json:"pluginCount"`
+	InstalledPlugins int                      `json:"installedPlugins"`
+	Stats            *marketplace.GitHubStats `json:"stats,omitempty"`
+	Plugins          []string                 `json:"plugins"`
+}
+
+func runMarketplaceInfo(cmd *cobra.Command, args []string) error {
+	info, err := buildMarketplaceInfo(args[0], marketplaceInfoProject)
+	if err != nil {
+		return err
+	}
+
+	if marketplaceInfoJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	return outputMarketplaceInfo(os.Stdout, info)
+}
+
+// buildMarketplaceInfo gathers details for the named marketplace from the
+// same sources as 'marketplace list', plus its cached manifest and stats
+func buildMarketplaceInfo(name, projectPath string) (*MarketplaceInfo, error) {
+	known, _ := config.LoadKnownMarketplaces()
+	extra, _ := settings.AllMarketplaces(projectPath)
+
+	info := &MarketplaceInfo{Name: name}
+	_, info.Installed = known[name]
+	found := info.Installed
+
+	for _, pm := range marketplace.PopularMarketplaces {
+		if pm.Name != name {
+			continue
+		}
+		info.DisplayName = pm.DisplayName
+		info.Repo = pm.Repo
+		info.Description = pm.Description
+		info.Stats = pm.StaticStats
+		found = true
+		break
+	}
+	if info.Repo == "" {
+		if entry, ok := known[name]; ok {
+			info.Repo = entry.Source.Repo
+		} else if em, ok := extra[name]; ok {
+			info.Repo = em.Source.Repo
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("marketplace '%s' not found (see 'plum marketplace list')", name)
+	}
+
+	if manifest, err := marketplace.LoadFromCache(name); err == nil && manifest != nil {
+		info.Cached = true
+		info.PluginCount = len(manifest.Plugins)
+		if info.Description == "" {
+			info.Description = manifest.Metadata.Description
+		}
+		for _, p := range manifest.Plugins {
+			info.Plugins = append(info.Plugins, p.Name)
+		}
+	}
+	if info.Plugins == nil {
+		info.Plugins = []string{}
+	}
+
+	// Prefer fresher cached stats over the static snapshot
+	if stats, err := marketplace.LoadStatsFromCache(name); err == nil && stats != nil {
+		info.Stats = stats
+	}
+
+	if installed, err := config.LoadInstalledPlugins(); err == nil {
+		for fullName := range installed.Plugins {
+			if _, m, ok := plugin.ParseFullName(fullName); ok && m == name {
+				info.InstalledPlugins++
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func outputMarketplaceInfo(w io.Writer, info *MarketplaceInfo) error {
+	_, _ = fmt.Fprintf(w, "Name:        %s\n", info.Name)
+	if info.DisplayName != "" {
+		_, _ = fmt.Fprintf(w, "Title:       %s\n", info.DisplayName)
+	}
+	if info.Repo != "" {
+		_, _ = fmt.Fprintf(w, "Repository:  %s\n", info.Repo)
+	}
+	if info.Description != "" {
+		_, _ = fmt.Fprintf(w, "Description: %s\n", info.Description)
+	}
+	if info.Installed {
+		_, _ = fmt.Fprintln(w, "Status:      installed")
+	} else {
+		_, _ = fmt.Fprintln(w, "Status:      discoverable")
+	}
+
+	if info.Cached {
+		_, _ = fmt.Fprintf(w, "Plugins:     %d total, %d installed\n", info.PluginCount, info.InstalledPlugins)
+	} else {
+		_, _ = fmt.Fprintf(w, "Plugins:     not cached, %d installed (run 'plum marketplace refresh')\n", info.InstalledPlugins)
+	}
+
+	if info.Stats != nil {
+		_, _ = fmt.Fprintf(w, "Stars:       %d\n", info.Stats.Stars)
+		_, _ = fmt.Fprintf(w, "Forks:       %d\n", info.Stats.Forks)
+		if !info.Stats.LastPushedAt.IsZero() {
+			_, _ = fmt.Fprintf(w, "Updated:     %s\n", info.Stats.LastPushedAt.Format("2006-01-02"))
+		}
+		_, _ = fmt.Fprintf(w, "Open Issues: %d\n", info.Stats.OpenIssues)
+	}
+
+	if len(info.Plugins) > 0 {
+		_, _ = fmt.Fprintln(w, "\nPlugins:")
+		for _, name := range info.Plugins {
+			_, _ = fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+
+	return nil
+}
+
 // marketplace add command
 var marketplaceAddCmd = &cobra.Command{
 	Use:   "add <repo>",
@@ -209,13 +395,17 @@ var marketplaceAddCmd = &cobra.Command{
 The marketplace is specified as a GitHub repository in the format owner/repo.
 You can optionally pin to a specific version or commit using #ref syntax.
 
-Custom marketplaces are stored in extraKnownMarketplaces in your settings.json.
+Custom marketplaces are stored in extraKnownMarketplaces in your settings.json,
+keyed by the repository name. Use --name to pick a different key, e.g. when two
+repositories share a name. Adding a different repository under a name that is
+already taken is refused.
 
 Examples:
   plum marketplace add myorg/my-plugins
   plum marketplace add myorg/my-plugins#v2.0.0     # Pin to tag
   plum marketplace add myorg/my-plugins#abc123     # Pin to commit
-  plum marketplace add myorg/my-plugins --scope=project`,
+  plum marketplace add myorg/my-plugins --scope=project
+  plum marketplace add myorg/marketplace --name=myorg-plugins`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMarketplaceAdd,
 }
@@ -223,6 +413,7 @@ Examples:
 var (
 	marketplaceAddScope   string
 	marketplaceAddProject string
+	marketplaceAddName    string
 )
 
 func init() {
@@ -230,13 +421,14 @@ func init() {
 
 	marketplaceAddCmd.Flags().StringVarP(&marketplaceAddScope, "scope", "s", "user", "Settings scope (user, project, local)")
 	marketplaceAddCmd.Flags().StringVar(&marketplaceAddProject, "project", "", "Project path (default: current directory)")
+	marketplaceAddCmd.Flags().StringVar(&marketplaceAddName, "name", "", "Marketplace name (default: the repository name)")
 }
 
 func runMarketplaceAdd(cmd *cobra.Command, args []string) error {
 	repoArg := args[0]
 
 	// Parse scope
-	scope, err := settings.ParseScope(marketplaceAddScope)
+	scope, err := parseScopeFlag(cmd, marketplaceAddScope, marketplaceAddProject)
 	if err != nil {
 		return err
 	}
@@ -252,6 +444,9 @@ func runMarketplaceAdd(cmd *cobra.Command, args []string) error {
 	if idx := strings.LastIndex(repoArg, "#"); idx > 0 {
 		repo = repoArg[:idx]
 		ref = repoArg[idx+1:]
+		if err := validateGitRef(ref); err != nil {
+			return err
+		}
 	}
 
 	// Validate repo format (should be owner/repo)
@@ -259,9 +454,24 @@ func runMarketplaceAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid repo format: expected owner/repo, got %s", repo)
 	}
 
-	// Derive marketplace name from repo
-	parts := strings.Split(repo, "/")
-	name := parts[len(parts)-1] // Use repo name as marketplace name
+	// Derive marketplace name from repo unless one was given
+	name := marketplaceAddName
+	if name == "" {
+		parts := strings.Split(repo, "/")
+		name = parts[len(parts)-1] // Use repo name as marketplace name
+	}
+	if err := validateMarketplaceKey(name); err != nil {
+		return err
+	}
+
+	// Refuse to silently replace a different repo registered under this name
+	existing, _ := settings.LoadSettings(scope, marketplaceAddProject)
+	if existing != nil {
+		if em, ok := existing.ExtraKnownMarketplaces[name]; ok && stripRef(em.Source.Repo) != repo {
+			return fmt.Errorf("marketplace '%s' already exists in %s scope (%s); use --name to add %s under a different name",
+				name, scope, em.Source.Repo, repo)
+		}
+	}
 
 	// Build source
 	source := settings.MarketplaceSource{
@@ -287,6 +497,67 @@ func runMarketplaceAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// validateMarketplaceKey checks a marketplace name used as a settings key. It
+// becomes a cache directory name and the part after '@' in plugin names.
+func validateMarketplaceKey(name string) error {
+	if err := install.ValidatePathComponent(name, "marketplace name"); err != nil {
+		return err
+	}
+	if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "@#") {
+		return fmt.Errorf("invalid marketplace name: %s", name)
+	}
+	for _, r := range name {
+		if r <= ' ' || r == 0x7f {
+			return fmt.Errorf("marketplace name contains whitespace or control characters: %q", name)
+		}
+	}
+	return nil
+}
+
+// stripRef returns repo without a trailing #ref pin
+func stripRef(repo string) string {
+	if idx := strings.LastIndex(repo, "#"); idx > 0 {
+		return repo[:idx]
+	}
+	return repo
+}
+
+// maxGitRefLength bounds refs stored in settings and injected into URLs
+const maxGitRefLength = 255
+
+// validateGitRef checks that a pinned ref is a plausible tag, branch name, or
+// commit SHA. Refs end up in raw content URLs, so anything that could change
+// the URL path (whitespace, traversal, backslashes) is rejected.
+func validateGitRef(ref string) error {
+	if ref == "" {
+		return fmt.Errorf("ref cannot be empty")
+	}
+	if len(ref) > maxGitRefLength {
+		return fmt.Errorf("ref too long (max %d characters)", maxGitRefLength)
+	}
+	if strings.Contains(ref, "..") {
+		return fmt.Errorf("ref contains invalid path traversal: %s", ref)
+	}
+	if strings.Contains(ref, "//") || strings.HasPrefix(ref, "/") || strings.HasSuffix(ref, "/") {
+		return fmt.Errorf("ref contains invalid path separator: %s", ref)
+	}
+	if strings.HasPrefix(ref, "-") || strings.HasSuffix(ref, ".") || strings.HasSuffix(ref, ".lock") {
+		return fmt.Errorf("invalid ref: %s", ref)
+	}
+	if strings.Contains(ref, "@{") {
+		return fmt.Errorf("invalid ref: %s", ref)
+	}
+	for _, r := range ref {
+		if r <= ' ' || r == 0x7f {
+			return fmt.Errorf("ref contains whitespace or control characters: %q", ref)
+		}
+		if strings.ContainsRune(`\~^:?*[#%`, r) {
+			return fmt.Errorf("ref contains invalid character %q: %s", r, ref)
+		}
+	}
+	return nil
+}
+
 // marketplace remove command
 var marketplaceRemoveCmd = &cobra.Command{
 	Use:   "remove <name>",
@@ -294,11 +565,21 @@ var marketplaceRemoveCmd = &cobra.Command{
 	Long: `Remove a custom marketplace from your settings.
 
 This removes the marketplace from extraKnownMarketplaces in your settings.json.
-It does not affect any plugins you have installed from that marketplace.
+Installed plugins are not uninstalled, but plugins from that marketplace can no
+longer be updated and become orphaned. Before removing, plum shows how many
+installed and enabled plugins came from the marketplace and asks for
+confirmation. Use --yes to skip the prompt.
+
+With --prune-plugins, the marketplace's installed plugins are uninstalled too:
+their cached files, install registry entries, and settings entries in every
+writable scope are removed. Nothing is pruned while another scope still
+lists the marketplace.
 
 Examples:
   plum marketplace remove my-plugins
-  plum marketplace remove my-plugins --scope=project`,
+  plum marketplace remove my-plugins --scope=project
+  plum marketplace remove my-plugins --yes
+  plum marketplace remove my-plugins --prune-plugins`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMarketplaceRemove,
 }
@@ -306,6 +587,8 @@ Examples:
 var (
 	marketplaceRemoveScope   string
 	marketplaceRemoveProject string
+	marketplaceRemoveYes     bool
+	marketplaceRemovePrune   bool
 )
 
 func init() {
@@ -313,6 +596,50 @@ func init() {
 
 	marketplaceRemoveCmd.Flags().StringVarP(&marketplaceRemoveScope, "scope", "s", "user", "Settings scope (user, project, local)")
 	marketplaceRemoveCmd.Flags().StringVar(&marketplaceRemoveProject, "project", "", "Project path (default: current directory)")
+	marketplaceRemoveCmd.Flags().BoolVarP(&marketplaceRemoveYes, "yes", "y", false, "Skip confirmation prompt")
+	marketplaceRemoveCmd.Flags().BoolVar(&marketplaceRemovePrune, "prune-plugins", false, "Also uninstall the marketplace's installed plugins")
+}
+
+// marketplaceImpact summarizes the plugins affected by removing a marketplace
+type marketplaceImpact struct {
+	Installed int // plugins from the marketplace present in any scope
+	Enabled   int // of those, plugins currently enabled
+}
+
+// computeMarketplaceImpact counts plugins in the merged settings that come
+// from the named marketplace.
+func computeMarketplaceImpact(name, projectPath string) (marketplaceImpact, error) {
+	var impact marketplaceImpact
+
+	states, err := settings.MergedPluginStates(projectPath)
+	if err != nil {
+		return impact, err
+	}
+
+	for _, state := range states {
+		if _, m, ok := plugin.ParseFullName(state.FullName); !ok || m != name {
+			continue
+		}
+		impact.Installed++
+		if state.Enabled {
+			impact.Enabled++
+		}
+	}
+
+	return impact, nil
+}
+
+// confirm prints a yes/no prompt and reports whether the answer was yes
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", prompt)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
 
 // marketplace refresh command
@@ -328,18 +655,24 @@ versions.
 By default, this only refreshes the catalog (plugin listings). Use --update
 to also update all installed plugins to their latest versions.
 
+With --max-age, only marketplaces cached longer ago than the given duration
+are fetched again; fresher cache entries are kept. Cached data otherwise
+expires after 24h, or after PLUM_CACHE_TTL (e.g. "6h") when set.
+
 Note: 'plum update' compares against cached marketplace data. Run 'plum marketplace
 refresh' first to ensure you have the latest version information.
 
 Examples:
   plum marketplace refresh              # Refresh catalog only
-  plum marketplace refresh --update     # Refresh catalog and update all plugins`,
+  plum marketplace refresh --update     # Refresh catalog and update all plugins
+  plum marketplace refresh --max-age 1h # Refresh only data older than an hour`,
 	RunE: runMarketplaceRefresh,
 }
 
 var (
 	marketplaceRefreshUpdate  bool
 	marketplaceRefreshProject string
+	marketplaceRefreshMaxAge  time.Duration
 )
 
 func init() {
@@ -347,13 +680,14 @@ func init() {
 
 	marketplaceRefreshCmd.Flags().BoolVar(&marketplaceRefreshUpdate, "update", false, "Also update all installed plugins after refresh")
 	marketplaceRefreshCmd.Flags().StringVar(&marketplaceRefreshProject, "project", "", "Project path for --update (default: current directory)")
+	marketplaceRefreshCmd.Flags().DurationVar(&marketplaceRefreshMaxAge, "max-age", 0, "Only refresh cached data older than this (e.g. 1h)")
 }
 
 func runMarketplaceRemove(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
 	// Parse scope
-	scope, err := settings.ParseScope(marketplaceRemoveScope)
+	scope, err := parseScopeFlag(cmd, marketplaceRemoveScope, marketplaceRemoveProject)
 	if err != nil {
 		return err
 	}
@@ -372,6 +706,37 @@ func runMarketplaceRemove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("marketplace '%s' not found in %s scope", name, scope)
 	}
 
+	// Show what depends on this marketplace before removing it
+	impact, err := computeMarketplaceImpact(name, marketplaceRemoveProject)
+	if err != nil {
+		return fmt.Errorf("failed to load plugin states: %w", err)
+	}
+	// Plugins stay usable while another scope still lists the marketplace
+	otherScopes := marketplaceScopesExcept(name, scope, marketplaceRemoveProject)
+	prune := marketplaceRemovePrune && len(otherScopes) == 0
+	switch {
+	case len(otherScopes) > 0:
+		if marketplaceRemovePrune {
+			fmt.Printf("'%s' is still listed in %s scope; its plugins will not be pruned\n", name, strings.Join(otherScopes, ", "))
+		}
+	case prune:
+		pluginNames, err := installedMarketplacePlugins(name)
+		if err != nil {
+			return fmt.Errorf("failed to load install registry: %w", err)
+		}
+		if len(pluginNames) > 0 {
+			fmt.Printf("%d installed plugin(s) from '%s' will be uninstalled: %s\n", len(pluginNames), name, strings.Join(pluginNames, ", "))
+		}
+	case impact.Installed > 0:
+		fmt.Printf("%d installed plugin(s) from '%s' (%d enabled) will become orphaned.\n", impact.Installed, name, impact.Enabled)
+		fmt.Println("They stay installed but can no longer be updated from this marketplace.")
+	}
+
+	if !marketplaceRemoveYes && !confirm(cmd.InOrStdin(), cmd.OutOrStdout(), fmt.Sprintf("Remove marketplace '%s'?", name)) {
+		fmt.Println("Aborted")
+		return nil
+	}
+
 	// Remove from settings
 	if err := settings.RemoveMarketplace(name, scope, marketplaceRemoveProject); err != nil {
 		return fmt.Errorf("failed to remove marketplace: %w", err)
@@ -379,20 +744,122 @@ func runMarketplaceRemove(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Removed marketplace '%s' from %s scope\n", name, scope)
 
+	if prune {
+		return pruneMarketplacePlugins(name, marketplaceRemoveProject)
+	}
+
 	return nil
 }
 
-func runMarketplaceRefresh(cmd *cobra.Command, args []string) error {
-	fmt.Println("Refreshing marketplace catalog...")
+// marketplaceScopesExcept returns the scopes other than skip whose settings
+// list the named marketplace. Unreadable scopes are treated as not listing it.
+func marketplaceScopesExcept(name string, skip settings.Scope, projectPath string) []string {
+	var scopes []string
+	for _, scope := range settings.AllScopes() {
+		if scope == skip {
+			continue
+		}
+		s, err := settings.LoadSettings(scope, projectPath)
+		if err != nil {
+			continue
+		}
+		if _, ok := s.ExtraKnownMarketplaces[name]; ok {
+			scopes = append(scopes, scope.String())
+		}
+	}
+	return scopes
+}
+
+// installedMarketplacePlugins returns the sorted full names of registered
+// installs from the named marketplace
+func installedMarketplacePlugins(name string) ([]string, error) {
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		return nil, err
+	}
 
-	// Use RefreshAll from marketplace package
-	if err := marketplace.RefreshAll(); err != nil {
-		return fmt.Errorf("failed to refresh marketplaces: %w", err)
+	var names []string
+	for fullName := range installed.Plugins {
+		if _, m, ok := plugin.ParseFullName(fullName); ok && m == name {
+			names = append(names, fullName)
+		}
 	}
+	sort.Strings(names)
+	return names, nil
+}
 
-	// Count how many marketplaces were refreshed
-	discovered, _ := marketplace.DiscoverPopularMarketplaces()
-	fmt.Printf("Refreshed %d marketplace(s)\n", len(discovered))
+// pruneMarketplacePlugins uninstalls every registered plugin from the named
+// marketplace: settings entries in all writable scopes, the install registry
+// entry, and the cached files. Each step is reported as it happens.
+func pruneMarketplacePlugins(name, projectPath string) error {
+	pluginNames, err := installedMarketplacePlugins(name)
+	if err != nil {
+		return fmt.Errorf("failed to load install registry: %w", err)
+	}
+	if len(pluginNames) == 0 {
+		fmt.Printf("No installed plugins from '%s' to prune\n", name)
+		return nil
+	}
+
+	var failures []string
+	for _, fullName := range pluginNames {
+		for _, scope := range settings.WritableScopes() {
+			s, err := settings.LoadSettings(scope, projectPath)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: failed to load %s settings: %v", fullName, scope, err))
+				continue
+			}
+			if _, ok := s.EnabledPlugins[fullName]; !ok {
+				continue
+			}
+			if err := removePluginFromScope(fullName, scope, projectPath); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s settings: %v", fullName, scope, err))
+				continue
+			}
+			fmt.Printf("Removed %s from %s scope settings\n", fullName, scope)
+		}
+
+		if err := unregisterInstalledPlugin(fullName); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: install registry: %v", fullName, err))
+			continue
+		}
+		if err := deletePluginCache(fullName); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: cache: %v", fullName, err))
+			continue
+		}
+		fmt.Printf("Pruned %s\n", fullName)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("pruning failed for some plugins:\n  %s", strings.Join(failures, "\n  "))
+	}
+	return nil
+}
+
+func runMarketplaceRefresh(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("max-age") {
+		if marketplaceRefreshMaxAge <= 0 {
+			return fmt.Errorf("--max-age must be positive, got %s", marketplaceRefreshMaxAge)
+		}
+		fmt.Printf("Refreshing marketplaces cached more than %s ago...\n", marketplaceRefreshMaxAge)
+
+		fetched, err := marketplace.RefreshOlderThan(context.Background(), marketplaceRefreshMaxAge)
+		if err != nil {
+			return fmt.Errorf("failed to refresh marketplaces: %w", err)
+		}
+		fmt.Printf("Refreshed %d marketplace(s)\n", len(fetched))
+	} else {
+		fmt.Println("Refreshing marketplace catalog...")
+
+		// Use RefreshAll from marketplace package
+		if err := marketplace.RefreshAll(context.Background()); err != nil {
+			return fmt.Errorf("failed to refresh marketplaces: %w", err)
+		}
+
+		// Count how many marketplaces were refreshed
+		discovered, _ := marketplace.DiscoverPopularMarketplaces(context.Background(), false)
+		fmt.Printf("Refreshed %d marketplace(s)\n", len(discovered))
+	}
 
 	// If --update flag, also update plugins
 	if marketplaceRefreshUpdate {
@@ -1,7 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/settings"
 )
 
 func TestMarketplaceCommand_Structure(t *testing.T) {
@@ -81,3 +92,460 @@ func TestMarketplaceCommand_HasListSubcommand(t *testing.T) {
 		t.Error("marketplace command should have 'list' subcommand")
 	}
 }
+
+func TestMarketplaceRemoveCommand_YesFlag(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"marketplace", "remove"})
+	if err != nil {
+		t.Fatalf("marketplace remove command not found: %v", err)
+	}
+
+	yesFlag := cmd.Flags().Lookup("yes")
+	if yesFlag == nil {
+		t.Fatal("expected --yes flag to exist")
+	}
+	if yesFlag.Shorthand != "y" {
+		t.Errorf("expected yes shorthand 'y', got %s", yesFlag.Shorthand)
+	}
+	if yesFlag.DefValue != "false" {
+		t.Errorf("expected yes default 'false', got %s", yesFlag.DefValue)
+	}
+}
+
+func TestComputeMarketplaceImpact(t *testing.T) {
+	tmpDir := t.TempDir()
+	claudeDir := filepath.Join(tmpDir, ".claude")
+	if err := os.MkdirAll(claudeDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+
+	userSettings := `{
+		"enabledPlugins": {
+			"alpha@my-market": true,
+			"beta@my-market": true,
+			"gamma@my-market": false,
+			"delta@other-market": true,
+			"epsilon@not-my-market": true
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(claudeDir, "settings.json"), []byte(userSettings), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := t.TempDir()
+	impact, err := computeMarketplaceImpact("my-market", projectDir)
+	if err != nil {
+		t.Fatalf("computeMarketplaceImpact failed: %v", err)
+	}
+
+	if impact.Installed != 3 {
+		t.Errorf("expected 3 installed plugins, got %d", impact.Installed)
+	}
+	if impact.Enabled != 2 {
+		t.Errorf("expected 2 enabled plugins, got %d", impact.Enabled)
+	}
+
+	impact, err = computeMarketplaceImpact("unused-market", projectDir)
+	if err != nil {
+		t.Fatalf("computeMarketplaceImpact failed: %v", err)
+	}
+	if impact.Installed != 0 || impact.Enabled != 0 {
+		t.Errorf("expected no impact for unused marketplace, got %+v", impact)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		var out strings.Builder
+		if got := confirm(strings.NewReader(tt.input), &out, "Continue?"); got != tt.want {
+			t.Errorf("confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.Contains(out.String(), "Continue? [y/N]") {
+			t.Errorf("expected prompt in output, got %q", out.String())
+		}
+	}
+}
+
+func TestValidateGitRef(t *testing.T) {
+	valid := []string{
+		"v1.0.0",
+		"v2.3.4-beta.1",
+		"main",
+		"release/2024-01",
+		"feature/add_thing",
+		"abc1234",
+		"0123456789abcdef0123456789abcdef01234567",
+	}
+	for _, ref := range valid {
+		if err := validateGitRef(ref); err != nil {
+			t.Errorf("validateGitRef(%q) unexpected error: %v", ref, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"v1 0",
+		"main\n",
+		"tab\there",
+		"../etc",
+		"release/../../main",
+		"/main",
+		"main/",
+		"a//b",
+		`back\slash`,
+		"-flag",
+		"ends.",
+		"branch.lock",
+		"HEAD@{1}",
+		"what?",
+		"glob*",
+		"a:b",
+		"enc%2F",
+		strings.Repeat("a", maxGitRefLength+1),
+	}
+	for _, ref := range invalid {
+		if err := validateGitRef(ref); err == nil {
+			t.Errorf("validateGitRef(%q) expected error", ref)
+		}
+	}
+}
+
+func TestMarketplaceInfo(t *testing.T) {
+	configDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	files := map[string]string{
+		filepath.Join(configDir, "settings.json"): `{"extraKnownMarketplaces": {
+			"team-market": {"source": {"source": "github", "repo": "acme/team-market"}}
+		}}`,
+		filepath.Join(configDir, "plugins", "installed_plugins.json"): `{"version": 2, "plugins": {
+			"alpha@team-market": [{"scope": "user", "version": "1.0.0"}],
+			"other@elsewhere": [{"scope": "user", "version": "1.0.0"}]
+		}}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := &marketplace.MarketplaceManifest{
+		Name:     "team-market",
+		Metadata: marketplace.MarketplaceMetadata{Description: "Team plugins"},
+		Plugins:  []marketplace.MarketplacePlugin{{Name: "alpha"}, {Name: "beta"}},
+	}
+	if err := marketplace.SaveToCache("team-market", manifest); err != nil {
+		t.Fatal(err)
+	}
+	if err := marketplace.SaveStatsToCache("team-market", &marketplace.GitHubStats{Stars: 12, Forks: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	marketplaceInfoProject = projectDir
+	defer func() { marketplaceInfoJSON = false; marketplaceInfoProject = "" }()
+
+	t.Run("cached marketplace as json", func(t *testing.T) {
+		marketplaceInfoJSON = true
+		output, err := captureStdout(t, func() error {
+			return runMarketplaceInfo(marketplaceInfoCmd, []string{"team-market"})
+		})
+		if err != nil {
+			t.Fatalf("runMarketplaceInfo failed: %v", err)
+		}
+
+		var info MarketplaceInfo
+		if err := json.Unmarshal([]byte(output), &info); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, output)
+		}
+		if info.Repo != "acme/team-market" || info.Description != "Team plugins" {
+			t.Errorf("unexpected repo/description: %+v", info)
+		}
+		if !info.Cached || info.PluginCount != 2 || info.InstalledPlugins != 1 {
+			t.Errorf("expected 2 cached plugins with 1 installed, got %+v", info)
+		}
+		if want := []string{"alpha", "beta"}; !reflect.DeepEqual(info.Plugins, want) {
+			t.Errorf("plugins = %v, want %v", info.Plugins, want)
+		}
+		if info.Stats == nil || info.Stats.Stars != 12 {
+			t.Errorf("expected cached stats, got %+v", info.Stats)
+		}
+	})
+
+	t.Run("cached marketplace as text", func(t *testing.T) {
+		marketplaceInfoJSON = false
+		output, err := captureStdout(t, func() error {
+			return runMarketplaceInfo(marketplaceInfoCmd, []string{"team-market"})
+		})
+		if err != nil {
+			t.Fatalf("runMarketplaceInfo failed: %v", err)
+		}
+		for _, want := range []string{"acme/team-market", "2 total, 1 installed", "Stars:       12", "  beta"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("unknown marketplace", func(t *testing.T) {
+		err := runMarketplaceInfo(marketplaceInfoCmd, []string{"no-such-market"})
+		if err == nil || !strings.Contains(err.Error(), "marketplace 'no-such-market' not found") {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+}
+
+func TestMarketplaceAdd_NameOverride(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	marketplaceAddScope = "user"
+	marketplaceAddProject = t.TempDir()
+	defer func() { marketplaceAddName = ""; marketplaceAddProject = "" }()
+
+	add := func(repo, name string) error {
+		marketplaceAddName = name
+		_, err := captureStdout(t, func() error {
+			return runMarketplaceAdd(marketplaceAddCmd, []string{repo})
+		})
+		return err
+	}
+
+	if err := add("acme/marketplace", "acme-plugins"); err != nil {
+		t.Fatalf("adding acme/marketplace failed: %v", err)
+	}
+	if err := add("globex/marketplace#v1.0.0", "globex-plugins"); err != nil {
+		t.Fatalf("adding globex/marketplace failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved struct {
+		ExtraKnownMarketplaces map[string]struct {
+			Source struct {
+				Repo string `json:"repo"`
+			} `json:"source"`
+		} `json:"extraKnownMarketplaces"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"acme-plugins": "acme/marketplace", "globex-plugins": "globex/marketplace#v1.0.0"}
+	if len(saved.ExtraKnownMarketplaces) != len(want) {
+		t.Fatalf("expected %d marketplaces, got %+v", len(want), saved.ExtraKnownMarketplaces)
+	}
+	for name, repo := range want {
+		if got := saved.ExtraKnownMarketplaces[name].Source.Repo; got != repo {
+			t.Errorf("%s: repo = %q, want %q", name, got, repo)
+		}
+	}
+
+	// Without --name the derived name is used
+	if err := add("acme/marketplace", ""); err != nil {
+		t.Fatalf("adding with the derived name failed: %v", err)
+	}
+	// ...and a different repo with the same derived name is refused
+	if err := add("globex/marketplace", ""); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected a name collision error, got %v", err)
+	}
+	// Re-adding the same repo (e.g. to change its pin) is allowed
+	if err := add("acme/marketplace#v2", ""); err != nil {
+		t.Errorf("re-adding the same repo should be allowed: %v", err)
+	}
+}
+
+func TestValidateMarketplaceKey(t *testing.T) {
+	valid := []string{"my-plugins", "acme_plugins", "plugins.v2"}
+	for _, name := range valid {
+		if err := validateMarketplaceKey(name); err != nil {
+			t.Errorf("validateMarketplaceKey(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := []string{"", ".", "..", ".hidden", "a/b", `a\b`, "a@b", "a#b", "a b", "tab\there"}
+	for _, name := range invalid {
+		if err := validateMarketplaceKey(name); err == nil {
+			t.Errorf("validateMarketplaceKey(%q) should fail", name)
+		}
+	}
+}
+
+func TestMarketplaceRemove_PrunePlugins(t *testing.T) {
+	configDir := setupInstallFixture(t)
+	projectDir := t.TempDir()
+
+	installScope = "user"
+	installProject = projectDir
+	defer func() { installProject = "" }()
+	for _, p := range []string{"alpha@claude-code-marketplace", "beta@claude-code-marketplace"} {
+		if _, err := captureStdout(t, func() error { return runInstall(installCmd, []string{p}) }); err != nil {
+			t.Fatalf("installing %s failed: %v", p, err)
+		}
+	}
+	if err := settings.AddMarketplace("claude-code-marketplace", settings.MarketplaceSource{
+		Source: "github", Repo: "ananddtyagi/cc-marketplace",
+	}, settings.ScopeUser, projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	marketplaceRemoveScope = "user"
+	marketplaceRemoveProject = projectDir
+	marketplaceRemoveYes = true
+	marketplaceRemovePrune = true
+	defer func() { marketplaceRemoveProject = ""; marketplaceRemoveYes = false; marketplaceRemovePrune = false }()
+
+	output, err := captureStdout(t, func() error {
+		return runMarketplaceRemove(marketplaceRemoveCmd, []string{"claude-code-marketplace"})
+	})
+	if err != nil {
+		t.Fatalf("runMarketplaceRemove failed: %v\n%s", err, output)
+	}
+	for _, want := range []string{
+		"2 installed plugin(s) from 'claude-code-marketplace' will be uninstalled",
+		"Removed marketplace 'claude-code-marketplace' from user scope",
+		"Removed alpha@claude-code-marketplace from user scope settings",
+		"Pruned alpha@claude-code-marketplace",
+		"Pruned beta@claude-code-marketplace",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed.Plugins) != 0 {
+		t.Errorf("registry entries should be removed, got %v", installed.Plugins)
+	}
+	userSettings, err := settings.LoadSettings(settings.ScopeUser, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(userSettings.EnabledPlugins) != 0 {
+		t.Errorf("settings entries should be removed, got %v", userSettings.EnabledPlugins)
+	}
+	for _, p := range []string{"alpha", "beta"} {
+		if _, err := os.Stat(filepath.Join(configDir, "plugins", "cache", "claude-code-marketplace", p)); !os.IsNotExist(err) {
+			t.Errorf("cache for %s should be deleted (stat err = %v)", p, err)
+		}
+	}
+}
+
+func TestMarketplaceRemove_PruneSkippedWhenListedElsewhere(t *testing.T) {
+	setupInstallFixture(t)
+	projectDir := t.TempDir()
+
+	installScope = "user"
+	installProject = projectDir
+	defer func() { installProject = "" }()
+	if _, err := captureStdout(t, func() error {
+		return runInstall(installCmd, []string{"alpha@claude-code-marketplace"})
+	}); err != nil {
+		t.Fatalf("runInstall failed: %v", err)
+	}
+	source := settings.MarketplaceSource{Source: "github", Repo: "ananddtyagi/cc-marketplace"}
+	for _, scope := range []settings.Scope{settings.ScopeUser, settings.ScopeProject} {
+		if err := settings.AddMarketplace("claude-code-marketplace", source, scope, projectDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	marketplaceRemoveScope = "user"
+	marketplaceRemoveProject = projectDir
+	marketplaceRemoveYes = true
+	marketplaceRemovePrune = true
+	defer func() { marketplaceRemoveProject = ""; marketplaceRemoveYes = false; marketplaceRemovePrune = false }()
+
+	output, err := captureStdout(t, func() error {
+		return runMarketplaceRemove(marketplaceRemoveCmd, []string{"claude-code-marketplace"})
+	})
+	if err != nil {
+		t.Fatalf("runMarketplaceRemove failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "still listed in project scope") {
+		t.Errorf("output should explain why pruning was skipped:\n%s", output)
+	}
+	if strings.Contains(output, "Pruned") {
+		t.Errorf("plugins should not be pruned while another scope lists the marketplace:\n%s", output)
+	}
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed.Plugins["alpha@claude-code-marketplace"]) == 0 {
+		t.Error("alpha should still be registered")
+	}
+}
+
+func TestMarketplaceRemove_KeepsPluginsByDefault(t *testing.T) {
+	setupInstallFixture(t)
+	projectDir := t.TempDir()
+
+	installScope = "user"
+	installProject = projectDir
+	defer func() { installProject = "" }()
+	if _, err := captureStdout(t, func() error {
+		return runInstall(installCmd, []string{"alpha@claude-code-marketplace"})
+	}); err != nil {
+		t.Fatalf("runInstall failed: %v", err)
+	}
+	if err := settings.AddMarketplace("claude-code-marketplace", settings.MarketplaceSource{
+		Source: "github", Repo: "ananddtyagi/cc-marketplace",
+	}, settings.ScopeUser, projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	marketplaceRemoveScope = "user"
+	marketplaceRemoveProject = projectDir
+	marketplaceRemoveYes = true
+	defer func() { marketplaceRemoveProject = ""; marketplaceRemoveYes = false }()
+
+	output, err := captureStdout(t, func() error {
+		return runMarketplaceRemove(marketplaceRemoveCmd, []string{"claude-code-marketplace"})
+	})
+	if err != nil {
+		t.Fatalf("runMarketplaceRemove failed: %v", err)
+	}
+	if strings.Contains(output, "Pruned") {
+		t.Errorf("plugins should not be pruned without --prune-plugins:\n%s", output)
+	}
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed.Plugins["alpha@claude-code-marketplace"]) == 0 {
+		t.Error("the installed plugin should be kept")
+	}
+}
+
+func TestMarketplaceList_NoCacheFetchFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	useFakeGitHub(t, server.URL)
+	t.Setenv("HOME", t.TempDir())
+
+	marketplaceListNoCache = true
+	defer func() { marketplaceListNoCache = false }()
+
+	err := runMarketplaceList(marketplaceListCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to fetch marketplaces") {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite a v1 install registry in the v2 format",
+	Long: `Rewrite installed_plugins.json in the v2 format if an older Claude Code
+version left it in the v1 format.
+
+plum reads v1 registries as they are and never rewrites them on read-only
+commands. The registry is converted the next time plum saves it (install,
+update, remove...), or right away with this command. The original file is
+kept as installed_plugins.json.v1.bak.
+
+Examples:
+  plum migrate`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		return err
+	}
+
+	return settings.WithLock(registryPath, func() error {
+		isV1, err := config.InstalledPluginsIsV1()
+		if err != nil {
+			return fmt.Errorf("failed to read install registry: %w", err)
+		}
+		if !isV1 {
+			fmt.Println("Install registry is already in the v2 format")
+			return nil
+		}
+
+		installed, err := config.LoadInstalledPlugins()
+		if err != nil {
+			return fmt.Errorf("failed to load install registry: %w", err)
+		}
+		if err := install.SaveRegistry(installed); err != nil {
+			return fmt.Errorf("failed to write install registry: %w", err)
+		}
+
+		fmt.Printf("Migrated %d plugin(s) in %s to the v2 format (original kept as %s.v1.bak)\n",
+			len(installed.Plugins), registryPath, registryPath)
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+)
+
+func TestMigrateCommand(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	registryPath := filepath.Join(configDir, "plugins", "installed_plugins.json")
+	if err := os.MkdirAll(filepath.Dir(registryPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	v1 := `{"version": 1, "plugins": {"alpha@mkt": {"version": "1.0.0", "installPath": "/cache/alpha"}}}`
+	if err := os.WriteFile(registryPath, []byte(v1), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read-only commands leave the v1 file alone
+	if _, err := captureStdout(t, func() error { return runPrune(pruneCmd, nil) }); err != nil {
+		t.Fatalf("runPrune failed: %v", err)
+	}
+	if data, _ := os.ReadFile(registryPath); string(data) != v1 {
+		t.Fatalf("read-only command rewrote the registry:\n%s", data)
+	}
+
+	output, err := captureStdout(t, func() error { return runMigrate(migrateCmd, nil) })
+	if err != nil {
+		t.Fatalf("runMigrate failed: %v", err)
+	}
+	if !strings.Contains(output, "Migrated 1 plugin(s)") {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if isV1, err := config.InstalledPluginsIsV1(); err != nil || isV1 {
+		t.Errorf("registry should be v2 after migrate, isV1 = %v, err = %v", isV1, err)
+	}
+	if backup, err := os.ReadFile(registryPath + ".v1.bak"); err != nil || string(backup) != v1 {
+		t.Errorf("v1 backup = %s, %v; want the original file", backup, err)
+	}
+
+	output, err = captureStdout(t, func() error { return runMigrate(migrateCmd, nil) })
+	if err != nil {
+		t.Fatalf("second runMigrate failed: %v", err)
+	}
+	if !strings.Contains(output, "already in the v2 format") {
+		t.Errorf("unexpected output: %s", output)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete orphaned plugin cache directories",
+	Long: `Delete cached plugin directories that have no entry in the install registry.
+
+These are the same directories 'plum doctor' reports as orphaned_cache. Only
+paths inside ~/.claude/plugins/cache are ever deleted.
+
+Examples:
+  plum prune
+  plum prune --dry-run
+  plum prune --json`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+var (
+	pruneDryRun bool
+	pruneJSON   bool
+)
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List orphaned directories without deleting them")
+	pruneCmd.Flags().BoolVar(&pruneJSON, "json", false, "Output as JSON")
+}
+
+// PruneResult lists the orphaned cache directories found by prune
+type PruneResult struct {
+	DryRun  bool     `json:"dryRun"`
+	Removed []string `json:"removed"` // Deleted paths, or paths that would be deleted with --dry-run
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		return fmt.Errorf("failed to get plugins directory: %w", err)
+	}
+	cacheDir := filepath.Join(pluginsDir, "cache")
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to load install registry: %w", err)
+	}
+
+	orphans, err := findOrphanedCaches(cacheDir, installed)
+	if err != nil {
+		return err
+	}
+
+	result := PruneResult{DryRun: pruneDryRun, Removed: make([]string, 0, len(orphans))}
+	var failed []string
+	for _, dir := range orphans {
+		if !pruneDryRun {
+			if err := os.RemoveAll(dir); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", dir, err))
+				continue
+			}
+		}
+		result.Removed = append(result.Removed, dir)
+	}
+
+	if pruneJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		outputPruneResult(result)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to remove some directories:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// findOrphanedCaches returns cached plugin directories with no registry entry.
+// Every path is checked to lie strictly inside cacheDir.
+func findOrphanedCaches(cacheDir string, installed *config.InstalledPluginsV2) ([]string, error) {
+	registered := registeredInstallPaths(installed)
+
+	pluginDirs, err := scanCachedPlugins(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan cache: %w", err)
+	}
+
+	var orphans []string
+	for _, dir := range pluginDirs {
+		if _, ok := registered[dir]; ok {
+			continue
+		}
+		if !isInsideDir(dir, cacheDir) {
+			return nil, fmt.Errorf("refusing to prune %s: outside cache directory %s", dir, cacheDir)
+		}
+		orphans = append(orphans, dir)
+	}
+	return orphans, nil
+}
+
+// isInsideDir reports whether path is strictly below dir
+func isInsideDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+func outputPruneResult(result PruneResult) {
+	if len(result.Removed) == 0 {
+		fmt.Println("No orphaned cache directories found")
+		return
+	}
+
+	verb := "Removed"
+	if result.DryRun {
+		verb = "Would remove"
+	}
+	for _, dir := range result.Removed {
+		fmt.Printf("%s %s\n", verb, shortenPath(dir))
+	}
+	noun := "directories"
+	if len(result.Removed) == 1 {
+		noun = "directory"
+	}
+	fmt.Printf("\n%s %d orphaned cache %s\n", verb, len(result.Removed), noun)
+}
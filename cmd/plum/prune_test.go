@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+)
+
+// setupPruneFixture creates a registered plugin and an orphan in the cache
+func setupPruneFixture(t *testing.T) (registeredDir, orphanDir string) {
+	t.Helper()
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	cacheDir := filepath.Join(configDir, "plugins", "cache")
+	registeredDir = filepath.Join(cacheDir, "mkt", "kept")
+	orphanDir = filepath.Join(cacheDir, "mkt", "orphan")
+	writeFixturePlugin(t, registeredDir, `{"name": "kept"}`, "commands/a.md")
+	writeFixturePlugin(t, orphanDir, `{"name": "orphan"}`, "commands/b.md")
+
+	installed := config.InstalledPluginsV2{
+		Version: 2,
+		Plugins: map[string][]config.PluginInstall{
+			"kept@mkt": {{Scope: "user", InstallPath: registeredDir}},
+		},
+	}
+	data, err := json.Marshal(installed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "plugins", "installed_plugins.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return registeredDir, orphanDir
+}
+
+func TestPruneCommand_RemovesOnlyOrphans(t *testing.T) {
+	registeredDir, orphanDir := setupPruneFixture(t)
+
+	pruneDryRun = false
+	pruneJSON = false
+	output, err := captureStdout(t, func() error { return runPrune(pruneCmd, nil) })
+	if err != nil {
+		t.Fatalf("runPrune failed: %v", err)
+	}
+
+	if _, err := os.Stat(orphanDir); !os.IsNotExist(err) {
+		t.Errorf("orphan should be removed (stat err = %v)", err)
+	}
+	if _, err := os.Stat(registeredDir); err != nil {
+		t.Errorf("registered plugin should be kept: %v", err)
+	}
+	if !strings.Contains(output, "Removed") || !strings.Contains(output, filepath.Join("mkt", "orphan")) {
+		t.Errorf("output should list the removed path:\n%s", output)
+	}
+}
+
+func TestPruneCommand_KeepsNestedPlugins(t *testing.T) {
+	registeredDir, orphanDir := setupPruneFixture(t)
+
+	// Example and vendored plugins shipped inside an installed plugin,
+	// including one that sorts before .claude-plugin
+	nested := []string{
+		filepath.Join(registeredDir, "examples", "demo"),
+		filepath.Join(registeredDir, "-vendored"),
+	}
+	for _, dir := range nested {
+		writeFixturePlugin(t, dir, `{"name": "nested"}`, "commands/c.md")
+	}
+
+	pruneDryRun = false
+	pruneJSON = false
+	if _, err := captureStdout(t, func() error { return runPrune(pruneCmd, nil) }); err != nil {
+		t.Fatalf("runPrune failed: %v", err)
+	}
+
+	for _, dir := range nested {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("nested plugin %s should be kept: %v", dir, err)
+		}
+	}
+	if _, err := os.Stat(orphanDir); !os.IsNotExist(err) {
+		t.Errorf("orphan should still be removed (stat err = %v)", err)
+	}
+}
+
+func TestPruneCommand_DryRunJSON(t *testing.T) {
+	_, orphanDir := setupPruneFixture(t)
+
+	pruneDryRun = true
+	pruneJSON = true
+	defer func() { pruneDryRun = false; pruneJSON = false }()
+
+	output, err := captureStdout(t, func() error { return runPrune(pruneCmd, nil) })
+	if err != nil {
+		t.Fatalf("runPrune failed: %v", err)
+	}
+
+	var result PruneResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	if !result.DryRun || len(result.Removed) != 1 || result.Removed[0] != orphanDir {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if _, err := os.Stat(orphanDir); err != nil {
+		t.Errorf("dry run should not delete the orphan: %v", err)
+	}
+}
+
+func TestIsInsideDir(t *testing.T) {
+	dir := filepath.Join("home", "user", ".claude", "plugins", "cache")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(dir, "mkt", "plugin"), true},
+		{dir, false},
+		{filepath.Join(dir, ".."), false},
+		{filepath.Join(dir, "..", "settings"), false},
+		{filepath.Join(dir, "..cache", "plugin"), true},
+	}
+
+	for _, tt := range tests {
+		if got := isInsideDir(tt.path, dir); got != tt.want {
+			t.Errorf("isInsideDir(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
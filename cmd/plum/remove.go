@@ -1,25 +1,27 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/settings"
 	"github.com/spf13/cobra"
 )
 
 var removeCmd = &cobra.Command{
 	Use:     "remove <plugin>",
-	Aliases: []string{"uninstall", "rm"},
+	Aliases: []string{"rm"},
 	Short:   "Remove a plugin",
-	Long: `Remove/uninstall a plugin.
+	Long: `Remove a plugin.
 
 This removes the plugin from the specified scope's settings.json and optionally
-deletes the cached plugin files.
+deletes the cached plugin files. Use 'plum uninstall' to always delete the
+cache and install registry entry as well.
 
 The plugin can be specified as:
   - plugin-name (uses first matching installed plugin)
@@ -160,12 +162,10 @@ func removePluginFromScope(fullName string, scope settings.Scope, projectPath st
 // deletePluginCache removes the cached plugin files
 func deletePluginCache(fullName string) error {
 	// Parse plugin@marketplace
-	parts := strings.SplitN(fullName, "@", 2)
-	if len(parts) != 2 {
+	pluginName, marketplace, ok := plugin.ParseFullName(fullName)
+	if !ok {
 		return fmt.Errorf("invalid plugin name format: %s", fullName)
 	}
-	pluginName := parts[0]
-	marketplace := parts[1]
 
 	// Get cache directory
 	pluginsDir, err := config.ClaudePluginsDir()
@@ -202,48 +202,60 @@ func unregisterInstalledPlugin(fullName string) error {
 		delete(installed.Plugins, fullName)
 
 		// Write back to file
-		path, err := config.InstalledPluginsPath()
-		if err != nil {
-			return err
-		}
+		return install.SaveRegistry(installed)
+	})
+}
 
-		// Ensure directory exists
-		dir := filepath.Dir(path)
-		// #nosec G301 -- Plugin directory needs to be readable by Claude Code
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
+// unregisterPluginInstall removes the plugin's install registry entry for one
+// scope, leaving its installs in other scopes and projects in place. It
+// reports how many installs remain; the plugin's key is deleted when none do.
+func unregisterPluginInstall(fullName string, scope settings.Scope, projectPath string) (int, error) {
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		return 0, err
+	}
 
-		data, err := json.MarshalIndent(installed, "", "  ")
+	var remaining int
+	err = settings.WithLock(registryPath, func() error {
+		installed, err := config.LoadInstalledPlugins()
 		if err != nil {
 			return err
 		}
 
-		// Write atomically
-		tmpFile, err := os.CreateTemp(dir, ".installed-*.json")
+		kept, err := installsExcept(installed.Plugins[fullName], scope, projectPath)
 		if err != nil {
 			return err
 		}
-		tmpPath := tmpFile.Name()
-		defer func() { _ = os.Remove(tmpPath) }()
-
-		if _, err := tmpFile.Write(data); err != nil {
-			_ = tmpFile.Close()
-			return err
-		}
-		if _, err := tmpFile.WriteString("\n"); err != nil {
-			_ = tmpFile.Close()
-			return err
-		}
-		if err := tmpFile.Close(); err != nil {
-			return err
+		remaining = len(kept)
+		if remaining == 0 {
+			delete(installed.Plugins, fullName)
+		} else {
+			installed.Plugins[fullName] = kept
 		}
+		return install.SaveRegistry(installed)
+	})
+	return remaining, err
+}
 
-		// #nosec G302 -- Config files need to be readable by Claude Code
-		if err := os.Chmod(tmpPath, 0644); err != nil {
-			return err
+// installsExcept returns the installs that are not the one for scope. Project
+// and local installs only match the same project; an empty projectPath is the
+// current directory, as when the install was registered.
+func installsExcept(installs []config.PluginInstall, scope settings.Scope, projectPath string) ([]config.PluginInstall, error) {
+	perProject := scope == settings.ScopeProject || scope == settings.ScopeLocal
+	if perProject && projectPath == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
 		}
+		projectPath = cwd
+	}
 
-		return settings.AtomicRename(tmpPath, path)
-	})
+	var kept []config.PluginInstall
+	for _, e := range installs {
+		if e.Scope == scope.String() && (!perProject || filepath.Clean(e.ProjectPath) == filepath.Clean(projectPath)) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept, nil
 }
@@ -35,22 +35,18 @@ func TestRemoveCommandStructure(t *testing.T) {
 }
 
 func TestRemoveCommandAliases(t *testing.T) {
-	// Remove should have aliases
-	hasUninstall := false
+	// Remove should have aliases; "uninstall" is its own command
 	hasRm := false
 
 	for _, alias := range removeCmd.Aliases {
 		if alias == "uninstall" {
-			hasUninstall = true
+			t.Error("'uninstall' should not alias remove; it is a separate command")
 		}
 		if alias == "rm" {
 			hasRm = true
 		}
 	}
 
-	if !hasUninstall {
-		t.Error("remove command should have 'uninstall' alias")
-	}
 	if !hasRm {
 		t.Error("remove command should have 'rm' alias")
 	}
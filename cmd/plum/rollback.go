@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <plugin@marketplace>",
+	Short: "Restore the version a plugin had before its last update",
+	Long: `Restore the version a plugin had before its last update.
+
+Before replacing a plugin's files, 'plum update' moves the old cache to
+~/.claude/plugins/cache/.backup/<marketplace>/<plugin>@<version> and saves
+the plugin's install registry entries beside it. Rollback puts that backup
+back in place and restores those entries, including the commit the old files
+came from. Only the most recent backup is kept for each plugin.
+
+Examples:
+  plum rollback ralph-wiggum@claude-code-plugins`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRollback,
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+}
+
+// backupDirName is the cache subdirectory holding pre-update plugin copies
+const backupDirName = ".backup"
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	pluginName, marketplaceName, version, err := parsePluginArg(args[0])
+	if err != nil {
+		return err
+	}
+	if marketplaceName == "" || version != "" {
+		return fmt.Errorf("plugin must be specified as plugin@marketplace: %s", args[0])
+	}
+	fullName := pluginName + "@" + marketplaceName
+
+	cachePath, err := install.CacheDir(marketplaceName, pluginName)
+	if err != nil {
+		return err
+	}
+
+	backupPath, backupVersion, err := findPluginBackup(marketplaceName, pluginName)
+	if err != nil {
+		return err
+	}
+	if backupPath == "" {
+		return fmt.Errorf("no backup found for %s", fullName)
+	}
+
+	// Read the saved registry entries before the backup is consumed
+	record, err := loadBackupRecord(backupPath)
+	if err != nil {
+		return err
+	}
+
+	if err := restorePluginBackup(backupPath, cachePath); err != nil {
+		return err
+	}
+	if record != nil {
+		err = restoreInstalls(fullName, record)
+	} else {
+		// Backups made before records were saved only know their version
+		err = setInstalledVersion(fullName, backupVersion)
+	}
+	if err != nil {
+		return fmt.Errorf("restored files but failed to update install registry: %w", err)
+	}
+
+	fmt.Printf("Rolled back %s to v%s\n", fullName, backupVersion)
+	return nil
+}
+
+// pluginBackupRoot returns the directory holding a marketplace's plugin backups
+// Path: ~/.claude/plugins/cache/.backup/<marketplace>/
+func pluginBackupRoot(marketplaceName string) (string, error) {
+	if err := install.ValidatePathComponent(marketplaceName, "marketplace name"); err != nil {
+		return "", err
+	}
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pluginsDir, "cache", backupDirName, marketplaceName), nil
+}
+
+// backupPluginCache moves a plugin's cache directory aside before an update,
+// replacing any earlier backup of the same plugin, and saves the plugin's
+// registry entries beside it. Returns the backup path, or "" if there was no
+// cache to back up.
+func backupPluginCache(cachePath, marketplaceName, pluginName, version string) (string, error) {
+	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	if version == "" {
+		version = "unknown"
+	}
+	if err := install.ValidatePathComponent(version, "version"); err != nil {
+		return "", err
+	}
+
+	root, err := pluginBackupRoot(marketplaceName)
+	if err != nil {
+		return "", err
+	}
+	// #nosec G301 -- Plugin cache needs to be readable by Claude Code
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// Keep at most one backup per plugin
+	old, err := pluginBackups(root, pluginName)
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range old {
+		if err := os.RemoveAll(dir); err != nil {
+			return "", fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		_ = os.Remove(backupRecordPath(dir))
+	}
+
+	backupPath := filepath.Join(root, pluginName+"@"+version)
+	if err := saveBackupRecord(backupPath, pluginName+"@"+marketplaceName); err != nil {
+		return "", err
+	}
+	if err := os.Rename(cachePath, backupPath); err != nil {
+		_ = os.Remove(backupRecordPath(backupPath))
+		return "", fmt.Errorf("failed to back up plugin cache: %w", err)
+	}
+	return backupPath, nil
+}
+
+// backupRecordPath returns the file holding a backup's registry entries
+func backupRecordPath(backupPath string) string {
+	return backupPath + ".json"
+}
+
+// saveBackupRecord writes the plugin's current registry entries beside its
+// backup, so rollback restores the version, ref and commit they record
+func saveBackupRecord(backupPath, fullName string) error {
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to load install registry: %w", err)
+	}
+	data, err := json.MarshalIndent(installed.Plugins[fullName], "", "  ")
+	if err != nil {
+		return err
+	}
+	// #nosec G306 -- Same permissions as the install registry
+	if err := os.WriteFile(backupRecordPath(backupPath), data, 0644); err != nil {
+		return fmt.Errorf("failed to save backup record: %w", err)
+	}
+	return nil
+}
+
+// loadBackupRecord reads a backup's saved registry entries. Returns nil when
+// the backup has no record.
+func loadBackupRecord(backupPath string) ([]config.PluginInstall, error) {
+	// #nosec G304 -- path is derived from the plugin cache directory
+	data, err := os.ReadFile(backupRecordPath(backupPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup record: %w", err)
+	}
+	var installs []config.PluginInstall
+	if err := json.Unmarshal(data, &installs); err != nil {
+		return nil, fmt.Errorf("invalid backup record %s: %w", backupRecordPath(backupPath), err)
+	}
+	return installs, nil
+}
+
+// findPluginBackup returns the most recent backup of a plugin and the version
+// it holds, or "" if there is none
+func findPluginBackup(marketplaceName, pluginName string) (path, version string, err error) {
+	root, err := pluginBackupRoot(marketplaceName)
+	if err != nil {
+		return "", "", err
+	}
+	backups, err := pluginBackups(root, pluginName)
+	if err != nil {
+		return "", "", err
+	}
+
+	var newest time.Time
+	for _, dir := range backups {
+		info, err := os.Stat(dir)
+		if err != nil {
+			continue
+		}
+		if path == "" || info.ModTime().After(newest) {
+			path, newest = dir, info.ModTime()
+		}
+	}
+	if path == "" {
+		return "", "", nil
+	}
+	return path, strings.TrimPrefix(filepath.Base(path), pluginName+"@"), nil
+}
+
+// pluginBackups lists the backup directories for a plugin under root
+func pluginBackups(root, pluginName string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), pluginName+"@") {
+			dirs = append(dirs, filepath.Join(root, e.Name()))
+		}
+	}
+	return dirs, nil
+}
+
+// restorePluginBackup replaces the plugin's cache directory with a backup
+func restorePluginBackup(backupPath, cachePath string) error {
+	if err := os.RemoveAll(cachePath); err != nil {
+		return fmt.Errorf("failed to remove current plugin cache: %w", err)
+	}
+	// #nosec G301 -- Plugin cache needs to be readable by Claude Code
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.Rename(backupPath, cachePath); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	_ = os.Remove(backupRecordPath(backupPath))
+	return nil
+}
+
+// restoreInstalls puts saved registry entries back, replacing the current
+// entry of the same scope and project. Installs removed since the backup stay
+// removed.
+func restoreInstalls(fullName string, saved []config.PluginInstall) error {
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		return err
+	}
+
+	return settings.WithLock(registryPath, func() error {
+		installed, err := config.LoadInstalledPlugins()
+		if err != nil {
+			return err
+		}
+
+		installs := installed.Plugins[fullName]
+		if len(installs) == 0 {
+			return fmt.Errorf("%s is not in the install registry", fullName)
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		for i, current := range installs {
+			for _, old := range saved {
+				if old.Scope == current.Scope && old.ProjectPath == current.ProjectPath {
+					installs[i] = old
+					installs[i].LastUpdated = now
+					break
+				}
+			}
+		}
+
+		return install.SaveRegistry(installed)
+	})
+}
+
+// setInstalledVersion records version on every registry install of a plugin
+func setInstalledVersion(fullName, version string) error {
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		return err
+	}
+
+	return settings.WithLock(registryPath, func() error {
+		installed, err := config.LoadInstalledPlugins()
+		if err != nil {
+			return err
+		}
+
+		installs := installed.Plugins[fullName]
+		if len(installs) == 0 {
+			return fmt.Errorf("%s is not in the install registry", fullName)
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		for i := range installs {
+			installs[i].Version = version
+			installs[i].LastUpdated = now
+		}
+
+		return install.SaveRegistry(installed)
+	})
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+func TestRollbackCommandRegistered(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"rollback"})
+	if err != nil || cmd != rollbackCmd {
+		t.Fatalf("rollback command not registered (err = %v)", err)
+	}
+}
+
+func TestUpdateThenRollback(t *testing.T) {
+	configDir := setupInstallFixture(t)
+	const fullName = "alpha@claude-code-marketplace"
+
+	settingsJSON := `{"enabledPlugins": {"` + fullName + `": true}}`
+	if err := os.WriteFile(filepath.Join(configDir, "settings.json"), []byte(settingsJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Old version in the cache and registry
+	cachePath := filepath.Join(configDir, "plugins", "cache", "claude-code-marketplace", "alpha")
+	writeFixturePlugin(t, cachePath, `{"name": "alpha", "version": "0.9.0", "commands": ["commands/old.md"]}`, "commands/old.md")
+	const oldCommit = "fedcba9876543210fedcba9876543210fedcba98"
+	if err := install.Register(fullName, cachePath, "0.9.0", "", oldCommit, settings.ScopeUser, ""); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshotFiles(t, cachePath)
+
+	opts := updateOptions{Project: t.TempDir()}
+	output, err := captureStdout(t, func() error { return performUpdate(updateCmd, nil, opts) })
+	if err != nil {
+		t.Fatalf("update failed: %v\n%s", err, output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cachePath, ".claude-plugin", "plugin.json"))
+	if err != nil || !strings.Contains(string(data), "commands/a.md") {
+		t.Fatalf("update should download the new plugin.json, got %q (err = %v)", data, err)
+	}
+	if v := installedVersion(t, fullName); v != "1.0.0" {
+		t.Errorf("registry version after update = %q, want 1.0.0", v)
+	}
+	backupPath := filepath.Join(configDir, "plugins", "cache", backupDirName, "claude-code-marketplace", "alpha@0.9.0")
+	if _, err := os.Stat(backupPath); err != nil {
+		t.Fatalf("update should back up the old cache: %v", err)
+	}
+
+	output, err = captureStdout(t, func() error { return runRollback(rollbackCmd, []string{fullName}) })
+	if err != nil {
+		t.Fatalf("rollback failed: %v", err)
+	}
+	if !strings.Contains(output, "Rolled back "+fullName+" to v0.9.0") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	after := snapshotFiles(t, cachePath)
+	if len(after) != len(before) {
+		t.Errorf("restored cache has %d files, want %d", len(after), len(before))
+	}
+	for path, content := range before {
+		if after[path] != content {
+			t.Errorf("restored %s = %q, want %q", path, after[path], content)
+		}
+	}
+	if v := installedVersion(t, fullName); v != "0.9.0" {
+		t.Errorf("registry version after rollback = %q, want 0.9.0", v)
+	}
+	// The commit is the old files', so freeze locks what is installed
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := installed.Plugins[fullName][0].GitCommitSha; got != oldCommit {
+		t.Errorf("registry commit after rollback = %q, want %q", got, oldCommit)
+	}
+	for _, path := range []string{backupPath, backupRecordPath(backupPath)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be consumed by rollback (stat err = %v)", path, err)
+		}
+	}
+}
+
+func TestBackupPluginCache_KeepsOneBackup(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	cachePath := filepath.Join(configDir, "plugins", "cache", "mkt", "alpha")
+
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		writeFixturePlugin(t, cachePath, `{"name": "alpha", "version": "`+version+`"}`)
+		if _, err := backupPluginCache(cachePath, "mkt", "alpha", version); err != nil {
+			t.Fatalf("backupPluginCache(%s) failed: %v", version, err)
+		}
+	}
+
+	root := filepath.Join(configDir, "plugins", "cache", backupDirName, "mkt")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The backup and its registry record
+	if len(entries) != 2 || entries[0].Name() != "alpha@1.1.0" || entries[1].Name() != "alpha@1.1.0.json" {
+		t.Errorf("expected only alpha@1.1.0 and its record to be kept, got %v", entries)
+	}
+
+	path, version, err := findPluginBackup("mkt", "alpha")
+	if err != nil || version != "1.1.0" || path != filepath.Join(root, "alpha@1.1.0") {
+		t.Errorf("findPluginBackup = %q, %q, %v", path, version, err)
+	}
+}
+
+func TestRollback_NoBackup(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+	err := runRollback(rollbackCmd, []string{"alpha@mkt"})
+	if err == nil || !strings.Contains(err.Error(), "no backup found") {
+		t.Errorf("expected no backup error, got %v", err)
+	}
+}
+
+func TestScanCachedPlugins_SkipsBackups(t *testing.T) {
+	cacheDir := t.TempDir()
+	writeFixturePlugin(t, filepath.Join(cacheDir, "mkt", "alpha"), `{"name": "alpha"}`)
+	writeFixturePlugin(t, filepath.Join(cacheDir, backupDirName, "mkt", "alpha@0.9.0"), `{"name": "alpha"}`)
+
+	dirs, err := scanCachedPlugins(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 1 || dirs[0] != filepath.Join(cacheDir, "mkt", "alpha") {
+		t.Errorf("scanCachedPlugins = %v, want only the installed plugin", dirs)
+	}
+}
+
+// installedVersion returns the registry version of a plugin's first install
+func installedVersion(t *testing.T, fullName string) string {
+	t.Helper()
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installs := installed.Plugins[fullName]
+	if len(installs) == 0 {
+		t.Fatalf("%s not in registry", fullName)
+	}
+	return installs[0].Version
+}
@@ -5,10 +5,14 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/itsdevcoffee/plum/internal/config"
 	"github.com/itsdevcoffee/plum/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+// plainTUI renders the TUI without colors or box drawing (--plain)
+var plainTUI bool
+
 var rootCmd = &cobra.Command{
 	Use:   "plum",
 	Short: "Plugin manager for Claude Code",
@@ -29,23 +33,39 @@ func init() {
 
 	// Customize version template to show full version info
 	rootCmd.SetVersionTemplate(formatVersion() + "\n")
+
+	rootCmd.Flags().BoolVar(&plainTUI, "plain", false, "Render without colors or box drawing (also enabled by NO_COLOR)")
 }
 
 // Execute runs the root command
 func Execute() {
 	// Cobra prints errors to stderr automatically, just handle exit code
 	if err := rootCmd.Execute(); err != nil {
+		if guidance := config.SetupGuidance(err); guidance != "" {
+			fmt.Fprintln(os.Stderr, "\n"+guidance)
+		}
 		os.Exit(1)
 	}
 }
 
 // runTUI launches the Bubbletea TUI
 func runTUI() {
-	p := tea.NewProgram(
-		ui.NewModel(),
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	opts := []tea.ProgramOption{tea.WithMouseCellMotion()}
+	switch currentTerminalSupport() {
+	case terminalNonInteractive:
+		fmt.Fprintln(os.Stderr, "plum's interactive browser needs a terminal (stdin and stdout must be a TTY and TERM not \"dumb\").")
+		fmt.Fprintln(os.Stderr, "Use the CLI subcommands instead, e.g. 'plum search <query>', 'plum list' or 'plum install <plugin>'.")
+		fmt.Fprintln(os.Stderr, "Run 'plum --help' for the full list.")
+		os.Exit(1)
+	case terminalFull:
+		opts = append(opts, tea.WithAltScreen())
+	}
+
+	if plainTUI || ui.NoColorRequested() {
+		ui.SetPlain(true)
+	}
+
+	p := tea.NewProgram(ui.NewModel(), opts...)
 
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running plum: %v\n", err)
@@ -18,13 +18,18 @@ var searchCmd = &cobra.Command{
 	Long: `Search for plugins across all registered and discoverable marketplaces.
 
 Uses fuzzy matching on plugin names, descriptions, and keywords.
-Results are ranked by relevance.
+Results are ranked by relevance with the same scoring as the interactive
+browser, so both list plugins in the same order. A query wrapped in slashes
+is a case-insensitive regular expression matched against name and
+description; results are ordered by where the match starts.
 
 Examples:
   plum search memory
   plum search "code review"
   plum search formatting --marketplace=claude-code-plugins
-  plum search --json memory`,
+  plum search --json memory
+  plum search '/^git-.*hooks/'
+  plum search memory --no-cache`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSearch,
 }
@@ -34,6 +39,7 @@ var (
 	searchMarketplace string
 	searchCategory    string
 	searchLimit       int
+	searchNoCache     bool
 )
 
 func init() {
@@ -43,6 +49,7 @@ func init() {
 	searchCmd.Flags().StringVarP(&searchMarketplace, "marketplace", "m", "", "Filter by marketplace")
 	searchCmd.Flags().StringVarP(&searchCategory, "category", "c", "", "Filter by category")
 	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Maximum number of results")
+	searchCmd.Flags().BoolVar(&searchNoCache, "no-cache", false, "Fetch marketplace data fresh from GitHub instead of using the cache")
 }
 
 // SearchResult represents a search result
@@ -60,9 +67,14 @@ type SearchResult struct {
 
 func runSearch(cmd *cobra.Command, args []string) error {
 	query := args[0]
+	if pattern, ok := search.RegexQuery(query); ok {
+		if _, err := search.CompileRegex(pattern); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
 
 	// Load all plugins
-	plugins, err := config.LoadAllPlugins()
+	plugins, err := loadMarketplacePlugins(config.LoadOptions{NoCache: searchNoCache})
 	if err != nil {
 		return fmt.Errorf("failed to load plugins: %w", err)
 	}
@@ -118,7 +130,7 @@ func outputSearchTable(results []SearchResult, query string) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
 	// Header
-	_, _ = fmt.Fprintln(w, "NAME\tMARKETPLACE\tDESCRIPTION")
+	_, _ = fmt.Fprintln(w, "NAME\tSCORE\tMARKETPLACE\tDESCRIPTION")
 
 	// Track if we have any special indicators to explain in legend
 	hasInstalled := false
@@ -152,7 +164,7 @@ func outputSearchTable(results []SearchResult, query string) error {
 			hasIncomplete = true
 		}
 
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", name, r.Marketplace, desc)
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", name, r.Score, r.Marketplace, desc)
 	}
 
 	// Print legend
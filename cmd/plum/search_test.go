@@ -1,7 +1,12 @@
 package main
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/search"
 )
 
 func TestSearchCommand_Structure(t *testing.T) {
@@ -75,3 +80,51 @@ func TestSearchResult_Fields(t *testing.T) {
 		t.Error("Score field missing")
 	}
 }
+
+func TestSearchCommand_MatchesSearchRanking(t *testing.T) {
+	plugins := []plugin.Plugin{
+		{Name: "memory-bank", Marketplace: "mkt-one", Description: "Persistent memory"},
+		{Name: "notes", Marketplace: "mkt-two", Description: "Keeps memory of notes", Keywords: []string{"memory"}},
+		{Name: "memory", Marketplace: "mkt-two", Description: "Exact match", Installed: true},
+		{Name: "docker", Marketplace: "mkt-one", Description: "Containers"},
+	}
+	useMarketplacePlugins(t, plugins)
+
+	searchJSON = true
+	searchLimit = 20
+	defer func() { searchJSON = false }()
+
+	output, err := captureStdout(t, func() error { return runSearch(searchCmd, []string{"memory"}) })
+	if err != nil {
+		t.Fatalf("runSearch failed: %v", err)
+	}
+	var results []SearchResult
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+
+	want := search.Search("memory", plugins)
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Name != want[i].Plugin.Name || r.Score != want[i].Score {
+			t.Errorf("results[%d] = %s (%d), want %s (%d)", i, r.Name, r.Score, want[i].Plugin.Name, want[i].Score)
+		}
+	}
+
+	// --limit keeps the top of the same ranking
+	searchLimit = 1
+	defer func() { searchLimit = 20 }()
+	searchJSON = false
+	output, err = captureStdout(t, func() error { return runSearch(searchCmd, []string{"memory"}) })
+	if err != nil {
+		t.Fatalf("runSearch failed: %v", err)
+	}
+	if !strings.Contains(output, "NAME") || !strings.Contains(output, "SCORE") {
+		t.Errorf("table should have NAME and SCORE columns:\n%s", output)
+	}
+	if !strings.Contains(output, "Found 1 plugin(s)") || !strings.Contains(output, want[0].Plugin.Name) {
+		t.Errorf("limited table should only list %s:\n%s", want[0].Plugin.Name, output)
+	}
+}
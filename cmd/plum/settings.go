@@ -0,0 +1,386 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Inspect Claude Code settings",
+	Long: `Inspect Claude Code settings across scopes.
+
+Available subcommands:
+  dump     Print the merged effective configuration
+  export   Write plugin and marketplace settings to a bundle file
+  import   Apply a bundle file written by export`,
+}
+
+var settingsDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the merged effective configuration",
+	Long: `Print the effective plugin and marketplace configuration after merging
+all settings scopes.
+
+Scopes are applied in precedence order: managed > local > project > user. For
+each plugin, dump shows its effective state, the scope that decided it, and any
+lower-precedence scopes whose value it overrides. Marketplaces are listed with
+every scope that defines them, the first one winning.
+
+Examples:
+  plum settings dump
+  plum settings dump --json
+  plum settings dump --project=/path/to/project`,
+	Args: cobra.NoArgs,
+	RunE: runSettingsDump,
+}
+
+var settingsExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Write plugin and marketplace settings to a bundle file",
+	Long: `Write the settings plum manages - enabledPlugins and extraKnownMarketplaces -
+from every writable scope (user, project, local) to a portable JSON bundle.
+
+Other settings such as permissions, hooks, and model are not exported. Use
+'plum settings import' to apply the bundle on another machine.
+
+Examples:
+  plum settings export plum-settings.json
+  plum settings export plum-settings.json --project=/path/to/project`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSettingsExport,
+}
+
+var settingsImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Apply a bundle file written by export",
+	Long: `Apply a bundle written by 'plum settings export'.
+
+Each scope in the bundle is merged into the matching settings file: plugin
+states and marketplaces from the bundle are added or overwritten, entries not in
+the bundle are kept, and fields plum does not manage are left untouched.
+
+Examples:
+  plum settings import plum-settings.json
+  plum settings import plum-settings.json --project=/path/to/project`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSettingsImport,
+}
+
+var (
+	settingsDumpJSON      bool
+	settingsDumpProject   string
+	settingsBundleProject string
+)
+
+func init() {
+	rootCmd.AddCommand(settingsCmd)
+	settingsCmd.AddCommand(settingsDumpCmd)
+	settingsCmd.AddCommand(settingsExportCmd)
+	settingsCmd.AddCommand(settingsImportCmd)
+
+	settingsDumpCmd.Flags().BoolVar(&settingsDumpJSON, "json", false, "Output as JSON")
+	settingsDumpCmd.Flags().StringVar(&settingsDumpProject, "project", "", "Project path (default: current directory)")
+	settingsExportCmd.Flags().StringVar(&settingsBundleProject, "project", "", "Project path (default: current directory)")
+	settingsImportCmd.Flags().StringVar(&settingsBundleProject, "project", "", "Project path (default: current directory)")
+}
+
+// SettingsDump is the merged effective configuration
+type SettingsDump struct {
+	Plugins      []PluginSettingsDump      `json:"plugins"`
+	Marketplaces []MarketplaceSettingsDump `json:"marketplaces"`
+}
+
+// PluginSettingsDump is a plugin's effective state and the scope that set it
+type PluginSettingsDump struct {
+	Name       string              `json:"name"`
+	Enabled    bool                `json:"enabled"`
+	Scope      string              `json:"scope"`
+	Overridden []ScopedPluginValue `json:"overridden,omitempty"` // Lower-precedence values, highest first
+}
+
+// ScopedPluginValue is a plugin's value in one scope
+type ScopedPluginValue struct {
+	Scope   string `json:"scope"`
+	Enabled bool   `json:"enabled"`
+}
+
+// MarketplaceSettingsDump is a marketplace from extraKnownMarketplaces
+type MarketplaceSettingsDump struct {
+	Name   string   `json:"name"`
+	Source string   `json:"source"`
+	Repo   string   `json:"repo"`
+	Scopes []string `json:"scopes"` // Scopes defining it, highest precedence (the winner) first
+}
+
+func runSettingsDump(cmd *cobra.Command, args []string) error {
+	dump, err := buildSettingsDump(settingsDumpProject)
+	if err != nil {
+		return err
+	}
+
+	if settingsDumpJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(dump)
+	}
+	return outputSettingsDump(os.Stdout, dump)
+}
+
+// buildSettingsDump merges all scopes. The winners come from the settings
+// package's precedence rules; each scope is also read on its own to report
+// what the winners override.
+func buildSettingsDump(projectPath string) (*SettingsDump, error) {
+	states, err := settings.MergedPluginStates(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	marketplaces, err := settings.AllMarketplaces(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	// Unreadable scopes are skipped, as in the merge itself
+	perScope := make(map[settings.Scope]*settings.Settings)
+	for _, scope := range settings.AllScopes() {
+		if s, err := settings.LoadSettings(scope, projectPath); err == nil {
+			perScope[scope] = s
+		}
+	}
+
+	dump := &SettingsDump{
+		Plugins:      make([]PluginSettingsDump, 0, len(states)),
+		Marketplaces: make([]MarketplaceSettingsDump, 0, len(marketplaces)),
+	}
+
+	for _, state := range states {
+		p := PluginSettingsDump{
+			Name:    state.FullName,
+			Enabled: state.Enabled,
+			Scope:   state.Scope.String(),
+		}
+		for _, scope := range settings.AllScopes() {
+			s := perScope[scope]
+			if scope == state.Scope || s == nil {
+				continue
+			}
+			// The winner is the highest scope that has it, so the rest are lower
+			if enabled, ok := s.EnabledPlugins[state.FullName]; ok {
+				p.Overridden = append(p.Overridden, ScopedPluginValue{Scope: scope.String(), Enabled: enabled})
+			}
+		}
+		dump.Plugins = append(dump.Plugins, p)
+	}
+	sort.Slice(dump.Plugins, func(i, j int) bool {
+		return dump.Plugins[i].Name < dump.Plugins[j].Name
+	})
+
+	for name, em := range marketplaces {
+		m := MarketplaceSettingsDump{
+			Name:   name,
+			Source: em.Source.Source,
+			Repo:   em.Source.Repo,
+		}
+		for _, scope := range settings.AllScopes() {
+			if s := perScope[scope]; s != nil {
+				if _, ok := s.ExtraKnownMarketplaces[name]; ok {
+					m.Scopes = append(m.Scopes, scope.String())
+				}
+			}
+		}
+		dump.Marketplaces = append(dump.Marketplaces, m)
+	}
+	sort.Slice(dump.Marketplaces, func(i, j int) bool {
+		return dump.Marketplaces[i].Name < dump.Marketplaces[j].Name
+	})
+
+	return dump, nil
+}
+
+func outputSettingsDump(w io.Writer, dump *SettingsDump) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "Plugins (precedence: managed > local > project > user)")
+	if len(dump.Plugins) == 0 {
+		_, _ = fmt.Fprintln(tw, "  (none)")
+	} else {
+		_, _ = fmt.Fprintln(tw, "  NAME\tSTATE\tSCOPE\tOVERRIDES")
+		for _, p := range dump.Plugins {
+			overrides := "-"
+			if len(p.Overridden) > 0 {
+				parts := make([]string, len(p.Overridden))
+				for i, o := range p.Overridden {
+					parts[i] = o.Scope + ": " + enabledLabel(o.Enabled)
+				}
+				overrides = strings.Join(parts, ", ")
+			}
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", p.Name, enabledLabel(p.Enabled), p.Scope, overrides)
+		}
+	}
+
+	_, _ = fmt.Fprintln(tw, "\nMarketplaces")
+	if len(dump.Marketplaces) == 0 {
+		_, _ = fmt.Fprintln(tw, "  (none)")
+	} else {
+		_, _ = fmt.Fprintln(tw, "  NAME\tSOURCE\tREPO\tSCOPES")
+		for _, m := range dump.Marketplaces {
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", m.Name, m.Source, m.Repo, strings.Join(m.Scopes, ", "))
+		}
+	}
+
+	return tw.Flush()
+}
+
+// enabledLabel renders an enabled flag as "enabled" or "disabled"
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// settingsBundleVersion is the bundle format written by settings export
+const settingsBundleVersion = 1
+
+// SettingsBundle is the portable form of the settings plum manages
+type SettingsBundle struct {
+	Version int                     `json:"version"`
+	Scopes  map[string]*ScopeBundle `json:"scopes"`
+}
+
+// ScopeBundle holds one scope's plum-managed settings
+type ScopeBundle struct {
+	EnabledPlugins         map[string]bool                      `json:"enabledPlugins,omitempty"`
+	ExtraKnownMarketplaces map[string]settings.ExtraMarketplace `json:"extraKnownMarketplaces,omitempty"`
+}
+
+func runSettingsExport(cmd *cobra.Command, args []string) error {
+	bundle, err := buildSettingsBundle(settingsBundleProject)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	if err := os.WriteFile(args[0], append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	plugins, marketplaces := bundle.counts()
+	fmt.Printf("Exported %d plugin(s) and %d marketplace(s) from %d scope(s) to %s\n",
+		plugins, marketplaces, len(bundle.Scopes), args[0])
+	return nil
+}
+
+// buildSettingsBundle collects enabledPlugins and extraKnownMarketplaces from
+// each writable scope. Managed settings are left out since they can't be
+// imported. Scopes with nothing to export are omitted.
+func buildSettingsBundle(projectPath string) (*SettingsBundle, error) {
+	bundle := &SettingsBundle{
+		Version: settingsBundleVersion,
+		Scopes:  make(map[string]*ScopeBundle),
+	}
+
+	for _, scope := range settings.WritableScopes() {
+		s, err := settings.LoadSettings(scope, projectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s settings: %w", scope, err)
+		}
+		if len(s.EnabledPlugins) == 0 && len(s.ExtraKnownMarketplaces) == 0 {
+			continue
+		}
+		bundle.Scopes[scope.String()] = &ScopeBundle{
+			EnabledPlugins:         s.EnabledPlugins,
+			ExtraKnownMarketplaces: s.ExtraKnownMarketplaces,
+		}
+	}
+
+	return bundle, nil
+}
+
+func runSettingsImport(cmd *cobra.Command, args []string) error {
+	// #nosec G304 -- Bundle path is provided by the user
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var bundle SettingsBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("invalid bundle %s: %w", args[0], err)
+	}
+
+	if err := applySettingsBundle(&bundle, settingsBundleProject); err != nil {
+		return err
+	}
+
+	plugins, marketplaces := bundle.counts()
+	fmt.Printf("Imported %d plugin(s) and %d marketplace(s) into %d scope(s) from %s\n",
+		plugins, marketplaces, len(bundle.Scopes), args[0])
+	return nil
+}
+
+// applySettingsBundle merges each scope of the bundle into its settings file.
+// Every scope name is checked before anything is written, so an unusable
+// bundle leaves all settings untouched.
+func applySettingsBundle(bundle *SettingsBundle, projectPath string) error {
+	if bundle.Version != settingsBundleVersion {
+		return fmt.Errorf("unsupported bundle version %d (expected %d)", bundle.Version, settingsBundleVersion)
+	}
+
+	for name := range bundle.Scopes {
+		scope, err := settings.ParseScope(name)
+		if err != nil || name == "" {
+			return fmt.Errorf("invalid scope in bundle: %q", name)
+		}
+		if !scope.IsWritable() {
+			return fmt.Errorf("cannot import into %s scope: %w", name, settings.ErrManagedReadOnly)
+		}
+	}
+
+	updates := make(map[settings.Scope]*settings.Settings)
+	for name, sb := range bundle.Scopes {
+		if sb == nil {
+			continue
+		}
+		scope, _ := settings.ParseScope(name)
+		s := settings.NewSettings()
+		for k, v := range sb.EnabledPlugins {
+			s.EnabledPlugins[k] = v
+		}
+		for k, v := range sb.ExtraKnownMarketplaces {
+			s.ExtraKnownMarketplaces[k] = v
+		}
+		updates[scope] = s
+	}
+
+	// MergeSettings keeps unmanaged fields and writes nothing if any scope
+	// would be rejected
+	if err := settings.MergeSettings(updates, projectPath); err != nil {
+		return fmt.Errorf("failed to import settings: %w", err)
+	}
+
+	return nil
+}
+
+// counts returns the number of plugin and marketplace entries across scopes
+func (b *SettingsBundle) counts() (plugins, marketplaces int) {
+	for _, sb := range b.Scopes {
+		if sb == nil {
+			continue
+		}
+		plugins += len(sb.EnabledPlugins)
+		marketplaces += len(sb.ExtraKnownMarketplaces)
+	}
+	return plugins, marketplaces
+}
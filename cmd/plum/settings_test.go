@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSettingsDump(t *testing.T) {
+	configDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	files := map[string]string{
+		filepath.Join(configDir, "settings.json"): `{
+			"enabledPlugins": {"shared@mkt": true, "split@mkt": true, "user-only@mkt": false},
+			"extraKnownMarketplaces": {
+				"team": {"source": {"source": "github", "repo": "acme/team-user"}},
+				"personal": {"source": {"source": "github", "repo": "me/personal"}}
+			}
+		}`,
+		filepath.Join(projectDir, ".claude", "settings.json"): `{
+			"enabledPlugins": {"shared@mkt": false, "split@mkt": false},
+			"extraKnownMarketplaces": {
+				"team": {"source": {"source": "github", "repo": "acme/team-project"}}
+			}
+		}`,
+		filepath.Join(projectDir, ".claude", "settings.local.json"): `{
+			"enabledPlugins": {"shared@mkt": true}
+		}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	settingsDumpProject = projectDir
+	defer func() { settingsDumpJSON = false; settingsDumpProject = "" }()
+
+	t.Run("json reports the winning scope", func(t *testing.T) {
+		settingsDumpJSON = true
+		output, err := captureStdout(t, func() error { return runSettingsDump(settingsDumpCmd, nil) })
+		if err != nil {
+			t.Fatalf("runSettingsDump failed: %v", err)
+		}
+
+		var dump SettingsDump
+		if err := json.Unmarshal([]byte(output), &dump); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, output)
+		}
+
+		want := []PluginSettingsDump{
+			{Name: "shared@mkt", Enabled: true, Scope: "local", Overridden: []ScopedPluginValue{
+				{Scope: "project", Enabled: false},
+				{Scope: "user", Enabled: true},
+			}},
+			{Name: "split@mkt", Enabled: false, Scope: "project", Overridden: []ScopedPluginValue{
+				{Scope: "user", Enabled: true},
+			}},
+			{Name: "user-only@mkt", Enabled: false, Scope: "user"},
+		}
+		if !reflect.DeepEqual(dump.Plugins, want) {
+			t.Errorf("plugins = %+v\nwant %+v", dump.Plugins, want)
+		}
+
+		wantMarketplaces := []MarketplaceSettingsDump{
+			{Name: "personal", Source: "github", Repo: "me/personal", Scopes: []string{"user"}},
+			{Name: "team", Source: "github", Repo: "acme/team-project", Scopes: []string{"project", "user"}},
+		}
+		if !reflect.DeepEqual(dump.Marketplaces, wantMarketplaces) {
+			t.Errorf("marketplaces = %+v\nwant %+v", dump.Marketplaces, wantMarketplaces)
+		}
+	})
+
+	t.Run("table shows overrides", func(t *testing.T) {
+		settingsDumpJSON = false
+		output, err := captureStdout(t, func() error { return runSettingsDump(settingsDumpCmd, nil) })
+		if err != nil {
+			t.Fatalf("runSettingsDump failed: %v", err)
+		}
+
+		for _, want := range []string{"project: disabled, user: enabled", "acme/team-project", "project, user"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q:\n%s", want, output)
+			}
+		}
+	})
+}
+
+func TestSettingsDump_Empty(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	settingsDumpProject = t.TempDir()
+	defer func() { settingsDumpProject = "" }()
+
+	output, err := captureStdout(t, func() error { return runSettingsDump(settingsDumpCmd, nil) })
+	if err != nil {
+		t.Fatalf("runSettingsDump failed: %v", err)
+	}
+	if strings.Count(output, "(none)") != 2 {
+		t.Errorf("expected no plugins and no marketplaces:\n%s", output)
+	}
+}
+
+func TestSettingsExportImport(t *testing.T) {
+	configDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	userPath := filepath.Join(configDir, "settings.json")
+	projectPath := filepath.Join(projectDir, ".claude", "settings.json")
+	files := map[string]string{
+		userPath: `{
+			"model": "opus",
+			"enabledPlugins": {"alpha@mkt": true, "beta@mkt": false},
+			"extraKnownMarketplaces": {
+				"team": {"source": {"source": "github", "repo": "acme/team"}}
+			}
+		}`,
+		projectPath: `{
+			"permissions": {"allow": ["Bash(ls)"]},
+			"enabledPlugins": {"alpha@mkt": false}
+		}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	settingsBundleProject = projectDir
+	defer func() { settingsBundleProject = "" }()
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+	output, err := captureStdout(t, func() error { return runSettingsExport(settingsExportCmd, []string{bundlePath}) })
+	if err != nil {
+		t.Fatalf("runSettingsExport failed: %v", err)
+	}
+	if !strings.Contains(output, "Exported 3 plugin(s) and 1 marketplace(s) from 2 scope(s)") {
+		t.Errorf("unexpected export output: %s", output)
+	}
+
+	// Wipe the managed fields, keeping the unmanaged ones plus a new entry
+	// that isn't in the bundle
+	wiped := map[string]string{
+		userPath:    `{"model": "opus", "enabledPlugins": {"gamma@mkt": true}}`,
+		projectPath: `{"permissions": {"allow": ["Bash(ls)"]}}`,
+	}
+	for path, content := range wiped {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err = captureStdout(t, func() error { return runSettingsImport(settingsImportCmd, []string{bundlePath}) })
+	if err != nil {
+		t.Fatalf("runSettingsImport failed: %v", err)
+	}
+	if !strings.Contains(output, "Imported 3 plugin(s) and 1 marketplace(s) into 2 scope(s)") {
+		t.Errorf("unexpected import output: %s", output)
+	}
+
+	readJSON := func(path string) map[string]any {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]any
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatalf("invalid JSON in %s: %v", path, err)
+		}
+		return m
+	}
+
+	user := readJSON(userPath)
+	wantUser := map[string]any{
+		"model":          "opus",
+		"enabledPlugins": map[string]any{"alpha@mkt": true, "beta@mkt": false, "gamma@mkt": true},
+		"extraKnownMarketplaces": map[string]any{
+			"team": map[string]any{"source": map[string]any{"source": "github", "repo": "acme/team"}},
+		},
+	}
+	if !reflect.DeepEqual(user, wantUser) {
+		t.Errorf("user settings = %v\nwant %v", user, wantUser)
+	}
+
+	project := readJSON(projectPath)
+	wantProject := map[string]any{
+		"permissions":    map[string]any{"allow": []any{"Bash(ls)"}},
+		"enabledPlugins": map[string]any{"alpha@mkt": false},
+	}
+	if !reflect.DeepEqual(project, wantProject) {
+		t.Errorf("project settings = %v\nwant %v", project, wantProject)
+	}
+
+	// Nothing was exported for local scope, so no file is created
+	if _, err := os.Stat(filepath.Join(projectDir, ".claude", "settings.local.json")); !os.IsNotExist(err) {
+		t.Errorf("local settings should not be created, stat err = %v", err)
+	}
+}
+
+func TestSettingsImport_RejectsInvalidBundles(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	settingsBundleProject = t.TempDir()
+	defer func() { settingsBundleProject = "" }()
+
+	tests := []struct {
+		name    string
+		bundle  string
+		wantErr string
+	}{
+		{"unknown version", `{"version": 2, "scopes": {}}`, "unsupported bundle version"},
+		{"unknown scope", `{"version": 1, "scopes": {"global": {}}}`, "invalid scope"},
+		{"managed scope", `{"version": 1, "scopes": {"managed": {}}}`, "managed scope is read-only"},
+		{"bad plugin key", `{"version": 1, "scopes": {"user": {"enabledPlugins": {"alpha": true}}}}`, "invalid plugin key"},
+		{"case variant", `{"version": 1, "scopes": {"user": {"enabledPlugins": {"alpha@Market": true, "alpha@market": false}}}}`, "differs only by case"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+			if err := os.WriteFile(bundlePath, []byte(tt.bundle), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := captureStdout(t, func() error { return runSettingsImport(settingsImportCmd, []string{bundlePath}) })
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("runSettingsImport error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(configDir, "settings.json")); !os.IsNotExist(err) {
+				t.Errorf("user settings should not be written, stat err = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Render a static TUI frame from fixture data",
+	Long: `Render a single frame of the TUI to stdout without starting an
+interactive session.
+
+The frame is built from a fixed set of fixture plugins, so the output is
+reproducible. This is intended for documentation screenshots and rendering
+regression tests.
+
+Examples:
+  plum snapshot
+  plum snapshot --view detail --query docker
+  plum snapshot --view marketplace --width 100 --height 30`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runSnapshot,
+}
+
+var (
+	snapshotWidth  int
+	snapshotHeight int
+	snapshotView   string
+	snapshotQuery  string
+)
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+
+	snapshotCmd.Flags().IntVar(&snapshotWidth, "width", 80, "Terminal width in columns")
+	snapshotCmd.Flags().IntVar(&snapshotHeight, "height", 24, "Terminal height in rows")
+	snapshotCmd.Flags().StringVar(&snapshotView, "view", ui.SnapshotViewList, "View to render (list, detail, marketplace)")
+	snapshotCmd.Flags().StringVar(&snapshotQuery, "query", "", "Search query to apply")
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	frame, err := ui.RenderSnapshot(ui.SnapshotOptions{
+		Width:   snapshotWidth,
+		Height:  snapshotHeight,
+		View:    snapshotView,
+		Query:   snapshotQuery,
+		Plugins: snapshotFixturePlugins(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), frame)
+	return err
+}
+
+// snapshotFixturePlugins returns the fixed plugin set used for snapshots
+func snapshotFixturePlugins() []plugin.Plugin {
+	return []plugin.Plugin{
+		{
+			Name:            "docker-helper",
+			Description:     "Build, run, and debug Docker containers from Claude",
+			Version:         "1.2.0",
+			Category:        "devops",
+			Keywords:        []string{"docker", "containers"},
+			Author:          plugin.Author{Name: "Plum Fixtures"},
+			Marketplace:     "plum-fixtures",
+			MarketplaceRepo: "https://github.com/itsdevcoffee/plum-fixtures",
+			Source:          "./plugins/docker-helper",
+			Installed:       true,
+			InstallPath:     "~/.claude/plugins/cache/plum-fixtures/docker-helper/1.2.0",
+		},
+		{
+			Name:            "test-runner",
+			Description:     "Run and summarize test suites across common frameworks",
+			Version:         "0.3.1",
+			Category:        "testing",
+			Keywords:        []string{"test", "ci"},
+			Author:          plugin.Author{Name: "Plum Fixtures"},
+			Marketplace:     "plum-fixtures",
+			MarketplaceRepo: "https://github.com/itsdevcoffee/plum-fixtures",
+			Source:          "./plugins/test-runner",
+		},
+		{
+			Name:            "commit-writer",
+			Description:     "Draft conventional commit messages from staged changes",
+			Version:         "2.0.0",
+			Category:        "productivity",
+			Keywords:        []string{"git", "commits"},
+			Author:          plugin.Author{Name: "Example Labs"},
+			Marketplace:     "example-market",
+			MarketplaceRepo: "https://github.com/example/example-market",
+			Source:          "./plugins/commit-writer",
+			IsDiscoverable:  true,
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func TestSnapshotCommand_Structure(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"snapshot"})
+	if err != nil {
+		t.Fatalf("snapshot command not found: %v", err)
+	}
+
+	if !cmd.Hidden {
+		t.Error("snapshot command should be hidden")
+	}
+
+	flags := []string{"width", "height", "view", "query"}
+	for _, name := range flags {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected flag --%s to exist", name)
+		}
+	}
+}
+
+func TestSnapshotCommand_ListGolden(t *testing.T) {
+	snapshotWidth = 80
+	snapshotHeight = 24
+	snapshotView = "list"
+	snapshotQuery = ""
+
+	var buf bytes.Buffer
+	snapshotCmd.SetOut(&buf)
+	defer snapshotCmd.SetOut(nil)
+
+	if err := runSnapshot(snapshotCmd, nil); err != nil {
+		t.Fatalf("runSnapshot failed: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "snapshot_list.golden")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create): %v", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("snapshot output does not match %s\n--- got ---\n%s\n--- want ---\n%s", golden, buf.String(), want)
+	}
+}
+
+func TestSnapshotCommand_InvalidView(t *testing.T) {
+	snapshotWidth = 80
+	snapshotHeight = 24
+	snapshotView = "bogus"
+	snapshotQuery = ""
+	defer func() { snapshotView = "list" }()
+
+	var buf bytes.Buffer
+	snapshotCmd.SetOut(&buf)
+	defer snapshotCmd.SetOut(nil)
+
+	if err := runSnapshot(snapshotCmd, nil); err == nil {
+		t.Error("expected error for unknown view")
+	}
+}
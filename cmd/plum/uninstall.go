@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/settings"
+	"github.com/spf13/cobra"
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall <plugin@marketplace>",
+	Short: "Uninstall a plugin completely",
+	Long: `Uninstall a plugin, reversing everything install did.
+
+This removes:
+  - the plugin's entry in the target scope's settings.json
+  - the target scope's install in installed_plugins_v2.json
+  - the cached plugin files under ~/.claude/plugins/cache/<marketplace>/<plugin>,
+    once no other scope still has the plugin installed
+
+Unlike remove, the plugin must be given as plugin@marketplace and the
+registry entry is always deleted. Managed scope is never modified.
+
+Examples:
+  plum uninstall ralph-wiggum@claude-code-plugins
+  plum uninstall memory@claude-code-plugins --scope=project
+  plum uninstall memory@claude-code-plugins --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUninstall,
+}
+
+var (
+	uninstallScope   string
+	uninstallProject string
+	uninstallDryRun  bool
+)
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+
+	uninstallCmd.Flags().StringVarP(&uninstallScope, "scope", "s", "user", "Target scope (user, project, local)")
+	uninstallCmd.Flags().StringVar(&uninstallProject, "project", "", "Project path (default: current directory)")
+	uninstallCmd.Flags().BoolVar(&uninstallDryRun, "dry-run", false, "Show what would be removed without changing anything")
+}
+
+func runUninstall(cmd *cobra.Command, args []string) error {
+	pluginName, marketplaceName, version, err := parsePluginArg(args[0])
+	if err != nil {
+		return err
+	}
+	if marketplaceName == "" || version != "" {
+		return fmt.Errorf("plugin must be specified as plugin@marketplace: %s", args[0])
+	}
+	fullName := pluginName + "@" + marketplaceName
+
+	scope, err := settings.ParseScope(uninstallScope)
+	if err != nil {
+		return err
+	}
+	if !scope.IsWritable() {
+		return fmt.Errorf("cannot uninstall from %s scope (read-only)", scope)
+	}
+
+	// Resolve the cache path before touching anything so a bad name fails cleanly
+	cachePath, err := install.CacheDir(marketplaceName, pluginName)
+	if err != nil {
+		return err
+	}
+
+	if uninstallDryRun {
+		return printUninstallPlan(fullName, scope, uninstallProject, cachePath)
+	}
+
+	if err := removePluginFromScope(fullName, scope, uninstallProject); err != nil {
+		return fmt.Errorf("failed to update %s settings: %w", scope, err)
+	}
+	fmt.Printf("Removed %s from %s scope settings\n", fullName, scope)
+
+	remaining, err := unregisterPluginInstall(fullName, scope, uninstallProject)
+	if err != nil {
+		return fmt.Errorf("failed to update install registry: %w", err)
+	}
+	fmt.Printf("Removed %s's %s scope install from install registry\n", fullName, scope)
+
+	// The cache is shared by every scope's install
+	if remaining > 0 {
+		fmt.Printf("Kept cache directory %s (still installed in %d other scope(s))\n", cachePath, remaining)
+		return nil
+	}
+	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Warning: cache directory already removed: %s\n", cachePath)
+		return nil
+	}
+	if err := os.RemoveAll(cachePath); err != nil {
+		return fmt.Errorf("failed to delete cache: %w", err)
+	}
+	fmt.Printf("Deleted cache directory %s\n", cachePath)
+
+	return nil
+}
+
+// printUninstallPlan prints what runUninstall would remove without touching
+// the settings, the registry, or the cache
+func printUninstallPlan(fullName string, scope settings.Scope, projectPath, cachePath string) error {
+	fmt.Printf("Would uninstall %s from %s scope\n", fullName, scope)
+
+	settingsPath, err := settings.ScopePath(scope, projectPath)
+	if err != nil {
+		return err
+	}
+	s, err := settings.LoadSettings(scope, projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load %s settings: %w", scope, err)
+	}
+	if _, ok := s.EnabledPlugins[fullName]; ok {
+		fmt.Printf("  Settings:    clear enabledPlugins[%q] in %s\n", fullName, settingsPath)
+	} else {
+		fmt.Printf("  Settings:    nothing to clear (%s not in %s)\n", fullName, settingsPath)
+	}
+
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		return err
+	}
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to load install registry: %w", err)
+	}
+	installs := installed.Plugins[fullName]
+	kept, err := installsExcept(installs, scope, projectPath)
+	if err != nil {
+		return err
+	}
+	if len(kept) < len(installs) {
+		fmt.Printf("  Registry:    remove %s's %s scope install from %s\n", fullName, scope, registryPath)
+	} else {
+		fmt.Printf("  Registry:    nothing to remove (%s not registered in %s scope)\n", fullName, scope)
+	}
+	if len(kept) > 0 {
+		fmt.Printf("  Cache:       keep %s (still installed in %d other scope(s))\n", cachePath, len(kept))
+		return nil
+	}
+
+	files, size, err := dirUsage(cachePath)
+	switch {
+	case os.IsNotExist(err):
+		fmt.Printf("  Cache:       nothing to delete (%s does not exist)\n", cachePath)
+	case err != nil:
+		return fmt.Errorf("failed to inspect cache: %w", err)
+	default:
+		fmt.Printf("  Cache:       delete %s (%d files, %s)\n", cachePath, files, formatSize(size))
+	}
+	return nil
+}
+
+// dirUsage counts the regular files under dir and their total size
+func dirUsage(dir string) (files int, size int64, err error) {
+	if _, err := os.Stat(dir); err != nil {
+		return 0, 0, err
+	}
+	err = filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	return files, size, err
+}
+
+// formatSize renders a byte count as B, KB or MB
+func formatSize(n int64) string {
+	switch {
+	case n < 1<<10:
+		return fmt.Sprintf("%d B", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it printed
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := fn()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	out, _ := io.ReadAll(r)
+	return string(out), err
+}
+
+func TestUninstallCommand_RemovesEverything(t *testing.T) {
+	configDir := setupInstallFixture(t)
+
+	installScope = "user"
+	installProject = t.TempDir()
+	uninstallScope = "user"
+	uninstallProject = installProject
+	defer func() { installProject = ""; uninstallProject = "" }()
+
+	if _, err := captureStdout(t, func() error {
+		return runInstall(installCmd, []string{"alpha@claude-code-marketplace"})
+	}); err != nil {
+		t.Fatalf("runInstall failed: %v", err)
+	}
+
+	cachePath := filepath.Join(configDir, "plugins", "cache", "claude-code-marketplace", "alpha")
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("install should create cache dir: %v", err)
+	}
+	if installed, err := config.LoadInstalledPlugins(); err != nil || len(installed.Plugins["alpha@claude-code-marketplace"]) == 0 {
+		t.Fatalf("install should register the plugin (err = %v)", err)
+	}
+
+	output, err := captureStdout(t, func() error {
+		return runUninstall(uninstallCmd, []string{"alpha@claude-code-marketplace"})
+	})
+	if err != nil {
+		t.Fatalf("runUninstall failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Removed alpha@claude-code-marketplace from user scope settings",
+		"Removed alpha@claude-code-marketplace's user scope install from install registry",
+		"Deleted cache directory",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("cache dir should be deleted (stat err = %v)", err)
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatalf("LoadInstalledPlugins failed: %v", err)
+	}
+	if _, ok := installed.Plugins["alpha@claude-code-marketplace"]; ok {
+		t.Error("registry entry should be removed")
+	}
+
+	userSettings, err := settings.LoadSettings(settings.ScopeUser, installProject)
+	if err != nil {
+		t.Fatalf("LoadSettings failed: %v", err)
+	}
+	if _, ok := userSettings.EnabledPlugins["alpha@claude-code-marketplace"]; ok {
+		t.Error("settings entry should be removed")
+	}
+
+	// A second uninstall warns about the missing cache but still succeeds
+	if _, err := captureStdout(t, func() error {
+		return runUninstall(uninstallCmd, []string{"alpha@claude-code-marketplace"})
+	}); err != nil {
+		t.Errorf("uninstall with cache already gone should not fail: %v", err)
+	}
+}
+
+func TestUninstallCommand_KeepsOtherScopes(t *testing.T) {
+	configDir := setupInstallFixture(t)
+	const fullName = "alpha@claude-code-marketplace"
+	projectDir := t.TempDir()
+
+	installProject = projectDir
+	uninstallProject = projectDir
+	defer func() { installProject = ""; uninstallProject = ""; installScope = "user"; uninstallScope = "user" }()
+	for _, scope := range []string{"user", "project"} {
+		installScope = scope
+		if _, err := captureStdout(t, func() error {
+			return runInstall(installCmd, []string{fullName})
+		}); err != nil {
+			t.Fatalf("runInstall --scope=%s failed: %v", scope, err)
+		}
+	}
+
+	uninstallScope = "project"
+	output, err := captureStdout(t, func() error {
+		return runUninstall(uninstallCmd, []string{fullName})
+	})
+	if err != nil {
+		t.Fatalf("runUninstall failed: %v", err)
+	}
+	if !strings.Contains(output, "Kept cache directory") {
+		t.Errorf("output should say the cache was kept:\n%s", output)
+	}
+
+	cachePath := filepath.Join(configDir, "plugins", "cache", "claude-code-marketplace", "alpha")
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Errorf("cache should survive while user scope has the plugin: %v", err)
+	}
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installs := installed.Plugins[fullName]
+	if len(installs) != 1 || installs[0].Scope != "user" {
+		t.Errorf("registry should keep only the user install, got %+v", installs)
+	}
+	userSettings, err := settings.LoadSettings(settings.ScopeUser, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !userSettings.EnabledPlugins[fullName] {
+		t.Error("plugin should stay enabled in user scope")
+	}
+
+	// Uninstalling the last scope removes the rest
+	uninstallScope = "user"
+	if _, err := captureStdout(t, func() error {
+		return runUninstall(uninstallCmd, []string{fullName})
+	}); err != nil {
+		t.Fatalf("runUninstall failed: %v", err)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("cache should be deleted with the last install (stat err = %v)", err)
+	}
+	installed, err = config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := installed.Plugins[fullName]; ok {
+		t.Error("registry key should be removed with the last install")
+	}
+}
+
+func TestUninstallCommand_Validation(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	defer func() { uninstallScope = "user" }()
+
+	tests := []struct {
+		name    string
+		arg     string
+		scope   string
+		wantErr string
+	}{
+		{"missing marketplace", "alpha", "user", "plugin@marketplace"},
+		{"pinned version", "alpha@mkt@1.0.0", "user", "plugin@marketplace"},
+		{"managed scope", "alpha@mkt", "managed", "read-only"},
+		{"path traversal", "alpha@..", "user", "path traversal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uninstallScope = tt.scope
+			err := runUninstall(uninstallCmd, []string{tt.arg})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUninstallCommand_DryRun(t *testing.T) {
+	configDir := setupInstallFixture(t)
+	const fullName = "alpha@claude-code-marketplace"
+
+	installScope = "user"
+	installProject = t.TempDir()
+	uninstallScope = "user"
+	uninstallProject = installProject
+	defer func() { installProject = ""; uninstallProject = ""; uninstallDryRun = false }()
+
+	if _, err := captureStdout(t, func() error {
+		return runInstall(installCmd, []string{fullName})
+	}); err != nil {
+		t.Fatalf("runInstall failed: %v", err)
+	}
+	before := snapshotFiles(t, configDir)
+
+	uninstallDryRun = true
+	output, err := captureStdout(t, func() error {
+		return runUninstall(uninstallCmd, []string{fullName})
+	})
+	if err != nil {
+		t.Fatalf("dry-run uninstall failed: %v", err)
+	}
+
+	cachePath := filepath.Join(configDir, "plugins", "cache", "claude-code-marketplace", "alpha")
+	registryPath, _ := config.InstalledPluginsPath()
+	for _, want := range []string{
+		"Would uninstall " + fullName + " from user scope",
+		`clear enabledPlugins["` + fullName + `"] in ` + filepath.Join(configDir, "settings.json"),
+		"remove " + fullName + "'s user scope install from " + registryPath,
+		"delete " + cachePath + " (1 files, ",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	after := snapshotFiles(t, configDir)
+	if len(after) != len(before) {
+		t.Errorf("dry run created or removed files: %d before, %d after", len(before), len(after))
+	}
+	for path, content := range before {
+		if after[path] != content {
+			t.Errorf("dry run modified %s", path)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		2048:            "2.0 KB",
+		3 * 1024 * 1024: "3.0 MB",
+	}
+	for n, want := range tests {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
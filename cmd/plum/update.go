@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
-	"github.com/Masterminds/semver/v3"
 	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/settings"
 	"github.com/spf13/cobra"
 )
@@ -22,18 +28,25 @@ The plugin can be specified as:
   - plugin-name (updates first matching installed plugin)
   - plugin-name@marketplace (specific marketplace)
 
+Plugins installed at a pinned version (plugin@marketplace@version) are left
+at their pin. Reinstall without a version to unpin them.
+
 Examples:
   plum update                      # Update all plugins
   plum update ralph-wiggum         # Update specific plugin
   plum update --dry-run            # Check for updates without installing
+  plum update --check              # Report update status without changing anything
+  plum update --check --json       # Machine-readable report for CI
   plum update --scope=project      # Only update project-scoped plugins`,
 	RunE: runUpdate,
 }
 
 var (
-	updateScope   string
-	updateProject string
-	updateDryRun  bool
+	updateScope     string
+	updateProject   string
+	updateDryRun    bool
+	updateCheckOnly bool
+	updateJSON      bool
 )
 
 func init() {
@@ -42,6 +55,8 @@ func init() {
 	updateCmd.Flags().StringVarP(&updateScope, "scope", "s", "", "Filter by scope (user, project, local)")
 	updateCmd.Flags().StringVar(&updateProject, "project", "", "Project path (default: current directory)")
 	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "Check for updates without installing")
+	updateCmd.Flags().BoolVar(&updateCheckOnly, "check", false, "Report update status for each plugin without modifying anything")
+	updateCmd.Flags().BoolVar(&updateJSON, "json", false, "Output the --check report as JSON")
 }
 
 // updateOptions contains parameters for the update operation
@@ -50,6 +65,8 @@ type updateOptions struct {
 	Scope   string // Filter by scope (empty = all)
 	Project string // Project path
 	DryRun  bool   // Check only, don't install
+	Check   bool   // Report status only; no installs and no registry writes
+	JSON    bool   // Output the check report as JSON (requires Check)
 }
 
 // updateInfo holds information about an available update
@@ -58,6 +75,7 @@ type updateInfo struct {
 	CurrentVersion string
 	LatestVersion  string
 	Scope          settings.Scope
+	Ref            string // Pinned ref, kept when re-registering
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
@@ -65,6 +83,8 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		Scope:   updateScope,
 		Project: updateProject,
 		DryRun:  updateDryRun,
+		Check:   updateCheckOnly,
+		JSON:    updateJSON,
 	}
 	return performUpdate(cmd, args, opts)
 }
@@ -72,6 +92,10 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 // performUpdate executes the update logic with explicit options
 // This function is safe to call from other commands without shared state issues
 func performUpdate(cmd *cobra.Command, args []string, opts updateOptions) error {
+	if opts.JSON && !opts.Check {
+		return fmt.Errorf("--json requires --check")
+	}
+
 	// Get list of plugins to update
 	var pluginsToCheck []string
 
@@ -105,7 +129,7 @@ func performUpdate(cmd *cobra.Command, args []string, opts updateOptions) error
 		}
 	}
 
-	if len(pluginsToCheck) == 0 {
+	if len(pluginsToCheck) == 0 && !opts.Check {
 		fmt.Println("No plugins to update")
 		return nil
 	}
@@ -130,53 +154,54 @@ func performUpdate(cmd *cobra.Command, args []string, opts updateOptions) error
 	}
 
 	// Check each plugin for updates
-	var updates []updateInfo
-	for _, fullName := range pluginsToCheck {
-		// Get current version from installed registry
-		currentVersion := ""
-		if installs, ok := installed.Plugins[fullName]; ok && len(installs) > 0 {
-			currentVersion = installs[0].Version
+	checks := checkForUpdates(pluginsToCheck, installed, latestVersions)
+
+	// Read-only report: nothing is installed and the registry is left untouched
+	if opts.Check {
+		if opts.JSON {
+			return outputUpdateCheckJSON(checks)
 		}
+		return outputUpdateCheckTable(checks)
+	}
 
-		// Get latest version from marketplace
-		latestVersion, ok := latestVersions[fullName]
-		if !ok {
-			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s not found in any marketplace\n", fullName)
+	var updates []updateInfo
+	var checked []string
+	for _, c := range checks {
+		if !c.InMarketplace {
 			continue
 		}
+		checked = append(checked, c.FullName)
 
-		// Compare versions using semver
-		if currentVersion == "" || isNewerVersion(latestVersion, currentVersion) {
-			// Determine scope for update
-			scope := settings.ScopeUser
-			if installs, ok := installed.Plugins[fullName]; ok && len(installs) > 0 {
-				parsedScope, _ := settings.ParseScope(installs[0].Scope)
-				scope = parsedScope
-			}
-
+		if c.Status == plugin.UpdateAvailable && c.Ref == "" {
 			updates = append(updates, updateInfo{
-				FullName:       fullName,
-				CurrentVersion: currentVersion,
-				LatestVersion:  latestVersion,
-				Scope:          scope,
+				FullName:       c.FullName,
+				CurrentVersion: c.CurrentVersion,
+				LatestVersion:  c.LatestVersion,
+				Scope:          c.Scope,
+				Ref:            c.Ref,
 			})
 		}
 	}
 
+	// Record the check once any updates have rewritten their registry entries
+	defer func() {
+		if err := recordUpdateChecks(checked, time.Now()); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to record update check: %v\n", err)
+		}
+	}()
+
+	// Print the status of every checked plugin
+	for _, c := range checks {
+		fmt.Printf("  %s: %s\n", c.FullName, c.describe())
+	}
+	fmt.Println()
+
 	if len(updates) == 0 {
 		fmt.Println("All plugins are up to date")
 		return nil
 	}
 
-	// Print available updates
-	fmt.Printf("Found %d update(s):\n\n", len(updates))
-	for _, u := range updates {
-		if u.CurrentVersion == "" {
-			fmt.Printf("  %s: (not installed) → %s\n", u.FullName, u.LatestVersion)
-		} else {
-			fmt.Printf("  %s: %s → %s\n", u.FullName, u.CurrentVersion, u.LatestVersion)
-		}
-	}
+	fmt.Printf("Found %d update(s)\n", len(updates))
 
 	if opts.DryRun {
 		fmt.Println("\nRun without --dry-run to install updates")
@@ -193,15 +218,14 @@ func performUpdate(cmd *cobra.Command, args []string, opts updateOptions) error
 		fmt.Printf("Updating %s...\n", u.FullName)
 
 		// Parse plugin name and marketplace
-		parts := strings.SplitN(u.FullName, "@", 2)
-		if len(parts) != 2 {
+		pluginName, marketplaceName, ok := plugin.ParseFullName(u.FullName)
+		if !ok {
 			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: invalid plugin name format: %s\n", u.FullName)
 			failedUpdates = append(failedUpdates, u.FullName)
 			continue
 		}
 
-		// Reinstall the plugin to update it
-		if err := installPlugin(u.FullName, u.Scope, opts.Project); err != nil {
+		if err := updatePlugin(pluginName, marketplaceName, u, opts.Project); err != nil {
 			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error updating %s: %v\n", u.FullName, err)
 			failedUpdates = append(failedUpdates, u.FullName)
 			continue
@@ -219,20 +243,209 @@ func performUpdate(cmd *cobra.Command, args []string, opts updateOptions) error
 	return nil
 }
 
-// isNewerVersion returns true if v1 is newer than v2 using semver comparison
-func isNewerVersion(v1, v2 string) bool {
-	// Clean version strings (remove 'v' prefix if present)
-	v1 = strings.TrimPrefix(v1, "v")
-	v2 = strings.TrimPrefix(v2, "v")
+// updatePlugin downloads the latest version of an installed plugin. The old
+// cache is moved to a backup first (see 'plum rollback') and put back if the
+// download fails.
+func updatePlugin(pluginName, marketplaceName string, u updateInfo, projectPath string) error {
+	pluginInfo, err := findPluginInMarketplaces(pluginName, marketplaceName, "", false)
+	if err != nil {
+		return err
+	}
+	if !pluginInfo.Installable {
+		return fmt.Errorf("plugin not installable via plum: %s", pluginInfo.InstallabilityReason)
+	}
+
+	cachePath, err := install.CacheDir(marketplaceName, pluginName)
+	if err != nil {
+		return err
+	}
+
+	backupPath, err := backupPluginCache(cachePath, marketplaceName, pluginName, u.CurrentVersion)
+	if err != nil {
+		return err
+	}
+
+	ref, err := install.DownloadToCache(context.Background(), pluginInfo, cachePath, true, os.Stderr)
+	if err != nil {
+		if backupPath != "" {
+			if restoreErr := restorePluginBackup(backupPath, cachePath); restoreErr != nil {
+				return fmt.Errorf("failed to download plugin: %w (restoring previous version also failed: %v)", err, restoreErr)
+			}
+		}
+		return fmt.Errorf("failed to download plugin: %w", err)
+	}
+
+	if err := install.Register(u.FullName, cachePath, pluginInfo.Version, u.Ref, install.ResolveCommit(pluginInfo, ref), u.Scope, projectPath); err != nil {
+		return fmt.Errorf("failed to register plugin: %w", err)
+	}
+
+	fmt.Printf("Updated %s to v%s\n", u.FullName, pluginInfo.Version)
+	return nil
+}
+
+// updateCheck is the result of comparing one plugin against its marketplace
+type updateCheck struct {
+	FullName       string
+	CurrentVersion string // Empty if not in the install registry
+	LatestVersion  string
+	InMarketplace  bool
+	Status         plugin.UpdateStatus
+	Scope          settings.Scope // Scope to update in
+	Ref            string         // Tag a pinned install came from; pinned plugins aren't updated
+}
+
+// checkForUpdates compares each plugin's registry version with the latest
+// marketplace version, in the order given
+func checkForUpdates(fullNames []string, installed *config.InstalledPluginsV2, latestVersions map[string]string) []updateCheck {
+	checks := make([]updateCheck, 0, len(fullNames))
+	for _, fullName := range fullNames {
+		c := updateCheck{FullName: fullName, Scope: settings.ScopeUser}
+
+		// Get current version and scope from installed registry
+		if installs, ok := installed.Plugins[fullName]; ok && len(installs) > 0 {
+			c.CurrentVersion = installs[0].Version
+			c.Ref = installs[0].Ref
+			if parsedScope, err := settings.ParseScope(installs[0].Scope); err == nil {
+				c.Scope = parsedScope
+			}
+		}
+
+		// Get latest version from marketplace
+		c.LatestVersion, c.InMarketplace = latestVersions[fullName]
+		c.Status = plugin.CheckUpdate(c.CurrentVersion, c.LatestVersion)
+
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// describe renders the check as "up to date", "update available X -> Y",
+// "pinned to REF" or "unknown"
+func (c updateCheck) describe() string {
+	if c.Ref != "" {
+		if c.Status == plugin.UpdateAvailable {
+			return fmt.Sprintf("pinned to %s (latest %s)", c.Ref, c.LatestVersion)
+		}
+		return fmt.Sprintf("pinned to %s", c.Ref)
+	}
+
+	switch c.Status {
+	case plugin.UpdateAvailable:
+		current := c.CurrentVersion
+		if current == "" {
+			current = "(not installed)"
+		}
+		return fmt.Sprintf("%s %s -> %s", c.Status, current, c.LatestVersion)
+	case plugin.UpToDate:
+		return fmt.Sprintf("%s (%s)", c.Status, c.CurrentVersion)
+	default:
+		if !c.InMarketplace {
+			return fmt.Sprintf("%s (not found in any marketplace)", c.Status)
+		}
+		return c.Status.String()
+	}
+}
+
+// UpdateCheckItem is one plugin in the `update --check` report
+type UpdateCheckItem struct {
+	Plugin          string `json:"plugin"`
+	Scope           string `json:"scope"`
+	CurrentVersion  string `json:"currentVersion"`
+	LatestVersion   string `json:"latestVersion"`
+	Status          string `json:"status"` // "up to date", "update available", "pinned" or "unknown"
+	UpdateAvailable bool   `json:"updateAvailable"`
+	PinnedRef       string `json:"pinnedRef,omitempty"`
+}
+
+func updateCheckItems(checks []updateCheck) []UpdateCheckItem {
+	items := make([]UpdateCheckItem, 0, len(checks))
+	for _, c := range checks {
+		item := UpdateCheckItem{
+			Plugin:          c.FullName,
+			Scope:           c.Scope.String(),
+			CurrentVersion:  c.CurrentVersion,
+			LatestVersion:   c.LatestVersion,
+			Status:          c.Status.String(),
+			UpdateAvailable: c.Status == plugin.UpdateAvailable,
+			PinnedRef:       c.Ref,
+		}
+		if c.Ref != "" {
+			item.Status = "pinned"
+			item.UpdateAvailable = false
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func outputUpdateCheckJSON(checks []updateCheck) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(updateCheckItems(checks))
+}
+
+func outputUpdateCheckTable(checks []updateCheck) error {
+	if len(checks) == 0 {
+		fmt.Println("No plugins installed")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PLUGIN\tSCOPE\tCURRENT\tLATEST\tSTATUS")
+	available := 0
+	for _, item := range updateCheckItems(checks) {
+		if item.UpdateAvailable {
+			available++
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			item.Plugin, item.Scope, orDash(item.CurrentVersion), orDash(item.LatestVersion), item.Status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d update(s) available\n", available)
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
 
-	// Parse versions
-	ver1, err1 := semver.NewVersion(v1)
-	ver2, err2 := semver.NewVersion(v2)
+// recordUpdateChecks stamps every registry install of the given plugins with
+// the time they were last compared against their marketplace
+func recordUpdateChecks(fullNames []string, at time.Time) error {
+	if len(fullNames) == 0 {
+		return nil
+	}
 
-	// If either version is invalid, fall back to string comparison
-	if err1 != nil || err2 != nil {
-		return v1 > v2
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		return err
 	}
 
-	return ver1.GreaterThan(ver2)
+	return settings.WithLock(registryPath, func() error {
+		installed, err := config.LoadInstalledPlugins()
+		if err != nil {
+			return err
+		}
+
+		stamp := at.UTC().Format(time.RFC3339)
+		changed := false
+		for _, fullName := range fullNames {
+			installs := installed.Plugins[fullName]
+			for i := range installs {
+				installs[i].LastCheckedAt = stamp
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		return install.SaveRegistry(installed)
+	})
 }
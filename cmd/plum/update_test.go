@@ -2,8 +2,18 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/install"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/settings"
 )
 
 func TestUpdateCommandRegistered(t *testing.T) {
@@ -51,6 +61,12 @@ func TestUpdateCommandFlags(t *testing.T) {
 	if dryRunFlag == nil {
 		t.Error("update command should have --dry-run flag")
 	}
+
+	for _, name := range []string{"check", "json"} {
+		if updateCmd.Flags().Lookup(name) == nil {
+			t.Errorf("update command should have --%s flag", name)
+		}
+	}
 }
 
 func TestUpdateCommandHelp(t *testing.T) {
@@ -84,7 +100,9 @@ func TestUpdateCommandHelp(t *testing.T) {
 	}
 }
 
-func TestIsNewerVersion(t *testing.T) {
+// TestCheckForUpdates_SemverOrdering verifies an update is offered only when
+// the marketplace version (v1) is newer than the installed one (v2)
+func TestCheckForUpdates_SemverOrdering(t *testing.T) {
 	tests := []struct {
 		v1       string
 		v2       string
@@ -105,10 +123,233 @@ func TestIsNewerVersion(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.v1+"_vs_"+tt.v2, func(t *testing.T) {
-			result := isNewerVersion(tt.v1, tt.v2)
-			if result != tt.expected {
-				t.Errorf("isNewerVersion(%q, %q) = %v, want %v", tt.v1, tt.v2, result, tt.expected)
+			installed := &config.InstalledPluginsV2{
+				Version: 2,
+				Plugins: map[string][]config.PluginInstall{"p@mkt": {{Scope: "user", Version: tt.v2}}},
+			}
+			checks := checkForUpdates([]string{"p@mkt"}, installed, map[string]string{"p@mkt": tt.v1})
+			if result := checks[0].Status == plugin.UpdateAvailable; result != tt.expected {
+				t.Errorf("update from %q to %q offered = %v, want %v", tt.v2, tt.v1, result, tt.expected)
 			}
 		})
 	}
 }
+
+func TestRecordUpdateChecks(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+	for _, fullName := range []string{"alpha@mkt", "beta@mkt"} {
+		if err := install.Register(fullName, "/cache/"+fullName, "1.0.0", "", "", settings.ScopeUser, ""); err != nil {
+			t.Fatalf("install.Register failed: %v", err)
+		}
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := recordUpdateChecks([]string{"alpha@mkt", "missing@mkt"}, at); err != nil {
+		t.Fatalf("recordUpdateChecks failed: %v", err)
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatalf("LoadInstalledPlugins failed: %v", err)
+	}
+	if got := installed.Plugins["alpha@mkt"][0].LastCheckedAt; got != "2026-03-01T12:00:00Z" {
+		t.Errorf("alpha LastCheckedAt = %q, want 2026-03-01T12:00:00Z", got)
+	}
+	if got := installed.Plugins["beta@mkt"][0].LastCheckedAt; got != "" {
+		t.Errorf("beta should not be stamped, got %q", got)
+	}
+	if _, ok := installed.Plugins["missing@mkt"]; ok {
+		t.Error("recording a check must not add registry entries")
+	}
+}
+
+func TestCheckForUpdates(t *testing.T) {
+	installed := &config.InstalledPluginsV2{
+		Version: 2,
+		Plugins: map[string][]config.PluginInstall{
+			"alpha@mkt": {{Scope: "project", Version: "1.0.0"}},
+			"beta@mkt":  {{Scope: "user", Version: "2.0.0"}},
+			"gamma@mkt": {{Scope: "user", Version: "1.0.0"}},
+			"delta@mkt": {{Scope: "user", Version: "1.0.0", Ref: "v1.0.0"}},
+		},
+	}
+	latest := map[string]string{
+		"alpha@mkt": "1.0.1",
+		"beta@mkt":  "1.9.0",
+		"delta@mkt": "1.1.0",
+	}
+
+	checks := checkForUpdates([]string{"alpha@mkt", "beta@mkt", "gamma@mkt", "delta@mkt"}, installed, latest)
+	if len(checks) != 4 {
+		t.Fatalf("expected 4 checks, got %d", len(checks))
+	}
+
+	want := []string{
+		"update available 1.0.0 -> 1.0.1",
+		"up to date (2.0.0)",
+		"unknown (not found in any marketplace)",
+		"pinned to v1.0.0 (latest 1.1.0)",
+	}
+	for i, c := range checks {
+		if got := c.describe(); got != want[i] {
+			t.Errorf("%s: describe() = %q, want %q", c.FullName, got, want[i])
+		}
+	}
+	if checks[0].Scope != settings.ScopeProject {
+		t.Errorf("expected alpha to update in project scope, got %s", checks[0].Scope)
+	}
+}
+
+// snapshotFiles records the contents of every file under dir
+func snapshotFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- test temp dir
+		if err != nil {
+			return err
+		}
+		files[path] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestUpdateCheck_JSON(t *testing.T) {
+	configDir := setupInstallFixture(t)
+
+	settingsJSON := `{"enabledPlugins": {"alpha@claude-code-marketplace": true, "beta@claude-code-marketplace": true}}`
+	if err := os.WriteFile(filepath.Join(configDir, "settings.json"), []byte(settingsJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := install.Register("alpha@claude-code-marketplace", "/cache/alpha", "0.9.0", "", "", settings.ScopeUser, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := install.Register("beta@claude-code-marketplace", "/cache/beta", "2.0.0", "", "", settings.ScopeUser, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	before := snapshotFiles(t, configDir)
+
+	opts := updateOptions{Project: t.TempDir(), Check: true, JSON: true}
+	output, err := captureStdout(t, func() error { return performUpdate(updateCmd, nil, opts) })
+	if err != nil {
+		t.Fatalf("update --check failed: %v", err)
+	}
+
+	var items []UpdateCheckItem
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+
+	want := map[string]UpdateCheckItem{
+		"alpha@claude-code-marketplace": {Plugin: "alpha@claude-code-marketplace", Scope: "user", CurrentVersion: "0.9.0", LatestVersion: "1.0.0", Status: "update available", UpdateAvailable: true},
+		"beta@claude-code-marketplace":  {Plugin: "beta@claude-code-marketplace", Scope: "user", CurrentVersion: "2.0.0", LatestVersion: "2.0.0", Status: "up to date"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d items, got %+v", len(want), items)
+	}
+	for _, item := range items {
+		if item != want[item.Plugin] {
+			t.Errorf("item = %+v, want %+v", item, want[item.Plugin])
+		}
+	}
+
+	// The JSON keys are a stable contract for CI consumers
+	var raw []map[string]any
+	_ = json.Unmarshal([]byte(output), &raw)
+	for _, key := range []string{"plugin", "scope", "currentVersion", "latestVersion", "status", "updateAvailable"} {
+		if _, ok := raw[0][key]; !ok {
+			t.Errorf("JSON item missing key %q", key)
+		}
+	}
+
+	after := snapshotFiles(t, configDir)
+	// plum's own cache may record lookups; Claude's config must be untouched
+	cacheDir, err := marketplace.PlumCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, files := range []map[string]string{before, after} {
+		for path := range files {
+			if strings.HasPrefix(path, cacheDir+string(filepath.Separator)) {
+				delete(files, path)
+			}
+		}
+	}
+	if len(after) != len(before) {
+		t.Errorf("--check created or removed files: %d before, %d after", len(before), len(after))
+	}
+	for path, content := range before {
+		if after[path] != content {
+			t.Errorf("--check modified %s", path)
+		}
+	}
+}
+
+func TestUpdate_KeepsPinnedPlugins(t *testing.T) {
+	configDir := setupInstallFixture(t)
+
+	settingsJSON := `{"enabledPlugins": {"alpha@claude-code-marketplace": true, "beta@claude-code-marketplace": true}}`
+	if err := os.WriteFile(filepath.Join(configDir, "settings.json"), []byte(settingsJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Both are behind the marketplace, but alpha is pinned
+	if err := install.Register("alpha@claude-code-marketplace", "/cache/alpha", "0.9.0", "v0.9.0", "", settings.ScopeUser, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := install.Register("beta@claude-code-marketplace", "/cache/beta", "1.0.0", "", "", settings.ScopeUser, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := updateOptions{Project: t.TempDir()}
+	output, err := captureStdout(t, func() error { return performUpdate(updateCmd, nil, opts) })
+	if err != nil {
+		t.Fatalf("update failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "pinned to v0.9.0 (latest 1.0.0)") || !strings.Contains(output, "Found 1 update(s)") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := installed.Plugins["alpha@claude-code-marketplace"][0]
+	if alpha.Version != "0.9.0" || alpha.Ref != "v0.9.0" {
+		t.Errorf("pinned alpha = version %q ref %q, want 0.9.0 at v0.9.0", alpha.Version, alpha.Ref)
+	}
+	if beta := installed.Plugins["beta@claude-code-marketplace"][0]; beta.Version != "2.0.0" {
+		t.Errorf("beta version = %q, want 2.0.0", beta.Version)
+	}
+
+	// --check reports the pin instead of an update
+	opts.Check, opts.JSON = true, true
+	output, err = captureStdout(t, func() error { return performUpdate(updateCmd, nil, opts) })
+	if err != nil {
+		t.Fatalf("update --check failed: %v", err)
+	}
+	var items []UpdateCheckItem
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	for _, item := range items {
+		if item.Plugin == "alpha@claude-code-marketplace" && (item.UpdateAvailable || item.Status != "pinned" || item.PinnedRef != "v0.9.0") {
+			t.Errorf("pinned item = %+v", item)
+		}
+	}
+}
+
+func TestUpdateJSONRequiresCheck(t *testing.T) {
+	err := performUpdate(updateCmd, nil, updateOptions{JSON: true})
+	if err == nil || !strings.Contains(err.Error(), "--check") {
+		t.Errorf("expected --json without --check to fail, got %v", err)
+	}
+}
@@ -1,10 +1,16 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/itsdevcoffee/plum/internal/marketplace"
 	"github.com/itsdevcoffee/plum/internal/plugin"
@@ -34,14 +40,16 @@ type InstalledPluginsV2 struct {
 
 // PluginInstall represents a single plugin installation entry
 type PluginInstall struct {
-	Scope        string `json:"scope"`
-	InstallPath  string `json:"installPath"`
-	Version      string `json:"version"`
-	InstalledAt  string `json:"installedAt"`
-	LastUpdated  string `json:"lastUpdated"`
-	GitCommitSha string `json:"gitCommitSha"`
-	IsLocal      bool   `json:"isLocal"`
-	ProjectPath  string `json:"projectPath,omitempty"`
+	Scope         string `json:"scope"`
+	InstallPath   string `json:"installPath"`
+	Version       string `json:"version"`
+	InstalledAt   string `json:"installedAt"`
+	LastUpdated   string `json:"lastUpdated"`
+	GitCommitSha  string `json:"gitCommitSha"`
+	Ref           string `json:"ref,omitempty"`           // Git tag of a pinned install (plugin@marketplace@version)
+	LastCheckedAt string `json:"lastCheckedAt,omitempty"` // When update last compared this install to its marketplace
+	IsLocal       bool   `json:"isLocal"`
+	ProjectPath   string `json:"projectPath,omitempty"`
 }
 
 // LoadKnownMarketplaces loads the known_marketplaces.json file
@@ -55,6 +63,11 @@ func LoadKnownMarketplaces() (KnownMarketplaces, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
+			// No plugins dir at all means Claude Code was never set up
+			if !ClaudeInstalled() {
+				dir, _ := ClaudeConfigDir()
+				return nil, fmt.Errorf("%w at %s", ErrClaudeNotInstalled, dir)
+			}
 			return nil, fmt.Errorf("claude Code marketplaces not found at %s - please run Claude Code and configure at least one marketplace using the /plugin command", path)
 		}
 		return nil, err
@@ -62,13 +75,14 @@ func LoadKnownMarketplaces() (KnownMarketplaces, error) {
 
 	var marketplaces KnownMarketplaces
 	if err := json.Unmarshal(data, &marketplaces); err != nil {
-		return nil, err
+		return nil, &CorruptFileError{Path: path, Err: err}
 	}
 
 	return marketplaces, nil
 }
 
-// LoadInstalledPlugins loads the installed_plugins_v2.json file
+// LoadInstalledPlugins loads the installed plugins registry (v2 format),
+// migrating a v1 registry first if needed
 func LoadInstalledPlugins() (*InstalledPluginsV2, error) {
 	path, err := InstalledPluginsPath()
 	if err != nil {
@@ -85,9 +99,15 @@ func LoadInstalledPlugins() (*InstalledPluginsV2, error) {
 		return nil, err
 	}
 
+	// Registries written by older Claude Code versions are read as v2. The
+	// file itself is only rewritten when the registry is next saved.
+	if isInstalledPluginsV1(data) {
+		return installedPluginsFromV1(data)
+	}
+
 	var installed InstalledPluginsV2
 	if err := json.Unmarshal(data, &installed); err != nil {
-		return nil, err
+		return nil, &CorruptFileError{Path: path, Err: err}
 	}
 
 	return &installed, nil
@@ -110,9 +130,41 @@ func LoadMarketplaceManifest(marketplacePath string) (*marketplace.MarketplaceMa
 	return &manifest, nil
 }
 
+// LoadOptions controls how LoadAllPluginsWithOptions reads marketplace data
+type LoadOptions struct {
+	NoCache    bool // Fetch discovered marketplaces fresh from GitHub, ignoring plum's cache
+	CachedOnly bool // Use only manifests already in plum's cache, even expired ones; never fetch
+}
+
 // LoadAllPlugins loads all plugins from all known marketplaces
 // Also discovers plugins from popular marketplaces not yet installed
+// Local metadata overrides from PLUM_OVERRIDES are applied last
 func LoadAllPlugins() ([]plugin.Plugin, error) {
+	return LoadAllPluginsWithOptions(LoadOptions{})
+}
+
+// LoadCachedPlugins is the fast path of LoadAllPlugins: installed marketplaces
+// plus whatever discovered marketplaces plum has cached, with no network
+// access. LoadAllPlugins is the slow path that fetches what is missing or
+// expired.
+func LoadCachedPlugins() ([]plugin.Plugin, error) {
+	return LoadAllPluginsWithOptions(LoadOptions{CachedOnly: true})
+}
+
+// LoadAllPluginsWithOptions is LoadAllPlugins with explicit load options
+func LoadAllPluginsWithOptions(opts LoadOptions) ([]plugin.Plugin, error) {
+	return LoadAllPluginsContext(context.Background(), opts)
+}
+
+// LoadAllPluginsContext is LoadAllPluginsWithOptions with a context that
+// cancels discovery. A cancelled load fails with ctx's error rather than
+// returning a partial list.
+func LoadAllPluginsContext(ctx context.Context, opts LoadOptions) ([]plugin.Plugin, error) {
+	overrides, err := LoadOverrides()
+	if err != nil {
+		return nil, err
+	}
+
 	marketplaces, err := LoadKnownMarketplaces()
 	if err != nil {
 		return nil, err
@@ -131,60 +183,39 @@ func LoadAllPlugins() ([]plugin.Plugin, error) {
 		}
 	}
 
-	var plugins []plugin.Plugin
-
-	// Track which marketplaces we've processed to avoid duplicates
-	processedMarketplaces := make(map[string]bool)
 	// Track ALL seen plugin names (across all sources) for global deduplication
 	// Maps plugin name -> source marketplace (first one wins)
 	seenPluginNames := make(map[string]string)
 
-	// 1. Process installed marketplaces first
-	for marketplaceName, entry := range marketplaces {
-		processedMarketplaces[marketplaceName] = true
-
-		manifest, err := LoadMarketplaceManifest(entry.InstallLocation)
-		if err != nil {
-			// Skip marketplaces we can't load
-			continue
-		}
-
-		// Look up repo/source from PopularMarketplaces for known marketplaces
-		var marketplaceRepo, marketplaceSource string
-		for _, pm := range marketplace.PopularMarketplaces {
-			if pm.Name == marketplaceName {
-				marketplaceRepo = pm.Repo
-				marketplaceSource, _ = marketplace.DeriveSource(pm.Repo)
-				break
-			}
-		}
-
-		// Track duplicates within this marketplace
-		seenInThisMarketplace := make(map[string]bool)
-
-		for _, mp := range manifest.Plugins {
-			// Skip duplicates within this marketplace
-			if seenInThisMarketplace[mp.Name] {
-				continue
-			}
-			seenInThisMarketplace[mp.Name] = true
-
+	// 1. Process installed marketplaces first. Manifests are parsed in
+	// parallel; merging walks marketplaces in name order, so which duplicate
+	// wins doesn't depend on scheduling.
+	var plugins []plugin.Plugin
+	for _, loaded := range loadInstalledMarketplaces(marketplaces, installedSet, marketplace.MaxConcurrentFetches) {
+		for _, p := range loaded {
 			// Skip if seen from a different marketplace
-			if existingMarket, exists := seenPluginNames[mp.Name]; exists && existingMarket != marketplaceName {
+			if existingMarket, exists := seenPluginNames[p.Name]; exists && existingMarket != p.Marketplace {
 				continue
 			}
-			seenPluginNames[mp.Name] = marketplaceName
-
-			p := convertMarketplacePlugin(mp, marketplaceName, marketplaceRepo, marketplaceSource, false, installedSet, entry.InstallLocation)
+			seenPluginNames[p.Name] = p.Marketplace
 			plugins = append(plugins, p)
 		}
 	}
 
 	// 2. Discover popular marketplaces (best effort - don't fail if this fails)
-	discovered, _ := marketplace.DiscoverPopularMarketplaces()
-	for marketplaceName, disc := range discovered {
+	var discovered map[string]*marketplace.DiscoveredMarketplace
+	if opts.CachedOnly {
+		discovered = marketplace.DiscoverCachedMarketplaces()
+	} else {
+		discovered, _ = marketplace.DiscoverPopularMarketplaces(ctx, opts.NoCache)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	for _, marketplaceName := range slices.Sorted(maps.Keys(discovered)) {
+		disc := discovered[marketplaceName]
 		// Skip if we already processed this marketplace from installed
-		if processedMarketplaces[marketplaceName] {
+		if _, ok := marketplaces[marketplaceName]; ok {
 			continue
 		}
 
@@ -210,9 +241,84 @@ func LoadAllPlugins() ([]plugin.Plugin, error) {
 		}
 	}
 
+	ApplyOverrides(plugins, overrides)
+
+	sort.SliceStable(plugins, func(i, j int) bool {
+		if plugins[i].Marketplace != plugins[j].Marketplace {
+			return plugins[i].Marketplace < plugins[j].Marketplace
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+
 	return plugins, nil
 }
 
+// loadInstalledMarketplaces parses each installed marketplace's manifest on
+// up to workers goroutines and converts its plugins. Results are returned in
+// marketplace name order, each deduplicated within its own marketplace;
+// marketplaces whose manifest can't be loaded yield no plugins.
+func loadInstalledMarketplaces(marketplaces KnownMarketplaces, installedSet map[string]PluginInstall, workers int) [][]plugin.Plugin {
+	names := slices.Sorted(maps.Keys(marketplaces))
+	results := make([][]plugin.Plugin, len(names))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(workers, 1)) // Semaphore for concurrency limiting
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, marketplaceName string, entry MarketplaceEntry) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// Each goroutine writes only its own slot
+			results[i] = loadInstalledMarketplace(marketplaceName, entry, installedSet)
+		}(i, name, marketplaces[name])
+	}
+	wg.Wait()
+
+	return results
+}
+
+// loadInstalledMarketplace converts one installed marketplace's plugins,
+// skipping duplicates within it. Returns nil if the manifest can't be loaded.
+func loadInstalledMarketplace(marketplaceName string, entry MarketplaceEntry, installedSet map[string]PluginInstall) []plugin.Plugin {
+	manifest, err := LoadMarketplaceManifest(entry.InstallLocation)
+	if err != nil {
+		// Skip marketplaces we can't load
+		return nil
+	}
+
+	// Look up repo/source from PopularMarketplaces for known marketplaces,
+	// falling back to the GitHub or GitLab repo recorded in its source
+	var marketplaceRepo, marketplaceSource string
+	for _, pm := range marketplace.PopularMarketplaces {
+		if pm.Name == marketplaceName {
+			marketplaceRepo = pm.Repo
+			break
+		}
+	}
+	if marketplaceRepo == "" {
+		marketplaceRepo = marketplace.RepoURLForSource(entry.Source.Source, entry.Source.Repo)
+	}
+	if marketplaceRepo != "" {
+		marketplaceSource, _ = marketplace.DeriveSource(marketplaceRepo)
+	}
+
+	var plugins []plugin.Plugin
+	seenInThisMarketplace := make(map[string]bool)
+	for _, mp := range manifest.Plugins {
+		// Skip duplicates within this marketplace
+		if seenInThisMarketplace[mp.Name] {
+			continue
+		}
+		seenInThisMarketplace[mp.Name] = true
+
+		plugins = append(plugins, convertMarketplacePlugin(mp, marketplaceName, marketplaceRepo, marketplaceSource, false, installedSet, entry.InstallLocation))
+	}
+	return plugins
+}
+
 // convertMarketplacePlugin converts a MarketplacePlugin to a Plugin.
 // marketplacePath is the local path to the marketplace directory (empty for discovered marketplaces).
 func convertMarketplacePlugin(
@@ -259,6 +365,7 @@ func convertMarketplacePlugin(
 		Marketplace:       marketplaceName,
 		MarketplaceRepo:   marketplaceRepo,
 		MarketplaceSource: marketplaceSource,
+		MarketplaceBranch: marketplace.CachedDefaultBranch(marketplaceSource),
 		Installed:         isInstalled,
 		IsDiscoverable:    isDiscoverable,
 		Source:            mp.Source,
@@ -266,6 +373,7 @@ func convertMarketplacePlugin(
 		Repository:        mp.Repository,
 		License:           mp.License,
 		Tags:              mp.Tags,
+		PluginJSONSHA256:  mp.SHA256,
 		HasLSPServers:     mp.HasLSPServers,
 		IsExternalURL:     mp.IsExternalURL,
 		IsIncomplete:      isIncomplete,
@@ -273,6 +381,15 @@ func convertMarketplacePlugin(
 
 	if isInstalled {
 		p.InstallPath = install.InstallPath
+		if checked, err := time.Parse(time.RFC3339, install.LastCheckedAt); err == nil {
+			p.LastCheckedAt = checked
+		}
+		for _, ts := range []string{install.LastUpdated, install.InstalledAt} {
+			if updated, err := time.Parse(time.RFC3339, ts); err == nil {
+				p.UpdatedAt = updated
+				break
+			}
+		}
 	}
 
 	return p
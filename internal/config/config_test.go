@@ -2,10 +2,15 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/itsdevcoffee/plum/internal/marketplace"
 )
 
 func TestLoadKnownMarketplaces(t *testing.T) {
@@ -349,14 +354,15 @@ func TestMarketplaceEntryJSON(t *testing.T) {
 func TestPluginInstallJSON(t *testing.T) {
 	t.Run("marshal and unmarshal with optional fields", func(t *testing.T) {
 		original := PluginInstall{
-			Scope:        "project",
-			InstallPath:  "/path/to/plugin",
-			Version:      "2.0.0",
-			InstalledAt:  "2025-12-17T00:00:00.000Z",
-			LastUpdated:  "2025-12-17T12:00:00.000Z",
-			GitCommitSha: "def456",
-			IsLocal:      true,
-			ProjectPath:  "/path/to/project",
+			Scope:         "project",
+			InstallPath:   "/path/to/plugin",
+			Version:       "2.0.0",
+			InstalledAt:   "2025-12-17T00:00:00.000Z",
+			LastUpdated:   "2025-12-17T12:00:00.000Z",
+			GitCommitSha:  "def456",
+			LastCheckedAt: "2025-12-18T08:00:00Z",
+			IsLocal:       true,
+			ProjectPath:   "/path/to/project",
 		}
 
 		data, err := json.Marshal(original)
@@ -378,5 +384,214 @@ func TestPluginInstallJSON(t *testing.T) {
 		if !unmarshaled.IsLocal {
 			t.Error("IsLocal = false, want true")
 		}
+		if unmarshaled.LastCheckedAt != original.LastCheckedAt {
+			t.Errorf("LastCheckedAt = %q, want %q", unmarshaled.LastCheckedAt, original.LastCheckedAt)
+		}
 	})
 }
+
+func TestLoadCachedPlugins(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+	t.Setenv(OverridesEnvVar, "")
+
+	localDir := filepath.Join(tmpDir, "local-mkt")
+	popular := marketplace.PopularMarketplaces[0]
+	cachedEntry := map[string]any{
+		// Long expired: the fast path uses it anyway instead of fetching
+		"fetchedAt": time.Now().Add(-30 * 24 * time.Hour),
+		"source":    "github",
+		"manifest": map[string]any{
+			"name":    popular.Name,
+			"plugins": []map[string]any{{"name": "cached-plugin"}},
+		},
+	}
+	cachedData, err := json.Marshal(cachedEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		filepath.Join(tmpDir, "plugins", "known_marketplaces.json"): `{
+			"local-mkt": {
+				"source": {"source": "directory", "path": "` + filepath.ToSlash(localDir) + `"},
+				"installLocation": "` + filepath.ToSlash(localDir) + `"
+			}
+		}`,
+		filepath.Join(localDir, ".claude-plugin", "marketplace.json"): `{
+			"name": "local-mkt",
+			"plugins": [{"name": "local-plugin"}]
+		}`,
+		filepath.Join(tmpDir, "plum", "cache", "marketplaces", popular.Name+".json"): string(cachedData),
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plugins, err := LoadCachedPlugins()
+	if err != nil {
+		t.Fatalf("LoadCachedPlugins() error = %v", err)
+	}
+
+	got := make(map[string]bool) // full name -> discoverable
+	for _, p := range plugins {
+		got[p.FullName()] = p.IsDiscoverable
+	}
+	want := map[string]bool{
+		"local-plugin@local-mkt":        false,
+		"cached-plugin@" + popular.Name: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadCachedPlugins() = %v, want %v (uncached marketplaces must not be fetched)", got, want)
+	}
+}
+
+// writeMarketplaceFixtures creates n local marketplaces under dir with
+// pluginsPer plugins each, each plugin with a plugin.json. Every marketplace
+// also repeats its first plugin and shares a "common" plugin with the others,
+// so both kinds of deduplication are exercised.
+func writeMarketplaceFixtures(tb testing.TB, dir string, n, pluginsPer int) KnownMarketplaces {
+	tb.Helper()
+	known := make(KnownMarketplaces, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("mkt-%02d", i)
+		location := filepath.Join(dir, name)
+
+		var entries []string
+		for j := 0; j < pluginsPer; j++ {
+			pluginName := fmt.Sprintf("plugin-%02d-%02d", i, j)
+			entries = append(entries, fmt.Sprintf(`{"name": %q, "version": "1.0.%d", "source": "./plugins/%s"}`, pluginName, j, pluginName))
+			pluginJSON := filepath.Join(location, "plugins", pluginName, ".claude-plugin", "plugin.json")
+			if err := os.MkdirAll(filepath.Dir(pluginJSON), 0750); err != nil {
+				tb.Fatal(err)
+			}
+			if err := os.WriteFile(pluginJSON, []byte(`{"name": "`+pluginName+`"}`), 0600); err != nil {
+				tb.Fatal(err)
+			}
+		}
+		entries = append(entries, entries[0], `{"name": "common"}`)
+
+		manifest := fmt.Sprintf(`{"name": %q, "plugins": [%s]}`, name, strings.Join(entries, ","))
+		manifestPath := filepath.Join(location, ".claude-plugin", "marketplace.json")
+		if err := os.MkdirAll(filepath.Dir(manifestPath), 0750); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(manifestPath, []byte(manifest), 0600); err != nil {
+			tb.Fatal(err)
+		}
+
+		known[name] = MarketplaceEntry{
+			Source:          MarketplaceSource{Source: "github", Repo: "owner/" + name},
+			InstallLocation: location,
+		}
+	}
+	return known
+}
+
+func TestLoadInstalledMarketplaces_ParallelMatchesSerial(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	known := writeMarketplaceFixtures(t, tmpDir, 12, 6)
+	known["broken"] = MarketplaceEntry{InstallLocation: filepath.Join(tmpDir, "missing")}
+	installedSet := map[string]PluginInstall{
+		"plugin-03-01@mkt-03": {InstallPath: "/cache/plugin-03-01", LastUpdated: "2025-06-01T00:00:00Z"},
+	}
+
+	serial := loadInstalledMarketplaces(known, installedSet, 1)
+	for run := 0; run < 5; run++ {
+		parallel := loadInstalledMarketplaces(known, installedSet, marketplace.MaxConcurrentFetches)
+		if !reflect.DeepEqual(parallel, serial) {
+			t.Fatalf("run %d: parallel result differs from serial", run)
+		}
+	}
+
+	if len(serial) != len(known) {
+		t.Fatalf("expected a result slot per marketplace, got %d", len(serial))
+	}
+	// "broken" sorts first and yields nothing; the rest drop their repeat
+	if serial[0] != nil {
+		t.Errorf("broken marketplace should yield no plugins, got %d", len(serial[0]))
+	}
+	if got := len(serial[1]); got != 7 {
+		t.Errorf("mkt-00 yielded %d plugins, want 7 (6 + common, repeat dropped)", got)
+	}
+	if !serial[4][1].Installed {
+		t.Error("plugin-03-01@mkt-03 should be marked installed")
+	}
+}
+
+func TestLoadAllPlugins_DeterministicOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+	t.Setenv(OverridesEnvVar, "")
+
+	known := writeMarketplaceFixtures(t, tmpDir, 12, 3)
+	data, err := json.Marshal(known)
+	if err != nil {
+		t.Fatal(err)
+	}
+	knownPath := filepath.Join(tmpDir, "plugins", "known_marketplaces.json")
+	if err := os.MkdirAll(filepath.Dir(knownPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(knownPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := LoadCachedPlugins()
+	if err != nil {
+		t.Fatalf("LoadCachedPlugins() error = %v", err)
+	}
+	// 3 plugins per marketplace, plus "common" once, from the first marketplace
+	if len(first) != 12*3+1 {
+		t.Fatalf("expected %d plugins, got %d", 12*3+1, len(first))
+	}
+	for i := 1; i < len(first); i++ {
+		prev, cur := first[i-1], first[i]
+		if prev.Marketplace > cur.Marketplace || (prev.Marketplace == cur.Marketplace && prev.Name > cur.Name) {
+			t.Fatalf("plugins not sorted by marketplace then name: %s before %s", prev.FullName(), cur.FullName())
+		}
+	}
+	for _, p := range first {
+		if p.Name == "common" && p.Marketplace != "mkt-00" {
+			t.Errorf("common should come from mkt-00, the first marketplace by name, got %s", p.Marketplace)
+		}
+	}
+
+	for run := 0; run < 5; run++ {
+		again, err := LoadCachedPlugins()
+		if err != nil {
+			t.Fatalf("LoadCachedPlugins() error = %v", err)
+		}
+		if !reflect.DeepEqual(again, first) {
+			t.Fatalf("run %d: LoadCachedPlugins() output changed between runs", run)
+		}
+	}
+}
+
+func BenchmarkLoadInstalledMarketplaces(b *testing.B) {
+	tmpDir := b.TempDir()
+	b.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+	known := writeMarketplaceFixtures(b, tmpDir, 24, 40)
+	installedSet := map[string]PluginInstall{}
+
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"parallel", marketplace.MaxConcurrentFetches},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				loadInstalledMarketplaces(known, installedSet, bc.workers)
+			}
+		})
+	}
+}
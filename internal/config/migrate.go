@@ -0,0 +1,101 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// installedPluginsV1 is the registry format used before installs were
+// tracked per scope: one install object per plugin instead of a list
+type installedPluginsV1 struct {
+	Version int                      `json:"version"`
+	Plugins map[string]PluginInstall `json:"plugins"`
+}
+
+// isInstalledPluginsV1 reports whether registry data is in the v1 format:
+// version 1, or plugin entries that are objects rather than lists
+func isInstalledPluginsV1(data []byte) bool {
+	var probe struct {
+		Version int                        `json:"version"`
+		Plugins map[string]json.RawMessage `json:"plugins"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	if probe.Version == 1 {
+		return true
+	}
+	for _, raw := range probe.Plugins {
+		return bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{"))
+	}
+	return false
+}
+
+// installedPluginsFromV1 converts v1 registry data into the v2 format in
+// memory. v1 had no scopes, so every install becomes a user-scope install.
+func installedPluginsFromV1(data []byte) (*InstalledPluginsV2, error) {
+	var v1 installedPluginsV1
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return nil, fmt.Errorf("invalid v1 plugin registry: %w", err)
+	}
+
+	v2 := &InstalledPluginsV2{Version: 2, Plugins: make(map[string][]PluginInstall, len(v1.Plugins))}
+	for fullName, install := range v1.Plugins {
+		if install.Scope == "" {
+			install.Scope = "user"
+		}
+		v2.Plugins[fullName] = []PluginInstall{install}
+	}
+	return v2, nil
+}
+
+// InstalledPluginsIsV1 reports whether the registry on disk is still in the
+// v1 format. LoadInstalledPlugins converts it on read without touching the
+// file; it's only rewritten as v2 when plum next saves the registry.
+func InstalledPluginsIsV1() (bool, error) {
+	path, err := InstalledPluginsPath()
+	if err != nil {
+		return false, err
+	}
+	// #nosec G304 -- path is derived from known config dirs, not untrusted input
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isInstalledPluginsV1(data), nil
+}
+
+// BackupInstalledPluginsV1 copies a v1 registry to <path>.v1.bak before it is
+// overwritten with the v2 format, and returns the backup path ("" when the
+// registry isn't v1). An existing backup is kept, so the original survives a
+// migration that was interrupted and re-run.
+func BackupInstalledPluginsV1() (string, error) {
+	path, err := InstalledPluginsPath()
+	if err != nil {
+		return "", err
+	}
+	// #nosec G304 -- path is derived from known config dirs, not untrusted input
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !isInstalledPluginsV1(data) {
+		return "", nil
+	}
+
+	backupPath := path + ".v1.bak"
+	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+		if err := os.WriteFile(backupPath, data, 0600); err != nil {
+			return "", fmt.Errorf("failed to back up v1 plugin registry: %w", err)
+		}
+	}
+	return backupPath, nil
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIsInstalledPluginsV1(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"version 1", `{"version": 1, "plugins": {}}`, true},
+		{"object entries without version", `{"plugins": {"a@m": {"version": "1.0.0"}}}`, true},
+		{"version 2", `{"version": 2, "plugins": {"a@m": [{"scope": "user"}]}}`, false},
+		{"list entries without version", `{"plugins": {"a@m": []}}`, false},
+		{"empty v2", `{"version": 2, "plugins": {}}`, false},
+		{"invalid JSON", `{`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInstalledPluginsV1([]byte(tt.data)); got != tt.want {
+				t.Errorf("isInstalledPluginsV1(%s) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadInstalledPlugins_MigratesV1(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	pluginsDir := filepath.Join(tmpDir, "plugins")
+	if err := os.MkdirAll(pluginsDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	registryPath := filepath.Join(pluginsDir, "installed_plugins.json")
+	v1 := `{
+		"version": 1,
+		"plugins": {
+			"alpha@mkt": {
+				"version": "1.0.0",
+				"installedAt": "2025-06-01T00:00:00.000Z",
+				"lastUpdated": "2025-06-02T00:00:00.000Z",
+				"installPath": "/cache/mkt/alpha",
+				"gitCommitSha": "abc123",
+				"isLocal": false
+			},
+			"beta@local-mkt": {
+				"version": "0.2.0",
+				"installPath": "/src/beta",
+				"isLocal": true
+			}
+		}
+	}`
+	if err := os.WriteFile(registryPath, []byte(v1), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := LoadInstalledPlugins()
+	if err != nil {
+		t.Fatalf("LoadInstalledPlugins() error = %v", err)
+	}
+
+	want := &InstalledPluginsV2{
+		Version: 2,
+		Plugins: map[string][]PluginInstall{
+			"alpha@mkt": {{
+				Scope:        "user",
+				InstallPath:  "/cache/mkt/alpha",
+				Version:      "1.0.0",
+				InstalledAt:  "2025-06-01T00:00:00.000Z",
+				LastUpdated:  "2025-06-02T00:00:00.000Z",
+				GitCommitSha: "abc123",
+			}},
+			"beta@local-mkt": {{
+				Scope:       "user",
+				InstallPath: "/src/beta",
+				Version:     "0.2.0",
+				IsLocal:     true,
+			}},
+		},
+	}
+	if !reflect.DeepEqual(installed, want) {
+		t.Errorf("migrated registry = %+v\nwant %+v", installed, want)
+	}
+
+	// Reading never rewrites Claude Code's file or leaves a backup behind
+	data, err := os.ReadFile(registryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != v1 {
+		t.Errorf("registry on disk changed on read:\n%s", data)
+	}
+	if _, err := os.Stat(registryPath + ".v1.bak"); !os.IsNotExist(err) {
+		t.Errorf("no backup should be written on read, stat err = %v", err)
+	}
+	if isV1, err := InstalledPluginsIsV1(); err != nil || !isV1 {
+		t.Errorf("InstalledPluginsIsV1() = %v, %v; want true", isV1, err)
+	}
+
+	// A backup is taken before a writer replaces the file, and kept after
+	backupPath, err := BackupInstalledPluginsV1()
+	if err != nil {
+		t.Fatalf("BackupInstalledPluginsV1() error = %v", err)
+	}
+	if backupPath != registryPath+".v1.bak" {
+		t.Errorf("backup path = %q", backupPath)
+	}
+	if err := os.WriteFile(registryPath, []byte(`{"version": 2, "plugins": {}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatalf("v1 backup missing: %v", err)
+	}
+	if string(backup) != v1 {
+		t.Errorf("v1 backup = %s, want the original file", backup)
+	}
+
+	// Once the file is v2 there's nothing more to back up
+	if backupPath, err := BackupInstalledPluginsV1(); err != nil || backupPath != "" {
+		t.Errorf("BackupInstalledPluginsV1() on v2 = %q, %v; want no backup", backupPath, err)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// OverridesEnvVar names the environment variable pointing at a local overrides file
+const OverridesEnvVar = "PLUM_OVERRIDES"
+
+// PluginOverrides represents the overrides file structure.
+// Plugins are keyed by full name (plugin@marketplace), e.g.:
+//
+//	{
+//	  "plugins": {
+//	    "docker-helper@plum-fixtures": {
+//	      "displayName": "Docker Helper",
+//	      "category": "devops",
+//	      "keywords": ["docker", "compose"]
+//	    }
+//	  }
+//	}
+type PluginOverrides struct {
+	Plugins map[string]PluginOverride `json:"plugins"`
+}
+
+// PluginOverride holds local metadata for a single plugin.
+// Only non-empty fields replace the marketplace values.
+type PluginOverride struct {
+	DisplayName string   `json:"displayName,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// LoadOverrides loads the overrides file named by PLUM_OVERRIDES.
+// Returns nil without error when the variable is unset.
+func LoadOverrides() (*PluginOverrides, error) {
+	path := os.Getenv(OverridesEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+
+	// #nosec G304 -- path is explicitly provided by the user via PLUM_OVERRIDES
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s file: %w", OverridesEnvVar, err)
+	}
+
+	var overrides PluginOverrides
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse %s file %s: %w", OverridesEnvVar, path, err)
+	}
+
+	return &overrides, nil
+}
+
+// ApplyOverrides merges overrides onto plugins by full name, in place
+func ApplyOverrides(plugins []plugin.Plugin, overrides *PluginOverrides) {
+	if overrides == nil || len(overrides.Plugins) == 0 {
+		return
+	}
+
+	for i := range plugins {
+		o, ok := overrides.Plugins[plugins[i].FullName()]
+		if !ok {
+			continue
+		}
+
+		p := &plugins[i]
+		if o.DisplayName != "" {
+			p.DisplayName = o.DisplayName
+		}
+		if o.Description != "" {
+			p.Description = o.Description
+		}
+		if o.Category != "" {
+			p.Category = o.Category
+		}
+		if len(o.Keywords) > 0 {
+			p.Keywords = o.Keywords
+		}
+		if len(o.Tags) > 0 {
+			p.Tags = o.Tags
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/search"
+)
+
+func TestLoadOverrides(t *testing.T) {
+	t.Run("unset", func(t *testing.T) {
+		t.Setenv(OverridesEnvVar, "")
+
+		overrides, err := LoadOverrides()
+		if err != nil {
+			t.Fatalf("LoadOverrides() error = %v", err)
+		}
+		if overrides != nil {
+			t.Errorf("LoadOverrides() = %v, want nil", overrides)
+		}
+	})
+
+	t.Run("valid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "overrides.json")
+		data := `{"plugins": {"docker-helper@market": {"displayName": "Docker Helper", "keywords": ["compose"]}}}`
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(OverridesEnvVar, path)
+
+		overrides, err := LoadOverrides()
+		if err != nil {
+			t.Fatalf("LoadOverrides() error = %v", err)
+		}
+		o, ok := overrides.Plugins["docker-helper@market"]
+		if !ok {
+			t.Fatal("override for docker-helper@market not loaded")
+		}
+		if o.DisplayName != "Docker Helper" {
+			t.Errorf("DisplayName = %q, want %q", o.DisplayName, "Docker Helper")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv(OverridesEnvVar, filepath.Join(t.TempDir(), "missing.json"))
+
+		if _, err := LoadOverrides(); err == nil {
+			t.Error("LoadOverrides() expected error for missing file")
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "overrides.json")
+		if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(OverridesEnvVar, path)
+
+		if _, err := LoadOverrides(); err == nil {
+			t.Error("LoadOverrides() expected error for invalid JSON")
+		}
+	})
+}
+
+func TestApplyOverrides(t *testing.T) {
+	plugins := []plugin.Plugin{
+		{
+			Name:        "docker-helper",
+			Marketplace: "market",
+			Description: "Docker tools",
+			Category:    "devops",
+			Keywords:    []string{"docker"},
+		},
+		{
+			Name:        "docker-helper",
+			Marketplace: "other-market",
+			Category:    "devops",
+		},
+	}
+
+	ApplyOverrides(plugins, &PluginOverrides{
+		Plugins: map[string]PluginOverride{
+			"docker-helper@market": {
+				DisplayName: "Docker Helper",
+				Category:    "containers",
+				Keywords:    []string{"docker", "kubernetes"},
+				Tags:        []string{"featured"},
+			},
+		},
+	})
+
+	p := plugins[0]
+	if p.DisplayName != "Docker Helper" {
+		t.Errorf("DisplayName = %q, want %q", p.DisplayName, "Docker Helper")
+	}
+	if p.Category != "containers" {
+		t.Errorf("Category = %q, want %q", p.Category, "containers")
+	}
+	if !reflect.DeepEqual(p.Tags, []string{"featured"}) {
+		t.Errorf("Tags = %v, want [featured]", p.Tags)
+	}
+	// Empty override fields keep the marketplace value
+	if p.Description != "Docker tools" {
+		t.Errorf("Description = %q, want unchanged %q", p.Description, "Docker tools")
+	}
+
+	// Overrides match by full name only
+	if plugins[1].DisplayName != "" || plugins[1].Category != "devops" {
+		t.Errorf("plugin from other marketplace should be untouched, got %+v", plugins[1])
+	}
+
+	// Search reflects the overridden keywords
+	results := search.Search("kubernetes", plugins)
+	if len(results) != 1 || results[0].Plugin.FullName() != "docker-helper@market" {
+		t.Errorf("Search(kubernetes) = %v, want docker-helper@market", results)
+	}
+}
+
+func TestApplyOverrides_Nil(t *testing.T) {
+	plugins := []plugin.Plugin{{Name: "a", Marketplace: "m", Category: "c"}}
+	ApplyOverrides(plugins, nil)
+
+	if plugins[0].Category != "c" {
+		t.Errorf("Category = %q, want unchanged", plugins[0].Category)
+	}
+}
@@ -8,20 +8,31 @@ import (
 )
 
 // ClaudeConfigDir returns the path to the Claude Code configuration directory
-// Respects CLAUDE_CONFIG_DIR environment variable for custom locations
+// Respects CLAUDE_CONFIG_DIR environment variable for custom locations, then
+// $XDG_CONFIG_HOME/claude when that directory exists. Many desktops set
+// XDG_CONFIG_HOME while Claude Code still uses ~/.claude, so the variable
+// alone isn't enough.
 func ClaudeConfigDir() (string, error) {
 	// 1. Check environment variable override
 	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
 		return dir, nil
 	}
 
-	// 2. Get user home directory
+	// 2. XDG base directory, only if Claude Code actually lives there
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		dir := filepath.Join(xdg, "claude")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+
+	// 3. Get user home directory
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
 
-	// 3. Platform-specific defaults
+	// 4. Platform-specific defaults
 	if runtime.GOOS == "windows" {
 		// Windows: %APPDATA%\ClaudeCode
 		appdata := os.Getenv("APPDATA")
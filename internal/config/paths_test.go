@@ -22,12 +22,73 @@ func TestClaudeConfigDir(t *testing.T) {
 		}
 	})
 
+	t.Run("with XDG_CONFIG_HOME only", func(t *testing.T) {
+		xdgDir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(xdgDir, "claude"), 0750); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", xdgDir)
+
+		got, err := ClaudeConfigDir()
+		if err != nil {
+			t.Fatalf("ClaudeConfigDir() error = %v", err)
+		}
+		want := filepath.Join(xdgDir, "claude")
+		if got != want {
+			t.Errorf("ClaudeConfigDir() = %q, want %q", got, want)
+		}
+
+		// Everything under the config dir follows it
+		plugins, err := ClaudePluginsDir()
+		if err != nil {
+			t.Fatalf("ClaudePluginsDir() error = %v", err)
+		}
+		if wantPlugins := filepath.Join(xdgDir, "claude", "plugins"); plugins != wantPlugins {
+			t.Errorf("ClaudePluginsDir() = %q, want %q", plugins, wantPlugins)
+		}
+	})
+
+	t.Run("XDG_CONFIG_HOME without a claude directory", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Skipping Unix test on Windows")
+		}
+
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		got, err := ClaudeConfigDir()
+		if err != nil {
+			t.Fatalf("ClaudeConfigDir() error = %v", err)
+		}
+		if want := filepath.Join(home, ".claude"); got != want {
+			t.Errorf("ClaudeConfigDir() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("CLAUDE_CONFIG_DIR takes precedence over XDG_CONFIG_HOME", func(t *testing.T) {
+		customDir := "/custom/claude/config"
+		t.Setenv("CLAUDE_CONFIG_DIR", customDir)
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		got, err := ClaudeConfigDir()
+		if err != nil {
+			t.Fatalf("ClaudeConfigDir() error = %v", err)
+		}
+		if got != customDir {
+			t.Errorf("ClaudeConfigDir() = %q, want %q", got, customDir)
+		}
+	})
+
 	t.Run("default path on unix", func(t *testing.T) {
 		if runtime.GOOS == "windows" {
 			t.Skip("Skipping Unix test on Windows")
 		}
 
 		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
 
 		got, err := ClaudeConfigDir()
 		if err != nil {
@@ -47,6 +108,7 @@ func TestClaudeConfigDir(t *testing.T) {
 		}
 
 		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
 		appdata := "C:\\Users\\TestUser\\AppData\\Roaming"
 		t.Setenv("APPDATA", appdata)
 
@@ -67,6 +129,7 @@ func TestClaudeConfigDir(t *testing.T) {
 		}
 
 		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
 		t.Setenv("APPDATA", "")
 
 		got, err := ClaudeConfigDir()
@@ -83,6 +146,7 @@ func TestClaudeConfigDir(t *testing.T) {
 
 	t.Run("path format validation", func(t *testing.T) {
 		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
 
 		got, err := ClaudeConfigDir()
 		if err != nil {
@@ -119,6 +183,7 @@ func TestClaudePluginsDir(t *testing.T) {
 
 	t.Run("absolute path", func(t *testing.T) {
 		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
 
 		got, err := ClaudePluginsDir()
 		if err != nil {
@@ -153,6 +218,7 @@ func TestKnownMarketplacesPath(t *testing.T) {
 
 	t.Run("absolute path with json extension", func(t *testing.T) {
 		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
 
 		got, err := KnownMarketplacesPath()
 		if err != nil {
@@ -187,6 +253,7 @@ func TestInstalledPluginsPath(t *testing.T) {
 
 	t.Run("absolute path with json extension", func(t *testing.T) {
 		t.Setenv("CLAUDE_CONFIG_DIR", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
 
 		got, err := InstalledPluginsPath()
 		if err != nil {
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFile is the name of the optional project-local plum config
+const ProjectConfigFile = ".plum.json"
+
+// ProjectConfig holds project-local plum preferences, e.g.:
+//
+//	{
+//	  "defaultScope": "project"
+//	}
+type ProjectConfig struct {
+	// DefaultScope is used when a command's --scope flag isn't passed
+	DefaultScope string `json:"defaultScope,omitempty"`
+}
+
+// FindProjectConfig walks up from dir looking for .plum.json.
+// Returns "" without error when no file is found.
+func FindProjectConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectConfigFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig loads the nearest .plum.json at or above dir.
+// Returns nil without error when there is none.
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	path, err := FindProjectConfig(dir)
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	// #nosec G304 -- path is a .plum.json found by walking up from the working directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var cfg ProjectConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
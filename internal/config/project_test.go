@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		cfg, err := LoadProjectConfig(t.TempDir())
+		if err != nil {
+			t.Fatalf("LoadProjectConfig() error = %v", err)
+		}
+		if cfg != nil {
+			t.Errorf("LoadProjectConfig() = %+v, want nil", cfg)
+		}
+	})
+
+	t.Run("found in parent", func(t *testing.T) {
+		root := t.TempDir()
+		nested := filepath.Join(root, "a", "b")
+		if err := os.MkdirAll(nested, 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, ProjectConfigFile), []byte(`{"defaultScope": "local"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		path, err := FindProjectConfig(nested)
+		if err != nil || path != filepath.Join(root, ProjectConfigFile) {
+			t.Errorf("FindProjectConfig() = %q, %v", path, err)
+		}
+
+		cfg, err := LoadProjectConfig(nested)
+		if err != nil {
+			t.Fatalf("LoadProjectConfig() error = %v", err)
+		}
+		if cfg == nil || cfg.DefaultScope != "local" {
+			t.Errorf("LoadProjectConfig() = %+v, want defaultScope local", cfg)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(`{`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadProjectConfig(dir); err == nil {
+			t.Error("expected parse error")
+		}
+	})
+}
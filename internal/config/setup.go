@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrClaudeNotInstalled is returned when the Claude Code config directory
+// (or its plugins directory) doesn't exist, usually because Claude Code has
+// never been installed or run
+var ErrClaudeNotInstalled = errors.New("claude Code config not found")
+
+// CorruptFileError is returned when a Claude Code config file exists but
+// can't be parsed
+type CorruptFileError struct {
+	Path string
+	Err  error
+}
+
+func (e *CorruptFileError) Error() string {
+	return fmt.Sprintf("invalid Claude Code config file %s: %v", e.Path, e.Err)
+}
+
+func (e *CorruptFileError) Unwrap() error {
+	return e.Err
+}
+
+// ClaudeInstalled reports whether the Claude Code plugins directory exists.
+// Claude Code creates it the first time it runs.
+func ClaudeInstalled() bool {
+	dir, err := ClaudePluginsDir()
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// SetupGuidance returns a user-facing explanation of how to fix err when it
+// comes from a missing or corrupt Claude Code configuration, or "" for any
+// other error
+func SetupGuidance(err error) string {
+	var corrupt *CorruptFileError
+	switch {
+	case errors.Is(err, ErrClaudeNotInstalled):
+		location := "~/.claude"
+		if dir, dirErr := ClaudeConfigDir(); dirErr == nil {
+			location = dir
+		}
+		return fmt.Sprintf(`Claude Code configuration was not found in %s.
+
+plum manages plugins for Claude Code, so Claude Code has to be set up first:
+  1. Install Claude Code: https://docs.anthropic.com/en/docs/claude-code
+  2. Run 'claude' once to create its configuration
+  3. Run /plugin inside Claude Code to add a marketplace, then start plum again
+
+If your configuration lives somewhere else, set CLAUDE_CONFIG_DIR to that directory.`, location)
+
+	case errors.As(err, &corrupt):
+		return fmt.Sprintf(`Claude Code configuration file %s could not be read:
+  %v
+
+The file exists but isn't valid JSON. Fix it by hand, or move it aside and run
+/plugin inside Claude Code to recreate it.`, corrupt.Path, corrupt.Err)
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClaudeInstalled(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	if ClaudeInstalled() {
+		t.Error("ClaudeInstalled() = true without a plugins directory")
+	}
+
+	// A plain file where the directory should be doesn't count
+	pluginsDir := filepath.Join(tmpDir, "plugins")
+	if err := os.WriteFile(pluginsDir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if ClaudeInstalled() {
+		t.Error("ClaudeInstalled() = true when plugins is a file")
+	}
+
+	if err := os.Remove(pluginsDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(pluginsDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if !ClaudeInstalled() {
+		t.Error("ClaudeInstalled() = false with a plugins directory")
+	}
+}
+
+func TestLoadKnownMarketplaces_SetupErrors(t *testing.T) {
+	t.Run("not installed", func(t *testing.T) {
+		t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+		_, err := LoadKnownMarketplaces()
+		if !errors.Is(err, ErrClaudeNotInstalled) {
+			t.Fatalf("LoadKnownMarketplaces() error = %v, want ErrClaudeNotInstalled", err)
+		}
+	})
+
+	t.Run("installed without marketplaces", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+		if err := os.MkdirAll(filepath.Join(tmpDir, "plugins"), 0750); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := LoadKnownMarketplaces()
+		if err == nil || errors.Is(err, ErrClaudeNotInstalled) {
+			t.Fatalf("LoadKnownMarketplaces() error = %v, want a missing marketplaces error", err)
+		}
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+		path := filepath.Join(tmpDir, "plugins", "known_marketplaces.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := LoadKnownMarketplaces()
+		var corrupt *CorruptFileError
+		if !errors.As(err, &corrupt) {
+			t.Fatalf("LoadKnownMarketplaces() error = %v, want *CorruptFileError", err)
+		}
+		if corrupt.Path != path {
+			t.Errorf("CorruptFileError.Path = %q, want %q", corrupt.Path, path)
+		}
+	})
+}
+
+func TestSetupGuidance(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+
+	tests := []struct {
+		name string
+		err  error
+		want []string // Substrings the guidance must contain; nil means no guidance
+	}{
+		{
+			name: "not installed",
+			err:  fmt.Errorf("failed to load plugins: %w", ErrClaudeNotInstalled),
+			want: []string{"was not found in " + configDir, "Install Claude Code", "CLAUDE_CONFIG_DIR"},
+		},
+		{
+			name: "corrupt file",
+			err:  fmt.Errorf("failed to load plugins: %w", &CorruptFileError{Path: "/x/known_marketplaces.json", Err: errors.New("unexpected end of JSON input")}),
+			want: []string{"/x/known_marketplaces.json", "unexpected end of JSON input", "isn't valid JSON"},
+		},
+		{
+			name: "other error",
+			err:  errors.New("permission denied"),
+		},
+		{
+			name: "nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SetupGuidance(tt.err)
+			if tt.want == nil {
+				if got != "" {
+					t.Errorf("SetupGuidance() = %q, want no guidance", got)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("SetupGuidance() missing %q:\n%s", want, got)
+				}
+			}
+			// Missing and corrupt config must not be confused
+			if strings.Contains(got, "Install Claude Code") != (tt.name == "not installed") {
+				t.Errorf("SetupGuidance() gave the wrong kind of guidance:\n%s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// Format is a plugin list export format
+type Format int
+
+const (
+	FormatMarkdown Format = iota // Markdown table
+	FormatJSON                   // JSON array of entries
+)
+
+// String returns the format's display name
+func (f Format) String() string {
+	if f == FormatJSON {
+		return "JSON"
+	}
+	return "Markdown"
+}
+
+// Extension returns the file extension for the format, without the dot
+func (f Format) Extension() string {
+	if f == FormatJSON {
+		return "json"
+	}
+	return "md"
+}
+
+// Entry is one exported plugin
+type Entry struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Marketplace    string `json:"marketplace"`
+	Description    string `json:"description"`
+	InstallCommand string `json:"installCommand"`
+}
+
+// Entries builds export entries for plugins, keeping their order
+func Entries(plugins []plugin.Plugin) []Entry {
+	entries := make([]Entry, len(plugins))
+	for i, p := range plugins {
+		entries[i] = Entry{
+			Name:           p.Name,
+			Version:        p.Version,
+			Marketplace:    p.Marketplace,
+			Description:    p.Description,
+			InstallCommand: p.InstallCommand(),
+		}
+	}
+	return entries
+}
+
+// Write serializes entries to w in the given format
+func Write(w io.Writer, format Format, entries []Entry) error {
+	if format == FormatJSON {
+		return WriteJSON(w, entries)
+	}
+	return WriteMarkdown(w, entries)
+}
+
+// WriteJSON writes entries as an indented JSON array
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// WriteMarkdown writes entries as a Markdown table
+func WriteMarkdown(w io.Writer, entries []Entry) error {
+	var b strings.Builder
+	b.WriteString("# Plugins\n\n")
+	b.WriteString("| Name | Version | Marketplace | Description | Install |\n")
+	b.WriteString("|------|---------|-------------|-------------|---------|\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | `%s` |\n",
+			markdownCell(e.Name), markdownCell(e.Version), markdownCell(e.Marketplace),
+			markdownCell(e.Description), e.InstallCommand)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell makes text safe inside a table cell: pipes are escaped and
+// line breaks become spaces
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
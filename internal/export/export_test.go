@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+func testEntries() []Entry {
+	return Entries([]plugin.Plugin{
+		{Name: "alpha", Version: "1.0.0", Marketplace: "mkt", Description: "First plugin"},
+		{Name: "beta", Version: "0.2.0", Marketplace: "other", Description: "Pipes | and\nnewlines"},
+	})
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatMarkdown, testEntries()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Plugins\n\n" +
+		"| Name | Version | Marketplace | Description | Install |\n" +
+		"|------|---------|-------------|-------------|---------|\n" +
+		"| alpha | 1.0.0 | mkt | First plugin | `/plugin install alpha@mkt` |\n" +
+		"| beta | 0.2.0 | other | Pipes \\| and newlines | `/plugin install beta@other` |\n"
+	if buf.String() != want {
+		t.Errorf("markdown output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, testEntries()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	want := map[string]string{
+		"name":           "alpha",
+		"version":        "1.0.0",
+		"marketplace":    "mkt",
+		"description":    "First plugin",
+		"installCommand": "/plugin install alpha@mkt",
+	}
+	for key, value := range want {
+		if got[0][key] != value {
+			t.Errorf("entry[0].%s = %q, want %q", key, got[0][key], value)
+		}
+	}
+
+	// An empty list is still an array
+	buf.Reset()
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("empty export = %q, want []", buf.String())
+	}
+}
@@ -0,0 +1,360 @@
+package install
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+)
+
+const (
+	// maxTotalDownloadSize is the maximum total download size per plugin (50 MB)
+	maxTotalDownloadSize = 50 << 20
+
+	// maxConcurrentFileDownloads limits parallel command/hook downloads per plugin
+	maxConcurrentFileDownloads = 4
+)
+
+// limitDownloads wraps download so the combined size of everything it fetches
+// stays under limit. Safe for concurrent use.
+func limitDownloads(download func(string) ([]byte, error), limit int64) func(string) ([]byte, error) {
+	var total atomic.Int64
+	return func(url string) ([]byte, error) {
+		data, err := download(url)
+		if err != nil {
+			return nil, err
+		}
+		if total.Add(int64(len(data))) > limit {
+			return nil, fmt.Errorf("plugin download size exceeded limit (%d MB)", limit>>20)
+		}
+		return data, nil
+	}
+}
+
+// ManifestFiles is the part of plugin.json that lists downloadable files
+type ManifestFiles struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Description string            `json:"description"`
+	Commands    []string          `json:"commands"`
+	Hooks       []string          `json:"hooks"`
+	SHA256      map[string]string `json:"sha256"` // path -> hex SHA-256
+}
+
+// pluginLocation returns the marketplace repo (host and owner/repo) and the
+// plugin's path within it
+func pluginLocation(target *Target) (repo marketplace.RepoLocation, sourcePath string, err error) {
+	repo, err = marketplace.ParseRepoLocation(target.MarketplaceRepo)
+	if err != nil {
+		return repo, "", fmt.Errorf("failed to derive source from repo: %w", err)
+	}
+
+	return repo, marketplace.PluginSourcePath(target.Source, target.Name), nil
+}
+
+// fetchPluginJSON downloads plugin.json and returns it with the ref it came
+// from. A recorded commit is used as is; pinned versions try their git tags;
+// otherwise the repo's default branch is tried first, then "main" and
+// "master" on 404.
+func fetchPluginJSON(target *Target, repo marketplace.RepoLocation, sourcePath string, download func(string) ([]byte, error)) (string, []byte, error) {
+	refs := repo.BranchCandidates
+	switch {
+	case target.Commit != "":
+		refs = func() []string { return []string{target.Commit} }
+	case target.Pinned:
+		refs = func() []string { return tagCandidates(target.Version) }
+	}
+
+	var (
+		ref           string
+		pluginJSONURL string
+		pluginJSON    []byte
+		err           error
+	)
+	for _, candidate := range refs() {
+		ref = candidate
+		pluginJSONURL = marketplace.PluginJSONURL(repo, ref, sourcePath)
+
+		pluginJSON, err = download(pluginJSONURL)
+		if !errors.Is(err, errDownloadNotFound) {
+			break
+		}
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to download plugin.json: %w", err)
+	}
+	if err := marketplace.CheckJSONResponse("", pluginJSON, pluginJSONURL, "plugin.json"); err != nil {
+		return "", nil, err
+	}
+
+	return ref, pluginJSON, nil
+}
+
+// tagCandidates returns the git tags to try for a pinned version. Tags name
+// releases of the whole marketplace repo, so this is a guess for marketplaces
+// with several plugins; lockfile installs use the recorded commit instead.
+func tagCandidates(version string) []string {
+	bare := strings.TrimPrefix(version, "v")
+	return []string{"v" + bare, bare}
+}
+
+// DownloadToCache downloads plugin files from the marketplace repo into cacheDir
+// and returns the git ref (branch or tag) the files came from.
+// When verify is true, declared SHA-256 checksums are checked before writing.
+// Files that fail to download are skipped with a warning written to warn,
+// possibly from several goroutines at once; nil discards the warnings.
+// Cancelling ctx stops the downloads in flight and returns ctx's error.
+func DownloadToCache(ctx context.Context, target *Target, cacheDir string, verify bool, warn io.Writer) (string, error) {
+	if warn == nil {
+		warn = io.Discard
+	}
+
+	repo, sourcePath, err := pluginLocation(target)
+	if err != nil {
+		return "", err
+	}
+
+	// Create cache directory
+	// #nosec G301 -- Plugin cache needs to be readable by Claude Code
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Track total download size to prevent DoS
+	download := func(url string) ([]byte, error) { return downloadFile(ctx, url) }
+	downloadWithLimit := limitDownloads(download, maxTotalDownloadSize)
+
+	// Download plugin.json to verify the plugin structure
+	branch, pluginJSON, err := fetchPluginJSON(target, repo, sourcePath, downloadWithLimit)
+	if err != nil {
+		return "", err
+	}
+	if verify && target.PluginJSONSHA256 != "" {
+		if err := verifySHA256(pluginJSON, target.PluginJSONSHA256); err != nil {
+			return "", fmt.Errorf("plugin.json failed verification: %w", err)
+		}
+	}
+
+	// Create .claude-plugin directory in cache
+	claudePluginDir := filepath.Join(cacheDir, ".claude-plugin")
+	// #nosec G301 -- Plugin directory needs to be readable by Claude Code
+	if err := os.MkdirAll(claudePluginDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create .claude-plugin directory: %w", err)
+	}
+
+	// Write plugin.json
+	pluginJSONPath := filepath.Join(claudePluginDir, "plugin.json")
+	// #nosec G306 -- Plugin files need to be readable by Claude Code
+	if err := os.WriteFile(pluginJSONPath, pluginJSON, 0644); err != nil {
+		return "", fmt.Errorf("failed to write plugin.json: %w", err)
+	}
+
+	// Parse plugin.json to get file list
+	var pluginManifest ManifestFiles
+	if err := json.Unmarshal(pluginJSON, &pluginManifest); err != nil {
+		// Not a fatal error - we have the plugin.json at least
+		_, _ = fmt.Fprintf(warn, "Warning: failed to parse plugin.json: %v\n", err)
+	}
+
+	// Only verify when requested; a nil map means nothing is checked
+	var hashes map[string]string
+	if verify {
+		hashes = pluginManifest.SHA256
+	}
+
+	// Download commands (non-executable)
+	downloadPluginFiles(pluginManifest.Commands, "command", cacheDir, repo, branch, sourcePath, downloadWithLimit, hashes, 0644, warn)
+
+	// Download hooks (executable)
+	downloadPluginFiles(pluginManifest.Hooks, "hook", cacheDir, repo, branch, sourcePath, downloadWithLimit, hashes, 0755, warn)
+
+	// Files skipped because of a cancel would leave the cache incomplete
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return branch, nil
+}
+
+// PlanDownload fetches plugin.json without writing anything and returns
+// the ref and the files an install would download, plugin.json first
+func PlanDownload(target *Target) (string, []string, error) {
+	repo, sourcePath, err := pluginLocation(target)
+	if err != nil {
+		return "", nil, err
+	}
+
+	download := func(url string) ([]byte, error) { return downloadFile(context.Background(), url) }
+	ref, pluginJSON, err := fetchPluginJSON(target, repo, sourcePath, download)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var pluginManifest ManifestFiles
+	if err := json.Unmarshal(pluginJSON, &pluginManifest); err != nil {
+		return "", nil, fmt.Errorf("failed to parse plugin.json: %w", err)
+	}
+
+	files := []string{".claude-plugin/plugin.json"}
+	files = append(files, pluginManifest.Commands...)
+	files = append(files, pluginManifest.Hooks...)
+	return ref, files, nil
+}
+
+// downloadPluginFiles downloads a list of plugin files to the cache directory.
+// fileType is used for warning messages (e.g., "command" or "hook").
+// perm specifies the file permissions (e.g., 0644 for commands, 0755 for hooks).
+// Up to maxConcurrentFileDownloads files are fetched in parallel.
+func downloadPluginFiles(
+	files []string,
+	fileType string,
+	cacheDir string,
+	repo marketplace.RepoLocation,
+	branch string,
+	sourcePath string,
+	downloadWithLimit func(string) ([]byte, error),
+	hashes map[string]string,
+	perm os.FileMode,
+	warn io.Writer,
+) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFileDownloads) // Semaphore for concurrency limiting
+	)
+
+	for _, file := range files {
+		wg.Add(1)
+		go func(file string) {
+			defer wg.Done()
+
+			// Acquire semaphore
+			sem <- struct{}{}
+			defer func() { <-sem }() // Release semaphore
+
+			downloadPluginFile(file, fileType, cacheDir, repo, branch, sourcePath, downloadWithLimit, hashes, perm, warn)
+		}(file)
+	}
+
+	wg.Wait()
+}
+
+// downloadPluginFile downloads and writes a single plugin file, warning on failure
+func downloadPluginFile(
+	file string,
+	fileType string,
+	cacheDir string,
+	repo marketplace.RepoLocation,
+	branch string,
+	sourcePath string,
+	downloadWithLimit func(string) ([]byte, error),
+	hashes map[string]string,
+	perm os.FileMode,
+	warn io.Writer,
+) {
+	// Validate path to prevent path traversal attacks
+	filePath, err := validatePluginFilePath(file, cacheDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(warn, "Warning: skipping invalid %s path %s: %v\n", fileType, file, err)
+		return
+	}
+
+	fileURL := repo.RawURL(branch, sourcePath+"/"+file)
+
+	content, err := downloadWithLimit(fileURL)
+	if err != nil {
+		_, _ = fmt.Fprintf(warn, "Warning: failed to download %s %s: %v\n", fileType, file, err)
+		return
+	}
+
+	if expected, ok := lookupFileHash(hashes, file); ok {
+		if err := verifySHA256(content, expected); err != nil {
+			_, _ = fmt.Fprintf(warn, "Warning: skipping %s %s: %v\n", fileType, file, err)
+			return
+		}
+	}
+
+	fileDir := filepath.Dir(filePath)
+	// #nosec G301 -- Plugin directory needs to be readable by Claude Code
+	if err := os.MkdirAll(fileDir, 0755); err != nil {
+		_, _ = fmt.Fprintf(warn, "Warning: failed to create directory for %s: %v\n", file, err)
+		return
+	}
+
+	// #nosec G306 -- Plugin files need appropriate permissions
+	if err := os.WriteFile(filePath, content, perm); err != nil {
+		_, _ = fmt.Fprintf(warn, "Warning: failed to write %s: %v\n", file, err)
+	}
+}
+
+// lookupFileHash finds the declared hash for a manifest file path, tolerating
+// "./" prefixes and redundant separators on either side
+func lookupFileHash(hashes map[string]string, file string) (string, bool) {
+	if len(hashes) == 0 {
+		return "", false
+	}
+	want := path.Clean(strings.TrimPrefix(file, "./"))
+	for p, hash := range hashes {
+		if path.Clean(strings.TrimPrefix(p, "./")) == want {
+			return hash, true
+		}
+	}
+	return "", false
+}
+
+// verifySHA256 checks content against an expected hex-encoded SHA-256 digest
+func verifySHA256(content []byte, expected string) error {
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("checksum mismatch (expected %s, got %s)", expected, actual)
+	}
+	return nil
+}
+
+// errDownloadNotFound is returned by downloadFile for a 404 response
+var errDownloadNotFound = errors.New("HTTP 404")
+
+// downloadFile downloads a file from a URL. Cancelling ctx aborts the request.
+func downloadFile(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "plum/0.4.0")
+
+	resp, err := marketplace.HTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := marketplace.CheckRateLimit(resp); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", errDownloadNotFound, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, url)
+	}
+
+	// Limit response size
+	limitedBody := io.LimitReader(resp.Body, 10<<20) // 10 MB limit
+	return io.ReadAll(limitedBody)
+}
@@ -0,0 +1,472 @@
+package install
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+)
+
+func TestDownloadToCache_HTMLResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body>404: Not Found</body></html>`))
+	}))
+	defer server.Close()
+	useFakeGitHub(t, server.URL)
+
+	result := &Target{
+		Name:            "test-plugin",
+		MarketplaceRepo: "https://github.com/owner/repo",
+		Source:          "./plugins/test-plugin",
+	}
+
+	_, err := DownloadToCache(context.Background(), result, t.TempDir(), true, nil)
+	if err == nil {
+		t.Fatal("expected error for HTML plugin.json response")
+	}
+	if !errors.Is(err, marketplace.ErrNotJSON) {
+		t.Errorf("expected ErrNotJSON, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "plugin.json not found or not JSON at "+server.URL) {
+		t.Errorf("expected clear error naming the URL, got: %v", err)
+	}
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifySHA256(t *testing.T) {
+	content := []byte("hello")
+	good := sha256Hex("hello")
+
+	if err := verifySHA256(content, good); err != nil {
+		t.Errorf("expected match, got %v", err)
+	}
+	if err := verifySHA256(content, strings.ToUpper(good)); err != nil {
+		t.Errorf("expected case-insensitive match, got %v", err)
+	}
+	if err := verifySHA256(content, sha256Hex("tampered")); err == nil {
+		t.Error("expected mismatch error")
+	}
+}
+
+func TestLookupFileHash(t *testing.T) {
+	hashes := map[string]string{"./commands/a.md": "aaa", "hooks/b.sh": "bbb"}
+
+	if h, ok := lookupFileHash(hashes, "commands/a.md"); !ok || h != "aaa" {
+		t.Errorf("expected aaa, got %q %v", h, ok)
+	}
+	if h, ok := lookupFileHash(hashes, "./hooks/b.sh"); !ok || h != "bbb" {
+		t.Errorf("expected bbb, got %q %v", h, ok)
+	}
+	if _, ok := lookupFileHash(hashes, "commands/missing.md"); ok {
+		t.Error("expected no hash for undeclared file")
+	}
+	if _, ok := lookupFileHash(nil, "commands/a.md"); ok {
+		t.Error("expected no hash from nil map")
+	}
+}
+
+// useFakeGitHub points raw content and API requests at url and isolates the
+// plum cache so resolved branches don't leak between tests
+func useFakeGitHub(t *testing.T, url string) {
+	t.Helper()
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+	originalRaw := marketplace.GitHubRawBase
+	originalAPI := marketplace.GitHubAPIBase
+	marketplace.GitHubRawBase = url
+	marketplace.GitHubAPIBase = url
+	t.Cleanup(func() {
+		marketplace.GitHubRawBase = originalRaw
+		marketplace.GitHubAPIBase = originalAPI
+	})
+}
+
+// newPluginServer serves a fake plugin at /owner/repo/<branch>/plugins/test-plugin.
+// defaultBranch is reported by the repos API; empty makes the API return 404.
+func newPluginServer(t *testing.T, defaultBranch, branch string, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/owner/repo" {
+			if defaultBranch == "" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"default_branch": "` + defaultBranch + `"}`))
+			return
+		}
+
+		prefix := "/owner/repo/" + branch + "/plugins/test-plugin/"
+		content, ok := files[strings.TrimPrefix(r.URL.Path, prefix)]
+		if !ok || !strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	useFakeGitHub(t, server.URL)
+
+	return server
+}
+
+func TestDownloadToCache_Checksums(t *testing.T) {
+	good := "# good command\n"
+	bad := "# tampered command\n"
+	unhashed := "# no hash declared\n"
+
+	pluginJSON := `{
+		"name": "test-plugin",
+		"commands": ["commands/good.md", "commands/bad.md", "commands/unhashed.md"],
+		"sha256": {
+			"commands/good.md": "` + sha256Hex(good) + `",
+			"commands/bad.md": "` + sha256Hex("# original command\n") + `"
+		}
+	}`
+
+	newPluginServer(t, "", "main", map[string]string{
+		".claude-plugin/plugin.json": pluginJSON,
+		"commands/good.md":           good,
+		"commands/bad.md":            bad,
+		"commands/unhashed.md":       unhashed,
+	})
+
+	result := &Target{
+		Name:             "test-plugin",
+		MarketplaceRepo:  "https://github.com/owner/repo",
+		Source:           "./plugins/test-plugin",
+		PluginJSONSHA256: sha256Hex(pluginJSON),
+	}
+
+	t.Run("verify", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		if _, err := DownloadToCache(context.Background(), result, cacheDir, true, nil); err != nil {
+			t.Fatalf("DownloadToCache failed: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(cacheDir, "commands", "good.md")); err != nil {
+			t.Errorf("matching file should be written: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(cacheDir, "commands", "bad.md")); !os.IsNotExist(err) {
+			t.Errorf("mismatched file should be skipped, stat err = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(cacheDir, "commands", "unhashed.md")); err != nil {
+			t.Errorf("file without declared hash should be written: %v", err)
+		}
+	})
+
+	t.Run("no verify", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		if _, err := DownloadToCache(context.Background(), result, cacheDir, false, nil); err != nil {
+			t.Fatalf("DownloadToCache failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(cacheDir, "commands", "bad.md")); err != nil {
+			t.Errorf("--no-verify should write mismatched file: %v", err)
+		}
+	})
+
+	t.Run("plugin.json mismatch fails install", func(t *testing.T) {
+		tampered := *result
+		tampered.PluginJSONSHA256 = sha256Hex("something else")
+
+		_, err := DownloadToCache(context.Background(), &tampered, t.TempDir(), true, nil)
+		if err == nil || !strings.Contains(err.Error(), "plugin.json failed verification") {
+			t.Errorf("expected plugin.json verification error, got %v", err)
+		}
+
+		if _, err := DownloadToCache(context.Background(), &tampered, t.TempDir(), false, nil); err != nil {
+			t.Errorf("--no-verify should bypass plugin.json check, got %v", err)
+		}
+	})
+}
+
+func TestDownloadToCache_DefaultBranch(t *testing.T) {
+	files := map[string]string{
+		".claude-plugin/plugin.json": `{"name": "test-plugin", "commands": ["commands/hello.md"]}`,
+		"commands/hello.md":          "# hello\n",
+	}
+	result := &Target{
+		Name:            "test-plugin",
+		MarketplaceRepo: "https://github.com/owner/repo",
+		Source:          "./plugins/test-plugin",
+	}
+
+	tests := []struct {
+		name          string
+		defaultBranch string // reported by the repos API ("" = API 404)
+		branch        string // branch the files actually live on
+	}{
+		{"non-main default branch", "trunk", "trunk"},
+		{"fallback to main", "", "main"},
+		{"fallback to master", "", "master"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newPluginServer(t, tt.defaultBranch, tt.branch, files)
+
+			cacheDir := t.TempDir()
+			if _, err := DownloadToCache(context.Background(), result, cacheDir, true, nil); err != nil {
+				t.Fatalf("DownloadToCache failed: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(cacheDir, "commands", "hello.md")); err != nil {
+				t.Errorf("command should be downloaded from %s: %v", tt.branch, err)
+			}
+		})
+	}
+
+	t.Run("missing on every branch", func(t *testing.T) {
+		newPluginServer(t, "", "develop", files)
+
+		_, err := DownloadToCache(context.Background(), result, t.TempDir(), true, nil)
+		if err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+			t.Errorf("expected 404 error, got %v", err)
+		}
+	})
+}
+
+func TestDownloadFile_GitHubToken(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	useFakeGitHub(t, server.URL)
+
+	t.Setenv("GITHUB_TOKEN", "gh-abc")
+	t.Setenv("GH_TOKEN", "")
+	if _, err := downloadFile(context.Background(), server.URL); err != nil {
+		t.Fatalf("downloadFile failed: %v", err)
+	}
+	if gotAuth != "Bearer gh-abc" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer gh-abc")
+	}
+
+	t.Setenv("GITHUB_TOKEN", "")
+	if _, err := downloadFile(context.Background(), server.URL); err != nil {
+		t.Fatalf("downloadFile failed: %v", err)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization = %q, want none without a token", gotAuth)
+	}
+}
+
+func TestDownloadToCache_GitLabWithoutToken(t *testing.T) {
+	files := map[string]string{
+		".claude-plugin/plugin.json": `{"name": "test-plugin", "commands": ["commands/hello.md"]}`,
+		"commands/hello.md":          "# hello\n",
+	}
+
+	var mu sync.Mutex
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		content, ok := files[strings.TrimPrefix(r.URL.Path, "/owner/repo/-/raw/main/plugins/test-plugin/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "gh-abc")
+	t.Setenv("GH_TOKEN", "")
+	originalGitLab := marketplace.GitLabBase
+	marketplace.GitLabBase = server.URL
+	defer func() { marketplace.GitLabBase = originalGitLab }()
+
+	result := &Target{
+		Name:            "test-plugin",
+		MarketplaceRepo: "https://gitlab.com/owner/repo",
+		Source:          "./plugins/test-plugin",
+	}
+	if _, err := DownloadToCache(context.Background(), result, t.TempDir(), true, nil); err != nil {
+		t.Fatalf("DownloadToCache failed: %v", err)
+	}
+
+	if len(authHeaders) == 0 {
+		t.Fatal("expected requests to the GitLab server")
+	}
+	for _, auth := range authHeaders {
+		if auth != "" {
+			t.Errorf("GitHub token sent to GitLab: Authorization = %q", auth)
+		}
+	}
+}
+
+func TestDownloadFile_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	_, err := downloadFile(context.Background(), server.URL)
+	if !errors.Is(err, marketplace.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestDownloadToCache_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/.claude-plugin/plugin.json") {
+			_, _ = w.Write([]byte(`{"name": "test-plugin", "commands": ["commands/a.md", "commands/b.md"]}`))
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/repos/") {
+			http.NotFound(w, r)
+			return
+		}
+		// Command files hang like a stalled download until the client gives up
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	useFakeGitHub(t, server.URL)
+
+	result := &Target{
+		Name:            "test-plugin",
+		MarketplaceRepo: "https://github.com/owner/repo",
+		Source:          "./plugins/test-plugin",
+	}
+	start := time.Now()
+	_, err := DownloadToCache(ctx, result, t.TempDir(), true, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled download took %s, want an early return", elapsed)
+	}
+}
+
+func TestDownloadToCache_PinnedTag(t *testing.T) {
+	files := map[string]string{
+		".claude-plugin/plugin.json": `{"name": "test-plugin", "version": "1.2.0"}`,
+	}
+	result := &Target{
+		Name:            "test-plugin",
+		MarketplaceRepo: "https://github.com/owner/repo",
+		Source:          "./plugins/test-plugin",
+		Version:         "1.2.0",
+		Pinned:          true,
+	}
+
+	for _, tag := range []string{"v1.2.0", "1.2.0"} {
+		t.Run(tag, func(t *testing.T) {
+			newPluginServer(t, "main", tag, files)
+
+			ref, err := DownloadToCache(context.Background(), result, t.TempDir(), true, nil)
+			if err != nil {
+				t.Fatalf("DownloadToCache failed: %v", err)
+			}
+			if ref != tag {
+				t.Errorf("ref = %q, want %q", ref, tag)
+			}
+		})
+	}
+
+	t.Run("tag missing", func(t *testing.T) {
+		// Files only exist on the default branch - a pin must not fall back to it
+		newPluginServer(t, "main", "main", files)
+
+		if _, err := DownloadToCache(context.Background(), result, t.TempDir(), true, nil); err == nil {
+			t.Error("expected error when the version tag does not exist")
+		}
+	})
+}
+
+func TestDownloadPluginFiles_Concurrent(t *testing.T) {
+	files := make(map[string]string)
+	var names []string
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("commands/cmd-%d.md", i)
+		files[name] = strings.Repeat("x", 100)
+		names = append(names, name)
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		content, ok := files[strings.TrimPrefix(r.URL.Path, "/owner/repo/main/plugins/test-plugin/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+	useFakeGitHub(t, server.URL)
+
+	countWritten := func(cacheDir string) int {
+		written := 0
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(cacheDir, name)); err == nil {
+				written++
+			}
+		}
+		return written
+	}
+
+	fetch := func(url string) ([]byte, error) { return downloadFile(context.Background(), url) }
+
+	t.Run("all files land on disk", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		download := limitDownloads(fetch, maxTotalDownloadSize)
+		downloadPluginFiles(names, "command", cacheDir, marketplace.RepoLocation{Host: marketplace.HostGitHub, Repo: "owner/repo"}, "main", "plugins/test-plugin", download, nil, 0644, io.Discard)
+
+		if got := countWritten(cacheDir); got != len(names) {
+			t.Errorf("expected %d files written, got %d", len(names), got)
+		}
+		if peak := maxInFlight.Load(); peak > maxConcurrentFileDownloads {
+			t.Errorf("expected at most %d concurrent downloads, got %d", maxConcurrentFileDownloads, peak)
+		}
+	})
+
+	t.Run("size cap still trips", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		// Room for exactly four 100-byte files
+		download := limitDownloads(fetch, 450)
+		downloadPluginFiles(names, "command", cacheDir, marketplace.RepoLocation{Host: marketplace.HostGitHub, Repo: "owner/repo"}, "main", "plugins/test-plugin", download, nil, 0644, io.Discard)
+
+		if got := countWritten(cacheDir); got != 4 {
+			t.Errorf("expected 4 files written under the size cap, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+// ValidatePathComponent checks if a path component is safe (no path traversal)
+func ValidatePathComponent(name, componentType string) error {
+	if name == "" {
+		return fmt.Errorf("%s cannot be empty", componentType)
+	}
+	if strings.Contains(name, "..") {
+		return fmt.Errorf("%s contains invalid path traversal: %s", componentType, name)
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("%s contains invalid path separator: %s", componentType, name)
+	}
+	if name == "." {
+		return fmt.Errorf("%s cannot be current directory", componentType)
+	}
+	return nil
+}
+
+// validatePluginFilePath validates a file path from plugin manifest is safe
+// Returns cleaned path relative to cacheDir, or error if path escapes
+func validatePluginFilePath(filePath, cacheDir string) (string, error) {
+	// Reject absolute paths
+	if filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("absolute paths not allowed: %s", filePath)
+	}
+
+	// Reject path traversal attempts
+	if strings.Contains(filePath, "..") {
+		return "", fmt.Errorf("path traversal not allowed: %s", filePath)
+	}
+
+	// Clean the path
+	cleanPath := filepath.Clean(filePath)
+
+	// Construct full path and verify it's under cacheDir
+	fullPath := filepath.Join(cacheDir, cleanPath)
+	absCache, err := filepath.Abs(cacheDir)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(fullPath)
+	if err != nil {
+		return "", err
+	}
+
+	// Ensure the path is under the cache directory
+	if !strings.HasPrefix(absPath, absCache+string(filepath.Separator)) && absPath != absCache {
+		return "", fmt.Errorf("path escapes cache directory: %s", filePath)
+	}
+
+	return fullPath, nil
+}
+
+// Target holds plugin info needed for installation
+type Target struct {
+	Name                 string
+	Marketplace          string
+	MarketplaceRepo      string
+	Version              string
+	Source               string        // Path within marketplace
+	PluginJSONSHA256     string        // Expected SHA-256 of plugin.json, if declared
+	Installable          bool          // Whether plum can install this plugin
+	InstallabilityReason string        // Human-readable reason if not installable
+	IsIncomplete         bool          // True if plugin is missing required files
+	Pinned               bool          // Download from the version's git tag instead of the default branch
+	Commit               string        // Exact commit to download from (lockfile installs); implies Pinned
+	Details              plugin.Plugin // Full marketplace entry
+}
+
+// NewTarget returns the install target for a marketplace plugin
+func NewTarget(p plugin.Plugin) *Target {
+	return &Target{
+		Name:                 p.Name,
+		Marketplace:          p.Marketplace,
+		MarketplaceRepo:      p.MarketplaceRepo,
+		Version:              p.Version,
+		Source:               p.Source,
+		PluginJSONSHA256:     p.PluginJSONSHA256,
+		Installable:          p.Installable(),
+		InstallabilityReason: p.InstallabilityReason(),
+		IsIncomplete:         p.IsIncomplete,
+		Details:              p,
+	}
+}
+
+// FullName returns the plugin's name@marketplace key
+func (t *Target) FullName() string {
+	return t.Name + "@" + t.Marketplace
+}
+
+// IsValidCache checks if a cache directory contains a valid plugin.json
+func IsValidCache(cacheDir string) bool {
+	pluginJSONPath := filepath.Join(cacheDir, ".claude-plugin", "plugin.json")
+	info, err := os.Stat(pluginJSONPath)
+	if err != nil {
+		return false
+	}
+	// Ensure it's a file with non-zero size
+	return !info.IsDir() && info.Size() > 0
+}
+
+// CacheDir returns the path to cache a plugin
+// Path: ~/.claude/plugins/cache/<marketplace>/<plugin>/
+func CacheDir(marketplaceName, pluginName string) (string, error) {
+	// Validate marketplace and plugin names to prevent path traversal
+	if err := ValidatePathComponent(marketplaceName, "marketplace name"); err != nil {
+		return "", err
+	}
+	if err := ValidatePathComponent(pluginName, "plugin name"); err != nil {
+		return "", err
+	}
+
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pluginsDir, "cache", marketplaceName, pluginName), nil
+}
+
+// CheckWritable fails fast, before anything is downloaded, when the
+// scope's settings or the plugins directory can't be written
+func CheckWritable(scope settings.Scope, projectPath string) error {
+	if err := settings.CheckWritable(scope, projectPath); err != nil {
+		return err
+	}
+
+	pluginsDir, err := config.ClaudePluginsDir()
+	if err != nil {
+		return err
+	}
+	if err := settings.CheckDirWritable(pluginsDir); err != nil {
+		return fmt.Errorf("cannot install plugins: %w", err)
+	}
+	return nil
+}
+
+// ResolveCommit returns the commit SHA that ref points to in the target's
+// marketplace repo, so lockfiles can reproduce the exact files. Resolution is
+// best effort: it returns "" when the host can't be asked.
+func ResolveCommit(target *Target, ref string) string {
+	if target.Commit != "" {
+		return target.Commit
+	}
+	repo, _, err := pluginLocation(target)
+	if err != nil || ref == "" {
+		return ""
+	}
+	commit, err := repo.ResolveCommit(ref)
+	if err != nil {
+		return ""
+	}
+	return commit
+}
+
+// ErrNotInstallable is returned by Install for plugins plum can't install,
+// such as LSP plugins or ones without a plugin manifest
+var ErrNotInstallable = errors.New("plugin not installable via plum")
+
+// Options control where and how Install installs a plugin
+type Options struct {
+	Scope       settings.Scope
+	ProjectPath string    // Project for project and local scopes (default: current directory)
+	NoVerify    bool      // Skip SHA-256 checksum verification of downloaded files
+	Warnings    io.Writer // Non-fatal download problems; nil discards them
+}
+
+// Result describes a completed install
+type Result struct {
+	CacheDir  string
+	Version   string
+	FromCache bool // Existing cached files were used instead of downloading
+}
+
+// Install downloads a plugin to the cache, registers it in the install
+// registry, and enables it in the scope's settings. A valid existing cache
+// is reused unless the install is pinned to a version. Cancelling ctx stops
+// the download.
+func Install(ctx context.Context, target *Target, opts Options) (*Result, error) {
+	if !target.Installable {
+		return nil, fmt.Errorf("%w: %s", ErrNotInstallable, target.InstallabilityReason)
+	}
+
+	cacheDir, err := CacheDir(target.Marketplace, target.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	// A valid cache lets installation succeed even if the remote download
+	// fails. Pinned installs always download so the cache matches the
+	// pinned ref.
+	result := &Result{
+		CacheDir:  cacheDir,
+		Version:   target.Version,
+		FromCache: IsValidCache(cacheDir) && !target.Pinned,
+	}
+
+	var ref, commit string
+	if !result.FromCache {
+		ref, err = DownloadToCache(ctx, target, cacheDir, !opts.NoVerify, opts.Warnings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download plugin: %w", err)
+		}
+		commit = ResolveCommit(target, ref)
+	}
+
+	// Only pinned installs record a ref; default-branch installs follow updates
+	if !target.Pinned {
+		ref = ""
+	}
+
+	fullName := target.FullName()
+	if err := Register(fullName, cacheDir, target.Version, ref, commit, opts.Scope, opts.ProjectPath); err != nil {
+		return nil, fmt.Errorf("failed to register plugin: %w", err)
+	}
+
+	if err := settings.SetPluginEnabled(fullName, true, opts.Scope, opts.ProjectPath); err != nil {
+		return nil, fmt.Errorf("failed to enable plugin: %w", err)
+	}
+
+	return result, nil
+}
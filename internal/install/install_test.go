@@ -0,0 +1,121 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+func TestValidatePathComponent(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{"valid name", "my-plugin", false},
+		{"valid with numbers", "plugin123", false},
+		{"empty", "", true},
+		{"path traversal", "..", true},
+		{"path traversal in name", "foo/../bar", true},
+		{"forward slash", "foo/bar", true},
+		{"backslash", "foo\\bar", true},
+		{"current dir", ".", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePathComponent(tt.input, "test")
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidatePathComponent(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidatePluginFilePath(t *testing.T) {
+	cacheDir := "/tmp/plum-test-cache"
+
+	tests := []struct {
+		name      string
+		filePath  string
+		wantError bool
+	}{
+		{"valid simple", "script.js", false},
+		{"valid nested", "src/main.js", false},
+		{"path traversal", "../escape.js", true},
+		{"path traversal nested", "src/../../escape.js", true},
+		{"absolute path", "/etc/passwd", true},
+		{"double dot", "..hidden", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validatePluginFilePath(tt.filePath, cacheDir)
+			if (err != nil) != tt.wantError {
+				t.Errorf("validatePluginFilePath(%q) error = %v, wantError %v", tt.filePath, err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestInstall(t *testing.T) {
+	newPluginServer(t, "main", "main", map[string]string{
+		".claude-plugin/plugin.json": `{"name": "test-plugin", "commands": ["commands/hello.md"]}`,
+		"commands/hello.md":          "# hello\n",
+	})
+	target := &Target{
+		Name:            "test-plugin",
+		Marketplace:     "mkt",
+		MarketplaceRepo: "https://github.com/owner/repo",
+		Source:          "./plugins/test-plugin",
+		Version:         "1.0.0",
+		Installable:     true,
+	}
+
+	result, err := Install(context.Background(), target, Options{Scope: settings.ScopeUser})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if result.FromCache {
+		t.Error("first install should download")
+	}
+	if _, err := os.Stat(filepath.Join(result.CacheDir, "commands", "hello.md")); err != nil {
+		t.Errorf("command should be downloaded: %v", err)
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installs := installed.Plugins["test-plugin@mkt"]; len(installs) != 1 || installs[0].InstallPath != result.CacheDir {
+		t.Errorf("registry entry = %+v, want one install at %s", installs, result.CacheDir)
+	}
+	s, err := settings.LoadSettings(settings.ScopeUser, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.EnabledPlugins["test-plugin@mkt"] {
+		t.Error("plugin should be enabled in user settings")
+	}
+
+	// A second install reuses the valid cache
+	result, err = Install(context.Background(), target, Options{Scope: settings.ScopeUser})
+	if err != nil || !result.FromCache {
+		t.Errorf("reinstall should use the cache, got %+v, %v", result, err)
+	}
+}
+
+func TestInstall_NotInstallable(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	target := &Target{Name: "lsp", Marketplace: "mkt", InstallabilityReason: "LSP plugin"}
+
+	_, err := Install(context.Background(), target, Options{Scope: settings.ScopeUser})
+	if !errors.Is(err, ErrNotInstallable) {
+		t.Errorf("expected ErrNotInstallable, got %v", err)
+	}
+}
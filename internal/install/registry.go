@@ -0,0 +1,146 @@
+package install
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+// Register adds the plugin to installed_plugins_v2.json
+// ref is the git tag or commit a pinned install was downloaded from (empty if
+// unpinned). commit is the resolved commit SHA of the downloaded files; when
+// empty (files reused from the cache), the SHA already recorded for the same
+// install path and version is kept.
+func Register(fullName, installPath, version, ref, commit string, scope settings.Scope, projectPath string) error {
+	// Get registry path for locking
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		return err
+	}
+
+	// Use file locking to prevent race conditions
+	return settings.WithLock(registryPath, func() error {
+		installed, err := config.LoadInstalledPlugins()
+		if err != nil {
+			return err
+		}
+
+		if commit == "" {
+			commit = recordedCommit(installed.Plugins[fullName], installPath, version)
+		}
+
+		// Create install entry
+		install := config.PluginInstall{
+			Scope:        scope.String(),
+			InstallPath:  installPath,
+			Version:      version,
+			InstalledAt:  time.Now().UTC().Format(time.RFC3339),
+			LastUpdated:  time.Now().UTC().Format(time.RFC3339),
+			GitCommitSha: commit,
+			Ref:          ref,
+			IsLocal:      false,
+		}
+
+		// Add project path for project/local scopes
+		if scope == settings.ScopeProject || scope == settings.ScopeLocal {
+			if projectPath == "" {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				projectPath = cwd
+			}
+			install.ProjectPath = projectPath
+		}
+
+		// Check if already installed
+		existing, ok := installed.Plugins[fullName]
+		if ok {
+			// Update existing entry for this scope
+			found := false
+			for i, e := range existing {
+				if e.Scope == scope.String() {
+					existing[i] = install
+					found = true
+					break
+				}
+			}
+			if !found {
+				existing = append(existing, install)
+			}
+			installed.Plugins[fullName] = existing
+		} else {
+			installed.Plugins[fullName] = []config.PluginInstall{install}
+		}
+
+		// Write back to file
+		return SaveRegistry(installed)
+	})
+}
+
+// recordedCommit returns the commit SHA of an existing install of the same
+// cached files, or "" if none is recorded
+func recordedCommit(installs []config.PluginInstall, installPath, version string) string {
+	for _, e := range installs {
+		if e.InstallPath == installPath && e.Version == version && e.GitCommitSha != "" {
+			return e.GitCommitSha
+		}
+	}
+	return ""
+}
+
+// SaveRegistry writes the installed plugins registry in the v2 format. A v1
+// registry being replaced is backed up first.
+func SaveRegistry(installed *config.InstalledPluginsV2) error {
+	path, err := config.InstalledPluginsPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := config.BackupInstalledPluginsV1(); err != nil {
+		return err
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(path)
+	// #nosec G301 -- Plugin directory needs to be readable by Claude Code
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(installed, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically
+	tmpFile, err := os.CreateTemp(dir, ".installed-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if _, err := tmpFile.WriteString("\n"); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	// #nosec G302 -- Config files need to be readable by Claude Code
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+
+	return settings.AtomicRename(tmpPath, path)
+}
@@ -0,0 +1,104 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+func TestRegister_RecordsRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	if err := Register("memory@market", filepath.Join(tmpDir, "cache"), "1.2.0", "v1.2.0", "", settings.ScopeUser, ""); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installs := installed.Plugins["memory@market"]
+	if len(installs) != 1 {
+		t.Fatalf("expected 1 install entry, got %d", len(installs))
+	}
+	if installs[0].Ref != "v1.2.0" {
+		t.Errorf("Ref = %q, want %q", installs[0].Ref, "v1.2.0")
+	}
+	if installs[0].Version != "1.2.0" {
+		t.Errorf("Version = %q, want %q", installs[0].Version, "1.2.0")
+	}
+}
+
+func TestRegister_KeepsCommitOfCachedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+	cacheDir := filepath.Join(tmpDir, "cache")
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+
+	if err := Register("memory@market", cacheDir, "1.2.0", "", commit, settings.ScopeUser, ""); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	// A second scope reuses the cached files without downloading
+	if err := Register("memory@market", cacheDir, "1.2.0", "", "", settings.ScopeProject, tmpDir); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installs := installed.Plugins["memory@market"]
+	if len(installs) != 2 {
+		t.Fatalf("expected 2 install entries, got %d", len(installs))
+	}
+	for _, e := range installs {
+		if e.GitCommitSha != commit {
+			t.Errorf("%s install GitCommitSha = %q, want %q", e.Scope, e.GitCommitSha, commit)
+		}
+	}
+}
+
+func TestRegister_UpgradesV1Registry(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	registryPath, err := config.InstalledPluginsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(registryPath), 0750); err != nil {
+		t.Fatal(err)
+	}
+	v1 := `{"version": 1, "plugins": {"old@market": {"version": "0.1.0", "installPath": "/cache/old"}}}`
+	if err := os.WriteFile(registryPath, []byte(v1), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Register("memory@market", filepath.Join(tmpDir, "cache"), "1.2.0", "", "", settings.ScopeUser, ""); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	// The registry was rewritten as v2, keeping the v1 install
+	if isV1, err := config.InstalledPluginsIsV1(); err != nil || isV1 {
+		t.Errorf("registry should be v2 after a save, isV1 = %v, err = %v", isV1, err)
+	}
+	installed, err := config.LoadInstalledPlugins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed.Plugins["old@market"]) != 1 || len(installed.Plugins["memory@market"]) != 1 {
+		t.Errorf("unexpected registry after save: %+v", installed.Plugins)
+	}
+
+	backup, err := os.ReadFile(registryPath + ".v1.bak")
+	if err != nil {
+		t.Fatalf("v1 backup missing: %v", err)
+	}
+	if string(backup) != v1 {
+		t.Errorf("v1 backup = %s, want the original file", backup)
+	}
+}
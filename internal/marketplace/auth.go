@@ -0,0 +1,93 @@
+package marketplace
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Environment variables checked, in order, for a GitHub token
+var githubTokenEnvVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// ErrRateLimited is returned when GitHub rejects a request because the
+// rate limit for the current (usually anonymous) client is exhausted
+var ErrRateLimited = errors.New("GitHub rate limit exceeded")
+
+// GitHubToken returns the token from GITHUB_TOKEN or GH_TOKEN, or "" if unset
+func GitHubToken() string {
+	for _, name := range githubTokenEnvVars {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// githubHosts are the hosts a GitHub token may be sent to
+var githubHosts = []string{"github.com", "api.github.com", "raw.githubusercontent.com"}
+
+// isGitHubHost reports whether a GitHub token may be sent to the host of u:
+// a GitHub host, or the host of GitHubRawBase or GitHubAPIBase when they
+// point somewhere else (tests)
+func isGitHubHost(u *url.URL) bool {
+	for _, host := range githubHosts {
+		if strings.EqualFold(u.Hostname(), host) {
+			return true
+		}
+	}
+	for _, base := range []string{GitHubRawBase, GitHubAPIBase} {
+		if b, err := url.Parse(base); err == nil && strings.EqualFold(b.Host, u.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// authTransport adds a bearer token to requests for GitHub hosts when one is
+// configured. Other hosts (GitLab, redirect targets) never see the token.
+// The token is read per request so a changed environment takes effect without
+// rebuilding the shared client.
+type authTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := GitHubToken()
+	if token == "" || req.Header.Get("Authorization") != "" || !isGitHubHost(req.URL) {
+		return t.base.RoundTrip(req)
+	}
+
+	// A redirect to another host doesn't inherit the token, even between
+	// GitHub hosts
+	if req.Response != nil && !strings.EqualFold(req.Response.Request.URL.Host, req.URL.Host) {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	authed := req.Clone(req.Context())
+	authed.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(authed)
+}
+
+// HTTPClient returns the shared HTTP client used for all GitHub requests.
+// Requests to GitHub hosts carry an Authorization header when GITHUB_TOKEN or
+// GH_TOKEN is set.
+func HTTPClient() *http.Client {
+	return httpClient()
+}
+
+// CheckRateLimit returns an ErrRateLimited error when resp is a 403 with no
+// remaining rate limit, telling the user how to raise the limit
+func CheckRateLimit(resp *http.Response) error {
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+	if GitHubToken() != "" {
+		return fmt.Errorf("%w for %s (token limit reached, try again later)", ErrRateLimited, resp.Request.URL)
+	}
+	return fmt.Errorf("%w for %s: set GITHUB_TOKEN or GH_TOKEN to raise the limit", ErrRateLimited, resp.Request.URL)
+}
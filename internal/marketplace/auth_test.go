@@ -0,0 +1,193 @@
+package marketplace
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHTTPClientAuthorizationHeader(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	originalAPI := GitHubAPIBase
+	GitHubAPIBase = server.URL
+	defer func() { GitHubAPIBase = originalAPI }()
+
+	tests := []struct {
+		name        string
+		githubToken string
+		ghToken     string
+		want        string
+	}{
+		{"no token", "", "", ""},
+		{"GITHUB_TOKEN", "gh-abc", "", "Bearer gh-abc"},
+		{"GH_TOKEN", "", "gh-def", "Bearer gh-def"},
+		{"GITHUB_TOKEN wins", "gh-abc", "gh-def", "Bearer gh-abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.githubToken)
+			t.Setenv("GH_TOKEN", tt.ghToken)
+			gotAuth = ""
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := HTTPClient().Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if gotAuth != tt.want {
+				t.Errorf("Authorization = %q, want %q", gotAuth, tt.want)
+			}
+			if req.Header.Get("Authorization") != "" {
+				t.Error("caller's request should not be modified")
+			}
+		})
+	}
+}
+
+func TestHTTPClientAuthorization_OtherHosts(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "gh-abc")
+	t.Setenv("GH_TOKEN", "")
+
+	var otherAuth string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherAuth = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+
+	var githubAuth string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		githubAuth = r.Header.Get("Authorization")
+		http.Redirect(w, r, other.URL+"/elsewhere", http.StatusFound)
+	}))
+	defer github.Close()
+
+	originalAPI := GitHubAPIBase
+	GitHubAPIBase = github.URL
+	defer func() { GitHubAPIBase = originalAPI }()
+
+	get := func(url string) {
+		t.Helper()
+		otherAuth, githubAuth = "unset", "unset"
+		resp, err := HTTPClient().Get(url)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	t.Run("non-GitHub host", func(t *testing.T) {
+		get(other.URL)
+		if otherAuth != "" {
+			t.Errorf("Authorization sent to non-GitHub host: %q", otherAuth)
+		}
+	})
+
+	t.Run("cross-host redirect", func(t *testing.T) {
+		get(github.URL)
+		if githubAuth != "Bearer gh-abc" {
+			t.Errorf("GitHub Authorization = %q, want %q", githubAuth, "Bearer gh-abc")
+		}
+		if otherAuth != "" {
+			t.Errorf("Authorization sent to redirect target: %q", otherAuth)
+		}
+	})
+}
+
+func TestIsGitHubHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://github.com/owner/repo", true},
+		{"https://api.github.com/repos/owner/repo", true},
+		{"https://raw.githubusercontent.com/owner/repo/main/x", true},
+		{"https://RAW.githubusercontent.com/x", true},
+		{"https://gitlab.com/owner/repo/-/raw/main/x", false},
+		{"https://github.com.evil.example/x", false},
+		{"https://example.com/x", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := isGitHubHost(u); got != tt.want {
+			t.Errorf("isGitHubHost(%s) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestCheckRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	originalAPI := GitHubAPIBase
+	GitHubAPIBase = server.URL
+	defer func() { GitHubAPIBase = originalAPI }()
+
+	t.Run("anonymous", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "")
+		t.Setenv("GH_TOKEN", "")
+
+		_, err := FetchGitHubStats("owner/repo")
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected ErrRateLimited, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+			t.Errorf("error should tell the user to set a token, got %v", err)
+		}
+	})
+
+	t.Run("with token", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "gh-abc")
+
+		_, err := FetchGitHubStats("owner/repo")
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected ErrRateLimited, got %v", err)
+		}
+		if strings.Contains(err.Error(), "set GITHUB_TOKEN") {
+			t.Errorf("should not suggest setting a token that is already set, got %v", err)
+		}
+	})
+}
+
+func TestCheckRateLimit_OtherResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		remaining string
+	}{
+		{"ok", http.StatusOK, "0"},
+		{"forbidden with quota left", http.StatusForbidden, "12"},
+		{"forbidden without header", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.remaining != "" {
+				resp.Header.Set("X-RateLimit-Remaining", tt.remaining)
+			}
+			if err := CheckRateLimit(resp); err != nil {
+				t.Errorf("CheckRateLimit() = %v, want nil", err)
+			}
+		})
+	}
+}
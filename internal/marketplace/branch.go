@@ -0,0 +1,266 @@
+package marketplace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FallbackBranch is tried after DefaultBranch when a repo's default branch is unknown
+const FallbackBranch = "master"
+
+// branchCacheFile stores resolved default branches alongside the manifest cache
+const branchCacheFile = "default_branches.json"
+
+// failedLookupTTL is how long a failed default branch lookup is remembered,
+// so an unreachable or rate-limited API isn't asked again on every fetch
+const failedLookupTTL = 10 * time.Minute
+
+// BranchCacheEntry records a repo's resolved default branch. An empty Branch
+// records a failed lookup.
+type BranchCacheEntry struct {
+	Branch    string    `json:"branch"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+var (
+	// In-memory view of the branch cache file, keyed by owner/repo
+	branchCacheMu      sync.Mutex
+	branchCacheEntries map[string]BranchCacheEntry
+	branchCacheLoaded  string // cache dir the entries were loaded from
+)
+
+// ResolveDefaultBranch returns the default branch of a GitHub repo (owner/repo),
+// querying the repos API at most once per cache TTL and caching the result.
+// Failed lookups are cached for failedLookupTTL.
+func ResolveDefaultBranch(source string) (string, error) {
+	return resolveDefaultBranch(context.Background(), source)
+}
+
+// resolveDefaultBranch is ResolveDefaultBranch with a context that can cancel
+// the lookup
+func resolveDefaultBranch(ctx context.Context, source string) (string, error) {
+	if entry, ok := cachedBranchEntry(source); ok {
+		if entry.Branch == "" {
+			return "", fmt.Errorf("default branch of %s is unknown (lookup failed recently)", source)
+		}
+		return entry.Branch, nil
+	}
+
+	branch, err := fetchDefaultBranch(ctx, source)
+	if err != nil {
+		// A cancelled lookup says nothing about the repo
+		if ctx.Err() == nil {
+			_ = saveDefaultBranch(source, "")
+		}
+		return "", err
+	}
+
+	// Caching is best effort - a failed write only costs another lookup
+	_ = saveDefaultBranch(source, branch)
+
+	return branch, nil
+}
+
+// branchOrDefault returns the resolved default branch of source, or
+// DefaultBranch when it can't be determined
+func branchOrDefault(ctx context.Context, source string) string {
+	if branch, err := resolveDefaultBranch(ctx, source); err == nil {
+		return branch
+	}
+	return DefaultBranch
+}
+
+// defaultBranch returns the branch to fetch the marketplace manifest from:
+// the resolved default branch on GitHub, DefaultBranch on GitLab
+func (r RepoLocation) defaultBranch(ctx context.Context) string {
+	if r.Host == HostGitLab {
+		return DefaultBranch
+	}
+	return branchOrDefault(ctx, r.Repo)
+}
+
+// CachedDefaultBranch returns the cached default branch for source without
+// touching the network. Returns "" when unknown, expired or the last lookup
+// failed.
+func CachedDefaultBranch(source string) string {
+	entry, _ := cachedBranchEntry(source)
+	return entry.Branch
+}
+
+// cachedBranchEntry returns the unexpired cache entry for source, if any
+func cachedBranchEntry(source string) (BranchCacheEntry, bool) {
+	if source == "" {
+		return BranchCacheEntry{}, false
+	}
+
+	branchCacheMu.Lock()
+	defer branchCacheMu.Unlock()
+
+	entries, err := loadBranchCacheLocked()
+	if err != nil {
+		return BranchCacheEntry{}, false
+	}
+
+	entry, ok := entries[source]
+	if !ok {
+		return BranchCacheEntry{}, false
+	}
+	ttl := cacheTTL()
+	if entry.Branch == "" {
+		ttl = min(ttl, failedLookupTTL)
+	}
+	if time.Since(entry.FetchedAt) >= ttl {
+		return BranchCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// BranchCandidates returns the branches to try for source, in order: the
+// resolved default branch (if it can be determined), then "main", then "master"
+func BranchCandidates(source string) []string {
+	candidates := make([]string, 0, 3)
+	if branch, err := ResolveDefaultBranch(source); err == nil && branch != "" {
+		candidates = append(candidates, branch)
+	}
+	for _, b := range []string{DefaultBranch, FallbackBranch} {
+		if !slices.Contains(candidates, b) {
+			candidates = append(candidates, b)
+		}
+	}
+	return candidates
+}
+
+// fetchDefaultBranch queries the GitHub repos API for the default branch
+func fetchDefaultBranch(ctx context.Context, source string) (string, error) {
+	owner, repo, err := extractOwnerRepo(source)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, HTTPTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/repos/%s/%s", GitHubAPIBase, owner, repo)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "plum-marketplace-browser/0.2.0")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch repo info: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := CheckRateLimit(resp); err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &httpStatusError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("GitHub API returned status %d for %s", resp.StatusCode, url),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBodySize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var info struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	if info.DefaultBranch == "" {
+		return "", fmt.Errorf("GitHub response for %s has no default branch", source)
+	}
+
+	return info.DefaultBranch, nil
+}
+
+// loadBranchCacheLocked loads the branch cache file, reusing the in-memory
+// copy unless the cache directory changed. Caller must hold branchCacheMu.
+func loadBranchCacheLocked() (map[string]BranchCacheEntry, error) {
+	cacheDir, err := PlumCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	if branchCacheEntries != nil && branchCacheLoaded == cacheDir {
+		return branchCacheEntries, nil
+	}
+
+	entries := make(map[string]BranchCacheEntry)
+
+	// #nosec G304 -- path is a fixed file name in the trusted cache directory
+	data, err := os.ReadFile(filepath.Join(cacheDir, branchCacheFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			// Corrupt cache - start over rather than failing lookups
+			entries = make(map[string]BranchCacheEntry)
+		}
+	}
+
+	branchCacheEntries = entries
+	branchCacheLoaded = cacheDir
+	return entries, nil
+}
+
+// saveDefaultBranch records a resolved branch ("" for a failed lookup) in
+// memory and on disk
+func saveDefaultBranch(source, branch string) error {
+	branchCacheMu.Lock()
+	defer branchCacheMu.Unlock()
+
+	entries, err := loadBranchCacheLocked()
+	if err != nil {
+		return err
+	}
+	entries[source] = BranchCacheEntry{Branch: branch, FetchedAt: time.Now()}
+
+	cacheDir := branchCacheLoaded
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(cacheDir, ".tmp-branches-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := atomicRename(tmpPath, filepath.Join(cacheDir, branchCacheFile)); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package marketplace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setupBranchTest points the API and cache at test locations
+func setupBranchTest(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	originalAPI := GitHubAPIBase
+	GitHubAPIBase = server.URL
+	t.Cleanup(func() { GitHubAPIBase = originalAPI })
+
+	tmpDir := t.TempDir()
+	originalDir := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	t.Cleanup(func() { plumCacheDir = originalDir })
+}
+
+func TestResolveDefaultBranch(t *testing.T) {
+	var requests atomic.Int32
+	setupBranchTest(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/repos/owner/repo" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "repo", "default_branch": "master"}`))
+	})
+
+	branch, err := ResolveDefaultBranch("owner/repo")
+	if err != nil {
+		t.Fatalf("ResolveDefaultBranch() error = %v", err)
+	}
+	if branch != "master" {
+		t.Errorf("ResolveDefaultBranch() = %q, want %q", branch, "master")
+	}
+
+	// Second call is served from cache
+	if _, err := ResolveDefaultBranch("owner/repo"); err != nil {
+		t.Fatalf("ResolveDefaultBranch() cached error = %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 API request, got %d", got)
+	}
+
+	if got := CachedDefaultBranch("owner/repo"); got != "master" {
+		t.Errorf("CachedDefaultBranch() = %q, want %q", got, "master")
+	}
+
+	// Result is persisted alongside the manifest cache
+	cacheDir, _ := PlumCacheDir()
+	if _, err := os.Stat(filepath.Join(cacheDir, branchCacheFile)); err != nil {
+		t.Errorf("branch cache file not written: %v", err)
+	}
+}
+
+func TestResolveDefaultBranch_Error(t *testing.T) {
+	var requests atomic.Int32
+	setupBranchTest(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	})
+
+	if _, err := ResolveDefaultBranch("owner/missing"); err == nil {
+		t.Error("ResolveDefaultBranch() expected error for 404")
+	}
+	if got := CachedDefaultBranch("owner/missing"); got != "" {
+		t.Errorf("failed lookup should not report a branch, got %q", got)
+	}
+
+	// The failure is cached too, so the API isn't asked again
+	if _, err := ResolveDefaultBranch("owner/missing"); err == nil {
+		t.Error("ResolveDefaultBranch() expected cached error")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 API request, got %d", got)
+	}
+
+	// Once failedLookupTTL has passed, the lookup is retried
+	branchCacheMu.Lock()
+	entries, _ := loadBranchCacheLocked()
+	entries["owner/missing"] = BranchCacheEntry{FetchedAt: time.Now().Add(-failedLookupTTL)}
+	branchCacheMu.Unlock()
+	_, _ = ResolveDefaultBranch("owner/missing")
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected an expired failure to be looked up again, got %d requests", got)
+	}
+}
+
+func TestBranchCandidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string // empty = 404
+		want     []string
+	}{
+		{"custom default", `{"default_branch": "develop"}`, []string{"develop", "main", "master"}},
+		{"main default", `{"default_branch": "main"}`, []string{"main", "master"}},
+		{"master default", `{"default_branch": "master"}`, []string{"master", "main"}},
+		{"unknown default", "", []string{"main", "master"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupBranchTest(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.response == "" {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(tt.response))
+			})
+
+			got := BranchCandidates("owner/repo")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BranchCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
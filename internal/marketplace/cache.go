@@ -10,18 +10,31 @@ import (
 )
 
 const (
-	// CacheTTL is how long cached marketplace data remains valid (24 hours)
+	// CacheTTL is how long cached marketplace data remains valid by default
+	// (24 hours). Override with PLUM_CACHE_TTL (e.g. "6h").
 	CacheTTL = 24 * time.Hour
 
 	// MaxMarketplaceNameLength limits marketplace name length for security
 	MaxMarketplaceNameLength = 100
 )
 
+// cacheTTL returns the effective TTL for cached manifests, GitHub stats,
+// and default branches
+func cacheTTL() time.Duration {
+	if v := os.Getenv("PLUM_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return CacheTTL
+}
+
 // CacheEntry represents a cached marketplace manifest with metadata
 type CacheEntry struct {
 	Manifest  *MarketplaceManifest `json:"manifest"`
 	FetchedAt time.Time            `json:"fetchedAt"`
 	Source    string               `json:"source"`
+	ETag      string               `json:"etag,omitempty"` // GitHub's ETag for the manifest, to revalidate it
 }
 
 // windowsReservedNames are device names that cause issues on Windows filesystems
@@ -108,6 +121,22 @@ func PlumCacheDir() (string, error) {
 // LoadFromCache loads a marketplace manifest from cache if valid
 // Returns nil if cache miss or expired (no error)
 func LoadFromCache(marketplaceName string) (*MarketplaceManifest, error) {
+	entry, err := loadCacheEntry(marketplaceName)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	// Check if cache is still valid
+	if !isCacheValid(*entry) {
+		return nil, nil // Expired - not an error
+	}
+
+	return entry.Manifest, nil
+}
+
+// loadCacheEntry loads a marketplace's cache entry whatever its age, so an
+// expired manifest can still be revalidated by ETag. Returns nil on a miss.
+func loadCacheEntry(marketplaceName string) (*CacheEntry, error) {
 	// Validate marketplace name for security
 	if err := validateMarketplaceName(marketplaceName); err != nil {
 		return nil, err
@@ -134,16 +163,26 @@ func LoadFromCache(marketplaceName string) (*MarketplaceManifest, error) {
 		return nil, err
 	}
 
-	// Check if cache is still valid
-	if !isCacheValid(entry) {
-		return nil, nil // Expired - not an error
-	}
-
-	return entry.Manifest, nil
+	return &entry, nil
 }
 
 // SaveToCache saves a marketplace manifest to cache using atomic write
 func SaveToCache(marketplaceName string, manifest *MarketplaceManifest) error {
+	return saveToCache(marketplaceName, manifest, "")
+}
+
+// saveToCache saves a manifest with the ETag it was served with (may be empty)
+func saveToCache(marketplaceName string, manifest *MarketplaceManifest, etag string) error {
+	return writeCacheEntry(marketplaceName, CacheEntry{
+		Manifest:  manifest,
+		FetchedAt: time.Now(),
+		Source:    marketplaceName,
+		ETag:      etag,
+	})
+}
+
+// writeCacheEntry writes a cache entry as is, keeping its FetchedAt
+func writeCacheEntry(marketplaceName string, entry CacheEntry) error {
 	// Validate marketplace name for security
 	if err := validateMarketplaceName(marketplaceName); err != nil {
 		return err
@@ -159,12 +198,6 @@ func SaveToCache(marketplaceName string, manifest *MarketplaceManifest) error {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	entry := CacheEntry{
-		Manifest:  manifest,
-		FetchedAt: time.Now(),
-		Source:    marketplaceName,
-	}
-
 	data, err := json.MarshalIndent(entry, "", "  ")
 	if err != nil {
 		return err
@@ -204,7 +237,7 @@ func SaveToCache(marketplaceName string, manifest *MarketplaceManifest) error {
 
 // isCacheValid checks if cache entry is still valid based on TTL
 func isCacheValid(entry CacheEntry) bool {
-	return time.Since(entry.FetchedAt) < CacheTTL
+	return time.Since(entry.FetchedAt) < cacheTTL()
 }
 
 // atomicRename performs an atomic rename with Windows fallback
@@ -1,10 +1,12 @@
 package marketplace
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateMarketplaceName(t *testing.T) {
@@ -172,3 +174,77 @@ func TestCacheDirectoryPermissions(t *testing.T) {
 		t.Errorf("Expected cache directory permissions 0700, got %o", info.Mode().Perm())
 	}
 }
+
+func TestCacheTTL(t *testing.T) {
+	tests := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", CacheTTL},
+		{"6h", 6 * time.Hour},
+		{"90s", 90 * time.Second},
+		{"soon", CacheTTL},
+		{"-1h", CacheTTL},
+		{"0", CacheTTL},
+	}
+	for _, tt := range tests {
+		t.Setenv("PLUM_CACHE_TTL", tt.env)
+		if got := cacheTTL(); got != tt.want {
+			t.Errorf("PLUM_CACHE_TTL=%q: cacheTTL() = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
+
+// backdate rewrites a cache file's fetchedAt to age ago
+func backdate(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	entry["fetchedAt"] = time.Now().Add(-age)
+	if data, err = json.Marshal(entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFromCache_ConfiguredTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	original := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	defer func() { plumCacheDir = original }()
+
+	if err := SaveToCache("ttl-test", &MarketplaceManifest{Name: "ttl-test"}); err != nil {
+		t.Fatalf("SaveToCache failed: %v", err)
+	}
+	if err := SaveStatsToCache("ttl-test", &GitHubStats{Stars: 7}); err != nil {
+		t.Fatalf("SaveStatsToCache failed: %v", err)
+	}
+	backdate(t, filepath.Join(tmpDir, "ttl-test.json"), time.Hour)
+	backdate(t, filepath.Join(tmpDir, "ttl-test_stats.json"), time.Hour)
+
+	t.Setenv("PLUM_CACHE_TTL", "2h")
+	if manifest, _ := LoadFromCache("ttl-test"); manifest == nil {
+		t.Error("an hour-old manifest should be valid with a 2h TTL")
+	}
+	if stats, _ := LoadStatsFromCache("ttl-test"); stats == nil {
+		t.Error("hour-old stats should be valid with a 2h TTL")
+	}
+
+	t.Setenv("PLUM_CACHE_TTL", "30m")
+	if manifest, err := LoadFromCache("ttl-test"); manifest != nil || err != nil {
+		t.Errorf("an hour-old manifest should expire with a 30m TTL, got %v, %v", manifest, err)
+	}
+	if stats, err := LoadStatsFromCache("ttl-test"); stats != nil || err != nil {
+		t.Errorf("hour-old stats should expire with a 30m TTL, got %v, %v", stats, err)
+	}
+}
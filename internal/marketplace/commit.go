@@ -0,0 +1,86 @@
+package marketplace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// commitSHAPattern matches a full 40-character git commit SHA
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// IsCommitSHA reports whether ref is a full commit SHA rather than a branch or tag
+func IsCommitSHA(ref string) bool {
+	return commitSHAPattern.MatchString(ref)
+}
+
+// ResolveCommit returns the commit SHA that ref (a branch, tag or SHA) points
+// to, so an install can be reproduced after the branch or tag moves
+func (r RepoLocation) ResolveCommit(ref string) (string, error) {
+	if IsCommitSHA(ref) {
+		return ref, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), HTTPTimeout)
+	defer cancel()
+
+	var apiURL string
+	switch r.Host {
+	case HostGitLab:
+		apiURL = fmt.Sprintf("%s/api/v4/projects/%s/repository/commits/%s",
+			GitLabBase, url.PathEscape(r.Repo), url.PathEscape(ref))
+	default:
+		apiURL = fmt.Sprintf("%s/repos/%s/commits/%s", GitHubAPIBase, r.Repo, url.PathEscape(ref))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "plum-marketplace-browser/0.2.0")
+	if r.Host != HostGitLab {
+		// Ask for the bare SHA instead of the full commit object
+		req.Header.Set("Accept", "application/vnd.github.sha")
+	}
+
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := CheckRateLimit(resp); err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &httpStatusError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("commit lookup returned status %d for %s", resp.StatusCode, apiURL),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBodySize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	sha := strings.TrimSpace(string(body))
+	if r.Host == HostGitLab {
+		var commit struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &commit); err != nil {
+			return "", fmt.Errorf("failed to parse GitLab response: %w", err)
+		}
+		sha = commit.ID
+	}
+	if !IsCommitSHA(sha) {
+		return "", fmt.Errorf("commit lookup for %s returned no commit SHA", ref)
+	}
+	return sha, nil
+}
@@ -0,0 +1,61 @@
+package marketplace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveCommit(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/repos/owner/repo/commits/main":
+			if r.Header.Get("Accept") != "application/vnd.github.sha" {
+				t.Errorf("GitHub lookup should ask for the bare SHA, Accept = %q", r.Header.Get("Accept"))
+			}
+			_, _ = w.Write([]byte(sha + "\n"))
+		case "/api/v4/projects/group%2Fsub%2Frepo/repository/commits/v1.0.0":
+			_, _ = w.Write([]byte(`{"id": "` + sha + `", "short_id": "0123456"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	originalAPI, originalGitLab := GitHubAPIBase, GitLabBase
+	GitHubAPIBase, GitLabBase = server.URL, server.URL
+	defer func() { GitHubAPIBase, GitLabBase = originalAPI, originalGitLab }()
+
+	tests := []struct {
+		name    string
+		loc     RepoLocation
+		ref     string
+		wantErr bool
+	}{
+		{"GitHub branch", RepoLocation{HostGitHub, "owner/repo"}, "main", false},
+		{"GitLab tag", RepoLocation{HostGitLab, "group/sub/repo"}, "v1.0.0", false},
+		{"already a SHA", RepoLocation{HostGitHub, "owner/unknown"}, sha, false},
+		{"unknown ref", RepoLocation{HostGitHub, "owner/repo"}, "missing", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.loc.ResolveCommit(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil || got != sha {
+				t.Errorf("ResolveCommit(%q) = %q, %v; want %q", tt.ref, got, err, sha)
+			}
+		})
+	}
+
+	if IsCommitSHA(strings.ToUpper(sha)) || IsCommitSHA("main") || !IsCommitSHA(sha) {
+		t.Error("IsCommitSHA should accept only full lowercase SHAs")
+	}
+}
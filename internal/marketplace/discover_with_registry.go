@@ -1,16 +1,30 @@
 package marketplace
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
+	"time"
 )
 
 // DiscoverWithRegistry fetches marketplaces using the latest registry
 // This is called when user presses Shift+U to update
 func DiscoverWithRegistry() (map[string]*MarketplaceManifest, error) {
+	manifests, _, err := discoverWithRegistry(context.Background(), nil, 0)
+	return manifests, err
+}
+
+// discoverWithRegistry fetches every marketplace in the registry,
+// revalidating the previous cache entries (by marketplace name) where given
+// and the current cache entries otherwise. With a positive maxAge, cached
+// manifests younger than maxAge are used as they are. Returns the manifests
+// and the sorted names of the marketplaces that were fetched. Cancelling ctx
+// stops early, returning what was fetched so far with ctx's error.
+func discoverWithRegistry(ctx context.Context, previous map[string]*CacheEntry, maxAge time.Duration) (map[string]*MarketplaceManifest, []string, error) {
 	// Fetch latest marketplace list from registry
-	marketplaceList, err := FetchRegistry()
+	marketplaceList, err := fetchRegistry(ctx)
 	if err != nil {
 		// Fallback to hardcoded
 		fmt.Fprintf(os.Stderr, "Warning: failed to fetch registry, using hardcoded list: %v\n", err)
@@ -19,6 +33,7 @@ func DiscoverWithRegistry() (map[string]*MarketplaceManifest, error) {
 
 	var (
 		manifests = make(map[string]*MarketplaceManifest)
+		fetched   []string
 		mu        sync.Mutex
 		wg        sync.WaitGroup
 		errs      []error
@@ -31,12 +46,30 @@ func DiscoverWithRegistry() (map[string]*MarketplaceManifest, error) {
 		go func(marketplace PopularMarketplace) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			sem <- struct{}{}
+			// Acquire semaphore, unless cancelled while waiting for a slot
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }() // Release semaphore
+			if ctx.Err() != nil {
+				return
+			}
+
+			if maxAge > 0 {
+				entry, _ := loadCacheEntry(marketplace.Name)
+				if entry != nil && entry.Manifest != nil && time.Since(entry.FetchedAt) < maxAge {
+					mu.Lock()
+					manifests[marketplace.Name] = entry.Manifest
+					mu.Unlock()
+					return
+				}
+			}
 
-			// Skip cache - force fresh fetch from GitHub
-			manifest, err := FetchManifestFromGitHub(marketplace.Repo)
+			// Skip the cache TTL - always ask GitHub, sending the cached ETag
+			// so an unchanged manifest isn't downloaded again
+			manifest, err := revalidateManifest(ctx, marketplace, previous[marketplace.Name])
 			if err != nil {
 				mu.Lock()
 				errs = append(errs, fmt.Errorf("%s: %w", marketplace.Name, err))
@@ -44,25 +77,25 @@ func DiscoverWithRegistry() (map[string]*MarketplaceManifest, error) {
 				return
 			}
 
-			// Update manifest name to match registry
-			manifest.Name = marketplace.Name
-
-			// Save to cache (log error but don't fail the fetch)
-			if err := SaveToCache(marketplace.Name, manifest); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to save %s to cache: %v\n", marketplace.Name, err)
-			}
-
 			mu.Lock()
 			manifests[marketplace.Name] = manifest
+			fetched = append(fetched, marketplace.Name)
 			mu.Unlock()
 		}(pm)
 	}
 
 	wg.Wait()
 
+	sort.Strings(fetched)
+
+	// Failures after cancellation are just the cancellation; don't warn about them
+	if err := ctx.Err(); err != nil {
+		return manifests, fetched, err
+	}
+
 	// If all fetches failed, return error
 	if len(manifests) == 0 && len(errs) > 0 {
-		return nil, fmt.Errorf("all marketplace fetches failed: %v", errs)
+		return nil, nil, fmt.Errorf("all marketplace fetches failed: %v", errs)
 	}
 
 	// Log partial failures
@@ -72,5 +105,5 @@ func DiscoverWithRegistry() (map[string]*MarketplaceManifest, error) {
 		}
 	}
 
-	return manifests, nil
+	return manifests, fetched, nil
 }
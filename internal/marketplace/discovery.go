@@ -1,6 +1,8 @@
 package marketplace
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -176,15 +178,13 @@ func mustParseTime(s string) time.Time {
 
 // DiscoverPopularMarketplaces fetches and returns manifests for popular marketplaces
 // Uses cached registry if available (from Shift+U), otherwise hardcoded list
-// Uses cache when available, fetches from GitHub otherwise
+// Uses cache when available, fetches from GitHub otherwise; noCache forces
+// fresh fetches without clearing the cache
 // Returns partial results on partial failures (best-effort)
-func DiscoverPopularMarketplaces() (map[string]*DiscoveredMarketplace, error) {
-	// Check if user has updated the registry (via Shift+U)
-	marketplaceList := PopularMarketplaces
-	if cachedRegistry, err := loadRegistryFromCache(); err == nil && cachedRegistry != nil {
-		// User pressed Shift+U before - use their updated registry
-		marketplaceList = cachedRegistry.Marketplaces
-	}
+// Cancelling ctx stops fetches in flight and skips the ones not yet started;
+// the marketplaces discovered so far are returned with ctx's error.
+func DiscoverPopularMarketplaces(ctx context.Context, noCache bool) (map[string]*DiscoveredMarketplace, error) {
+	marketplaceList := popularMarketplaceList()
 
 	var (
 		discovered = make(map[string]*DiscoveredMarketplace)
@@ -200,11 +200,18 @@ func DiscoverPopularMarketplaces() (map[string]*DiscoveredMarketplace, error) {
 		go func(marketplace PopularMarketplace) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			sem <- struct{}{}
+			// Acquire semaphore, unless cancelled while waiting for a slot
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }() // Release semaphore
+			if ctx.Err() != nil {
+				return
+			}
 
-			disc, err := fetchMarketplaceFromGitHub(marketplace)
+			disc, err := fetchMarketplaceFromGitHub(ctx, marketplace, noCache)
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -220,6 +227,11 @@ func DiscoverPopularMarketplaces() (map[string]*DiscoveredMarketplace, error) {
 
 	wg.Wait()
 
+	// Failures after cancellation are just the cancellation; don't warn about them
+	if err := ctx.Err(); err != nil {
+		return discovered, err
+	}
+
 	// If all fetches failed, return error
 	if len(discovered) == 0 && len(errs) > 0 {
 		return nil, fmt.Errorf("all marketplace fetches failed: %v", errs)
@@ -235,37 +247,99 @@ func DiscoverPopularMarketplaces() (map[string]*DiscoveredMarketplace, error) {
 	return discovered, nil
 }
 
-// fetchMarketplaceFromGitHub fetches a single marketplace with caching
-func fetchMarketplaceFromGitHub(pm PopularMarketplace) (*DiscoveredMarketplace, error) {
-	// Derive CLI source from repo URL
-	source, err := DeriveSource(pm.Repo)
-	if err != nil {
-		return nil, fmt.Errorf("failed to derive source: %w", err)
+// popularMarketplaceList returns the registry cached by Shift+U if the user
+// has updated it, otherwise the hardcoded list
+func popularMarketplaceList() []PopularMarketplace {
+	if cachedRegistry, err := loadRegistryFromCache(); err == nil && cachedRegistry != nil {
+		return cachedRegistry.Marketplaces
 	}
+	return PopularMarketplaces
+}
 
-	// Try cache first
-	cached, err := LoadFromCache(pm.Name)
-	if err == nil && cached != nil {
-		return &DiscoveredMarketplace{
-			Manifest: cached,
+// DiscoverCachedMarketplaces returns the popular marketplaces that already
+// have a manifest in plum's cache, without touching the network. Expired
+// entries are included: this is meant for a fast first render that a full
+// DiscoverPopularMarketplaces call then brings up to date.
+func DiscoverCachedMarketplaces() map[string]*DiscoveredMarketplace {
+	discovered := make(map[string]*DiscoveredMarketplace)
+	for _, pm := range popularMarketplaceList() {
+		entry, err := loadCacheEntry(pm.Name)
+		if err != nil || entry == nil || entry.Manifest == nil {
+			continue
+		}
+		source, err := DeriveSource(pm.Repo)
+		if err != nil {
+			continue
+		}
+		discovered[pm.Name] = &DiscoveredMarketplace{
+			Manifest: entry.Manifest,
 			Repo:     pm.Repo,
 			Source:   source,
-		}, nil
+		}
 	}
+	return discovered
+}
 
-	// Cache miss or expired - fetch from GitHub
-	manifest, err := FetchManifestFromGitHub(pm.Repo)
-	if err != nil {
+// fetchManifest fetches a marketplace manifest from GitHub, conditional on
+// an ETag (variable for testing)
+var fetchManifest = FetchManifestIfChanged
+
+// revalidateManifest fetches a marketplace's manifest and saves it to the
+// cache. A cached copy with an ETag (previous, or the cache entry when nil)
+// is sent for revalidation: if GitHub reports it unchanged, the cached
+// manifest is reused and only its cache timestamp is refreshed.
+func revalidateManifest(ctx context.Context, pm PopularMarketplace, previous *CacheEntry) (*MarketplaceManifest, error) {
+	if previous == nil {
+		previous, _ = loadCacheEntry(pm.Name)
+	}
+	etag := ""
+	if previous != nil && previous.Manifest != nil {
+		etag = previous.ETag
+	}
+
+	manifest, newETag, err := fetchManifest(ctx, pm.Repo, etag)
+	if errors.Is(err, ErrNotModified) {
+		manifest, newETag = previous.Manifest, etag
+	} else if err != nil {
 		return nil, err
 	}
 
-	// Update manifest name to match our hardcoded name
+	// Update manifest name to match our registry name
 	manifest.Name = pm.Name
 
 	// Save to cache (log error but don't fail)
-	if err := SaveToCache(pm.Name, manifest); err != nil {
+	if err := saveToCache(pm.Name, manifest, newETag); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to save %s to cache: %v\n", pm.Name, err)
 	}
+	return manifest, nil
+}
+
+// fetchMarketplaceFromGitHub fetches a single marketplace with caching.
+// With noCache the cached manifest is ignored, but the fresh one is still saved.
+func fetchMarketplaceFromGitHub(ctx context.Context, pm PopularMarketplace, noCache bool) (*DiscoveredMarketplace, error) {
+	// Derive CLI source from repo URL
+	source, err := DeriveSource(pm.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive source: %w", err)
+	}
+
+	// Try cache first
+	if !noCache {
+		cached, err := LoadFromCache(pm.Name)
+		if err == nil && cached != nil {
+			return &DiscoveredMarketplace{
+				Manifest: cached,
+				Repo:     pm.Repo,
+				Source:   source,
+			}, nil
+		}
+	}
+
+	// Cache miss, expired or bypassed - fetch from GitHub
+	manifest, err := revalidateManifest(ctx, pm, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return &DiscoveredMarketplace{
 		Manifest: manifest,
@@ -1,7 +1,18 @@
 package marketplace
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestPopularMarketplaces verifies the hardcoded marketplace list
@@ -153,3 +164,259 @@ func TestPluginSearchSource(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchMarketplaceFromGitHub_NoCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	original := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	defer func() { plumCacheDir = original }()
+
+	pm := PopularMarketplace{Name: "test-marketplace", Repo: "https://github.com/test/repo"}
+
+	// Seed the cache with a stale manifest
+	stale := &MarketplaceManifest{Name: pm.Name, Plugins: []MarketplacePlugin{{Name: "stale"}}}
+	if err := SaveToCache(pm.Name, stale); err != nil {
+		t.Fatalf("SaveToCache failed: %v", err)
+	}
+
+	fetches := 0
+	originalFetch := fetchManifest
+	fetchManifest = func(ctx context.Context, repoURL, etag string) (*MarketplaceManifest, string, error) {
+		fetches++
+		return &MarketplaceManifest{Name: "upstream-name", Plugins: []MarketplacePlugin{{Name: "fresh"}}}, "", nil
+	}
+	defer func() { fetchManifest = originalFetch }()
+
+	t.Run("cache is used by default", func(t *testing.T) {
+		disc, err := fetchMarketplaceFromGitHub(context.Background(), pm, false)
+		if err != nil {
+			t.Fatalf("fetchMarketplaceFromGitHub failed: %v", err)
+		}
+		if fetches != 0 {
+			t.Errorf("expected no fetch on cache hit, got %d", fetches)
+		}
+		if disc.Manifest.Plugins[0].Name != "stale" {
+			t.Errorf("expected cached manifest, got plugin %q", disc.Manifest.Plugins[0].Name)
+		}
+	})
+
+	t.Run("noCache fetches fresh and refreshes the cache", func(t *testing.T) {
+		disc, err := fetchMarketplaceFromGitHub(context.Background(), pm, true)
+		if err != nil {
+			t.Fatalf("fetchMarketplaceFromGitHub failed: %v", err)
+		}
+		if fetches != 1 {
+			t.Errorf("expected 1 fetch with noCache, got %d", fetches)
+		}
+		if disc.Manifest.Plugins[0].Name != "fresh" {
+			t.Errorf("expected fresh manifest, got plugin %q", disc.Manifest.Plugins[0].Name)
+		}
+		if disc.Manifest.Name != pm.Name {
+			t.Errorf("expected manifest name %q, got %q", pm.Name, disc.Manifest.Name)
+		}
+
+		cached, err := LoadFromCache(pm.Name)
+		if err != nil || cached == nil {
+			t.Fatalf("expected cache to survive, got %v, %v", cached, err)
+		}
+		if cached.Plugins[0].Name != "fresh" {
+			t.Errorf("expected cache updated with fresh manifest, got plugin %q", cached.Plugins[0].Name)
+		}
+	})
+}
+
+func TestFetchMarketplaceFromGitHub_RevalidatesByETag(t *testing.T) {
+	setupBranchTest(t, http.NotFound)
+	tmpDir := t.TempDir()
+	original := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	defer func() { plumCacheDir = original }()
+
+	currentETag := `"v1"`
+	bodies := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == currentETag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		bodies++
+		w.Header().Set("ETag", currentETag)
+		_, _ = fmt.Fprintf(w, `{"name":"upstream","owner":{},"metadata":{},"plugins":[{"name":"plugin-%s"}]}`, strings.Trim(currentETag, `"`))
+	}))
+	defer server.Close()
+
+	originalBase := GitHubRawBase
+	GitHubRawBase = server.URL
+	defer func() { GitHubRawBase = originalBase }()
+
+	pm := PopularMarketplace{Name: "etag-marketplace", Repo: "https://github.com/test/repo"}
+	expire := func() {
+		t.Helper()
+		entry, err := loadCacheEntry(pm.Name)
+		if err != nil || entry == nil {
+			t.Fatalf("expected a cache entry, got %v, %v", entry, err)
+		}
+		entry.FetchedAt = time.Now().Add(-2 * CacheTTL)
+		data, _ := json.Marshal(entry)
+		if err := os.WriteFile(filepath.Join(tmpDir, pm.Name+".json"), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// First fetch downloads the manifest and caches its ETag
+	disc, err := fetchMarketplaceFromGitHub(context.Background(), pm, false)
+	if err != nil {
+		t.Fatalf("fetchMarketplaceFromGitHub failed: %v", err)
+	}
+	if bodies != 1 || disc.Manifest.Plugins[0].Name != "plugin-v1" {
+		t.Fatalf("expected one download of v1, got %d bodies, plugin %q", bodies, disc.Manifest.Plugins[0].Name)
+	}
+	if entry, _ := loadCacheEntry(pm.Name); entry == nil || entry.ETag != `"v1"` {
+		t.Fatalf("expected the ETag to be cached, got %+v", entry)
+	}
+
+	// Expired and unchanged: a 304 reuses the cached manifest and refreshes its timestamp
+	expire()
+	disc, err = fetchMarketplaceFromGitHub(context.Background(), pm, false)
+	if err != nil {
+		t.Fatalf("fetchMarketplaceFromGitHub failed: %v", err)
+	}
+	if bodies != 1 {
+		t.Errorf("an unchanged manifest should not be downloaded again, got %d bodies", bodies)
+	}
+	if disc.Manifest.Plugins[0].Name != "plugin-v1" || disc.Manifest.Name != pm.Name {
+		t.Errorf("expected the cached manifest, got %+v", disc.Manifest)
+	}
+	if cached, _ := LoadFromCache(pm.Name); cached == nil {
+		t.Error("a 304 should refresh the cache timestamp")
+	}
+
+	// Expired and changed: a 200 replaces the manifest and its ETag
+	currentETag = `"v2"`
+	expire()
+	disc, err = fetchMarketplaceFromGitHub(context.Background(), pm, false)
+	if err != nil {
+		t.Fatalf("fetchMarketplaceFromGitHub failed: %v", err)
+	}
+	if bodies != 2 || disc.Manifest.Plugins[0].Name != "plugin-v2" {
+		t.Errorf("expected a download of v2, got %d bodies, plugin %q", bodies, disc.Manifest.Plugins[0].Name)
+	}
+	if entry, _ := loadCacheEntry(pm.Name); entry == nil || entry.ETag != `"v2"` {
+		t.Errorf("expected the new ETag to be cached, got %+v", entry)
+	}
+
+	// RefreshAll clears the cache, so it carries the ETags over from before
+	if entries := withETag(cachedManifestEntries()); entries[pm.Name] == nil || entries[pm.Name].ETag != `"v2"` {
+		t.Errorf("cached entries with an ETag = %v, want %s with its ETag", entries, pm.Name)
+	}
+}
+
+func TestDiscoverCachedMarketplaces(t *testing.T) {
+	tmpDir := t.TempDir()
+	original := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	defer func() { plumCacheDir = original }()
+
+	originalFetch := fetchManifest
+	fetchManifest = func(ctx context.Context, repoURL, etag string) (*MarketplaceManifest, string, error) {
+		t.Errorf("unexpected fetch of %s", repoURL)
+		return nil, "", ErrNotModified
+	}
+	defer func() { fetchManifest = originalFetch }()
+
+	fresh, expired := PopularMarketplaces[0], PopularMarketplaces[1]
+	for _, pm := range []PopularMarketplace{fresh, expired} {
+		manifest := &MarketplaceManifest{Name: pm.Name, Plugins: []MarketplacePlugin{{Name: "from-" + pm.Name}}}
+		if err := SaveToCache(pm.Name, manifest); err != nil {
+			t.Fatalf("SaveToCache failed: %v", err)
+		}
+	}
+	backdate(t, filepath.Join(tmpDir, expired.Name+".json"), 2*CacheTTL)
+
+	discovered := DiscoverCachedMarketplaces()
+
+	if len(discovered) != 2 {
+		t.Fatalf("expected only the 2 cached marketplaces, got %d", len(discovered))
+	}
+	for _, pm := range []PopularMarketplace{fresh, expired} {
+		disc := discovered[pm.Name]
+		if disc == nil {
+			t.Errorf("%s missing from cached discovery", pm.Name)
+			continue
+		}
+		if disc.Repo != pm.Repo {
+			t.Errorf("%s repo = %q, want %q", pm.Name, disc.Repo, pm.Repo)
+		}
+		if len(disc.Manifest.Plugins) != 1 || disc.Manifest.Plugins[0].Name != "from-"+pm.Name {
+			t.Errorf("%s manifest = %+v, want the cached one", pm.Name, disc.Manifest)
+		}
+	}
+}
+
+func TestDiscoverPopularMarketplaces_Cancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	original := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	defer func() { plumCacheDir = original }()
+
+	// More marketplaces than fetch slots, from a cached registry so the
+	// test stays off the network
+	var list []PopularMarketplace
+	for i := 0; i < 3*MaxConcurrentFetches; i++ {
+		list = append(list, PopularMarketplace{Name: fmt.Sprintf("mkt-%d", i), Repo: fmt.Sprintf("https://github.com/test/mkt-%d", i)})
+	}
+	if err := saveRegistryToCache(&MarketplaceRegistry{Marketplaces: list}); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu      sync.Mutex
+		started int
+		first   = make(chan struct{})
+	)
+	originalFetch := fetchManifest
+	fetchManifest = func(ctx context.Context, repoURL, etag string) (*MarketplaceManifest, string, error) {
+		mu.Lock()
+		started++
+		if started == 1 {
+			close(first)
+		}
+		mu.Unlock()
+		// Hang like a stalled download until cancelled
+		<-ctx.Done()
+		return nil, "", ctx.Err()
+	}
+	defer func() { fetchManifest = originalFetch }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-first
+		cancel()
+	}()
+
+	start := time.Now()
+	discovered, err := DiscoverPopularMarketplaces(ctx, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("cancelled discovery took %s, want an early return", elapsed)
+	}
+	if len(discovered) != 0 {
+		t.Errorf("expected nothing discovered, got %d", len(discovered))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if started > MaxConcurrentFetches {
+		t.Errorf("%d fetches started; queued fetches should be skipped after cancellation", started)
+	}
+}
@@ -1,6 +1,7 @@
 package marketplace
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 )
@@ -31,6 +33,14 @@ var (
 	GitHubRawBase = "https://raw.githubusercontent.com"
 )
 
+// ErrNotJSON is returned when a response that should be JSON is an HTML page
+// or other non-JSON content (typically a wrong repo path or a proxy error page)
+var ErrNotJSON = errors.New("not found or not JSON")
+
+// ErrNotModified is returned by FetchManifestIfChanged when the manifest on
+// GitHub still matches the ETag sent with the request
+var ErrNotModified = errors.New("manifest not modified")
+
 var (
 	// Singleton HTTP client for connection reuse
 	httpClientOnce sync.Once
@@ -84,66 +94,99 @@ func isRetryableError(err error) bool {
 }
 
 // FetchManifestFromGitHub fetches marketplace.json from a GitHub repo with retries
-// repoURL format: "https://github.com/owner/repo-name" or "owner/repo-name" (legacy)
+// repoURL format: "https://github.com/owner/repo-name", a gitlab.com repo URL,
+// or "owner/repo-name" (legacy, GitHub)
 // Returns the parsed manifest or error
 func FetchManifestFromGitHub(repoURL string) (*MarketplaceManifest, error) {
-	// Extract owner/repo from full URL if needed
-	ownerRepo, err := DeriveSource(repoURL)
+	manifest, _, err := FetchManifestIfChanged(context.Background(), repoURL, "")
+	return manifest, err
+}
+
+// FetchManifestIfChanged fetches marketplace.json like FetchManifestFromGitHub,
+// sending etag (if any) as If-None-Match. It returns the manifest with the
+// response's ETag, or ErrNotModified when GitHub answers 304 Not Modified.
+// Cancelling ctx aborts the request in flight and any remaining retries.
+func FetchManifestIfChanged(ctx context.Context, repoURL, etag string) (*MarketplaceManifest, string, error) {
+	// Find the repo's host; a bare owner/repo is a GitHub repo (legacy)
+	loc, err := ParseRepoLocation(repoURL)
 	if err != nil {
-		// If DeriveSource fails, assume it's already in owner/repo format (legacy)
-		ownerRepo = repoURL
+		if strings.Contains(repoURL, "://") {
+			return nil, "", err
+		}
+		loc = RepoLocation{Host: HostGitHub, Repo: repoURL}
 	}
 
+	// Resolve the branch once; plugin links read the same cached answer
+	branch := loc.defaultBranch(ctx)
+
 	var lastErr error
 
 	// Retry with exponential backoff for transient failures
 	for attempt := 0; attempt < MaxRetries; attempt++ {
-		manifest, err := fetchManifestAttempt(ownerRepo)
+		manifest, newETag, err := fetchManifestAttempt(ctx, loc, branch, etag)
 		if err == nil {
-			return manifest, nil
+			return manifest, newETag, nil
 		}
 
 		lastErr = err
 
-		// Only retry transient failures (network errors, 5xx, 429)
+		// Only retry transient failures (network errors, 5xx, 429), and
+		// never once the caller has given up
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
 		if !isRetryableError(err) {
-			return nil, err
+			return nil, "", err
 		}
 
 		// Backoff before retry (except on last attempt): 1s, 2s, 4s
 		if attempt < MaxRetries-1 {
 			backoff := time.Duration(1<<uint(attempt)) * time.Second
-			time.Sleep(backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, "", ctx.Err()
+			}
 		}
 	}
 
-	return nil, fmt.Errorf("failed after %d attempts: %w", MaxRetries, lastErr)
+	return nil, "", fmt.Errorf("failed after %d attempts: %w", MaxRetries, lastErr)
 }
 
-// fetchManifestAttempt performs a single fetch attempt
-func fetchManifestAttempt(repo string) (*MarketplaceManifest, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), HTTPTimeout)
+// fetchManifestAttempt performs a single fetch attempt from branch,
+// conditional on etag when it isn't empty
+func fetchManifestAttempt(ctx context.Context, loc RepoLocation, branch, etag string) (*MarketplaceManifest, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, HTTPTimeout)
 	defer cancel()
 
-	url := buildRawURL(repo)
+	url := loc.RawURL(branch, ".claude-plugin/marketplace.json")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add User-Agent header (GitHub best practice)
 	req.Header.Set("User-Agent", "plum-marketplace-browser/0.2.0")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from GitHub: %w", err)
+		return nil, "", fmt.Errorf("failed to fetch from GitHub: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := CheckRateLimit(resp); err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, "", ErrNotModified
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, &httpStatusError{
+		return nil, "", &httpStatusError{
 			StatusCode: resp.StatusCode,
 			Message:    fmt.Sprintf("GitHub returned status %d for %s", resp.StatusCode, url),
 		}
@@ -153,7 +196,7 @@ func fetchManifestAttempt(repo string) (*MarketplaceManifest, error) {
 	limitedBody := io.LimitReader(resp.Body, MaxResponseBodySize)
 	body, err := io.ReadAll(limitedBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Check if we hit the size limit
@@ -161,23 +204,43 @@ func fetchManifestAttempt(repo string) (*MarketplaceManifest, error) {
 		// Try reading one more byte to confirm truncation
 		var oneByte [1]byte
 		if n, _ := resp.Body.Read(oneByte[:]); n > 0 {
-			return nil, fmt.Errorf("response body exceeded %d bytes", MaxResponseBodySize)
+			return nil, "", fmt.Errorf("response body exceeded %d bytes", MaxResponseBodySize)
 		}
 	}
 
+	if err := CheckJSONResponse(resp.Header.Get("Content-Type"), body, url, "marketplace manifest"); err != nil {
+		return nil, "", err
+	}
+
 	var manifest MarketplaceManifest
 	if err := json.Unmarshal(body, &manifest); err != nil {
-		return nil, fmt.Errorf("failed to parse marketplace.json: %w", err)
+		return nil, "", fmt.Errorf("failed to parse marketplace.json: %w", err)
 	}
 
-	return &manifest, nil
+	return &manifest, resp.Header.Get("ETag"), nil
 }
 
-// buildRawURL constructs the raw GitHub URL for marketplace.json
-// Example: https://raw.githubusercontent.com/owner/repo/main/.claude-plugin/marketplace.json
-func buildRawURL(repo string) string {
-	return fmt.Sprintf("%s/%s/%s/.claude-plugin/marketplace.json",
-		GitHubRawBase, repo, DefaultBranch)
+// CheckJSONResponse returns an ErrNotJSON error naming what and url when body
+// is clearly not a JSON document: an HTML content type, or content that does
+// not start with an object or array. Malformed JSON is left to the decoder so
+// its error message is preserved. contentType may be empty.
+func CheckJSONResponse(contentType string, body []byte, url, what string) error {
+	if strings.Contains(strings.ToLower(contentType), "text/html") {
+		return fmt.Errorf("%s %w at %s (got HTML page)", what, ErrNotJSON, url)
+	}
+
+	trimmed := bytes.TrimLeft(body, " \t\r\n\ufeff")
+	if len(trimmed) == 0 {
+		return fmt.Errorf("%s %w at %s (empty response)", what, ErrNotJSON, url)
+	}
+	switch trimmed[0] {
+	case '{', '[':
+		return nil
+	case '<':
+		return fmt.Errorf("%s %w at %s (got HTML page)", what, ErrNotJSON, url)
+	default:
+		return fmt.Errorf("%s %w at %s", what, ErrNotJSON, url)
+	}
 }
 
 // httpClient returns a singleton HTTP client for connection reuse
@@ -185,11 +248,13 @@ func httpClient() *http.Client {
 	httpClientOnce.Do(func() {
 		httpClientInst = &http.Client{
 			Timeout: HTTPTimeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        10,
-				MaxIdleConnsPerHost: 5,
-				IdleConnTimeout:     90 * time.Second,
-				TLSHandshakeTimeout: 10 * time.Second,
+			Transport: &authTransport{
+				base: &http.Transport{
+					MaxIdleConns:        10,
+					MaxIdleConnsPerHost: 5,
+					IdleConnTimeout:     90 * time.Second,
+					TLSHandshakeTimeout: 10 * time.Second,
+				},
 			},
 		}
 	})
@@ -12,11 +12,8 @@ import (
 	"time"
 )
 
-const (
-	// GitHubStatsCacheTTL is how long cached GitHub stats remain valid (24 hours)
-	GitHubStatsCacheTTL = 24 * time.Hour
-
-	// GitHubAPIBase is the base URL for GitHub API v3
+var (
+	// GitHubAPIBase is the base URL for GitHub API v3 (variable for testing)
 	GitHubAPIBase = "https://api.github.com"
 )
 
@@ -65,6 +62,9 @@ func FetchGitHubStats(repoURL string) (*GitHubStats, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := CheckRateLimit(resp); err != nil {
+		return nil, err
+	}
 	if resp.StatusCode != 200 {
 		// Non-fatal - allow graceful degradation
 		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
@@ -114,7 +114,7 @@ func LoadStatsFromCache(marketplaceName string) (*GitHubStats, error) {
 	}
 
 	// Check TTL
-	if time.Since(entry.FetchedAt) > GitHubStatsCacheTTL {
+	if time.Since(entry.FetchedAt) > cacheTTL() {
 		return nil, nil // Expired
 	}
 
@@ -1,6 +1,7 @@
 package marketplace
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -22,6 +23,7 @@ func TestFetchManifestFromGitHub_BodySizeLimit(t *testing.T) {
 	originalBase := GitHubRawBase
 	GitHubRawBase = server.URL
 	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, http.NotFound)
 
 	// Test that a response exceeding the limit triggers an error
 	_, err := FetchManifestFromGitHub("test/repo")
@@ -53,6 +55,7 @@ func TestFetchManifestFromGitHub_Retry(t *testing.T) {
 	originalBase := GitHubRawBase
 	GitHubRawBase = server.URL
 	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, http.NotFound)
 
 	// Test that retry succeeds after transient failures
 	manifest, err := FetchManifestFromGitHub("test/repo")
@@ -95,6 +98,7 @@ func TestFetchManifestAttempt_InvalidJSON(t *testing.T) {
 	originalBase := GitHubRawBase
 	GitHubRawBase = server.URL
 	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, http.NotFound)
 
 	// Test that invalid JSON is not retried (parsing errors are not transient)
 	_, err := FetchManifestFromGitHub("test/repo")
@@ -124,6 +128,7 @@ func TestFetchManifestAttempt_Timeout(t *testing.T) {
 	originalBase := GitHubRawBase
 	GitHubRawBase = server.URL
 	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, http.NotFound)
 
 	// Test that timeout errors trigger retries (they are transient)
 	_, err := FetchManifestFromGitHub("test/repo")
@@ -150,6 +155,7 @@ func TestNonRetryableError(t *testing.T) {
 	originalBase := GitHubRawBase
 	GitHubRawBase = server.URL
 	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, http.NotFound)
 
 	// Test that 404 errors are not retried
 	_, err := FetchManifestFromGitHub("test/repo")
@@ -169,3 +175,224 @@ func TestNonRetryableError(t *testing.T) {
 		t.Errorf("Expected status code 404, got: %d", statusErr.StatusCode)
 	}
 }
+
+func TestFetchManifestAttempt_HTMLResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><body>404: Not Found</body></html>`))
+	}))
+	defer server.Close()
+
+	// Override GitHubRawBase for testing
+	originalBase := GitHubRawBase
+	GitHubRawBase = server.URL
+	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, http.NotFound)
+
+	_, err := FetchManifestFromGitHub("test/repo")
+	if err == nil {
+		t.Fatal("Expected error for HTML response, got nil")
+	}
+	if !errors.Is(err, ErrNotJSON) {
+		t.Errorf("Expected ErrNotJSON, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "marketplace manifest not found or not JSON at "+server.URL) {
+		t.Errorf("Expected clear error naming the URL, got: %v", err)
+	}
+}
+
+func TestCheckJSONResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     bool
+	}{
+		{"json object", "text/plain; charset=utf-8", `{"name":"x"}`, false},
+		{"json array with whitespace", "", "  \n[1,2]", false},
+		{"malformed json left to decoder", "", `{invalid`, false},
+		{"html content type", "text/html", `{"name":"x"}`, true},
+		{"html body sniffed", "", `<html><body>Not Found</body></html>`, true},
+		{"plain text", "text/plain", `404: Not Found`, true},
+		{"empty body", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckJSONResponse(tt.contentType, []byte(tt.body), "https://example.com/x.json", "plugin.json")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckJSONResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "plugin.json not found or not JSON at https://example.com/x.json") {
+				t.Errorf("unexpected error message: %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchManifestIfChanged_ETag(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"test","owner":{},"metadata":{},"plugins":[]}`))
+	}))
+	defer server.Close()
+
+	originalBase := GitHubRawBase
+	GitHubRawBase = server.URL
+	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, http.NotFound)
+
+	t.Run("no etag fetches the manifest and its etag", func(t *testing.T) {
+		manifest, etag, err := FetchManifestIfChanged(context.Background(), "test/repo", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if manifest == nil || manifest.Name != "test" || etag != `"v1"` {
+			t.Errorf("got manifest %+v, etag %q", manifest, etag)
+		}
+	})
+
+	t.Run("matching etag is not modified", func(t *testing.T) {
+		attempts = 0
+		manifest, _, err := FetchManifestIfChanged(context.Background(), "test/repo", `"v1"`)
+		if !errors.Is(err, ErrNotModified) {
+			t.Fatalf("expected ErrNotModified, got %v", err)
+		}
+		if manifest != nil {
+			t.Error("a 304 should not return a manifest")
+		}
+		if attempts != 1 {
+			t.Errorf("a 304 should not be retried, got %d attempts", attempts)
+		}
+	})
+
+	t.Run("stale etag fetches the new manifest", func(t *testing.T) {
+		manifest, etag, err := FetchManifestIfChanged(context.Background(), "test/repo", `"v0"`)
+		if err != nil || manifest == nil || etag != `"v1"` {
+			t.Errorf("got manifest %+v, etag %q, err %v", manifest, etag, err)
+		}
+	})
+}
+
+func TestFetchManifestIfChanged_DefaultBranch(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/test/repo/develop/.claude-plugin/marketplace.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "test", "plugins": []}`))
+	}))
+	defer server.Close()
+
+	originalBase := GitHubRawBase
+	GitHubRawBase = server.URL
+	defer func() { GitHubRawBase = originalBase }()
+	setupBranchTest(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"default_branch": "develop"}`))
+	})
+
+	if _, _, err := FetchManifestIfChanged(context.Background(), "test/repo", ""); err != nil {
+		t.Fatalf("FetchManifestIfChanged failed: %v (requested %v)", err, paths)
+	}
+	// Plugin links read the branch the manifest came from
+	if got := CachedDefaultBranch("test/repo"); got != "develop" {
+		t.Errorf("CachedDefaultBranch() = %q, want %q", got, "develop")
+	}
+}
+
+func TestFetchManifestIfChanged_GitLab(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/group/sub/repo/-/raw/main/.claude-plugin/marketplace.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "test", "plugins": []}`))
+	}))
+	defer server.Close()
+
+	originalBase := GitLabBase
+	GitLabBase = server.URL
+	defer func() { GitLabBase = originalBase }()
+	// GitLab repos never ask the GitHub API for a default branch
+	setupBranchTest(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected GitHub API request %s", r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	if _, _, err := FetchManifestIfChanged(context.Background(), "https://gitlab.com/group/sub/repo", ""); err != nil {
+		t.Fatalf("FetchManifestIfChanged failed: %v (requested %v)", err, paths)
+	}
+
+	if _, _, err := FetchManifestIfChanged(context.Background(), "https://codeberg.org/user/repo", ""); err == nil {
+		t.Error("expected an error for an unsupported host")
+	}
+}
+
+func TestFetchManifestIfChanged_Cancelled(t *testing.T) {
+	t.Run("request in flight", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Hang until the client gives up
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		originalBase := GitHubRawBase
+		GitHubRawBase = server.URL
+		defer func() { GitHubRawBase = originalBase }()
+		setupBranchTest(t, http.NotFound)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, _, err := FetchManifestIfChanged(ctx, "test/repo", "")
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("cancelled fetch took %s, want an early return", elapsed)
+		}
+	})
+
+	t.Run("during retry backoff", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		originalBase := GitHubRawBase
+		GitHubRawBase = server.URL
+		defer func() { GitHubRawBase = originalBase }()
+		setupBranchTest(t, http.NotFound)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, _, err := FetchManifestIfChanged(ctx, "test/repo", "")
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		// The first backoff is 1s; cancelling must cut it short
+		if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+			t.Errorf("cancelled fetch took %s, want it to stop during the backoff", elapsed)
+		}
+		if attempts != 1 {
+			t.Errorf("expected no retry after cancellation, got %d attempts", attempts)
+		}
+	})
+}
@@ -1,8 +1,13 @@
 package marketplace
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // ClearCache removes all cached marketplace data
@@ -30,18 +35,130 @@ func ClearCache() error {
 	return nil
 }
 
-// RefreshAll clears cache and re-fetches all marketplaces using latest registry
-func RefreshAll() error {
+// RefreshAll clears cache and re-fetches all marketplaces using latest registry.
+// If ctx is cancelled part way, the cached manifests that weren't re-fetched
+// yet are put back so the cache isn't left half empty.
+func RefreshAll(ctx context.Context) error {
+	// Remember the cached manifests: the ones GitHub gave ETags for are
+	// revalidated instead of downloaded again, and all of them are restored
+	// if the refresh is cancelled
+	cached := cachedManifestEntries()
+	previous := withETag(cached)
+
 	// Clear existing cache
 	if err := ClearCache(); err != nil {
 		return fmt.Errorf("failed to clear cache: %w", err)
 	}
 
 	// Fetch fresh data from registry (this will repopulate cache with ALL marketplaces)
-	_, err := DiscoverWithRegistry()
+	manifests, _, err := discoverWithRegistry(ctx, previous, 0)
+	if ctx.Err() != nil {
+		for name, entry := range cached {
+			if _, refreshed := manifests[name]; !refreshed {
+				_ = writeCacheEntry(name, *entry) // Best effort
+			}
+		}
+		return ctx.Err()
+	}
 	if err != nil {
 		return fmt.Errorf("failed to refresh marketplaces: %w", err)
 	}
 
 	return nil
 }
+
+// RefreshOlderThan re-fetches the marketplaces whose cached manifest is
+// missing or older than maxAge and drops GitHub stats older than maxAge,
+// leaving fresher cache entries alone. Returns the names of the
+// marketplaces that were fetched.
+func RefreshOlderThan(ctx context.Context, maxAge time.Duration) ([]string, error) {
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("max age must be positive, got %s", maxAge)
+	}
+
+	expireStatsOlderThan(maxAge)
+
+	_, fetched, err := discoverWithRegistry(ctx, nil, maxAge)
+	if ctx.Err() != nil {
+		return fetched, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh marketplaces: %w", err)
+	}
+	return fetched, nil
+}
+
+// expireStatsOlderThan removes cached GitHub stats fetched more than maxAge
+// ago, so they are fetched again on next use. Unreadable entries are skipped.
+func expireStatsOlderThan(maxAge time.Duration) {
+	cacheDir, err := PlumCacheDir()
+	if err != nil {
+		return
+	}
+	files, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return
+	}
+
+	for _, f := range files {
+		name, ok := strings.CutSuffix(f.Name(), "_stats.json")
+		if !ok || f.IsDir() || validateMarketplaceName(name) != nil {
+			continue
+		}
+		path := filepath.Join(cacheDir, f.Name())
+		// #nosec G304 -- path is a validated marketplace name in the trusted cache directory
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry GitHubStatsCacheEntry
+		if json.Unmarshal(data, &entry) != nil || time.Since(entry.FetchedAt) < maxAge {
+			continue
+		}
+		_ = os.Remove(path)
+	}
+}
+
+// withETag returns the entries that have an ETag
+func withETag(entries map[string]*CacheEntry) map[string]*CacheEntry {
+	etagged := make(map[string]*CacheEntry)
+	for name, entry := range entries {
+		if entry.ETag != "" {
+			etagged[name] = entry
+		}
+	}
+	return etagged
+}
+
+// cachedManifestEntries returns the cached manifests by marketplace name.
+// Unreadable entries are skipped.
+func cachedManifestEntries() map[string]*CacheEntry {
+	entries := make(map[string]*CacheEntry)
+
+	cacheDir, err := PlumCacheDir()
+	if err != nil {
+		return entries
+	}
+	files, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return entries
+	}
+
+	for _, f := range files {
+		name, ok := strings.CutSuffix(f.Name(), ".json")
+		if !ok || f.IsDir() || validateMarketplaceName(name) != nil {
+			continue
+		}
+		// #nosec G304 -- path is a validated marketplace name in the trusted cache directory
+		data, err := os.ReadFile(filepath.Join(cacheDir, f.Name()))
+		if err != nil {
+			continue
+		}
+		var entry CacheEntry
+		if json.Unmarshal(data, &entry) != nil || entry.Manifest == nil {
+			continue
+		}
+		entries[name] = &entry
+	}
+	return entries
+}
@@ -1,9 +1,13 @@
 package marketplace
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // TestClearCache verifies cache clearing functionality
@@ -73,3 +77,115 @@ func TestClearCache(t *testing.T) {
 		}
 	})
 }
+
+func TestRefreshOlderThan(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalPlumCacheDir := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	defer func() { plumCacheDir = originalPlumCacheDir }()
+
+	var fetches []string
+	originalFetch := fetchManifest
+	fetchManifest = func(ctx context.Context, repoURL, etag string) (*MarketplaceManifest, string, error) {
+		fetches = append(fetches, repoURL)
+		return &MarketplaceManifest{Name: "upstream"}, "", nil
+	}
+	defer func() { fetchManifest = originalFetch }()
+
+	// A cached registry keeps the test off the network
+	if err := saveRegistryToCache(&MarketplaceRegistry{Marketplaces: []PopularMarketplace{
+		{Name: "fresh", Repo: "https://github.com/test/fresh"},
+		{Name: "stale", Repo: "https://github.com/test/stale"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"fresh", "stale"} {
+		if err := SaveToCache(name, &MarketplaceManifest{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveStatsToCache(name, &GitHubStats{Stars: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backdate(t, filepath.Join(tmpDir, "stale.json"), 3*time.Hour)
+	backdate(t, filepath.Join(tmpDir, "stale_stats.json"), 3*time.Hour)
+
+	refreshed, err := RefreshOlderThan(context.Background(), time.Hour)
+	if err != nil {
+		t.Fatalf("RefreshOlderThan failed: %v", err)
+	}
+	if want := []string{"stale"}; !reflect.DeepEqual(refreshed, want) {
+		t.Errorf("refreshed = %v, want %v", refreshed, want)
+	}
+	if want := []string{"https://github.com/test/stale"}; !reflect.DeepEqual(fetches, want) {
+		t.Errorf("fetched %v, want only the stale marketplace", fetches)
+	}
+	if entry, _ := loadCacheEntry("stale"); entry == nil || time.Since(entry.FetchedAt) > time.Minute {
+		t.Errorf("the stale manifest should be cached again, got %+v", entry)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "stale_stats.json")); !os.IsNotExist(err) {
+		t.Error("stats older than the max age should be dropped")
+	}
+	if stats, _ := LoadStatsFromCache("fresh"); stats == nil {
+		t.Error("stats younger than the max age should be kept")
+	}
+
+	if _, err := RefreshOlderThan(context.Background(), 0); err == nil {
+		t.Error("a zero max age should be rejected")
+	}
+}
+
+func TestRefreshAll_CancelledRestoresCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalPlumCacheDir := plumCacheDir
+	plumCacheDir = func() (string, error) {
+		return tmpDir, nil
+	}
+	defer func() { plumCacheDir = originalPlumCacheDir }()
+
+	fetches := 0
+	originalFetch := fetchManifest
+	fetchManifest = func(ctx context.Context, repoURL, etag string) (*MarketplaceManifest, string, error) {
+		fetches++
+		return nil, "", ctx.Err()
+	}
+	defer func() { fetchManifest = originalFetch }()
+
+	// Already cancelled, so the registry fetch fails without a network
+	// round trip and no marketplace gets re-fetched
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	names := []string{PopularMarketplaces[0].Name, PopularMarketplaces[1].Name}
+	for _, name := range names {
+		if err := saveToCache(name, &MarketplaceManifest{Name: name}, `"etag-`+name+`"`); err != nil {
+			t.Fatal(err)
+		}
+		backdate(t, filepath.Join(tmpDir, name+".json"), 2*time.Hour)
+	}
+
+	err := RefreshAll(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if fetches != 0 {
+		t.Errorf("%d fetches started after cancellation", fetches)
+	}
+
+	for _, name := range names {
+		entry, err := loadCacheEntry(name)
+		if err != nil || entry == nil {
+			t.Fatalf("%s should be restored to the cache, got %v", name, err)
+		}
+		// Restored as it was, not marked as freshly fetched
+		if age := time.Since(entry.FetchedAt); age < time.Hour {
+			t.Errorf("%s restored with age %s, want its original age", name, age)
+		}
+		if entry.ETag != `"etag-`+name+`"` {
+			t.Errorf("%s restored with ETag %q", name, entry.ETag)
+		}
+	}
+}
@@ -39,6 +39,11 @@ type RegistryCacheEntry struct {
 // FetchRegistry fetches the marketplace registry from GitHub
 // Falls back to hardcoded PopularMarketplaces on failure
 func FetchRegistry() ([]PopularMarketplace, error) {
+	return fetchRegistry(context.Background())
+}
+
+// fetchRegistry is FetchRegistry with a context that can cancel the fetch
+func fetchRegistry(ctx context.Context) ([]PopularMarketplace, error) {
 	// Try cache first (6-hour TTL for registry)
 	cached, err := loadRegistryFromCache()
 	if err == nil && cached != nil {
@@ -46,7 +51,7 @@ func FetchRegistry() ([]PopularMarketplace, error) {
 	}
 
 	// Cache miss or expired - fetch from GitHub
-	registry, err := fetchRegistryFromGitHub()
+	registry, err := fetchRegistryFromGitHub(ctx)
 	if err != nil {
 		// Fallback to hardcoded list
 		return PopularMarketplaces, nil
@@ -60,8 +65,9 @@ func FetchRegistry() ([]PopularMarketplace, error) {
 
 // FetchRegistryWithComparison fetches registry and compares with current
 // Returns new marketplaces count and the full list
-// Compares against CACHED registry if available, otherwise uses provided list
-func FetchRegistryWithComparison(current []PopularMarketplace) ([]PopularMarketplace, int, error) {
+// Compares against CACHED registry if available, otherwise uses provided list.
+// Cancelling ctx abandons the fetch.
+func FetchRegistryWithComparison(ctx context.Context, current []PopularMarketplace) ([]PopularMarketplace, int, error) {
 	// IMPORTANT: Load cached registry BEFORE fetching new one for comparison
 	cachedRegistry, err := loadRegistryFromCache()
 	var compareList []PopularMarketplace
@@ -74,7 +80,7 @@ func FetchRegistryWithComparison(current []PopularMarketplace) ([]PopularMarketp
 	}
 
 	// Now fetch the latest registry (DON'T save to cache yet - only save on Shift+U)
-	registry, err := fetchRegistryFromGitHub()
+	registry, err := fetchRegistryFromGitHub(ctx)
 	if err != nil {
 		// Return cached list if available, otherwise hardcoded
 		if cachedRegistry != nil {
@@ -102,8 +108,8 @@ func FetchRegistryWithComparison(current []PopularMarketplace) ([]PopularMarketp
 }
 
 // fetchRegistryFromGitHub fetches the registry from GitHub
-func fetchRegistryFromGitHub() (*MarketplaceRegistry, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), HTTPTimeout)
+func fetchRegistryFromGitHub(ctx context.Context) (*MarketplaceRegistry, error) {
+	ctx, cancel := context.WithTimeout(ctx, HTTPTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, RegistryURL, nil)
@@ -120,6 +126,9 @@ func fetchRegistryFromGitHub() (*MarketplaceRegistry, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := CheckRateLimit(resp); err != nil {
+		return nil, err
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("GitHub returned status %d for registry", resp.StatusCode)
 	}
@@ -6,9 +6,11 @@ import (
 	"strings"
 )
 
-// DeriveSource converts a repo URL to Claude Code CLI source format
+// DeriveSource converts a repo URL to Claude Code CLI source format.
+// GitHub and GitLab URLs go through ParseRepoLocation:
 // GitHub: https://github.com/owner/repo → owner/repo
-// Others: https://gitlab.com/company/plugins → https://gitlab.com/company/plugins (full URL)
+// GitLab: https://gitlab.com/group/repo.git → https://gitlab.com/group/repo
+// Others: https://codeberg.org/user/plugins → https://codeberg.org/user/plugins (full URL)
 func DeriveSource(repoURL string) (string, error) {
 	if repoURL == "" {
 		return "", fmt.Errorf("empty repo URL")
@@ -25,19 +27,16 @@ func DeriveSource(repoURL string) (string, error) {
 		return "", fmt.Errorf("invalid repo URL: missing scheme or host")
 	}
 
-	// GitHub: extract owner/repo shorthand
-	if u.Host == "github.com" {
-		path := strings.TrimPrefix(u.Path, "/")
-		path = strings.TrimSuffix(path, ".git")
-
-		parts := strings.Split(path, "/")
-		if len(parts) >= 2 {
-			return fmt.Sprintf("%s/%s", parts[0], parts[1]), nil
+	switch u.Host {
+	case "github.com", "gitlab.com":
+		loc, err := ParseRepoLocation(repoURL)
+		if err != nil {
+			return "", err
 		}
-		return "", fmt.Errorf("invalid GitHub path: %s", u.Path)
+		return loc.Source(), nil
 	}
 
-	// For non-GitHub (GitLab, Codeberg, etc.), use full URL
+	// For other hosts (Codeberg, self-hosted...), use full URL
 	return repoURL, nil
 }
 
@@ -49,3 +48,115 @@ func IsGitHubRepo(repoURL string) bool {
 	}
 	return u.Host == "github.com"
 }
+
+// PluginSourcePath returns a plugin's directory within its marketplace repo.
+// A leading "./" is dropped; an empty or "." source defaults to plugins/<name>.
+func PluginSourcePath(source, name string) string {
+	sourcePath := strings.TrimPrefix(source, "./")
+	if sourcePath == "" || sourcePath == "." {
+		sourcePath = "plugins/" + name
+	}
+	return sourcePath
+}
+
+// Repo hosts plum can download plugin files from. The values match the
+// "source" field of a marketplace source in settings.json.
+const (
+	HostGitHub = "github"
+	HostGitLab = "gitlab"
+)
+
+// GitLabBase is the base URL for GitLab repos and raw content (variable for testing)
+var GitLabBase = "https://gitlab.com"
+
+// RepoLocation identifies a marketplace repo on a supported host
+type RepoLocation struct {
+	Host string // HostGitHub or HostGitLab
+	Repo string // owner/repo (GitLab projects may include subgroups)
+}
+
+// Source returns the repo in Claude Code CLI source format: owner/repo for
+// GitHub, the canonical repo URL for GitLab
+func (r RepoLocation) Source() string {
+	if r.Host == HostGitLab {
+		return RepoURLForSource(HostGitLab, r.Repo)
+	}
+	return r.Repo
+}
+
+// ParseRepoLocation recognizes github.com and gitlab.com repo URLs
+// GitHub: https://github.com/owner/repo → {github, owner/repo}
+// GitLab: https://gitlab.com/group/sub/repo → {gitlab, group/sub/repo}
+func ParseRepoLocation(repoURL string) (RepoLocation, error) {
+	if repoURL == "" {
+		return RepoLocation{}, fmt.Errorf("empty repo URL")
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return RepoLocation{}, fmt.Errorf("invalid repo URL: %s", repoURL)
+	}
+
+	path := strings.Trim(u.Path, "/")
+	path = strings.TrimSuffix(path, ".git")
+
+	switch u.Host {
+	case "github.com":
+		parts := strings.Split(path, "/")
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return RepoLocation{}, fmt.Errorf("invalid GitHub path: %s", u.Path)
+		}
+		return RepoLocation{Host: HostGitHub, Repo: parts[0] + "/" + parts[1]}, nil
+	case "gitlab.com":
+		// GitLab project paths end where "/-/" (tree, blob, raw...) begins
+		if i := strings.Index(path, "/-/"); i >= 0 {
+			path = path[:i]
+		}
+		if !strings.Contains(path, "/") || strings.HasSuffix(path, "/") {
+			return RepoLocation{}, fmt.Errorf("invalid GitLab path: %s", u.Path)
+		}
+		return RepoLocation{Host: HostGitLab, Repo: path}, nil
+	default:
+		return RepoLocation{}, fmt.Errorf("unsupported repo host %s (only GitHub and GitLab are supported)", u.Host)
+	}
+}
+
+// RepoURLForSource builds a repo URL from a settings marketplace source
+// ("github" or "gitlab") and its owner/repo. Returns "" for other sources.
+func RepoURLForSource(source, repo string) string {
+	if repo == "" {
+		return ""
+	}
+	switch source {
+	case HostGitHub:
+		return "https://github.com/" + repo
+	case HostGitLab:
+		return "https://gitlab.com/" + repo
+	default:
+		return ""
+	}
+}
+
+// RawURL returns the raw content URL of path at ref
+// GitHub: <GitHubRawBase>/owner/repo/<ref>/<path>
+// GitLab: <GitLabBase>/owner/repo/-/raw/<ref>/<path>
+func (r RepoLocation) RawURL(ref, path string) string {
+	if r.Host == HostGitLab {
+		return fmt.Sprintf("%s/%s/-/raw/%s/%s", GitLabBase, r.Repo, ref, path)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", GitHubRawBase, r.Repo, ref, path)
+}
+
+// BranchCandidates returns the branches to try when downloading. GitHub repos
+// try their resolved default branch first; GitLab repos try main, then master.
+func (r RepoLocation) BranchCandidates() []string {
+	if r.Host == HostGitLab {
+		return []string{DefaultBranch, FallbackBranch}
+	}
+	return BranchCandidates(r.Repo)
+}
+
+// PluginJSONURL returns the raw URL of a plugin's plugin.json
+// Example: https://raw.githubusercontent.com/owner/repo/main/plugins/name/.claude-plugin/plugin.json
+func PluginJSONURL(loc RepoLocation, ref, sourcePath string) string {
+	return loc.RawURL(ref, sourcePath+"/.claude-plugin/plugin.json")
+}
@@ -35,6 +35,24 @@ func TestDeriveSource(t *testing.T) {
 			want:    "https://gitlab.com/company/plugins",
 			wantErr: false,
 		},
+		{
+			name:    "GitLab HTTPS URL with .git suffix",
+			repoURL: "https://gitlab.com/company/plugins.git",
+			want:    "https://gitlab.com/company/plugins",
+			wantErr: false,
+		},
+		{
+			name:    "GitLab subgroup tree URL",
+			repoURL: "https://gitlab.com/group/sub/plugins/-/tree/main",
+			want:    "https://gitlab.com/group/sub/plugins",
+			wantErr: false,
+		},
+		{
+			name:    "GitLab URL with insufficient path segments",
+			repoURL: "https://gitlab.com/company",
+			want:    "",
+			wantErr: true,
+		},
 		{
 			name:    "Codeberg HTTPS URL",
 			repoURL: "https://codeberg.org/user/marketplace",
@@ -139,3 +157,101 @@ func TestIsGitHubRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestPluginJSONURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"relative path", "./plugins/custom-dir", "https://raw.githubusercontent.com/owner/repo/main/plugins/custom-dir/.claude-plugin/plugin.json"},
+		{"path without ./", "tools/alpha", "https://raw.githubusercontent.com/owner/repo/main/tools/alpha/.claude-plugin/plugin.json"},
+		{"empty", "", "https://raw.githubusercontent.com/owner/repo/main/plugins/alpha/.claude-plugin/plugin.json"},
+		{"dot", ".", "https://raw.githubusercontent.com/owner/repo/main/plugins/alpha/.claude-plugin/plugin.json"},
+		{"dot slash", "./", "https://raw.githubusercontent.com/owner/repo/main/plugins/alpha/.claude-plugin/plugin.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := RepoLocation{Host: HostGitHub, Repo: "owner/repo"}
+			got := PluginJSONURL(repo, "main", PluginSourcePath(tt.source, "alpha"))
+			if got != tt.want {
+				t.Errorf("PluginJSONURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRepoLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoURL string
+		want    RepoLocation
+		wantErr bool
+	}{
+		{"GitHub", "https://github.com/owner/repo", RepoLocation{HostGitHub, "owner/repo"}, false},
+		{"GitHub with .git", "https://github.com/owner/repo.git", RepoLocation{HostGitHub, "owner/repo"}, false},
+		{"GitHub tree URL", "https://github.com/owner/repo/tree/main/plugins", RepoLocation{HostGitHub, "owner/repo"}, false},
+		{"GitLab", "https://gitlab.com/owner/repo", RepoLocation{HostGitLab, "owner/repo"}, false},
+		{"GitLab subgroup", "https://gitlab.com/group/sub/repo/", RepoLocation{HostGitLab, "group/sub/repo"}, false},
+		{"GitLab tree URL", "https://gitlab.com/owner/repo/-/tree/main", RepoLocation{HostGitLab, "owner/repo"}, false},
+		{"GitLab missing repo", "https://gitlab.com/owner", RepoLocation{}, true},
+		{"GitHub missing repo", "https://github.com/owner", RepoLocation{}, true},
+		{"unsupported host", "https://codeberg.org/user/repo", RepoLocation{}, true},
+		{"empty", "", RepoLocation{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRepoLocation(tt.repoURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepoLocation(%q) error = %v, wantErr %v", tt.repoURL, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRepoLocation(%q) = %+v, want %+v", tt.repoURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoLocation_RawURL(t *testing.T) {
+	tests := []struct {
+		name string
+		repo RepoLocation
+		want string
+	}{
+		{"GitHub", RepoLocation{HostGitHub, "owner/repo"}, "https://raw.githubusercontent.com/owner/repo/main/plugins/alpha/commands/a.md"},
+		{"GitLab", RepoLocation{HostGitLab, "group/sub/repo"}, "https://gitlab.com/group/sub/repo/-/raw/main/plugins/alpha/commands/a.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.RawURL("main", "plugins/alpha/commands/a.md"); got != tt.want {
+				t.Errorf("RawURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// GitLab never consults the GitHub API for its default branch
+	gitlab := RepoLocation{HostGitLab, "owner/repo"}
+	if got := gitlab.BranchCandidates(); len(got) != 2 || got[0] != DefaultBranch || got[1] != FallbackBranch {
+		t.Errorf("GitLab BranchCandidates() = %v, want [main master]", got)
+	}
+}
+
+func TestRepoURLForSource(t *testing.T) {
+	tests := []struct {
+		source, repo, want string
+	}{
+		{HostGitHub, "owner/repo", "https://github.com/owner/repo"},
+		{HostGitLab, "owner/repo", "https://gitlab.com/owner/repo"},
+		{"git", "https://example.com/repo.git", ""},
+		{HostGitHub, "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := RepoURLForSource(tt.source, tt.repo); got != tt.want {
+			t.Errorf("RepoURLForSource(%q, %q) = %q, want %q", tt.source, tt.repo, got, tt.want)
+		}
+	}
+}
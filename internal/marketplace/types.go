@@ -38,6 +38,7 @@ type MarketplacePlugin struct {
 	Keywords    []string `json:"keywords"`
 	Tags        []string `json:"tags"`
 	Strict      bool     `json:"strict"`
+	SHA256      string   `json:"sha256,omitempty"` // Expected SHA-256 of the plugin's .claude-plugin/plugin.json
 
 	// Installability tracking (set during unmarshaling or validation)
 	HasLSPServers bool `json:"-"` // True if plugin has lspServers config (built into Claude Code)
@@ -3,29 +3,35 @@ package plugin
 import (
 	"encoding/json"
 	"strings"
+	"time"
 )
 
 // Plugin represents a Claude Code plugin from any marketplace.
 // Contains metadata, installation state, and marketplace source information.
 // Used for search, display, and installation command generation.
 type Plugin struct {
-	Name              string   `json:"name"`
-	Description       string   `json:"description"`
-	Version           string   `json:"version"`
-	Keywords          []string `json:"keywords"`
-	Category          string   `json:"category"`
-	Author            Author   `json:"author"`
-	Marketplace       string   `json:"-"`      // Friendly marketplace name (e.g., "feedmob-plugins")
-	MarketplaceRepo   string   `json:"-"`      // Full repo URL for display (e.g., "https://github.com/feed-mob/claude-code-marketplace")
-	MarketplaceSource string   `json:"-"`      // CLI source format (e.g., "feed-mob/claude-code-marketplace" for GitHub)
-	Installed         bool     `json:"-"`      // Whether this plugin is currently installed
-	IsDiscoverable    bool     `json:"-"`      // Whether from a discoverable (not installed) marketplace
-	InstallPath       string   `json:"-"`      // Path if installed
-	Source            string   `json:"source"` // Source path within marketplace
-	Homepage          string   `json:"homepage"`
-	Repository        string   `json:"repository"` // Source repository URL
-	License           string   `json:"license"`    // License identifier (e.g., "MIT")
-	Tags              []string `json:"tags"`       // Categorization tags
+	Name              string    `json:"name"`
+	DisplayName       string    `json:"-"` // Optional friendly name from local overrides
+	Description       string    `json:"description"`
+	Version           string    `json:"version"`
+	Keywords          []string  `json:"keywords"`
+	Category          string    `json:"category"`
+	Author            Author    `json:"author"`
+	Marketplace       string    `json:"-"`      // Friendly marketplace name (e.g., "feedmob-plugins")
+	MarketplaceRepo   string    `json:"-"`      // Full repo URL for display (e.g., "https://github.com/feed-mob/claude-code-marketplace")
+	MarketplaceSource string    `json:"-"`      // CLI source format (e.g., "feed-mob/claude-code-marketplace" for GitHub)
+	MarketplaceBranch string    `json:"-"`      // Default branch of the marketplace repo, if known (empty = "main")
+	Installed         bool      `json:"-"`      // Whether this plugin is currently installed
+	IsDiscoverable    bool      `json:"-"`      // Whether from a discoverable (not installed) marketplace
+	InstallPath       string    `json:"-"`      // Path if installed
+	Source            string    `json:"source"` // Source path within marketplace
+	Homepage          string    `json:"homepage"`
+	Repository        string    `json:"repository"` // Source repository URL
+	License           string    `json:"license"`    // License identifier (e.g., "MIT")
+	Tags              []string  `json:"tags"`       // Categorization tags
+	PluginJSONSHA256  string    `json:"-"`          // Expected SHA-256 of plugin.json (from marketplace entry)
+	LastCheckedAt     time.Time `json:"-"`          // When `plum update` last checked this install (zero if never)
+	UpdatedAt         time.Time `json:"-"`          // When this install was last installed or updated (zero if unknown)
 
 	// Installability tracking
 	HasLSPServers bool `json:"-"` // True if plugin has lspServers config (built into Claude Code)
@@ -82,6 +88,17 @@ func (p Plugin) FullName() string {
 	return p.Name + "@" + p.Marketplace
 }
 
+// ParseFullName splits a "name@marketplace" identifier at its last '@', so
+// plugin names may themselves contain '@'. ok is false when there is no '@'
+// or either side is empty.
+func ParseFullName(fullName string) (name, marketplace string, ok bool) {
+	idx := strings.LastIndex(fullName, "@")
+	if idx <= 0 || idx == len(fullName)-1 {
+		return "", "", false
+	}
+	return fullName[:idx], fullName[idx+1:], true
+}
+
 // InstallCommand returns the command to install this plugin
 func (p Plugin) InstallCommand() string {
 	return "/plugin install " + p.FullName()
@@ -93,7 +110,11 @@ func (p Plugin) FilterValue() string {
 }
 
 // Title implements the list.DefaultItem interface
+// Returns the display name when one is set, otherwise the plugin name
 func (p Plugin) Title() string {
+	if p.DisplayName != "" {
+		return p.DisplayName
+	}
 	return p.Name
 }
 
@@ -149,10 +170,17 @@ func (p *Plugin) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// GitHubURL returns the GitHub URL for this plugin's source code
-// Constructs URL from MarketplaceRepo + Source path
-// Example: https://github.com/owner/repo/tree/main/plugins/plugin-name
+// GitHubURL returns the web URL for this plugin's source code.
+// Kept for existing callers; it is the same as SourceURL and may point at GitLab.
 func (p Plugin) GitHubURL() string {
+	return p.SourceURL()
+}
+
+// SourceURL returns the web URL for this plugin's source code on its
+// marketplace's host. Constructs URL from MarketplaceRepo + branch + Source path
+// GitHub: https://github.com/owner/repo/tree/main/plugins/plugin-name
+// GitLab: https://gitlab.com/owner/repo/-/tree/main/plugins/plugin-name
+func (p Plugin) SourceURL() string {
 	if p.MarketplaceRepo == "" {
 		return ""
 	}
@@ -165,6 +193,14 @@ func (p Plugin) GitHubURL() string {
 		sourcePath = "plugins/" + p.Name
 	}
 
-	// Construct GitHub tree URL
-	return p.MarketplaceRepo + "/tree/main/" + sourcePath
+	branch := p.MarketplaceBranch
+	if branch == "" {
+		branch = "main"
+	}
+
+	repo := strings.TrimSuffix(p.MarketplaceRepo, "/")
+	if strings.HasPrefix(repo, "https://gitlab.com/") {
+		return repo + "/-/tree/" + branch + "/" + sourcePath
+	}
+	return repo + "/tree/" + branch + "/" + sourcePath
 }
@@ -44,6 +44,44 @@ func TestFullName(t *testing.T) {
 	}
 }
 
+// TestParseFullName verifies name@marketplace splitting
+func TestParseFullName(t *testing.T) {
+	tests := []struct {
+		input           string
+		wantName        string
+		wantMarketplace string
+		wantOK          bool
+	}{
+		{"memory@claude-code-plugins", "memory", "claude-code-plugins", true},
+		{"a@b", "a", "b", true},
+		{"team@tool@mkt", "team@tool", "mkt", true},
+		{"@scope/plugin@mkt", "@scope/plugin", "mkt", true},
+		{"plugin@@mkt", "plugin@", "mkt", true},
+		{"plugin", "", "", false},
+		{"", "", "", false},
+		{"@mkt", "", "", false},
+		{"plugin@", "", "", false},
+		{"plugin@mkt@", "", "", false},
+		{"@", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			name, marketplace, ok := ParseFullName(tt.input)
+			if name != tt.wantName || marketplace != tt.wantMarketplace || ok != tt.wantOK {
+				t.Errorf("ParseFullName(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.input, name, marketplace, ok, tt.wantName, tt.wantMarketplace, tt.wantOK)
+			}
+		})
+	}
+
+	// Round trip with FullName
+	p := Plugin{Name: "team@tool", Marketplace: "mkt"}
+	if name, marketplace, ok := ParseFullName(p.FullName()); !ok || name != p.Name || marketplace != p.Marketplace {
+		t.Errorf("ParseFullName(FullName()) = (%q, %q, %v)", name, marketplace, ok)
+	}
+}
+
 // TestInstallCommand verifies install command format
 func TestInstallCommand(t *testing.T) {
 	tests := []struct {
@@ -144,6 +182,11 @@ func TestTitle(t *testing.T) {
 			plugin:      Plugin{Name: "test plugin name"},
 			expectValue: "test plugin name",
 		},
+		{
+			name:        "display name override",
+			plugin:      Plugin{Name: "docker-helper", DisplayName: "Docker Helper"},
+			expectValue: "Docker Helper",
+		},
 	}
 
 	for _, tt := range tests {
@@ -280,6 +323,15 @@ func TestGitHubURL(t *testing.T) {
 			},
 			expectValue: "github.com/owner/repo/tree/main/plugins/test",
 		},
+		{
+			name: "non-main default branch",
+			plugin: Plugin{
+				MarketplaceRepo:   "https://github.com/owner/repo",
+				MarketplaceBranch: "master",
+				Source:            "./plugins/test",
+			},
+			expectValue: "https://github.com/owner/repo/tree/master/plugins/test",
+		},
 	}
 
 	for _, tt := range tests {
@@ -292,6 +344,23 @@ func TestGitHubURL(t *testing.T) {
 	}
 }
 
+// TestSourceURL_GitLab verifies GitLab marketplaces link to GitLab's tree view
+func TestSourceURL_GitLab(t *testing.T) {
+	p := Plugin{
+		Name:            "my-plugin",
+		MarketplaceRepo: "https://gitlab.com/group/repo",
+		Source:          "./plugins/my-plugin",
+	}
+
+	want := "https://gitlab.com/group/repo/-/tree/main/plugins/my-plugin"
+	if got := p.SourceURL(); got != want {
+		t.Errorf("SourceURL() = %q, want %q", got, want)
+	}
+	if got := p.GitHubURL(); got != want {
+		t.Errorf("GitHubURL() = %q, want %q", got, want)
+	}
+}
+
 // TestPluginStruct verifies the Plugin struct can be created and fields accessed
 func TestPluginStruct(t *testing.T) {
 	t.Run("create plugin with all fields", func(t *testing.T) {
@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// UpdateStatus describes how an installed version compares to the latest
+// marketplace version
+type UpdateStatus int
+
+const (
+	UpdateUnknown   UpdateStatus = iota // Latest version unknown
+	UpToDate                            // Installed version is current (or newer)
+	UpdateAvailable                     // Marketplace has a newer version
+)
+
+// String returns the status as shown in update output
+func (s UpdateStatus) String() string {
+	switch s {
+	case UpToDate:
+		return "up to date"
+	case UpdateAvailable:
+		return "update available"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseVersion parses a semantic version, accepting a leading "v"
+func ParseVersion(v string) (*semver.Version, error) {
+	return semver.NewVersion(strings.TrimPrefix(strings.TrimSpace(v), "v"))
+}
+
+// CheckUpdate compares an installed version with the latest marketplace
+// version. Semver versions only report an update when latest is strictly
+// newer, so downgrades are never offered; otherwise any difference counts.
+// A missing installed version always needs an update.
+func CheckUpdate(installed, latest string) UpdateStatus {
+	if latest == "" {
+		return UpdateUnknown
+	}
+	if installed == "" {
+		return UpdateAvailable
+	}
+
+	ver1, err1 := ParseVersion(installed)
+	ver2, err2 := ParseVersion(latest)
+	if err1 != nil || err2 != nil {
+		if strings.TrimPrefix(installed, "v") != strings.TrimPrefix(latest, "v") {
+			return UpdateAvailable
+		}
+		return UpToDate
+	}
+
+	if ver2.GreaterThan(ver1) {
+		return UpdateAvailable
+	}
+	return UpToDate
+}
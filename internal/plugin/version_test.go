@@ -0,0 +1,55 @@
+package plugin
+
+import "testing"
+
+func TestCheckUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed string
+		latest    string
+		want      UpdateStatus
+	}{
+		{"patch update", "1.0.0", "1.0.1", UpdateAvailable},
+		{"minor update with v prefix", "v1.0.0", "1.1.0", UpdateAvailable},
+		{"same version", "1.2.3", "1.2.3", UpToDate},
+		{"same version, v prefix differs", "v1.2.3", "1.2.3", UpToDate},
+		{"downgrade suppressed", "2.0.0", "1.9.9", UpToDate},
+		{"prerelease to release", "1.0.0-beta", "1.0.0", UpdateAvailable},
+		{"malformed versions differ", "nightly-42", "nightly-43", UpdateAvailable},
+		{"malformed versions equal", "latest", "latest", UpToDate},
+		{"malformed installed, semver latest", "abc", "1.0.0", UpdateAvailable},
+		{"not installed", "", "1.0.0", UpdateAvailable},
+		{"latest unknown", "1.0.0", "", UpdateUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckUpdate(tt.installed, tt.latest); got != tt.want {
+				t.Errorf("CheckUpdate(%q, %q) = %s, want %s", tt.installed, tt.latest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for _, v := range []string{"1.2.3", "v1.2.3", " 1.2.3 "} {
+		parsed, err := ParseVersion(v)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) failed: %v", v, err)
+			continue
+		}
+		if parsed.String() != "1.2.3" {
+			t.Errorf("ParseVersion(%q) = %s, want 1.2.3", v, parsed)
+		}
+	}
+
+	if _, err := ParseVersion("not-a-version"); err == nil {
+		t.Error("ParseVersion should reject malformed versions")
+	}
+}
+
+func TestUpdateStatusString(t *testing.T) {
+	if UpToDate.String() != "up to date" || UpdateAvailable.String() != "update available" || UpdateUnknown.String() != "unknown" {
+		t.Error("unexpected UpdateStatus strings")
+	}
+}
@@ -0,0 +1,86 @@
+package search
+
+import (
+	"unicode"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// MatchIndexesForName returns the rune positions in name that match query,
+// for highlighting the rendered plugin name. Scoring matches against the
+// combined searchable text, so these indexes are computed against the name
+// alone: a plugin that matched only on its description returns nil.
+//
+// A contiguous (case-insensitive) substring match is preferred; otherwise the
+// fuzzy subsequence match is used. Indexes are rune offsets, not byte offsets.
+func MatchIndexesForName(query, name string) []int {
+	if query == "" || name == "" {
+		return nil
+	}
+
+	nameRunes := []rune(name)
+	queryRunes := []rune(query)
+
+	if start := indexFoldRunes(nameRunes, queryRunes); start >= 0 {
+		indexes := make([]int, len(queryRunes))
+		for i := range indexes {
+			indexes[i] = start + i
+		}
+		return indexes
+	}
+
+	matches := fuzzy.Find(query, []string{name})
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// fuzzy reports byte offsets; convert them to rune offsets
+	byteToRune := make(map[int]int, len(nameRunes))
+	runeIdx := 0
+	for byteIdx := range name {
+		byteToRune[byteIdx] = runeIdx
+		runeIdx++
+	}
+
+	indexes := make([]int, 0, len(matches[0].MatchedIndexes))
+	for _, b := range matches[0].MatchedIndexes {
+		if r, ok := byteToRune[b]; ok {
+			indexes = append(indexes, r)
+		}
+	}
+	return indexes
+}
+
+// indexFoldRunes returns the rune index of the first case-insensitive
+// occurrence of needle in hay, or -1
+func indexFoldRunes(hay, needle []rune) int {
+	if len(needle) == 0 || len(needle) > len(hay) {
+		return -1
+	}
+
+outer:
+	for i := 0; i+len(needle) <= len(hay); i++ {
+		for j, r := range needle {
+			if unicode.ToLower(hay[i+j]) != unicode.ToLower(r) {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// runeIndexesInRange returns the rune positions of name whose bytes fall in
+// [start, end). Byte offsets past the end of name are ignored, so a match
+// that continues into the description only highlights the name's part.
+func runeIndexesInRange(name string, start, end int) []int {
+	var indexes []int
+	runeIdx := 0
+	for byteIdx := range name {
+		if byteIdx >= start && byteIdx < end {
+			indexes = append(indexes, runeIdx)
+		}
+		runeIdx++
+	}
+	return indexes
+}
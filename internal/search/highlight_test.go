@@ -0,0 +1,87 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+func TestMatchIndexesForName(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		plugin   string
+		expected []int
+	}{
+		{"fuzzy subsequence", "tst", "test", []int{0, 2, 3}},
+		{"contiguous substring", "ting", "testing-tool", []int{3, 4, 5, 6}},
+		{"case insensitive", "DOCK", "docker-helper", []int{0, 1, 2, 3}},
+		{"unicode prefix shifts rune indexes", "tool", "café-tool", []int{5, 6, 7, 8}},
+		{"fuzzy across multibyte runes", "cft", "café-tool", []int{0, 2, 5}},
+		{"no match in name", "database", "test-plugin", nil},
+		{"empty query", "", "test", nil},
+		{"empty name", "test", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchIndexesForName(tt.query, tt.plugin)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("MatchIndexesForName(%q, %q) = %v, want %v", tt.query, tt.plugin, got, tt.expected)
+			}
+
+			runes := []rune(tt.plugin)
+			for _, idx := range got {
+				if idx < 0 || idx >= len(runes) {
+					t.Errorf("index %d out of range for %q", idx, tt.plugin)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchIndexesForName_DescriptionOnlyMatch(t *testing.T) {
+	plugins := createTestPlugins()
+
+	// "framework" only appears in testing-tool's description, so the search
+	// finds it but the name must get no highlight
+	results := Search("framework", plugins)
+	if len(results) == 0 || results[0].Plugin.Name != "testing-tool" {
+		t.Fatalf("expected testing-tool to match via description, got %v", results)
+	}
+
+	if idx := MatchIndexesForName("framework", results[0].Plugin.Name); idx != nil {
+		t.Errorf("expected no name indexes for description-only match, got %v", idx)
+	}
+}
+
+func TestSearch_MatchedIndices(t *testing.T) {
+	plugins := []plugin.Plugin{{Name: "test", Description: "Example"}}
+
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{"tst", []int{0, 2, 3}},
+		{"est", []int{1, 2, 3}},
+		{"/es/", []int{1, 2}},
+		{"/t exa/", []int{3}},
+		{"st example", []int{2, 3}},
+		{"t es", []int{0, 1, 2}},
+		{"example", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := Search(tt.query, plugins)
+			if len(results) != 1 {
+				t.Fatalf("Search(%q) returned %d results, want 1", tt.query, len(results))
+			}
+			if got := results[0].MatchedIndices; !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Search(%q) MatchedIndices = %v, want %v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,116 +1,173 @@
 package search
 
 import (
+	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/itsdevcoffee/plum/internal/plugin"
-	"github.com/sahilm/fuzzy"
 )
 
 // RankedPlugin wraps a plugin with its search score
 type RankedPlugin struct {
 	Plugin plugin.Plugin
 	Score  int
+	// MatchedIndices are the rune positions in Plugin.Name that matched the
+	// query, for highlighting. Nil when the name didn't match or the query
+	// was empty.
+	MatchedIndices []int
+}
+
+// Options tunes how search results are ranked
+type Options struct {
+	// PreferInstalled lists installed plugins first for an empty query and
+	// gives them a small score boost and tie-break otherwise
+	PreferInstalled bool
+}
+
+// DefaultOptions returns the options used by Search
+func DefaultOptions() Options {
+	return Options{PreferInstalled: true}
+}
+
+// ScoreWeights are the points each kind of match adds to a plugin's score.
+// Fuzzy name and description matches are scaled from the matcher's own score
+// and aren't weighted.
+type ScoreWeights struct {
+	ExactName      int // name equals the query
+	PartialName    int // name contains the query
+	Keyword        int // per keyword equal to the query
+	PartialKeyword int // per keyword containing the query
+	Description    int // description contains the query
+	Category       int // category contains the query
+	Author         int // author name or company contains the query
+	Installed      int // boost for matching installed plugins
+}
+
+// DefaultScoreWeights returns the weights used by Search
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{
+		ExactName:      100,
+		PartialName:    70,
+		Keyword:        30,
+		PartialKeyword: 20,
+		Description:    25,
+		Category:       15,
+		Author:         10,
+		Installed:      5,
+	}
 }
 
 // Search performs fuzzy search on plugins and returns ranked results.
 // Empty query returns all plugins sorted by installed status then name.
-// Scoring algorithm: exact match (100), partial (70), fuzzy (0-50),
-// keywords (30), category (15), description (25), installed boost (+5).
+// Whitespace separates terms that must all match; their scores are summed.
+// Terms prefixed with "-" exclude plugins containing them (e.g. "docker -compose");
+// a query of only exclusions returns all remaining plugins.
+// A query wrapped in slashes (e.g. "/^git-.*hooks/") is a regular expression;
+// see RegexSearch.
+// Scoring uses DefaultScoreWeights: exact match (100), partial (70), fuzzy (0-50),
+// keywords (30), category (15), author (10), description (25), installed boost (+5).
 func Search(query string, plugins []plugin.Plugin) []RankedPlugin {
-	if query == "" {
-		// Return all plugins sorted by name when no query
-		results := make([]RankedPlugin, len(plugins))
-		for i, p := range plugins {
-			results[i] = RankedPlugin{Plugin: p, Score: 0}
-		}
-		sort.Slice(results, func(i, j int) bool {
-			// Installed plugins first, then by name
-			if results[i].Plugin.Installed != results[j].Plugin.Installed {
-				return results[i].Plugin.Installed
-			}
-			return results[i].Plugin.Name < results[j].Plugin.Name
-		})
-		return results
+	return SearchWithWeights(query, plugins, DefaultScoreWeights())
+}
+
+// SearchWithWeights is Search with custom score weights
+func SearchWithWeights(query string, plugins []plugin.Plugin, weights ScoreWeights) []RankedPlugin {
+	return search(query, plugins, DefaultOptions(), weights)
+}
+
+// SearchWithOptions is Search with configurable ranking. With PreferInstalled
+// off, installed plugins get no boost and ties are broken by name alone.
+func SearchWithOptions(query string, plugins []plugin.Plugin, opts Options) []RankedPlugin {
+	return search(query, plugins, opts, DefaultScoreWeights())
+}
+
+func search(query string, plugins []plugin.Plugin, opts Options, weights ScoreWeights) []RankedPlugin {
+	return newIndex(plugins).search(query, opts, weights)
+}
+
+// RegexQuery reports whether query is a /pattern/ regular expression and
+// returns the pattern between the slashes
+func RegexQuery(query string) (string, bool) {
+	query = strings.TrimSpace(query)
+	if len(query) < 3 || !strings.HasPrefix(query, "/") || !strings.HasSuffix(query, "/") {
+		return "", false
 	}
+	return query[1 : len(query)-1], true
+}
 
-	query = strings.ToLower(query)
-	var results []RankedPlugin
+// CompileRegex compiles a search pattern. Matching ignores case, like the
+// fuzzy search.
+func CompileRegex(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// RegexSearch returns plugins whose name and description match re, bypassing
+// fuzzy scoring. Earlier matches rank higher (name before description), then
+// plugins are ordered by name. Scores only reflect that order.
+func RegexSearch(re *regexp.Regexp, plugins []plugin.Plugin) []RankedPlugin {
+	type match struct {
+		plugin plugin.Plugin
+		pos    int
+		end    int
+	}
 
+	var matches []match
 	for _, p := range plugins {
-		score := scorePlugin(query, p)
-		if score > 0 {
-			results = append(results, RankedPlugin{Plugin: p, Score: score})
+		if loc := re.FindStringIndex(p.Name + " " + p.Description); loc != nil {
+			matches = append(matches, match{plugin: p, pos: loc[0], end: loc[1]})
 		}
 	}
 
-	// Sort by score descending, then by installed status, then by name
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].Score != results[j].Score {
-			return results[i].Score > results[j].Score
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].pos != matches[j].pos {
+			return matches[i].pos < matches[j].pos
 		}
-		if results[i].Plugin.Installed != results[j].Plugin.Installed {
-			return results[i].Plugin.Installed
-		}
-		return results[i].Plugin.Name < results[j].Plugin.Name
+		return matches[i].plugin.Name < matches[j].plugin.Name
 	})
 
-	return results
-}
-
-// scorePlugin calculates a relevance score for a plugin given a query
-func scorePlugin(query string, p plugin.Plugin) int {
-	score := 0
-	lowerName := strings.ToLower(p.Name)
-	lowerDesc := strings.ToLower(p.Description)
-	lowerCategory := strings.ToLower(p.Category)
-
-	// Exact name match: +100 points
-	if lowerName == query {
-		score += 100
-	} else if strings.Contains(lowerName, query) {
-		// Partial name match: +70 points
-		score += 70
-	} else {
-		// Fuzzy name match
-		nameMatches := fuzzy.Find(query, []string{lowerName})
-		if len(nameMatches) > 0 {
-			// Scale fuzzy score (0-100) to 0-50 points
-			score += nameMatches[0].Score / 2
+	results := make([]RankedPlugin, len(matches))
+	for i, m := range matches {
+		results[i] = RankedPlugin{
+			Plugin:         m.plugin,
+			Score:          len(matches) - i,
+			MatchedIndices: runeIndexesInRange(m.plugin.Name, m.pos, m.end),
 		}
 	}
+	return results
+}
 
-	// Keyword exact match: +30 per keyword
-	for _, kw := range p.Keywords {
-		lowerKw := strings.ToLower(kw)
-		if lowerKw == query {
-			score += 30
-		} else if strings.Contains(lowerKw, query) {
-			score += 20
+// ParseQuery splits a query into the positive search text and lowercased
+// exclusion terms (tokens starting with "-"). Without exclusions the query
+// is returned unchanged.
+func ParseQuery(query string) (positive string, excluded []string) {
+	var terms []string
+	for _, token := range strings.Fields(query) {
+		if len(token) > 1 && strings.HasPrefix(token, "-") {
+			excluded = append(excluded, strings.ToLower(token[1:]))
+			continue
 		}
+		terms = append(terms, token)
 	}
 
-	// Category match: +15 points
-	if strings.Contains(lowerCategory, query) {
-		score += 15
+	if len(excluded) == 0 {
+		return query, nil
 	}
+	return strings.Join(terms, " "), excluded
+}
 
-	// Description fuzzy match: +20 * match score
-	if strings.Contains(lowerDesc, query) {
-		score += 25
-	} else {
-		descMatches := fuzzy.Find(query, []string{lowerDesc})
-		if len(descMatches) > 0 {
-			score += descMatches[0].Score / 5
-		}
-	}
+// scorePlugin calculates a relevance score for a plugin given a query,
+// including the installed boost, using the default weights
+func scorePlugin(query string, p plugin.Plugin) int {
+	weights := DefaultScoreWeights()
+	return boostInstalled(relevanceScore(query, p, weights), p, weights)
+}
 
-	// Boost installed plugins slightly
+// boostInstalled raises a matching installed plugin's score slightly
+func boostInstalled(score int, p plugin.Plugin, weights ScoreWeights) int {
 	if p.Installed && score > 0 {
-		score += 5
+		score += weights.Installed
 	}
-
 	return score
 }
 
@@ -122,7 +179,13 @@ type PluginSearchSource struct {
 // String returns the searchable string for item at index i
 func (s PluginSearchSource) String(i int) string {
 	p := s.Plugins[i]
-	return p.Name + " " + p.Description + " " + strings.Join(p.Keywords, " ")
+	text := p.Name + " " + p.Description + " " + strings.Join(p.Keywords, " ")
+	for _, author := range []string{p.Author.Name, p.Author.Company} {
+		if author != "" {
+			text += " " + author
+		}
+	}
+	return text
 }
 
 // Len returns the number of items
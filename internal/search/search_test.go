@@ -1,6 +1,7 @@
 package search
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/itsdevcoffee/plum/internal/plugin"
@@ -80,8 +81,9 @@ func TestSearch(t *testing.T) {
 		{
 			name:           "multi-word query",
 			query:          "test tool",
-			expectCount:    0, // No exact match for "test tool"
-			expectMinScore: 0,
+			expectCount:    1, // Every term must match: "test" and "tool" in the name
+			expectFirst:    "testing-tool",
+			expectMinScore: 140,
 		},
 		{
 			name:           "special characters",
@@ -216,6 +218,206 @@ func TestSearchSorting(t *testing.T) {
 	})
 }
 
+// TestSearchWithOptions_PreferInstalled verifies the installed-first toggle
+func TestSearchWithOptions_PreferInstalled(t *testing.T) {
+	plugins := []plugin.Plugin{
+		{Name: "zeta-docker", Installed: true},
+		{Name: "beta-docker", Installed: false},
+		{Name: "alpha-docker", Installed: false},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+		want  []string
+	}{
+		{"empty query, preferred", "", Options{PreferInstalled: true}, []string{"zeta-docker", "alpha-docker", "beta-docker"}},
+		{"empty query, not preferred", "", Options{PreferInstalled: false}, []string{"alpha-docker", "beta-docker", "zeta-docker"}},
+		{"query, preferred", "docker", Options{PreferInstalled: true}, []string{"zeta-docker", "alpha-docker", "beta-docker"}},
+		{"query, not preferred", "docker", Options{PreferInstalled: false}, []string{"alpha-docker", "beta-docker", "zeta-docker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := SearchWithOptions(tt.query, plugins, tt.opts)
+			if len(results) != len(tt.want) {
+				t.Fatalf("Expected %d results, got %d", len(tt.want), len(results))
+			}
+			for i, r := range results {
+				if r.Plugin.Name != tt.want[i] {
+					t.Errorf("results[%d] = %s, want %s", i, r.Plugin.Name, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Without the preference, installed plugins get no score boost
+	boosted := SearchWithOptions("docker", plugins, DefaultOptions())
+	plain := SearchWithOptions("docker", plugins, Options{})
+	if boosted[0].Score != plain[2].Score+5 {
+		t.Errorf("installed boost = %d, want +5 over %d", boosted[0].Score, plain[2].Score)
+	}
+}
+
+// TestRegexSearch verifies /pattern/ queries bypass fuzzy scoring
+func TestRegexSearch(t *testing.T) {
+	plugins := []plugin.Plugin{
+		{Name: "docker-compose", Description: "Compose helpers"},
+		{Name: "git-hooks", Description: "Manage git hooks"},
+		{Name: "commit-helper", Description: "Write git commit messages"},
+		{Name: "notes", Description: "Take notes"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"anchored name", "/^git-/", "git-hooks"},
+		{"ranked by match position", "/git/", "git-hooks,commit-helper"},
+		{"case-insensitive", "/COMPOSE/", "docker-compose"},
+		{"alternation", "/^(notes|docker)/", "docker-compose,notes"},
+		{"no matches", "/kubernetes/", ""},
+		{"invalid regex", "/git(/", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Search(tt.query, plugins)
+			if got := rankedNames(results); got != tt.want {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score >= results[i-1].Score {
+					t.Errorf("scores should decrease with rank, got %d then %d", results[i-1].Score, results[i].Score)
+				}
+			}
+		})
+	}
+}
+
+func TestRegexQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		pattern string
+		ok      bool
+	}{
+		{"/^git/", "^git", true},
+		{"  /a b/  ", "a b", true},
+		{"//", "", false},
+		{"/git", "", false},
+		{"git/", "", false},
+		{"docker", "", false},
+	}
+	for _, tt := range tests {
+		pattern, ok := RegexQuery(tt.query)
+		if pattern != tt.pattern || ok != tt.ok {
+			t.Errorf("RegexQuery(%q) = %q, %v, want %q, %v", tt.query, pattern, ok, tt.pattern, tt.ok)
+		}
+	}
+
+	if _, err := CompileRegex("git("); err == nil {
+		t.Error("CompileRegex should reject an invalid pattern")
+	}
+}
+
+// TestSearch_MultiTerm verifies whitespace-separated terms are ANDed
+func TestSearch_MultiTerm(t *testing.T) {
+	plugins := createTestPlugins()
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"name and description", "testing framework", "testing-tool"},
+		{"name and keyword", "docker container", "docker-plugin"},
+		{"description and category", "workflow devops", "automation-plugin"},
+		{"term order doesn't matter", "tool test", "testing-tool"},
+		{"unmatched term excludes", "docker kubernetes", ""},
+		{"extra whitespace", "  parse   data  ", "data-parser"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Search(tt.query, plugins)
+			if got := rankedNames(results); got != tt.want {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	// Term scores are summed
+	both := Search("testing framework", plugins)
+	single := Search("testing", plugins)
+	var singleScore int
+	for _, r := range single {
+		if r.Plugin.Name == "testing-tool" {
+			singleScore = r.Score
+		}
+	}
+	if len(both) != 1 || both[0].Score <= singleScore {
+		t.Errorf("two-term score should exceed the single-term score %d, got %v", singleScore, both)
+	}
+}
+
+// TestSearch_MatchesAuthor verifies author names and companies are searchable
+func TestSearch_MatchesAuthor(t *testing.T) {
+	plugins := []plugin.Plugin{
+		{Name: "lint", Author: plugin.Author{Name: "Jo Dev", Company: "Acme Corp"}},
+		{Name: "format", Author: plugin.Author{Name: "Acme Tools"}},
+		{Name: "deploy", Author: plugin.Author{Name: "Other"}},
+	}
+
+	results := Search("acme", plugins)
+	if got := rankedNames(results); got != "format,lint" {
+		t.Errorf("Search(acme) = %q, want format,lint", got)
+	}
+	for _, r := range results {
+		if r.Score != DefaultScoreWeights().Author {
+			t.Errorf("%s score = %d, want the author weight %d", r.Plugin.Name, r.Score, DefaultScoreWeights().Author)
+		}
+	}
+}
+
+// TestSearchWithWeights verifies custom weights change the ranking
+func TestSearchWithWeights(t *testing.T) {
+	plugins := []plugin.Plugin{
+		{Name: "docker-tools", Description: "Container helpers"},
+		{Name: "compose", Keywords: []string{"docker"}, Description: "Multi-container apps"},
+	}
+
+	// Defaults: partial name (70) beats keyword (30)
+	results := Search("docker", plugins)
+	if len(results) != 2 || results[0].Plugin.Name != "docker-tools" {
+		t.Fatalf("default ranking = %v, want docker-tools first", rankedNames(results))
+	}
+	if got := SearchWithWeights("docker", plugins, DefaultScoreWeights()); rankedNames(got) != rankedNames(results) {
+		t.Errorf("SearchWithWeights(defaults) = %v, want %v", rankedNames(got), rankedNames(results))
+	}
+
+	// Keywords weighted higher than name matches
+	weights := DefaultScoreWeights()
+	weights.Keyword = 150
+	results = SearchWithWeights("docker", plugins, weights)
+	if len(results) != 2 || results[0].Plugin.Name != "compose" {
+		t.Errorf("keyword-heavy ranking = %v, want compose first", rankedNames(results))
+	}
+	if results[0].Score < 150 {
+		t.Errorf("compose score = %d, want at least the keyword weight 150", results[0].Score)
+	}
+}
+
+// rankedNames joins result names for comparison and error messages
+func rankedNames(results []RankedPlugin) string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Plugin.Name
+	}
+	return strings.Join(names, ",")
+}
+
 // TestScorePlugin verifies the scoring algorithm
 func TestScorePlugin(t *testing.T) {
 	tests := []struct {
@@ -341,6 +543,15 @@ func TestPluginSearchSource(t *testing.T) {
 		}
 	})
 
+	t.Run("String includes author name and company", func(t *testing.T) {
+		source := PluginSearchSource{Plugins: []plugin.Plugin{
+			{Name: "lint", Author: plugin.Author{Name: "Jo Dev", Company: "Acme"}},
+		}}
+		if got := source.String(0); !contains(got, "Jo Dev") || !contains(got, "Acme") {
+			t.Errorf("String should include the author, got %q", got)
+		}
+	})
+
 	t.Run("String includes all keywords", func(t *testing.T) {
 		str := source.String(1)
 
@@ -404,6 +615,74 @@ func TestSearchEdgeCases(t *testing.T) {
 	})
 }
 
+func TestSearchExclusions(t *testing.T) {
+	plugins := []plugin.Plugin{
+		{Name: "docker-helper", Description: "Manage docker containers"},
+		{Name: "docker-compose", Description: "Compose stacks with docker"},
+		{Name: "docker-swarm", Description: "Swarm orchestration for docker", Keywords: []string{"cluster"}},
+		{Name: "kube-tools", Description: "Kubernetes helpers", Category: "DevOps"},
+	}
+
+	names := func(results []RankedPlugin) []string {
+		out := make([]string, len(results))
+		for i, r := range results {
+			out[i] = r.Plugin.Name
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"single exclusion", "docker -compose", []string{"docker-helper", "docker-swarm"}},
+		{"multiple exclusions", "docker -compose -CLUSTER", []string{"docker-helper"}},
+		{"exclusion matches category", "-devops", []string{"docker-compose", "docker-helper", "docker-swarm"}},
+		{"only exclusions returns the rest", "-docker", []string{"kube-tools"}},
+		{"exclusion before positive term", "-swarm docker", []string{"docker-helper", "docker-compose"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(Search(tt.query, plugins))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			want := make(map[string]bool)
+			for _, n := range tt.want {
+				want[n] = true
+			}
+			for _, n := range got {
+				if !want[n] {
+					t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	positive, excluded := ParseQuery("code review")
+	if positive != "code review" || excluded != nil {
+		t.Errorf("query without exclusions should be unchanged, got %q %v", positive, excluded)
+	}
+
+	positive, excluded = ParseQuery("  docker  -Compose -k8s ")
+	if positive != "docker" {
+		t.Errorf("expected positive %q, got %q", "docker", positive)
+	}
+	if len(excluded) != 2 || excluded[0] != "compose" || excluded[1] != "k8s" {
+		t.Errorf("expected lowercased exclusions [compose k8s], got %v", excluded)
+	}
+
+	positive, excluded = ParseQuery("a - b")
+	if positive != "a - b" || excluded != nil {
+		t.Errorf("a lone dash should not be an exclusion, got %q %v", positive, excluded)
+	}
+}
+
 // Helper functions
 
 func createTestPlugins() []plugin.Plugin {
@@ -0,0 +1,293 @@
+package search
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/sahilm/fuzzy"
+)
+
+// Searcher runs repeated queries over one plugin set. The lowercased
+// searchable text is built once and reused until the plugins change.
+type Searcher struct {
+	index *index
+	key   uint64 // hash of the indexed plugins' searchable fields
+	// builds counts index rebuilds, so tests can check reuse
+	builds int
+}
+
+// NewSearcher returns a Searcher indexing plugins
+func NewSearcher(plugins []plugin.Plugin) *Searcher {
+	s := &Searcher{}
+	s.Update(plugins)
+	return s
+}
+
+// Update points the searcher at plugins. The index is only rebuilt when the
+// plugins' searchable content differs from what is already indexed; plugins
+// must not be modified in place after being passed in.
+func (s *Searcher) Update(plugins []plugin.Plugin) {
+	if s.index != nil && sameSlice(s.index.plugins, plugins) {
+		return
+	}
+
+	key := pluginsKey(plugins)
+	if s.index != nil && key == s.key {
+		// Same searchable content in a new slice; results should carry
+		// the new plugin values
+		s.index.plugins = plugins
+		return
+	}
+
+	s.index = newIndex(plugins)
+	s.key = key
+	s.builds++
+}
+
+// Query is Search over the indexed plugins
+func (s *Searcher) Query(query string) []RankedPlugin {
+	return s.QueryWithOptions(query, DefaultOptions())
+}
+
+// QueryWithOptions is SearchWithOptions over the indexed plugins
+func (s *Searcher) QueryWithOptions(query string, opts Options) []RankedPlugin {
+	return s.index.search(query, opts, DefaultScoreWeights())
+}
+
+// sameSlice reports whether a and b are the same slice of the same array
+func sameSlice(a, b []plugin.Plugin) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return len(a) == 0 || &a[0] == &b[0]
+}
+
+// pluginsKey hashes the fields an index is built from
+func pluginsKey(plugins []plugin.Plugin) uint64 {
+	h := fnv.New64a()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	for _, p := range plugins {
+		write(p.Name)
+		write(p.Description)
+		write(p.Category)
+		write(p.Author.Name)
+		write(p.Author.Company)
+		for _, list := range [][]string{p.Keywords, p.Tags} {
+			for _, v := range list {
+				write(v)
+			}
+			write("")
+		}
+	}
+	return h.Sum64()
+}
+
+// index pairs plugins with their precomputed searchable text
+type index struct {
+	plugins []plugin.Plugin
+	entries []indexEntry
+}
+
+// indexEntry is a plugin's lowercased searchable fields
+type indexEntry struct {
+	name          string
+	description   string
+	category      string
+	keywords      []string
+	authorName    string
+	authorCompany string
+	// text is everything exclusion terms are matched against
+	text string
+}
+
+func newIndex(plugins []plugin.Plugin) *index {
+	entries := make([]indexEntry, len(plugins))
+	for i, p := range plugins {
+		entries[i] = newIndexEntry(p)
+	}
+	return &index{plugins: plugins, entries: entries}
+}
+
+func newIndexEntry(p plugin.Plugin) indexEntry {
+	keywords := make([]string, len(p.Keywords))
+	for i, kw := range p.Keywords {
+		keywords[i] = strings.ToLower(kw)
+	}
+	return indexEntry{
+		name:          strings.ToLower(p.Name),
+		description:   strings.ToLower(p.Description),
+		category:      strings.ToLower(p.Category),
+		keywords:      keywords,
+		authorName:    strings.ToLower(p.Author.Name),
+		authorCompany: strings.ToLower(p.Author.Company),
+		text: strings.ToLower(strings.Join([]string{
+			p.Name, p.Description, p.Category,
+			strings.Join(p.Keywords, " "), strings.Join(p.Tags, " "),
+		}, " ")),
+	}
+}
+
+// search implements Search over the index
+func (ix *index) search(query string, opts Options, weights ScoreWeights) []RankedPlugin {
+	if pattern, ok := RegexQuery(query); ok {
+		re, err := CompileRegex(pattern)
+		if err != nil {
+			return nil
+		}
+		return RegexSearch(re, ix.plugins)
+	}
+
+	query, excluded := ParseQuery(query)
+
+	if strings.TrimSpace(query) == "" {
+		// Return all plugins sorted by name when no query
+		results := make([]RankedPlugin, 0, len(ix.plugins))
+		for i, p := range ix.plugins {
+			if !ix.entries[i].excluded(excluded) {
+				results = append(results, RankedPlugin{Plugin: p, Score: 0})
+			}
+		}
+		sort.Slice(results, func(i, j int) bool {
+			// Installed plugins first (if preferred), then by name
+			if opts.PreferInstalled && results[i].Plugin.Installed != results[j].Plugin.Installed {
+				return results[i].Plugin.Installed
+			}
+			return results[i].Plugin.Name < results[j].Plugin.Name
+		})
+		return results
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+	var results []RankedPlugin
+
+	for i, p := range ix.plugins {
+		entry := ix.entries[i]
+		if entry.excluded(excluded) {
+			continue
+		}
+		score := entry.termsScore(terms, weights)
+		if opts.PreferInstalled {
+			score = boostInstalled(score, p, weights)
+		}
+		if score > 0 {
+			results = append(results, RankedPlugin{
+				Plugin:         p,
+				Score:          score,
+				MatchedIndices: termsMatchIndexes(terms, p.Name),
+			})
+		}
+	}
+
+	// Sort by score descending, then by installed status (if preferred), then by name
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if opts.PreferInstalled && results[i].Plugin.Installed != results[j].Plugin.Installed {
+			return results[i].Plugin.Installed
+		}
+		return results[i].Plugin.Name < results[j].Plugin.Name
+	})
+
+	return results
+}
+
+// excluded reports whether the entry contains any exclusion term
+func (e indexEntry) excluded(terms []string) bool {
+	for _, term := range terms {
+		if strings.Contains(e.text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// termsScore sums each term's relevance score. Every term must match, so a
+// term that scores 0 excludes the plugin.
+func (e indexEntry) termsScore(terms []string, weights ScoreWeights) int {
+	total := 0
+	for _, term := range terms {
+		score := e.score(term, weights)
+		if score == 0 {
+			return 0
+		}
+		total += score
+	}
+	return total
+}
+
+// termsMatchIndexes merges the name highlight positions of every term
+func termsMatchIndexes(terms []string, name string) []int {
+	if len(terms) == 1 {
+		return MatchIndexesForName(terms[0], name)
+	}
+
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, term := range terms {
+		for _, i := range MatchIndexesForName(term, name) {
+			if !seen[i] {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// relevanceScore scores how well a plugin matches a query
+func relevanceScore(query string, p plugin.Plugin, weights ScoreWeights) int {
+	return newIndexEntry(p).score(query, weights)
+}
+
+// score scores how well the entry matches a lowercased query term
+func (e indexEntry) score(query string, weights ScoreWeights) int {
+	score := 0
+
+	if e.name == query {
+		score += weights.ExactName
+	} else if strings.Contains(e.name, query) {
+		score += weights.PartialName
+	} else {
+		// Fuzzy name match
+		nameMatches := fuzzy.Find(query, []string{e.name})
+		if len(nameMatches) > 0 {
+			// Scale fuzzy score (0-100) to 0-50 points
+			score += nameMatches[0].Score / 2
+		}
+	}
+
+	for _, kw := range e.keywords {
+		if kw == query {
+			score += weights.Keyword
+		} else if strings.Contains(kw, query) {
+			score += weights.PartialKeyword
+		}
+	}
+
+	if strings.Contains(e.category, query) {
+		score += weights.Category
+	}
+
+	if strings.Contains(e.authorName, query) || strings.Contains(e.authorCompany, query) {
+		score += weights.Author
+	}
+
+	// Description: substring match, else scaled fuzzy match
+	if strings.Contains(e.description, query) {
+		score += weights.Description
+	} else {
+		descMatches := fuzzy.Find(query, []string{e.description})
+		if len(descMatches) > 0 {
+			score += descMatches[0].Score / 5
+		}
+	}
+
+	return score
+}
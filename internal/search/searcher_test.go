@@ -0,0 +1,43 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+func TestSearcher_MatchesSearch(t *testing.T) {
+	plugins := createTestPlugins()
+	s := NewSearcher(plugins)
+
+	queries := []string{"", "test", "testing tool", "docker -deploy", "/^test/", "/[/", "zzz"}
+	for _, q := range queries {
+		want := rankedNames(Search(q, plugins))
+		if got := rankedNames(s.Query(q)); got != want {
+			t.Errorf("Query(%q) = %s, want %s", q, got, want)
+		}
+	}
+}
+
+func TestSearcher_ReusesIndex(t *testing.T) {
+	plugins := createTestPlugins()
+	s := NewSearcher(plugins)
+
+	s.Update(plugins)
+	copied := append([]plugin.Plugin(nil), plugins...)
+	copied[0].Installed = !copied[0].Installed
+	s.Update(copied)
+	if s.builds != 1 {
+		t.Fatalf("builds = %d after updating with the same content, want 1", s.builds)
+	}
+	if got := s.Query(copied[0].Name); len(got) == 0 || got[0].Plugin.Installed != copied[0].Installed {
+		t.Error("results should carry the latest plugin values")
+	}
+
+	changed := append([]plugin.Plugin(nil), plugins...)
+	changed[0].Description = "something else entirely"
+	s.Update(changed)
+	if s.builds != 2 {
+		t.Errorf("builds = %d after a content change, want 2", s.builds)
+	}
+}
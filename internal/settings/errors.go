@@ -9,4 +9,12 @@ var (
 
 	// ErrManagedReadOnly is returned when attempting to write to managed scope
 	ErrManagedReadOnly = errors.New("managed scope is read-only")
+
+	// ErrCaseVariantKey is returned when a plugin key differs only by case
+	// from a key already present in the same scope
+	ErrCaseVariantKey = errors.New("plugin key differs only by case from an existing entry")
+
+	// ErrNotWritable is returned when a settings or plugins directory can't
+	// be written to
+	ErrNotWritable = errors.New("directory is not writable")
 )
@@ -0,0 +1,56 @@
+package settings
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// normalizePluginKey returns the key used to detect case-variant duplicates.
+// The plugin segment keeps its case; the marketplace segment is compared
+// case-insensitively because marketplace names are not case-sensitive in practice.
+func normalizePluginKey(key string) string {
+	name, marketplace, ok := plugin.ParseFullName(key)
+	if !ok {
+		return key
+	}
+	return name + "@" + strings.ToLower(marketplace)
+}
+
+// FindCaseVariant returns an existing key in plugins that refers to the same
+// plugin as key but differs only by marketplace case. An exact match is not
+// considered a variant.
+func FindCaseVariant(key string, plugins map[string]bool) (string, bool) {
+	normalized := normalizePluginKey(key)
+	for existing := range plugins {
+		if existing != key && normalizePluginKey(existing) == normalized {
+			return existing, true
+		}
+	}
+	return "", false
+}
+
+// CaseVariantDuplicates returns groups of keys in plugins that differ only by
+// marketplace case. Each group and the list of groups are sorted.
+func CaseVariantDuplicates(plugins map[string]bool) [][]string {
+	groups := make(map[string][]string)
+	for key := range plugins {
+		normalized := normalizePluginKey(key)
+		groups[normalized] = append(groups[normalized], key)
+	}
+
+	var duplicates [][]string
+	for _, keys := range groups {
+		if len(keys) > 1 {
+			sort.Strings(keys)
+			duplicates = append(duplicates, keys)
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i][0] < duplicates[j][0]
+	})
+
+	return duplicates
+}
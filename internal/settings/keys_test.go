@@ -0,0 +1,72 @@
+package settings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizePluginKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"foo@market", "foo@market"},
+		{"foo@Market", "foo@market"},
+		{"Foo@MARKET", "Foo@market"},
+		{"no-at-sign", "no-at-sign"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizePluginKey(tt.key); got != tt.want {
+			t.Errorf("normalizePluginKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestFindCaseVariant(t *testing.T) {
+	plugins := map[string]bool{
+		"foo@Market": true,
+		"bar@market": false,
+	}
+
+	if existing, ok := FindCaseVariant("foo@market", plugins); !ok || existing != "foo@Market" {
+		t.Errorf("FindCaseVariant(foo@market) = %q, %v; want foo@Market, true", existing, ok)
+	}
+
+	if _, ok := FindCaseVariant("foo@Market", plugins); ok {
+		t.Error("exact match should not be reported as a case variant")
+	}
+
+	if _, ok := FindCaseVariant("Foo@market", plugins); ok {
+		t.Error("plugin segment is case-sensitive and should not match")
+	}
+
+	if _, ok := FindCaseVariant("baz@market", plugins); ok {
+		t.Error("unrelated key should not match")
+	}
+}
+
+func TestCaseVariantDuplicates(t *testing.T) {
+	plugins := map[string]bool{
+		"foo@Market": true,
+		"foo@market": false,
+		"foo@MARKET": true,
+		"bar@other":  true,
+		"baz@Other":  true,
+		"baz@other":  true,
+	}
+
+	got := CaseVariantDuplicates(plugins)
+	want := [][]string{
+		{"baz@Other", "baz@other"},
+		{"foo@MARKET", "foo@Market", "foo@market"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CaseVariantDuplicates() = %v, want %v", got, want)
+	}
+
+	if dups := CaseVariantDuplicates(map[string]bool{"a@x": true, "b@x": true}); len(dups) != 0 {
+		t.Errorf("expected no duplicates, got %v", dups)
+	}
+}
@@ -108,6 +108,9 @@ func TestManagedSettingsPath(t *testing.T) {
 }
 
 func TestUserSettingsPath(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
 	path, err := UserSettingsPath()
 	if err != nil {
 		t.Fatalf("UserSettingsPath() error = %v", err)
@@ -124,6 +127,34 @@ func TestUserSettingsPath(t *testing.T) {
 	}
 }
 
+func TestUserSettingsPath_XDGConfigHome(t *testing.T) {
+	xdgDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(xdgDir, "claude"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+
+	path, err := ScopePath(ScopeUser, "")
+	if err != nil {
+		t.Fatalf("ScopePath(user) error = %v", err)
+	}
+	if want := filepath.Join(xdgDir, "claude", "settings.json"); path != want {
+		t.Errorf("ScopePath(user) = %q, want %q", path, want)
+	}
+
+	// CLAUDE_CONFIG_DIR still wins when both are set
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	path, err = ScopePath(ScopeUser, "")
+	if err != nil {
+		t.Fatalf("ScopePath(user) error = %v", err)
+	}
+	if want := filepath.Join(configDir, "settings.json"); path != want {
+		t.Errorf("ScopePath(user) = %q, want %q", path, want)
+	}
+}
+
 func TestProjectSettingsPath(t *testing.T) {
 	// Test with explicit project path
 	projectPath := "/tmp/test-project"
@@ -3,8 +3,11 @@ package settings
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
-	"strings"
+	"slices"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
 )
 
 const (
@@ -99,7 +102,66 @@ type ExtraMarketplace struct {
 // MarketplaceSource represents the source of a marketplace
 type MarketplaceSource struct {
 	Source string `json:"source"` // e.g., "github"
-	Repo   string `json:"repo"`   // e.g., "owner/repo"
+	Repo   string `json:"repo"`   // e.g., "owner/repo"; only github and gitlab sources have one
+
+	// otherFields preserves source fields plum doesn't use, such as the path
+	// of a directory source or the url of a git source
+	otherFields map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling to preserve unknown fields
+func (m *MarketplaceSource) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if rawSource, ok := raw["source"]; ok {
+		if err := json.Unmarshal(rawSource, &m.Source); err != nil {
+			return err
+		}
+		delete(raw, "source")
+	}
+	if rawRepo, ok := raw["repo"]; ok {
+		if err := json.Unmarshal(rawRepo, &m.Repo); err != nil {
+			return err
+		}
+		delete(raw, "repo")
+	}
+
+	if len(raw) > 0 {
+		m.otherFields = raw
+	}
+	return nil
+}
+
+// MarshalJSON implements custom JSON marshaling to preserve unknown fields
+func (m MarketplaceSource) MarshalJSON() ([]byte, error) {
+	result := make(map[string]json.RawMessage, len(m.otherFields)+2)
+	for key, value := range m.otherFields {
+		result[key] = value
+	}
+
+	source, err := json.Marshal(m.Source)
+	if err != nil {
+		return nil, err
+	}
+	result["source"] = source
+	if m.Repo != "" {
+		repo, err := json.Marshal(m.Repo)
+		if err != nil {
+			return nil, err
+		}
+		result["repo"] = repo
+	}
+
+	return json.Marshal(result)
+}
+
+// sourceNeedsRepo reports whether a marketplace source type is located by
+// source.repo. Other types (directory, git, url) use their own fields.
+func sourceNeedsRepo(source string) bool {
+	return source == "github" || source == "gitlab"
 }
 
 // PluginState represents the enabled/disabled state of a plugin with its scope
@@ -166,9 +228,10 @@ func LoadSettingsFromPath(path string) (*Settings, error) {
 		settings.otherFields = make(map[string]json.RawMessage)
 	}
 
-	// Validate structure to prevent resource exhaustion
+	// Validate structure to prevent resource exhaustion and so partially
+	// valid data is never written back
 	if err := validateSettings(&settings); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 
 	return &settings, nil
@@ -184,10 +247,26 @@ func validateSettings(s *Settings) error {
 		return fmt.Errorf("too many marketplaces: %d (max %d)", len(s.ExtraKnownMarketplaces), maxMarketplaceEntries)
 	}
 
-	// Validate plugin key format (must be plugin@marketplace)
-	for key := range s.EnabledPlugins {
-		if !strings.Contains(key, "@") {
-			return fmt.Errorf("invalid plugin key format (missing @): %s", key)
+	// Validate plugin key format (must be plugin@marketplace). Keys are
+	// checked in order so the same file always reports the same key.
+	for _, key := range slices.Sorted(maps.Keys(s.EnabledPlugins)) {
+		if _, _, ok := plugin.ParseFullName(key); !ok {
+			return fmt.Errorf("invalid plugin key format (expected plugin@marketplace): %q", key)
+		}
+	}
+
+	// Validate marketplace entries have a source type, and a repo for the
+	// source types that need one
+	for _, name := range slices.Sorted(maps.Keys(s.ExtraKnownMarketplaces)) {
+		source := s.ExtraKnownMarketplaces[name].Source
+		if name == "" {
+			return fmt.Errorf("invalid extraKnownMarketplaces entry: empty marketplace name")
+		}
+		if source.Source == "" {
+			return fmt.Errorf("invalid extraKnownMarketplaces entry %q: missing source.source", name)
+		}
+		if sourceNeedsRepo(source.Source) && source.Repo == "" {
+			return fmt.Errorf("invalid extraKnownMarketplaces entry %q: missing source.repo", name)
 		}
 	}
 
@@ -226,6 +305,46 @@ func MergedPluginStates(projectPath string) ([]PluginState, error) {
 	return states, nil
 }
 
+// ScopeValue is a plugin's enabled value in one scope
+type ScopeValue struct {
+	Scope   Scope
+	Enabled bool
+}
+
+// ScopeConflict is a plugin set in more than one scope with differing values
+type ScopeConflict struct {
+	FullName string
+	Values   []ScopeValue // Every scope that sets the plugin, in precedence order (the first wins)
+}
+
+// DetectConflicts returns the plugins that are enabled in one scope and
+// disabled in another, sorted by name. Scopes that can't be read are skipped,
+// as in MergedPluginStates.
+func DetectConflicts(projectPath string) ([]ScopeConflict, error) {
+	values := make(map[string][]ScopeValue)
+	for _, scope := range AllScopes() {
+		settings, err := LoadSettings(scope, projectPath)
+		if err != nil {
+			continue
+		}
+		for fullName, enabled := range settings.EnabledPlugins {
+			values[fullName] = append(values[fullName], ScopeValue{Scope: scope, Enabled: enabled})
+		}
+	}
+
+	var conflicts []ScopeConflict
+	for _, fullName := range slices.Sorted(maps.Keys(values)) {
+		vals := values[fullName]
+		for _, v := range vals[1:] {
+			if v.Enabled != vals[0].Enabled {
+				conflicts = append(conflicts, ScopeConflict{FullName: fullName, Values: vals})
+				break
+			}
+		}
+	}
+	return conflicts, nil
+}
+
 // GetPluginState returns the effective state for a specific plugin
 // Returns the state from the highest precedence scope that has it
 func GetPluginState(pluginFullName string, projectPath string) (*PluginState, error) {
@@ -1,8 +1,11 @@
 package settings
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -292,6 +295,48 @@ func TestGetPluginState(t *testing.T) {
 	}
 }
 
+func TestDetectConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	userDir := filepath.Join(tmpDir, "user")
+	projectDir := filepath.Join(tmpDir, "project")
+	t.Setenv("CLAUDE_CONFIG_DIR", userDir)
+
+	files := map[string]string{
+		filepath.Join(userDir, "settings.json"): `{"enabledPlugins": {
+			"conflict@market": true,
+			"agree@market": true,
+			"user-only@market": false
+		}}`,
+		filepath.Join(projectDir, ".claude", "settings.json"): `{"enabledPlugins": {
+			"conflict@market": false,
+			"agree@market": true
+		}}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conflicts, err := DetectConflicts(projectDir)
+	if err != nil {
+		t.Fatalf("DetectConflicts error = %v", err)
+	}
+	want := []ScopeConflict{{
+		FullName: "conflict@market",
+		Values: []ScopeValue{
+			{Scope: ScopeProject, Enabled: false},
+			{Scope: ScopeUser, Enabled: true},
+		},
+	}}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("conflicts = %+v, want %+v", conflicts, want)
+	}
+}
+
 func TestAllMarketplaces(t *testing.T) {
 	// Create temp project with settings
 	tmpDir := t.TempDir()
@@ -353,6 +398,112 @@ func TestLoadSettingsFromPath_InvalidPluginKeyFormat(t *testing.T) {
 	}
 }
 
+func TestLoadSettingsFromPath_StructuralErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "plugin key with empty plugin name",
+			content: `{"enabledPlugins": {"ok@mkt": true, "@x": true}}`,
+			wantErr: `invalid plugin key format (expected plugin@marketplace): "@x"`,
+		},
+		{
+			name:    "plugin key with empty marketplace",
+			content: `{"enabledPlugins": {"plugin@": true}}`,
+			wantErr: `"plugin@"`,
+		},
+		{
+			name: "marketplace missing repo",
+			content: `{"extraKnownMarketplaces": {
+				"good": {"source": {"source": "github", "repo": "acme/good"}},
+				"broken": {"source": {"source": "github"}}
+			}}`,
+			wantErr: `invalid extraKnownMarketplaces entry "broken": missing source.repo`,
+		},
+		{
+			name:    "gitlab marketplace missing repo",
+			content: `{"extraKnownMarketplaces": {"broken": {"source": {"source": "gitlab"}}}}`,
+			wantErr: `invalid extraKnownMarketplaces entry "broken": missing source.repo`,
+		},
+		{
+			name:    "marketplace missing source type",
+			content: `{"extraKnownMarketplaces": {"broken": {"source": {"repo": "acme/broken"}}}}`,
+			wantErr: `invalid extraKnownMarketplaces entry "broken": missing source.source`,
+		},
+		{
+			name:    "marketplace without a source object",
+			content: `{"extraKnownMarketplaces": {"broken": {}}}`,
+			wantErr: `entry "broken": missing source.source`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settingsPath := filepath.Join(t.TempDir(), "settings.json")
+			if err := os.WriteFile(settingsPath, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadSettingsFromPath(settingsPath)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadSettingsFromPath error = %v, want %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), settingsPath) {
+				t.Errorf("error should name the settings file: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadSettingsFromPath_SourcesWithoutRepo(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+	content := `{"extraKnownMarketplaces": {
+		"local": {"source": {"source": "directory", "path": "/tmp/x"}},
+		"mirror": {"source": {"source": "git", "url": "https://git.example.com/plugins.git"}},
+		"hosted": {"source": {"source": "url", "url": "https://example.com/marketplace.json"}}
+	}}`
+	if err := os.WriteFile(settingsPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := LoadSettingsFromPath(settingsPath)
+	if err != nil {
+		t.Fatalf("expected directory, git and url sources to load, got %v", err)
+	}
+	if got := settings.ExtraKnownMarketplaces["local"].Source.Source; got != "directory" {
+		t.Errorf("local source = %q, want directory", got)
+	}
+}
+
+func TestMarketplaceSourcePreservesUnknownFields(t *testing.T) {
+	input := `{"source":"directory","path":"/tmp/x","ref":"main"}`
+
+	var source MarketplaceSource
+	if err := json.Unmarshal([]byte(input), &source); err != nil {
+		t.Fatal(err)
+	}
+	if source.Source != "directory" || source.Repo != "" {
+		t.Errorf("parsed source = %+v", source)
+	}
+
+	out, err := json.Marshal(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(input), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %s, want %s", out, input)
+	}
+}
+
 func TestLoadSettingsFromPath_ValidPluginKeyFormat(t *testing.T) {
 	tmpDir := t.TempDir()
 	settingsPath := filepath.Join(tmpDir, "settings.json")
@@ -408,6 +559,30 @@ func TestValidateSettings(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "invalid plugin key - empty marketplace",
+			settings: &Settings{
+				EnabledPlugins:         map[string]bool{"plugin@": true},
+				ExtraKnownMarketplaces: map[string]ExtraMarketplace{},
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid plugin key - empty plugin name",
+			settings: &Settings{
+				EnabledPlugins:         map[string]bool{"@market": true},
+				ExtraKnownMarketplaces: map[string]ExtraMarketplace{},
+			},
+			wantError: true,
+		},
+		{
+			name: "valid key with @ in plugin name",
+			settings: &Settings{
+				EnabledPlugins:         map[string]bool{"team@tool@market": true},
+				ExtraKnownMarketplaces: map[string]ExtraMarketplace{},
+			},
+			wantError: false,
+		},
 		{
 			name: "multiple valid keys",
 			settings: &Settings{
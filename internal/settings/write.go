@@ -3,8 +3,11 @@ package settings
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 )
 
 // SaveSettings saves settings to a specific scope
@@ -42,6 +45,11 @@ func SaveSettings(s *Settings, scope Scope, projectPath string) error {
 		existing.ExtraKnownMarketplaces[k] = v
 	}
 
+	// Never write a file that would be rejected on the next load
+	if err := validateSettings(existing); err != nil {
+		return err
+	}
+
 	// Marshal to JSON with indentation
 	data, err := json.MarshalIndent(existing, "", "  ")
 	if err != nil {
@@ -85,6 +93,72 @@ func SaveSettings(s *Settings, scope Scope, projectPath string) error {
 	return nil
 }
 
+// MergeSettings merges plugin and marketplace entries into several scopes at
+// once, holding every scope's lock. Each merged scope is checked before any
+// file is written, so a rejected entry leaves all scopes untouched. Like
+// SetPluginEnabled, a second spelling of an existing plugin key is refused.
+func MergeSettings(updates map[Scope]*Settings, projectPath string) error {
+	var scopes []Scope
+	var paths []string
+	for _, scope := range AllScopes() {
+		if updates[scope] == nil {
+			continue
+		}
+		if !scope.IsWritable() {
+			return ErrManagedReadOnly
+		}
+		path, err := ScopePath(scope, projectPath)
+		if err != nil {
+			return err
+		}
+		scopes = append(scopes, scope)
+		paths = append(paths, path)
+	}
+
+	return withLocks(paths, func() error {
+		merged := make([]*Settings, len(scopes))
+		for i, scope := range scopes {
+			existing, err := LoadSettingsFromPath(paths[i])
+			if err != nil {
+				return fmt.Errorf("failed to load %s settings: %w", scope, err)
+			}
+			for k, v := range updates[scope].EnabledPlugins {
+				existing.EnabledPlugins[k] = v
+			}
+			for k, v := range updates[scope].ExtraKnownMarketplaces {
+				existing.ExtraKnownMarketplaces[k] = v
+			}
+
+			for _, key := range slices.Sorted(maps.Keys(updates[scope].EnabledPlugins)) {
+				if variant, ok := FindCaseVariant(key, existing.EnabledPlugins); ok {
+					return fmt.Errorf("%s settings: %w: %s (existing: %s)", scope, ErrCaseVariantKey, key, variant)
+				}
+			}
+			if err := validateSettings(existing); err != nil {
+				return fmt.Errorf("%s settings: %w", scope, err)
+			}
+			merged[i] = existing
+		}
+
+		for i, scope := range scopes {
+			if err := saveSettingsDirect(merged[i], paths[i]); err != nil {
+				return fmt.Errorf("failed to write %s settings: %w", scope, err)
+			}
+		}
+		return nil
+	})
+}
+
+// withLocks runs fn holding the lock of every path, taken in order
+func withLocks(paths []string, fn func() error) error {
+	if len(paths) == 0 {
+		return fn()
+	}
+	return WithLock(paths[0], func() error {
+		return withLocks(paths[1:], fn)
+	})
+}
+
 // SetPluginEnabled sets the enabled state for a plugin in the specified scope
 func SetPluginEnabled(fullName string, enabled bool, scope Scope, projectPath string) error {
 	// Validate scope is writable
@@ -105,6 +179,11 @@ func SetPluginEnabled(fullName string, enabled bool, scope Scope, projectPath st
 			return fmt.Errorf("failed to load settings: %w", err)
 		}
 
+		// Refuse to add a second spelling of an existing key
+		if existing, ok := FindCaseVariant(fullName, settings.EnabledPlugins); ok {
+			return fmt.Errorf("%w: %s (existing: %s)", ErrCaseVariantKey, fullName, existing)
+		}
+
 		// Update the plugin state
 		settings.EnabledPlugins[fullName] = enabled
 
@@ -113,6 +192,49 @@ func SetPluginEnabled(fullName string, enabled bool, scope Scope, projectPath st
 	})
 }
 
+// SetAllPluginsEnabled sets every plugin in the specified scope to enabled in
+// one locked write, so only a single backup is taken. Returns the full names
+// whose state changed, sorted.
+func SetAllPluginsEnabled(enabled bool, scope Scope, projectPath string) ([]string, error) {
+	// Validate scope is writable
+	if !scope.IsWritable() {
+		return nil, ErrManagedReadOnly
+	}
+
+	path, err := ScopePath(scope, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	err = WithLock(path, func() error {
+		settings, err := LoadSettingsFromPath(path)
+		if err != nil {
+			return fmt.Errorf("failed to load settings: %w", err)
+		}
+
+		for fullName, current := range settings.EnabledPlugins {
+			if current != enabled {
+				settings.EnabledPlugins[fullName] = enabled
+				changed = append(changed, fullName)
+			}
+		}
+
+		// Nothing to flip - leave the file untouched
+		if len(changed) == 0 {
+			return nil
+		}
+
+		return saveSettingsDirect(settings, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
+
 // RemovePluginFromScope removes a plugin entry from a specific scope
 func RemovePluginFromScope(fullName string, scope Scope, projectPath string) error {
 	// Validate scope is writable
@@ -273,3 +395,55 @@ func AtomicRename(tmpPath, finalPath string) error {
 	}
 	return nil
 }
+
+// CheckWritable verifies that settings for scope can be saved, so commands can
+// fail before doing any other work. The settings directory, or its nearest
+// existing parent when it hasn't been created yet, must accept new files.
+func CheckWritable(scope Scope, projectPath string) error {
+	if !scope.IsWritable() {
+		return ErrManagedReadOnly
+	}
+
+	path, err := ScopePath(scope, projectPath)
+	if err != nil {
+		return err
+	}
+	if err := CheckDirWritable(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("cannot save %s settings: %w", scope, err)
+	}
+	return nil
+}
+
+// CheckDirWritable verifies that files can be created in dir, or in its
+// nearest existing parent when dir doesn't exist yet
+func CheckDirWritable(dir string) error {
+	existing := dir
+	for {
+		info, err := os.Stat(existing)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory: %w", existing, ErrNotWritable)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("%s (%v): %w", existing, err, ErrNotWritable)
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return fmt.Errorf("%s: %w", dir, ErrNotWritable)
+		}
+		existing = parent
+	}
+
+	// Permission bits don't tell the whole story (ACLs, read-only mounts),
+	// so probe with a real file
+	f, err := os.CreateTemp(existing, ".plum-write-check-*")
+	if err != nil {
+		return fmt.Errorf("%s: %w", existing, ErrNotWritable)
+	}
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+	return nil
+}
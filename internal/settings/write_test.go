@@ -2,8 +2,12 @@ package settings
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -589,6 +593,38 @@ func TestSetPluginEnabledPreservesUnknownFields(t *testing.T) {
 	}
 }
 
+func TestSetPluginEnabledKeepsDirectoryMarketplace(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	path, _ := ScopePath(ScopeUser, tmpDir)
+	initialJSON := `{"extraKnownMarketplaces": {"local": {"source": {"source": "directory", "path": "/tmp/x"}}}}`
+	if err := os.WriteFile(path, []byte(initialJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetPluginEnabled("a@local", true, ScopeUser, tmpDir); err != nil {
+		t.Fatalf("SetPluginEnabled failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		ExtraKnownMarketplaces map[string]struct {
+			Source map[string]any `json:"source"`
+		} `json:"extraKnownMarketplaces"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"source": "directory", "path": "/tmp/x"}
+	if got := result.ExtraKnownMarketplaces["local"].Source; !reflect.DeepEqual(got, want) {
+		t.Errorf("directory source = %v, want %v", got, want)
+	}
+}
+
 func TestRemovePluginFromScopePreservesUnknownFields(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -696,3 +732,239 @@ func TestAddMarketplacePreservesUnknownFields(t *testing.T) {
 		t.Error("new marketplace was not added")
 	}
 }
+
+func TestSetPluginEnabledRefusesCaseVariant(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+
+	if err := SetPluginEnabled("foo@Market", true, ScopeUser, tmpDir); err != nil {
+		t.Fatalf("SetPluginEnabled() error = %v", err)
+	}
+
+	err := SetPluginEnabled("foo@market", false, ScopeUser, tmpDir)
+	if !errors.Is(err, ErrCaseVariantKey) {
+		t.Fatalf("SetPluginEnabled() error = %v, want ErrCaseVariantKey", err)
+	}
+
+	settings, err := LoadSettings(ScopeUser, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to load settings: %v", err)
+	}
+	if len(settings.EnabledPlugins) != 1 || !settings.EnabledPlugins["foo@Market"] {
+		t.Errorf("expected only the original key to remain, got %v", settings.EnabledPlugins)
+	}
+
+	// Updating the existing spelling is still allowed
+	if err := SetPluginEnabled("foo@Market", false, ScopeUser, tmpDir); err != nil {
+		t.Errorf("SetPluginEnabled() on existing key error = %v", err)
+	}
+}
+
+func TestMergeSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+	projectDir := t.TempDir()
+
+	if err := SetPluginEnabled("foo@Market", true, ScopeUser, projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	project := NewSettings()
+	project.EnabledPlugins["bar@market"] = true
+	user := NewSettings()
+	user.EnabledPlugins["baz@market"] = true
+	if err := MergeSettings(map[Scope]*Settings{ScopeUser: user, ScopeProject: project}, projectDir); err != nil {
+		t.Fatalf("MergeSettings() error = %v", err)
+	}
+	got, err := LoadSettings(ScopeUser, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.EnabledPlugins) != 2 || !got.EnabledPlugins["foo@Market"] || !got.EnabledPlugins["baz@market"] {
+		t.Errorf("user plugins = %v, want foo@Market and baz@market", got.EnabledPlugins)
+	}
+
+	// A case variant in one scope stops every scope from being written
+	project = NewSettings()
+	project.EnabledPlugins["qux@market"] = true
+	user = NewSettings()
+	user.EnabledPlugins["foo@market"] = false
+	err = MergeSettings(map[Scope]*Settings{ScopeUser: user, ScopeProject: project}, projectDir)
+	if !errors.Is(err, ErrCaseVariantKey) {
+		t.Fatalf("MergeSettings() error = %v, want ErrCaseVariantKey", err)
+	}
+	got, err = LoadSettings(ScopeProject, projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.EnabledPlugins["qux@market"]; ok {
+		t.Error("project settings should not be written when user settings are rejected")
+	}
+
+	if err := MergeSettings(map[Scope]*Settings{ScopeManaged: NewSettings()}, projectDir); !errors.Is(err, ErrManagedReadOnly) {
+		t.Errorf("MergeSettings() into managed scope error = %v, want ErrManagedReadOnly", err)
+	}
+}
+
+func TestSetAllPluginsEnabled(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cleanup := setEnvForTest(t, "CLAUDE_CONFIG_DIR", tmpDir)
+	defer cleanup()
+
+	initialJSON := `{
+  "customField": "custom value",
+  "enabledPlugins": {"a@market": true, "b@market": true, "c@market": false}
+}`
+
+	path, _ := ScopePath(ScopeUser, tmpDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(initialJSON), 0600); err != nil {
+		t.Fatalf("Failed to write initial settings: %v", err)
+	}
+
+	changed, err := SetAllPluginsEnabled(false, ScopeUser, tmpDir)
+	if err != nil {
+		t.Fatalf("SetAllPluginsEnabled failed: %v", err)
+	}
+	if len(changed) != 2 || changed[0] != "a@market" || changed[1] != "b@market" {
+		t.Errorf("changed = %v, want [a@market b@market]", changed)
+	}
+
+	settings, err := LoadSettings(ScopeUser, tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to load settings: %v", err)
+	}
+	for name, enabled := range settings.EnabledPlugins {
+		if enabled {
+			t.Errorf("%s should be disabled", name)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read settings: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if result["customField"] != "custom value" {
+		t.Errorf("customField lost: %v", result["customField"])
+	}
+
+	// The backup holds the pre-change settings
+	backup, err := os.ReadFile(path + ".backup-plum")
+	if err != nil {
+		t.Fatalf("expected backup file: %v", err)
+	}
+	if string(backup) != initialJSON {
+		t.Errorf("backup = %q, want original settings", backup)
+	}
+
+	// Re-enabling flips everything back, including the originally disabled plugin
+	changed, err = SetAllPluginsEnabled(true, ScopeUser, tmpDir)
+	if err != nil {
+		t.Fatalf("SetAllPluginsEnabled(true) failed: %v", err)
+	}
+	if len(changed) != 3 {
+		t.Errorf("changed = %v, want 3 plugins", changed)
+	}
+
+	// Nothing left to flip
+	changed, err = SetAllPluginsEnabled(true, ScopeUser, tmpDir)
+	if err != nil {
+		t.Fatalf("SetAllPluginsEnabled(true) again failed: %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("changed = %v, want none", changed)
+	}
+}
+
+func TestSetAllPluginsEnabledManagedScope(t *testing.T) {
+	if _, err := SetAllPluginsEnabled(false, ScopeManaged, t.TempDir()); !errors.Is(err, ErrManagedReadOnly) {
+		t.Errorf("expected ErrManagedReadOnly, got %v", err)
+	}
+}
+
+// readOnlyDir creates a directory files can't be created in. Skips when the
+// platform or user ignores directory permissions.
+func readOnlyDir(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced for this user")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+	return dir
+}
+
+func TestCheckWritable(t *testing.T) {
+	project := t.TempDir()
+	if err := CheckWritable(ScopeProject, project); err != nil {
+		t.Errorf("writable project: %v", err)
+	}
+	// .claude doesn't exist yet; checking must not create it
+	if _, err := os.Stat(filepath.Join(project, ".claude")); !os.IsNotExist(err) {
+		t.Errorf("CheckWritable should not create the settings directory (stat err = %v)", err)
+	}
+
+	if err := CheckWritable(ScopeManaged, project); !errors.Is(err, ErrManagedReadOnly) {
+		t.Errorf("managed scope: got %v, want ErrManagedReadOnly", err)
+	}
+
+	readOnly := readOnlyDir(t)
+	err := CheckWritable(ScopeLocal, readOnly)
+	if !errors.Is(err, ErrNotWritable) {
+		t.Fatalf("read-only project: got %v, want ErrNotWritable", err)
+	}
+	if !strings.Contains(err.Error(), "local settings") || !strings.Contains(err.Error(), readOnly) {
+		t.Errorf("error should name the scope and directory: %v", err)
+	}
+}
+
+func TestCheckDirWritable(t *testing.T) {
+	dir := t.TempDir()
+	if err := CheckDirWritable(filepath.Join(dir, "missing", "nested")); err != nil {
+		t.Errorf("missing dir under a writable parent: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("CheckDirWritable left files behind: %v", entries)
+	}
+
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckDirWritable(filepath.Join(file, "child")); !errors.Is(err, ErrNotWritable) {
+		t.Errorf("path under a file: got %v, want ErrNotWritable", err)
+	}
+
+	if err := CheckDirWritable(filepath.Join(readOnlyDir(t), "plugins")); !errors.Is(err, ErrNotWritable) {
+		t.Errorf("read-only parent: got %v, want ErrNotWritable", err)
+	}
+}
+
+func TestSaveSettingsRejectsInvalidEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	cleanup := setEnvForTest(t, "CLAUDE_CONFIG_DIR", tmpDir)
+	defer cleanup()
+
+	s := NewSettings()
+	s.EnabledPlugins["no-marketplace"] = true
+
+	if err := SaveSettings(s, ScopeUser, tmpDir); err == nil {
+		t.Fatal("SaveSettings should reject a plugin key without a marketplace")
+	}
+
+	path, _ := UserSettingsPath()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("settings file should not be written, stat err = %v", err)
+	}
+}
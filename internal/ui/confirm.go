@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// installConfirmedMsg starts an install the user confirmed
+type installConfirmedMsg struct {
+	plugin plugin.Plugin
+}
+
+// confirmInstall returns the command run once an install is confirmed
+func confirmInstall(p plugin.Plugin) tea.Cmd {
+	return func() tea.Msg {
+		return installConfirmedMsg{plugin: p}
+	}
+}
+
+// requestConfirm opens the confirmation dialog. cmd runs only if the user
+// confirms; either way the dialog returns to the current view.
+func (m *Model) requestConfirm(prompt string, cmd tea.Cmd) {
+	m.confirmPrompt = prompt
+	m.confirmCmd = cmd
+	m.confirmReturn = m.viewState
+	m.viewState = ViewConfirm
+}
+
+// closeConfirm drops the pending command and returns to the previous view
+func (m *Model) closeConfirm() {
+	m.viewState = m.confirmReturn
+	m.confirmPrompt = ""
+	m.confirmCmd = nil
+}
+
+// handleConfirmKeys handles keys in the confirmation dialog
+func (m Model) handleConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); {
+	case m.keys.Confirm.Yes.Has(key):
+		cmd := m.confirmCmd
+		m.closeConfirm()
+		return m, cmd
+
+	case m.keys.Confirm.No.Has(key):
+		m.closeConfirm()
+		return m, nil
+	}
+	return m, nil
+}
+
+// confirmView renders the confirmation dialog centered in the window
+func (m Model) confirmView() string {
+	maxWidth := m.windowWidth - 10
+	if maxWidth < 30 {
+		maxWidth = 30
+	}
+	if maxWidth > 60 {
+		maxWidth = 60
+	}
+
+	var b strings.Builder
+	b.WriteString(DetailTitleStyle.Render(wrapText(m.confirmPrompt, maxWidth)))
+	b.WriteString("\n\n")
+	b.WriteString(HelpStyle.Render(KeyStyle.Render("y/enter") + " confirm" + statusBarSeparator + KeyStyle.Render("n/esc") + " cancel"))
+	box := ConfirmBoxStyle.Render(b.String())
+
+	if m.windowWidth <= 0 || m.windowHeight <= 0 {
+		return box
+	}
+	return lipgloss.Place(m.windowWidth, m.windowHeight, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+// execProcess suspends the TUI and runs cmd, sending the callback's message
+// when it exits. Tests replace it to capture the command without running it.
+var execProcess = tea.ExecProcess
+
+// settingsEditedMsg is sent when the editor (or file manager) exits
+type settingsEditedMsg struct {
+	err error
+}
+
+// clearEditorErrorMsg clears the "Editor failed" indicator
+type clearEditorErrorMsg struct{}
+
+func clearEditorError() tea.Cmd {
+	return clearFlashAfter(3*time.Second, clearEditorErrorMsg{})
+}
+
+// openerCommand returns the system command that opens target with its default handler
+func openerCommand(target string) (string, []string) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open", []string{target}
+	case "windows":
+		return "explorer", []string{target}
+	default:
+		return "xdg-open", []string{target}
+	}
+}
+
+// settingsEditCommand builds the command that opens a settings file.
+// $VISUAL and then $EDITOR are used when set (they may include arguments,
+// e.g. "code -w"); otherwise the containing directory is opened in the
+// system file manager.
+func settingsEditCommand(path string, getenv func(string) string) *exec.Cmd {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(getenv(key)); len(fields) > 0 {
+			args := append(fields[1:], path)
+			// #nosec G204 -- editor is chosen by the user via $VISUAL/$EDITOR
+			return exec.Command(fields[0], args...)
+		}
+	}
+
+	name, args := openerCommand(filepath.Dir(path))
+	// #nosec G204 -- cmd is determined by runtime.GOOS (trusted), args is settings directory
+	return exec.Command(name, args...)
+}
+
+// editSettingsScope returns the scope whose settings.json the edit key opens:
+// the scope deciding the selected plugin's state, else the highest-precedence
+// writable scope with a settings file for the current project, else user
+func (m Model) editSettingsScope() settings.Scope {
+	if p := m.SelectedPlugin(); p != nil {
+		if state, ok := m.pluginStates[p.FullName()]; ok && state.Scope.IsWritable() {
+			return state.Scope
+		}
+	}
+	for _, scope := range settings.WritableScopes() {
+		path, err := settings.ScopePath(scope, "")
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return scope
+		}
+	}
+	return settings.ScopeUser
+}
+
+// editSettings opens the settings file for scope and reloads plugins once
+// the editor exits
+func editSettings(scope settings.Scope) tea.Cmd {
+	path, err := settings.ScopePath(scope, "")
+	if err != nil {
+		return func() tea.Msg { return settingsEditedMsg{err: err} }
+	}
+
+	// Make sure the directory exists so the editor can create the file
+	// #nosec G301 -- Claude config directory needs to be readable by Claude Code
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return func() tea.Msg { return settingsEditedMsg{err: err} }
+	}
+
+	return execProcess(settingsEditCommand(path, os.Getenv), func(err error) tea.Msg {
+		return settingsEditedMsg{err: err}
+	})
+}
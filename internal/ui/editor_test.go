@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+func envFrom(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestSettingsEditCommand(t *testing.T) {
+	path := filepath.Join("home", ".claude", "settings.json")
+
+	t.Run("uses EDITOR", func(t *testing.T) {
+		cmd := settingsEditCommand(path, envFrom(map[string]string{"EDITOR": "vim"}))
+		if cmd.Args[0] != "vim" {
+			t.Errorf("expected vim, got %v", cmd.Args)
+		}
+		if got := cmd.Args[len(cmd.Args)-1]; got != path {
+			t.Errorf("expected last arg %s, got %s", path, got)
+		}
+	})
+
+	t.Run("VISUAL takes precedence and keeps arguments", func(t *testing.T) {
+		cmd := settingsEditCommand(path, envFrom(map[string]string{"VISUAL": "code -w", "EDITOR": "vim"}))
+		want := []string{"code", "-w", path}
+		if len(cmd.Args) != len(want) {
+			t.Fatalf("expected args %v, got %v", want, cmd.Args)
+		}
+		for i := range want {
+			if cmd.Args[i] != want[i] {
+				t.Errorf("arg %d: expected %s, got %s", i, want[i], cmd.Args[i])
+			}
+		}
+	})
+
+	t.Run("falls back to file manager on directory", func(t *testing.T) {
+		cmd := settingsEditCommand(path, envFrom(nil))
+		name, _ := openerCommand(filepath.Dir(path))
+		if cmd.Args[0] != name {
+			t.Errorf("expected opener %s, got %v", name, cmd.Args)
+		}
+		if got := cmd.Args[len(cmd.Args)-1]; got != filepath.Dir(path) {
+			t.Errorf("expected directory %s, got %s", filepath.Dir(path), got)
+		}
+	})
+}
+
+func TestEditSettingsKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", tmpDir)
+	t.Chdir(t.TempDir())
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+
+	var ran *exec.Cmd
+	origExec := execProcess
+	execProcess = func(c *exec.Cmd, fn tea.ExecCallback) tea.Cmd {
+		ran = c
+		return func() tea.Msg { return fn(nil) }
+	}
+	defer func() { execProcess = origExec }()
+
+	model := NewModel()
+	model.loading = false
+	model.viewState = ViewHelp
+
+	updatedModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	model = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("expected a command from 'e' in help view")
+	}
+
+	msg := cmd()
+	if ran == nil {
+		t.Fatal("expected the editor command to be executed")
+	}
+	wantPath := filepath.Join(tmpDir, "settings.json")
+	if got := ran.Args[len(ran.Args)-1]; got != wantPath {
+		t.Errorf("expected editor to open %s, got %s", wantPath, got)
+	}
+
+	edited, ok := msg.(settingsEditedMsg)
+	if !ok {
+		t.Fatalf("expected settingsEditedMsg, got %T", msg)
+	}
+
+	// A successful edit triggers a reload
+	_, reload := model.Update(edited)
+	if reload == nil {
+		t.Error("expected reload command after editor exits")
+	}
+
+	// A failed edit shows an error flash
+	updatedModel, _ = model.Update(settingsEditedMsg{err: errors.New("boom")})
+	model = updatedModel.(Model)
+	if !model.editorErrorFlash {
+		t.Error("expected editor error flash after failure")
+	}
+}
+
+func TestEditSettingsScope(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	projectDir := t.TempDir()
+	t.Chdir(projectDir)
+
+	model := NewModel()
+	model.loading = false
+	model.allPlugins = []plugin.Plugin{{Name: "alpha", Marketplace: "mkt"}}
+	model.pluginStates = nil
+	model.applyFilter()
+
+	scopePath := func(scope settings.Scope) string {
+		t.Helper()
+		path, err := settings.ScopePath(scope, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// No settings for this project: user scope
+	if got := model.editSettingsScope(); got != settings.ScopeUser {
+		t.Errorf("scope without project settings = %s, want user", got)
+	}
+
+	// The project's own settings file is the active one
+	projectSettings := scopePath(settings.ScopeProject)
+	if err := os.MkdirAll(filepath.Dir(projectSettings), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectSettings, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := model.editSettingsScope(); got != settings.ScopeProject {
+		t.Errorf("scope with project settings = %s, want project", got)
+	}
+
+	// The scope deciding the selected plugin wins
+	model.pluginStates = map[string]settings.PluginState{
+		"alpha@mkt": {FullName: "alpha@mkt", Enabled: true, Scope: settings.ScopeLocal},
+	}
+	if got := model.editSettingsScope(); got != settings.ScopeLocal {
+		t.Errorf("scope deciding the selected plugin = %s, want local", got)
+	}
+
+	// Managed settings can't be edited, so the project's file opens instead
+	model.pluginStates["alpha@mkt"] = settings.PluginState{FullName: "alpha@mkt", Enabled: true, Scope: settings.ScopeManaged}
+	if got := model.editSettingsScope(); got != settings.ScopeProject {
+		t.Errorf("scope for a managed plugin = %s, want project", got)
+	}
+
+	// The edit key opens that scope's file
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+	var ran *exec.Cmd
+	origExec := execProcess
+	execProcess = func(c *exec.Cmd, fn tea.ExecCallback) tea.Cmd {
+		ran = c
+		return func() tea.Msg { return fn(nil) }
+	}
+	defer func() { execProcess = origExec }()
+	model.viewState = ViewHelp
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if cmd == nil {
+		t.Fatal("expected a command from 'e' in help view")
+	}
+	cmd()
+	if ran == nil || ran.Args[len(ran.Args)-1] != projectSettings {
+		t.Errorf("editor should open %s, ran %v", projectSettings, ran)
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/itsdevcoffee/plum/internal/export"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// exportFileName is the base name of list exports, written to the working
+// directory with the format's extension
+const exportFileName = "plum-export"
+
+// writeExportFile writes entries to plum-export.<ext> in the working
+// directory, replacing any earlier export, and returns the file's path
+func writeExportFile(format export.Format, entries []export.Entry) (string, error) {
+	path, err := filepath.Abs(exportFileName + "." + format.Extension())
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, entries); err != nil {
+		return "", err
+	}
+	// #nosec G306 -- exports are meant to be shared like any document
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ExportResults writes the current filtered results in the export format
+func (m *Model) ExportResults() {
+	plugins := make([]plugin.Plugin, len(m.results))
+	for i, rp := range m.results {
+		plugins[i] = rp.Plugin
+	}
+
+	m.toggleFlash, m.toggleError = "", nil
+	path, err := writeExportFile(m.exportFormat, export.Entries(plugins))
+	if err != nil {
+		m.toggleError = fmt.Errorf("export failed: %w", err)
+		return
+	}
+	m.toggleFlash = fmt.Sprintf("Exported %d to %s", len(plugins), path)
+}
+
+// NextExportFormat switches between Markdown and JSON exports
+func (m *Model) NextExportFormat() {
+	if m.exportFormat == export.FormatMarkdown {
+		m.exportFormat = export.FormatJSON
+	} else {
+		m.exportFormat = export.FormatMarkdown
+	}
+	m.toggleFlash, m.toggleError = "Export format: "+m.exportFormat.String(), nil
+}
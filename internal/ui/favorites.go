@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/settings"
+)
+
+// favoritesFileName is the favorites store, a JSON array of plugin full names
+const favoritesFileName = "favorites.json"
+
+// favoritesPath returns where favorites are stored (variable for testing).
+// The file sits in plum's data directory, next to the marketplace cache
+// rather than inside it, so a cache refresh doesn't wipe it.
+var favoritesPath = func() (string, error) {
+	cacheDir, err := marketplace.PlumCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(filepath.Dir(cacheDir)), favoritesFileName), nil
+}
+
+// readFavorites reads the favorite plugins' full names. A missing file
+// means no favorites.
+func readFavorites() (map[string]bool, error) {
+	path, err := favoritesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G304 -- path is built from plum's data directory
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	favorites := make(map[string]bool, len(names))
+	for _, name := range names {
+		favorites[name] = true
+	}
+	return favorites, nil
+}
+
+// writeFavorites writes the favorite plugins' full names, sorted, replacing
+// the file atomically
+func writeFavorites(favorites map[string]bool) error {
+	path, err := favoritesPath()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(favorites))
+	for name, ok := range favorites {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(dir, ".tmp-favorites-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // Cleanup on failure - best effort
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	return settings.AtomicRename(tmpPath, path)
+}
+
+// loadFavorites loads the favorites for the model, treating an unreadable
+// store as empty (variable for testing)
+var loadFavorites = func() map[string]bool {
+	favorites, err := readFavorites()
+	if err != nil {
+		return map[string]bool{}
+	}
+	return favorites
+}
+
+// saveFavorites persists the favorites (variable for testing)
+var saveFavorites = writeFavorites
+
+// ToggleFavorite stars or unstars the selected plugin and saves the change
+func (m *Model) ToggleFavorite() {
+	p := m.SelectedPlugin()
+	if p == nil {
+		return
+	}
+
+	// Copy before writing so earlier Model values keep their favorites
+	favorites := make(map[string]bool, len(m.favorites)+1)
+	for name := range m.favorites {
+		favorites[name] = true
+	}
+	name := p.FullName()
+	if favorites[name] {
+		delete(favorites, name)
+	} else {
+		favorites[name] = true
+	}
+
+	m.toggleFlash, m.toggleError = "", nil
+	if err := saveFavorites(favorites); err != nil {
+		m.toggleError = fmt.Errorf("favorites not saved: %w", err)
+		return
+	}
+	m.favorites = favorites
+	if favorites[name] {
+		m.toggleFlash = "Added to favorites"
+	} else {
+		m.toggleFlash = "Removed from favorites"
+	}
+	if m.filterMode == FilterFavorites && m.viewState == ViewList {
+		m.results = m.filteredSearch(m.textInput.Value())
+		if m.cursor >= len(m.results) && m.cursor > 0 {
+			m.cursor = len(m.results) - 1
+		}
+		m.UpdateScroll()
+	}
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// useFavoritesFile points the favorites store at a file in a temp dir
+func useFavoritesFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plum", favoritesFileName)
+	original := favoritesPath
+	favoritesPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { favoritesPath = original })
+	return path
+}
+
+func TestFavoritesStore(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		path := useFavoritesFile(t)
+
+		want := map[string]bool{"beta@mkt": true, "alpha@mkt": true}
+		if err := writeFavorites(want); err != nil {
+			t.Fatalf("writeFavorites failed: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"alpha@mkt",`) {
+			t.Errorf("favorites should be stored as a sorted JSON array, got %s", data)
+		}
+
+		got, err := readFavorites()
+		if err != nil {
+			t.Fatalf("readFavorites failed: %v", err)
+		}
+		if len(got) != 2 || !got["alpha@mkt"] || !got["beta@mkt"] {
+			t.Errorf("readFavorites = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		useFavoritesFile(t)
+		got, err := readFavorites()
+		if err != nil || len(got) != 0 {
+			t.Errorf("readFavorites = %v, %v; want empty set", got, err)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := useFavoritesFile(t)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readFavorites(); err == nil {
+			t.Error("expected a parse error")
+		}
+		if got := loadFavorites(); len(got) != 0 {
+			t.Errorf("loadFavorites should fall back to no favorites, got %v", got)
+		}
+	})
+}
+
+func TestFavoritesFilter(t *testing.T) {
+	var saved []map[string]bool
+	saveErr := error(nil)
+	original := saveFavorites
+	saveFavorites = func(favorites map[string]bool) error {
+		saved = append(saved, favorites)
+		return saveErr
+	}
+	defer func() { saveFavorites = original }()
+
+	m := NewModel()
+	m.loading = false
+	m.allPlugins = []plugin.Plugin{
+		{Name: "alpha", Marketplace: "mkt"},
+		{Name: "beta", Marketplace: "mkt"},
+		{Name: "gamma", Marketplace: "mkt"},
+	}
+	m.applyFilter()
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+
+	// Star alpha with '*' and gamma with 'F'
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+
+	if m.textInput.Value() != "" {
+		t.Errorf("star keys should not type into the search, got %q", m.textInput.Value())
+	}
+	if len(saved) != 2 || !saved[1]["alpha@mkt"] || !saved[1]["gamma@mkt"] {
+		t.Fatalf("expected favorites to be saved after each toggle, got %v", saved)
+	}
+	if !strings.Contains(m.renderFilterTabs(), "Favorites (2)") {
+		t.Errorf("filter tabs should count favorites: %s", m.renderFilterTabs())
+	}
+
+	m.filterMode = FilterFavorites
+	m.applyFilter()
+	if len(m.results) != 2 || m.results[0].Plugin.Name != "alpha" || m.results[1].Plugin.Name != "gamma" {
+		t.Fatalf("favorites filter = %v, want alpha and gamma", m.results)
+	}
+
+	// Unstarring in the favorites tab drops the plugin from the list
+	m.cursor = 1
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	if len(m.results) != 1 || m.results[0].Plugin.Name != "alpha" || m.cursor != 0 {
+		t.Errorf("after unstarring gamma, results = %v, cursor = %d", m.results, m.cursor)
+	}
+
+	// A failed save keeps the old favorites and reports the error
+	saveErr = errors.New("disk full")
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	if !m.favorites["alpha@mkt"] || m.toggleError == nil {
+		t.Errorf("failed save should keep alpha starred and flash an error, error = %v", m.toggleError)
+	}
+}
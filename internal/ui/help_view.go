@@ -9,9 +9,9 @@ import (
 
 // helpView renders the help view with sticky header/footer
 func (m Model) helpView() string {
-	helpWrapperStyle := lipgloss.NewStyle().Padding(0, 2, 0, 2)
-	helpBoxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+	helpWrapperStyle := newStyle().Padding(0, 2, 0, 2)
+	helpBoxStyle := newStyle().
+		Border(boxBorder()).
 		BorderForeground(PlumBright).
 		Padding(1, 2)
 
@@ -44,9 +44,9 @@ func (m Model) generateHelpHeader() string {
 
 	title := DetailTitleStyle.Render("🍑 plum Help")
 
-	installedOnlyStyle := lipgloss.NewStyle().Foreground(Success)
+	installedOnlyStyle := newStyle().Foreground(Success)
 	legendText := installedOnlyStyle.Render("🟢") + " = installed only"
-	legendStyle := lipgloss.NewStyle().
+	legendStyle := newStyle().
 		Foreground(TextMuted).
 		Align(lipgloss.Right).
 		Width(contentWidth - lipgloss.Width(title))
@@ -57,16 +57,21 @@ func (m Model) generateHelpHeader() string {
 	var b strings.Builder
 	b.WriteString(headerLine)
 	b.WriteString("\n")
-	b.WriteString(strings.Repeat("─", contentWidth))
+	b.WriteString(strings.Repeat(ruleChar, contentWidth))
 	return b.String()
 }
 
 // generateHelpFooter generates the sticky footer
 func (m Model) generateHelpFooter() string {
 	var b strings.Builder
-	b.WriteString(strings.Repeat("─", 58))
+	b.WriteString(strings.Repeat(ruleChar, 58))
 	b.WriteString("\n")
-	b.WriteString(HelpTextStyle.Render("  Press any key to return  (↑↓ to scroll)"))
+	if m.editorErrorFlash {
+		errorStyle := newStyle().Foreground(Error).Bold(true)
+		b.WriteString(errorStyle.Render("  ✗ Could not open editor"))
+	} else {
+		b.WriteString(HelpTextStyle.Render("  esc to return  •  ↑↓ to scroll  •  e edit settings.json"))
+	}
 	return b.String()
 }
 
@@ -74,9 +79,9 @@ func (m Model) generateHelpFooter() string {
 func (m Model) generateHelpSections() string {
 	var b strings.Builder
 
-	contextStyle := lipgloss.NewStyle().Foreground(TextMuted).Italic(true)
-	installedOnlyStyle := lipgloss.NewStyle().Foreground(Success)
-	dividerStyle := lipgloss.NewStyle().Foreground(BorderSubtle)
+	contextStyle := newStyle().Foreground(TextMuted).Italic(true)
+	installedOnlyStyle := newStyle().Foreground(Success)
+	dividerStyle := newStyle().Foreground(BorderSubtle)
 
 	// Navigation section
 	b.WriteString(HelpSectionStyle.Render("  🧭 Navigation"))
@@ -87,11 +92,14 @@ func (m Model) generateHelpSections() string {
 		{"Ctrl+u PgUp", "Page up"},
 		{"Ctrl+d PgDn", "Page down"},
 		{"Home / End", "Jump to edges"},
+		{"gg / G", "Jump to edges (gg with empty search)"},
+		{"↑ / ↓ at top", "Recall earlier / later searches"},
+		{"Click / wheel", "Select (double-click opens)"},
 	}
 	for _, h := range navKeys {
 		b.WriteString(fmt.Sprintf("    %s  %s\n", KeyStyle.Width(16).Render(h.key), HelpTextStyle.Render(h.desc)))
 	}
-	b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", 56)))
+	b.WriteString(dividerStyle.Render("  " + strings.Repeat(ruleChar, 56)))
 	b.WriteString("\n")
 
 	// Views & Browsing section
@@ -101,6 +109,8 @@ func (m Model) generateHelpSections() string {
 		{"Enter", "View details", "(plugin/marketplace list)"},
 		{"Shift+M", "Marketplace browser", "(any view)"},
 		{"?", "Toggle help", "(any view)"},
+		{"Space", "Mark plugin", "(list, empty search)"},
+		{"y", "Copy marked install commands", "(list)"},
 	}
 	for _, h := range viewKeys {
 		desc := HelpTextStyle.Render(h.desc)
@@ -109,19 +119,23 @@ func (m Model) generateHelpSections() string {
 		}
 		b.WriteString(fmt.Sprintf("    %s  %s\n", KeyStyle.Width(16).Render(h.key), desc))
 	}
-	b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", 56)))
+	b.WriteString(dividerStyle.Render("  " + strings.Repeat(ruleChar, 56)))
 	b.WriteString("\n")
 
 	// Plugin Actions section
 	b.WriteString(HelpSectionStyle.Render("  📦 Plugin Actions ") + contextStyle.Render("(plugin detail view)"))
 	b.WriteString("\n")
 	pluginKeys := []struct{ key, desc, suffix string }{
+		{"i", "Install in user scope (asks first)", ""},
+		{"e", "Enable / disable (Shift+E in list)", " 🟢"},
+		{"f *", "Star / unstar (Shift+F or * in list)", ""},
 		{"c", "Copy install command", ""},
 		{"y", "Copy plugin install", " (discover only)"},
 		{"g", "Open on GitHub", ""},
 		{"o", "Open local directory", " 🟢"},
 		{"p", "Copy local path", " 🟢"},
 		{"l", "Copy GitHub link", ""},
+		{"r", "Copy raw plugin.json URL", ""},
 	}
 	for _, h := range pluginKeys {
 		desc := HelpTextStyle.Render(h.desc)
@@ -134,13 +148,14 @@ func (m Model) generateHelpSections() string {
 		}
 		b.WriteString(fmt.Sprintf("    %s  %s\n", KeyStyle.Width(16).Render(h.key), desc))
 	}
-	b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", 56)))
+	b.WriteString(dividerStyle.Render("  " + strings.Repeat(ruleChar, 56)))
 	b.WriteString("\n")
 
 	// Marketplace Actions section
 	b.WriteString(HelpSectionStyle.Render("  🏪 Marketplace Actions ") + contextStyle.Render("(marketplace detail)"))
 	b.WriteString("\n")
 	marketplaceKeys := []struct{ key, desc string }{
+		{"Enter", "Browse this marketplace's plugins"},
 		{"c", "Copy marketplace install command"},
 		{"f", "Filter plugins by this marketplace"},
 		{"g", "Open on GitHub"},
@@ -149,35 +164,45 @@ func (m Model) generateHelpSections() string {
 	for _, h := range marketplaceKeys {
 		b.WriteString(fmt.Sprintf("    %s  %s\n", KeyStyle.Width(16).Render(h.key), HelpTextStyle.Render(h.desc)))
 	}
-	b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", 56)))
+	b.WriteString(dividerStyle.Render("  " + strings.Repeat(ruleChar, 56)))
 	b.WriteString("\n")
 
 	// Display & Filters section
 	b.WriteString(HelpSectionStyle.Render("  🎨 Display & Views ") + contextStyle.Render("(plugin list)"))
 	b.WriteString("\n")
 	displayKeys := []struct{ key, desc string }{
-		{"Tab →", "Next view (All/Discover/Ready/Installed)"},
+		{"Tab →", "Next view (All/Discover/Ready/Installed/★)"},
 		{"Shift+Tab ←", "Previous view"},
 		{"Shift+V", "Toggle display mode (card/slim)"},
+		{"Ctrl+o", "Show selected description (slim)"},
+		{"Shift+S Ctrl+s", "Sort: relevance / name / marketplace / recent"},
+		{"Ctrl+b", "Toggle installed-first ranking"},
+		{"Shift+X", "Export results to plum-export.md/.json"},
+		{"Ctrl+x", "Switch export format (Markdown/JSON)"},
 		{"@marketplace", "Filter by marketplace (in search)"},
+		{"#category", "Filter by category (in search)"},
+		{"+tag", "Filter by keyword or tag (in search)"},
+		{"author:name", "Filter by author (in search)"},
+		{"/regex/", "Match name and description by regexp"},
 	}
 	for _, h := range displayKeys {
 		b.WriteString(fmt.Sprintf("    %s  %s\n", KeyStyle.Width(16).Render(h.key), HelpTextStyle.Render(h.desc)))
 	}
-	b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", 56)))
+	b.WriteString(dividerStyle.Render("  " + strings.Repeat(ruleChar, 56)))
 	b.WriteString("\n")
 
-	// Marketplace Sorting section
-	b.WriteString(HelpSectionStyle.Render("  🔄 Marketplace Sorting ") + contextStyle.Render("(marketplace list)"))
+	// Marketplace Sorting & Filtering section
+	b.WriteString(HelpSectionStyle.Render("  🔄 Marketplace Sorting & Filtering ") + contextStyle.Render("(marketplace list)"))
 	b.WriteString("\n")
 	sortKeys := []struct{ key, desc string }{
 		{"Tab →", "Next sort order (Plugins/Stars/Name/Updated)"},
 		{"Shift+Tab ←", "Previous sort order"},
+		{"f", "Cycle filter (All/Installed/Available)"},
 	}
 	for _, h := range sortKeys {
 		b.WriteString(fmt.Sprintf("    %s  %s\n", KeyStyle.Width(16).Render(h.key), HelpTextStyle.Render(h.desc)))
 	}
-	b.WriteString(dividerStyle.Render("  " + strings.Repeat("─", 56)))
+	b.WriteString(dividerStyle.Render("  " + strings.Repeat(ruleChar, 56)))
 	b.WriteString("\n")
 
 	// System section
@@ -185,6 +210,7 @@ func (m Model) generateHelpSections() string {
 	b.WriteString("\n")
 	systemKeys := []struct{ key, desc string }{
 		{"Shift+U", "Refresh marketplaces"},
+		{"e", "Edit settings.json (in help)"},
 		{"Esc", "Back / Clear / Cancel"},
 		{"Ctrl+c / q", "Quit"},
 	}
@@ -200,36 +226,5 @@ func (m Model) renderHelpScrollbar() string {
 	if m.helpViewport.Height <= 0 || (m.helpViewport.AtTop() && m.helpViewport.AtBottom()) {
 		return ""
 	}
-
-	visibleHeight := m.helpViewport.Height
-	scrollPercent := m.helpViewport.ScrollPercent()
-	totalHeight := visibleHeight * 2
-
-	thumbHeight := (visibleHeight * visibleHeight) / totalHeight
-	if thumbHeight < 1 {
-		thumbHeight = 1
-	}
-	if thumbHeight > visibleHeight {
-		thumbHeight = visibleHeight
-	}
-
-	trackHeight := visibleHeight - thumbHeight
-	thumbPos := int(float64(trackHeight) * scrollPercent)
-
-	thumbStyle := lipgloss.NewStyle().Foreground(PlumBright)
-	trackStyle := lipgloss.NewStyle().Foreground(BorderSubtle)
-
-	var scrollbar strings.Builder
-	for i := 0; i < visibleHeight; i++ {
-		if i >= thumbPos && i < thumbPos+thumbHeight {
-			scrollbar.WriteString(thumbStyle.Render("█"))
-		} else {
-			scrollbar.WriteString(trackStyle.Render("░"))
-		}
-		if i < visibleHeight-1 {
-			scrollbar.WriteString("\n")
-		}
-	}
-
-	return " " + scrollbar.String()
+	return renderScrollbar(m.helpViewport.Height, m.helpContentLines, m.helpViewport.ScrollPercent())
 }
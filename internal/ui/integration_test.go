@@ -23,12 +23,25 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/export"
+	"github.com/itsdevcoffee/plum/internal/marketplace"
 	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/search"
+	"github.com/itsdevcoffee/plum/internal/settings"
 )
 
 // TestInitialLoad verifies the application initializes correctly
@@ -201,6 +214,189 @@ func TestNavigationFlow(t *testing.T) {
 	})
 }
 
+// TestVimJumpKeys verifies gg and G in the plugin and marketplace lists
+func TestVimJumpKeys(t *testing.T) {
+	press := func(m Model, keys ...tea.KeyMsg) (Model, tea.Cmd) {
+		var cmd tea.Cmd
+		for _, k := range keys {
+			var updated tea.Model
+			updated, cmd = m.Update(k)
+			m = updated.(Model)
+		}
+		return m, cmd
+	}
+	g := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}
+	shiftG := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}}
+
+	newListModel := func() Model {
+		m := NewModel()
+		m.allPlugins = createTestPlugins()
+		m.loading = false
+		m.applyFilter()
+		m.windowHeight = 40
+		m.cursor = 2
+		return m
+	}
+
+	t.Run("gg jumps to top", func(t *testing.T) {
+		m, cmd := press(newListModel(), g, g)
+		if m.cursor != 0 || m.pendingTop != "" {
+			t.Errorf("cursor = %d, pendingTop = %q after gg, want 0 and none", m.cursor, m.pendingTop)
+		}
+		if cmd == nil || m.targetCursorY != 0 {
+			t.Error("gg should animate the cursor like Home")
+		}
+		if m.textInput.Value() != "" {
+			t.Errorf("gg should not type into search, got %q", m.textInput.Value())
+		}
+	})
+
+	t.Run("G jumps to bottom", func(t *testing.T) {
+		m, cmd := press(newListModel(), shiftG)
+		if want := len(m.results) - 1; m.cursor != want {
+			t.Errorf("cursor = %d after G, want %d", m.cursor, want)
+		}
+		if cmd == nil {
+			t.Error("G should animate the cursor like End")
+		}
+	})
+
+	t.Run("g then another key types both", func(t *testing.T) {
+		m, _ := press(newListModel(), g)
+		if m.pendingTop == "" || m.textInput.Value() != "" {
+			t.Fatalf("first g should be held, pendingTop = %q, search = %q", m.pendingTop, m.textInput.Value())
+		}
+		m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+		if m.pendingTop != "" || m.textInput.Value() != "gi" {
+			t.Errorf("search = %q, pendingTop = %q, want \"gi\" and none", m.textInput.Value(), m.pendingTop)
+		}
+	})
+
+	t.Run("g types normally mid-search", func(t *testing.T) {
+		m := newListModel()
+		m.textInput.SetValue("bu")
+		m, _ = press(m, g, g)
+		if m.textInput.Value() != "bugg" || m.pendingTop != "" {
+			t.Errorf("search = %q, want \"bugg\"", m.textInput.Value())
+		}
+	})
+
+	t.Run("marketplace list", func(t *testing.T) {
+		m := NewModel()
+		m.windowHeight = 40
+		m.viewState = ViewMarketplaceList
+		m.marketplaceItems = append(createTestMarketplaceItems(),
+			MarketplaceItem{Name: "test-marketplace-3", DisplayName: "Test Marketplace 3", Status: MarketplaceCached},
+		)
+		last := len(m.FilteredMarketplaceItems()) - 1
+
+		m, _ = press(m, shiftG)
+		if m.marketplaceCursor != last {
+			t.Errorf("cursor = %d after G, want %d", m.marketplaceCursor, last)
+		}
+		m, _ = press(m, g, g)
+		if m.marketplaceCursor != 0 || m.pendingTop != "" {
+			t.Errorf("cursor = %d after gg, want 0", m.marketplaceCursor)
+		}
+
+		// Another key between the g presses cancels the jump
+		m, _ = press(m, g, tea.KeyMsg{Type: tea.KeyDown}, g)
+		if m.marketplaceCursor != 1 || m.pendingTop == "" {
+			t.Errorf("cursor = %d, pendingTop = %q; want 1 and a fresh pending g", m.marketplaceCursor, m.pendingTop)
+		}
+	})
+}
+
+// TestSearchHistory verifies Up and Down recall submitted searches without
+// getting in the way of list navigation
+func TestSearchHistory(t *testing.T) {
+	m := NewModel()
+	m.allPlugins = createTestPlugins()
+	m.loading = false
+	m.applyFilter()
+	m.windowHeight = 40
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	up := tea.KeyMsg{Type: tea.KeyUp}
+
+	// Submit three searches by opening a result for each
+	for _, q := range []string{"test", "plugin", "tool"} {
+		m.viewState = ViewList
+		m.textInput.SetValue(q)
+		m.results = m.filteredSearch(q)
+		press(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	if got := strings.Join(m.searchHistory, ","); got != "test,plugin,tool" {
+		t.Fatalf("searchHistory = %s", got)
+	}
+
+	// Back to the list with the search cleared
+	m.viewState = ViewList
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.textInput.Value() != "" {
+		t.Fatalf("esc should clear the search, got %q", m.textInput.Value())
+	}
+
+	for _, want := range []string{"tool", "plugin", "test", "test"} {
+		press(up)
+		if got := m.textInput.Value(); got != want {
+			t.Fatalf("Up recalled %q, want %q", got, want)
+		}
+		if m.cursor != 0 || len(m.results) == 0 {
+			t.Fatalf("recalled search should run with the cursor at the top, cursor %d results %d", m.cursor, len(m.results))
+		}
+	}
+
+	// Down steps forward again, then back to the empty draft
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	for _, want := range []string{"plugin", "tool", ""} {
+		press(down)
+		if got := m.textInput.Value(); got != want {
+			t.Fatalf("Down recalled %q, want %q", got, want)
+		}
+	}
+	if m.cursor != 0 {
+		t.Fatalf("Down back to the draft should keep the cursor at the top, got %d", m.cursor)
+	}
+	// Past the draft, Down navigates the list again
+	press(down)
+	if m.cursor != 1 || m.textInput.Value() != "" {
+		t.Fatalf("Down after the draft should move the cursor, got cursor %d search %q", m.cursor, m.textInput.Value())
+	}
+	m.cursor = 0
+	for range 3 {
+		press(up)
+	}
+	if m.textInput.Value() != "test" {
+		t.Fatalf("Up should recall again after Down, got %q", m.textInput.Value())
+	}
+
+	// Editing the recalled query ends the recall; Up navigates again
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	press(up)
+	if m.textInput.Value() != "testx" {
+		t.Errorf("Up with edited text should not recall, got %q", m.textInput.Value())
+	}
+
+	// Below the top of the list, Up moves the cursor even with an empty search
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	m.cursor = 2
+	press(up)
+	if m.cursor != 1 || m.textInput.Value() != "" {
+		t.Errorf("Up should move the cursor, got cursor %d search %q", m.cursor, m.textInput.Value())
+	}
+
+	// Resubmitting moves a query to the newest spot
+	m.recordSearch("plugin")
+	if got := strings.Join(m.searchHistory, ","); got != "test,tool,plugin" {
+		t.Errorf("searchHistory = %s", got)
+	}
+}
+
 // TestViewTransitions verifies navigation between views
 func TestViewTransitions(t *testing.T) {
 	model := NewModel()
@@ -306,6 +502,213 @@ func TestFilterMode(t *testing.T) {
 	})
 }
 
+// TestMarketplacePrefixRespectsFilterMode verifies @marketplace queries still
+// honour the active filter tab
+func TestMarketplacePrefixRespectsFilterMode(t *testing.T) {
+	model := NewModel()
+	model.allPlugins = []plugin.Plugin{
+		{Name: "docker-run", Marketplace: "docker", Installed: true},
+		{Name: "docker-build", Marketplace: "docker"},
+		{Name: "docker-compose", Marketplace: "docker", IsDiscoverable: true},
+		{Name: "docker-lint", Marketplace: "other", Installed: true},
+	}
+	model.loading = false
+
+	tests := []struct {
+		mode  FilterMode
+		query string
+		want  []string
+	}{
+		{FilterAll, "@docker", []string{"docker-run", "docker-build", "docker-compose"}},
+		{FilterInstalled, "@docker", []string{"docker-run"}},
+		{FilterReady, "@docker", []string{"docker-build"}},
+		{FilterDiscover, "@docker", []string{"docker-compose"}},
+		{FilterInstalled, "@docker run", []string{"docker-run"}},
+		{FilterReady, "@docker run", nil},
+		{FilterInstalled, "@other", []string{"docker-lint"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(FilterModeNames[tt.mode]+" "+tt.query, func(t *testing.T) {
+			model.filterMode = tt.mode
+
+			var got []string
+			for _, r := range model.filteredSearch(tt.query) {
+				got = append(got, r.Plugin.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filteredSearch(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+
+			// Tab counts must agree with the filtered results
+			if count := model.getDynamicFilterCounts(tt.query)[tt.mode]; count != len(tt.want) {
+				t.Errorf("count for %s = %d, want %d", FilterModeNames[tt.mode], count, len(tt.want))
+			}
+		})
+	}
+}
+
+// TestCategoryAndTagQueryFilters verifies #category and +tag query tokens
+func TestCategoryAndTagQueryFilters(t *testing.T) {
+	model := NewModel()
+	model.allPlugins = []plugin.Plugin{
+		{Name: "docker-run", Category: "DevOps", Keywords: []string{"containers"}, Marketplace: "docker"},
+		{Name: "docker-docs", Category: "documentation", Tags: []string{"Containers"}, Marketplace: "docker"},
+		{Name: "k8s-deploy", Category: "devops", Keywords: []string{"kubernetes"}, Marketplace: "other"},
+		{Name: "notes", Category: "productivity", Marketplace: "other"},
+	}
+	model.loading = false
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"#devops", []string{"docker-run", "k8s-deploy"}},
+		{"#DevOps", []string{"docker-run", "k8s-deploy"}},
+		{"+containers", []string{"docker-run", "docker-docs"}},
+		{"docker #DevOps", []string{"docker-run"}},
+		{"docker run #devops", []string{"docker-run"}},
+		{"docker deploy #devops", nil},
+		{"docker +containers", []string{"docker-docs", "docker-run"}},
+		{"#devops +kubernetes", []string{"k8s-deploy"}},
+		{"@docker #devops", []string{"docker-run"}},
+		{"@other +containers", nil},
+		{"#security", nil},
+		{"+missing", nil},
+		{"notes #devops", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []string
+			for _, r := range model.filteredSearch(tt.query) {
+				got = append(got, r.Plugin.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filteredSearch(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRegexQueryError verifies an invalid /regex/ shows an error instead of
+// crashing, and a valid one filters the list
+func TestRegexQueryError(t *testing.T) {
+	model := NewModel()
+	model.windowWidth = 100
+	model.windowHeight = 30
+	model.allPlugins = []plugin.Plugin{
+		{Name: "git-hooks", Marketplace: "mkt"},
+		{Name: "docker-run", Marketplace: "mkt"},
+	}
+	model.loading = false
+
+	model.textInput.SetValue("/^git/")
+	model.results = model.filteredSearch(model.textInput.Value())
+	if len(model.results) != 1 || model.results[0].Plugin.Name != "git-hooks" {
+		t.Errorf("valid regex results = %v", model.results)
+	}
+	if err := model.queryError(); err != nil {
+		t.Errorf("valid regex reported error: %v", err)
+	}
+
+	model.textInput.SetValue("/git(/")
+	model.results = model.filteredSearch(model.textInput.Value())
+	if len(model.results) != 0 {
+		t.Errorf("invalid regex should match nothing, got %v", model.results)
+	}
+	if !strings.Contains(model.View(), "invalid regular expression") {
+		t.Error("invalid regex should show an error below the search box")
+	}
+}
+
+// TestRenderHighlightedName verifies matched runes are rendered as separate
+// emphasized segments
+func TestRenderHighlightedName(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(0) // TrueColor, so styles emit escape codes
+	defer lipgloss.SetColorProfile(prev)
+
+	style := PluginNameStyle
+	match := MatchHighlightStyle.Inherit(style)
+
+	tests := []struct {
+		name    string
+		matched []int
+		want    string
+	}{
+		{"test", []int{0, 2, 3}, match.Render("t") + style.Render("e") + match.Render("st")},
+		{"café", []int{3}, style.Render("caf") + match.Render("é")},
+		{"test", nil, style.Render("test")},
+	}
+	for _, tt := range tests {
+		if got := renderHighlightedName(tt.name, tt.matched, style); got != tt.want {
+			t.Errorf("renderHighlightedName(%q, %v) = %q, want %q", tt.name, tt.matched, got, tt.want)
+		}
+	}
+	if match.Render("t") == style.Render("t") {
+		t.Error("matched runes should be styled differently")
+	}
+}
+
+// TestAuthorQueryFilter verifies author: restricts results by author and is
+// stripped from the free text
+func TestAuthorQueryFilter(t *testing.T) {
+	model := NewModel()
+	model.allPlugins = []plugin.Plugin{
+		{Name: "acme-lint", Author: plugin.Author{Name: "Acme Inc"}},
+		{Name: "acme-format", Author: plugin.Author{Company: "ACME"}},
+		{Name: "lint-other", Author: plugin.Author{Name: "Someone Else"}},
+		{Name: "no-author"},
+	}
+	model.loading = false
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"author:acme", []string{"acme-lint", "acme-format"}},
+		{"Author:ACME", []string{"acme-lint", "acme-format"}},
+		{"lint author:acme", []string{"acme-lint"}},
+		{"author:someone lint", []string{"lint-other"}},
+		{"author:nobody", nil},
+		{"format author:someone", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []string
+			for _, r := range model.filteredSearch(tt.query) {
+				got = append(got, r.Plugin.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filteredSearch(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	f := parseQueryFilters("lint author:Acme docker")
+	if f.Text != "lint docker" || strings.Join(f.Authors, ",") != "acme" {
+		t.Errorf("parseQueryFilters = text %q, authors %v; want \"lint docker\", [acme]", f.Text, f.Authors)
+	}
+}
+
+// TestParseQueryFilters verifies filter tokens are split from the free text
+func TestParseQueryFilters(t *testing.T) {
+	f := parseQueryFilters("@mkt docker #DevOps +Containers -compose")
+	if f.Marketplace != "mkt" || f.Text != "docker -compose" {
+		t.Errorf("marketplace/text = %q/%q, want mkt/\"docker -compose\"", f.Marketplace, f.Text)
+	}
+	if strings.Join(f.Categories, ",") != "devops" || strings.Join(f.Tags, ",") != "containers" {
+		t.Errorf("categories/tags = %v/%v, want [devops]/[containers]", f.Categories, f.Tags)
+	}
+
+	// A lone prefix character is plain text
+	if f := parseQueryFilters("c# +"); f.active() || f.Text != "c# +" {
+		t.Errorf("parseQueryFilters(%q) = %+v, want plain text", "c# +", f)
+	}
+}
+
 // TestWindowResize verifies responsive behavior
 func TestWindowResize(t *testing.T) {
 	model := NewModel()
@@ -339,6 +742,140 @@ func TestWindowResize(t *testing.T) {
 	}
 }
 
+// TestWindowResizeKeepsCursorVisible verifies resizing a scrolled list
+// re-clamps the scroll offsets so the selection stays on screen
+func TestWindowResizeKeepsCursorVisible(t *testing.T) {
+	model := NewModel()
+	model.loading = false
+	for i := 0; i < 60; i++ {
+		model.allPlugins = append(model.allPlugins, plugin.Plugin{Name: fmt.Sprintf("plugin-%02d", i)})
+	}
+	model.applyFilter()
+	for i := 0; i < 30; i++ {
+		model.marketplaceItems = append(model.marketplaceItems, MarketplaceItem{Name: fmt.Sprintf("mkt-%02d", i)})
+	}
+
+	resize := func(height int) {
+		updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: height})
+		model = updatedModel.(Model)
+	}
+	checkVisible := func(label string, cursor, offset, total int) {
+		t.Helper()
+		maxVisible := model.maxVisibleItems()
+		if offset < 0 || offset > total-maxVisible && offset != 0 {
+			t.Errorf("%s: offset %d out of bounds for %d items, %d visible", label, offset, total, maxVisible)
+		}
+		if cursor < offset || cursor >= offset+maxVisible {
+			t.Errorf("%s: cursor %d off screen (offset %d, %d visible)", label, cursor, offset, maxVisible)
+		}
+	}
+
+	resize(80)
+	model.cursor = 40
+	model.UpdateScroll()
+	model.marketplaceCursor = 25
+	model.UpdateMarketplaceScroll()
+
+	for _, height := range []int{20, 14, 200, 30} {
+		resize(height)
+		label := fmt.Sprintf("height %d", height)
+		checkVisible(label+" plugins", model.cursor, model.scrollOffset, len(model.results))
+		checkVisible(label+" marketplaces", model.marketplaceCursor, model.marketplaceScrollOffset, len(model.marketplaceItems))
+		if want := float64(model.cursor - model.scrollOffset); model.cursorY != want || model.targetCursorY != want {
+			t.Errorf("%s: animated cursor = %v/%v, want %v", label, model.cursorY, model.targetCursorY, want)
+		}
+		// Rendering must not slice past the results
+		_ = model.VisibleResults()
+		_ = model.VisibleMarketplaceItems()
+	}
+}
+
+// TestSearchDebounce verifies rapid keystrokes update the input immediately
+// but only the last keystroke's debounce message re-filters the list
+func TestSearchDebounce(t *testing.T) {
+	model := NewModel()
+	model.windowWidth = 100
+	model.windowHeight = 40
+	model.allPlugins = []plugin.Plugin{
+		{Name: "docker-run"},
+		{Name: "dotenv"},
+		{Name: "notes"},
+	}
+	model.loading = false
+	model.applyFilter()
+	initial := len(model.results)
+
+	for _, r := range "doc" {
+		updatedModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updatedModel.(Model)
+		if cmd == nil {
+			t.Fatalf("keystroke %q should schedule a debounced search", r)
+		}
+	}
+
+	if model.textInput.Value() != "doc" {
+		t.Errorf("input = %q, want it updated immediately", model.textInput.Value())
+	}
+	if len(model.results) != initial {
+		t.Errorf("results should not be filtered before the debounce fires, got %d", len(model.results))
+	}
+	if model.searchSeq != 3 {
+		t.Fatalf("searchSeq = %d, want 3", model.searchSeq)
+	}
+
+	// Stale ticks from earlier keystrokes are ignored
+	for seq := 1; seq < model.searchSeq; seq++ {
+		updatedModel, _ := model.Update(searchDebounceMsg{seq: seq})
+		model = updatedModel.(Model)
+		if len(model.results) != initial {
+			t.Fatalf("stale debounce %d ran the search", seq)
+		}
+	}
+
+	// The latest tick runs the search once, for the final value
+	updatedModel, _ := model.Update(searchDebounceMsg{seq: model.searchSeq})
+	model = updatedModel.(Model)
+	if len(model.results) != 1 || model.results[0].Plugin.Name != "docker-run" {
+		t.Errorf("results after debounce = %v, want only docker-run", model.results)
+	}
+}
+
+// TestSearchDebounce_EnterFlushes verifies Enter pressed before the debounce
+// fires opens a result for the typed query, not the previous one
+func TestSearchDebounce_EnterFlushes(t *testing.T) {
+	model := NewModel()
+	model.windowWidth = 100
+	model.windowHeight = 40
+	model.allPlugins = []plugin.Plugin{
+		{Name: "alpha"},
+		{Name: "docker-run"},
+	}
+	model.loading = false
+	model.applyFilter()
+
+	for _, r := range "dock" {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updatedModel.(Model)
+	}
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updatedModel.(Model)
+
+	if model.viewState != ViewDetail {
+		t.Fatalf("viewState = %v, want ViewDetail", model.viewState)
+	}
+	if p := model.SelectedPlugin(); p == nil || p.Name != "docker-run" {
+		t.Fatalf("Enter opened %v, want docker-run", p)
+	}
+
+	// The debounce tick arriving afterwards changes nothing
+	results := model.results
+	updatedModel, _ = model.Update(searchDebounceMsg{seq: model.searchSeq})
+	model = updatedModel.(Model)
+	if len(model.results) != len(results) || model.SelectedPlugin().Name != "docker-run" {
+		t.Errorf("late debounce changed the selection to %v", model.SelectedPlugin())
+	}
+}
+
 // TestSelectedPlugin verifies plugin selection logic
 func TestSelectedPlugin(t *testing.T) {
 	model := NewModel()
@@ -472,13 +1009,20 @@ func TestCopyFunctionality(t *testing.T) {
 			t.Skip("Test requires non-discoverable plugin")
 		}
 
-		// Note: Can't actually test clipboard.WriteAll in unit tests
-		// but we can verify the command format would be correct
-		expectedCmd := "/plugin install " + p.Name + "@" + p.Marketplace
-		actualCmd := p.InstallCommand()
+		var copied string
+		origWrite := clipboardWrite
+		clipboardWrite = func(text string) error { copied = text; return nil }
+		defer func() { clipboardWrite = origWrite }()
+
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+		model = updatedModel.(Model)
 
-		if actualCmd != expectedCmd {
-			t.Errorf("Expected install command %q, got %q", expectedCmd, actualCmd)
+		expectedCmd := "/plugin install " + p.Name + "@" + p.Marketplace
+		if copied != expectedCmd {
+			t.Errorf("Expected install command %q to be copied, got %q", expectedCmd, copied)
+		}
+		if !model.copiedFlash {
+			t.Error("Expected copied flash after c")
 		}
 	})
 
@@ -522,6 +1066,76 @@ func TestCopyFunctionality(t *testing.T) {
 	})
 }
 
+// TestCopyActionsUseClipboardWriter checks the text each copy key writes
+// and the flash it shows, for both successful and failed writes
+func TestCopyActionsUseClipboardWriter(t *testing.T) {
+	installed := plugin.Plugin{
+		Name: "local", Marketplace: "mkt", Installed: true, InstallPath: "/tmp/plugins/local",
+		MarketplaceRepo: "https://github.com/owner/repo",
+	}
+	discoverable := plugin.Plugin{
+		Name: "remote", Marketplace: "new-mkt", MarketplaceSource: "owner/new-mkt", IsDiscoverable: true,
+	}
+	marketplaceItem := &MarketplaceItem{Name: "new-mkt", Repo: "https://github.com/owner/new-mkt", Status: MarketplaceAvailable}
+
+	tests := []struct {
+		name  string
+		view  ViewState
+		p     plugin.Plugin
+		key   rune
+		want  string
+		flash func(Model) bool
+	}{
+		{"c discoverable", ViewDetail, discoverable, 'c', "/plugin marketplace add owner/new-mkt", func(m Model) bool { return m.copiedFlash }},
+		{"y discoverable", ViewDetail, discoverable, 'y', "/plugin install remote@new-mkt", func(m Model) bool { return m.copiedFlash }},
+		{"l link", ViewDetail, installed, 'l', "https://github.com/owner/repo/tree/main/plugins/local", func(m Model) bool { return m.linkCopiedFlash }},
+		{"p path", ViewDetail, installed, 'p', "/tmp/plugins/local", func(m Model) bool { return m.pathCopiedFlash }},
+		{"marketplace c", ViewMarketplaceDetail, plugin.Plugin{}, 'c', "/plugin marketplace add owner/new-mkt", func(m Model) bool { return m.copiedFlash }},
+	}
+
+	for _, tt := range tests {
+		for _, fail := range []bool{false, true} {
+			name := tt.name
+			if fail {
+				name += " error"
+			}
+			t.Run(name, func(t *testing.T) {
+				var copied []string
+				origWrite := clipboardWrite
+				clipboardWrite = func(text string) error {
+					copied = append(copied, text)
+					if fail {
+						return fmt.Errorf("no clipboard")
+					}
+					return nil
+				}
+				defer func() { clipboardWrite = origWrite }()
+
+				model := NewModel()
+				model.allPlugins = []plugin.Plugin{tt.p}
+				model.loading = false
+				model.applyFilter()
+				model.viewState = tt.view
+				model.selectedMarketplace = marketplaceItem
+
+				updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}})
+				model = updatedModel.(Model)
+
+				if len(copied) != 1 || copied[0] != tt.want {
+					t.Fatalf("copied %q, want %q", copied, tt.want)
+				}
+				if fail {
+					if !model.clipboardErrorFlash || tt.flash(model) {
+						t.Error("failed write should show the clipboard error, not the copied flash")
+					}
+				} else if !tt.flash(model) || model.clipboardErrorFlash {
+					t.Error("successful write should show the copied flash")
+				}
+			})
+		}
+	}
+}
+
 // TestMarketplaceBrowser verifies marketplace browser functionality
 func TestMarketplaceBrowser(t *testing.T) {
 	model := NewModel()
@@ -564,47 +1178,456 @@ func TestMarketplaceBrowser(t *testing.T) {
 	})
 }
 
-// TestDisplayModeToggle verifies view mode switching
-func TestDisplayModeToggle(t *testing.T) {
-	model := NewModel()
-	model.allPlugins = createTestPlugins()
-	model.loading = false
-	model.applyFilter()
+// TestMarketplaceDetailTopPlugins verifies the detail view lists plugins from
+// the cached manifest
+func TestMarketplaceDetailTopPlugins(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
 
-	initialMode := model.displayMode
+	manifest := &marketplace.MarketplaceManifest{Name: "test-marketplace-1"}
+	for _, name := range []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta"} {
+		manifest.Plugins = append(manifest.Plugins, marketplace.MarketplacePlugin{Name: name})
+	}
+	if err := marketplace.SaveToCache("test-marketplace-1", manifest); err != nil {
+		t.Fatal(err)
+	}
 
-	// Press Shift+V to toggle display mode
-	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}}
-	updatedModel, _ := model.Update(msg)
-	model = updatedModel.(Model)
+	model := NewModel()
+	model.windowWidth = 100
+	model.windowHeight = 40
+	model.viewState = ViewMarketplaceList
+	model.marketplaceItems = createTestMarketplaceItems()
 
-	if model.displayMode == initialMode {
-		t.Error("Display mode should toggle after Shift+V")
+	openDetail := func(cursor int) string {
+		model.viewState = ViewMarketplaceList
+		model.marketplaceCursor = cursor
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = updatedModel.(Model)
+		return model.marketplaceDetailView()
 	}
 
-	// Toggle again should return to original
-	updatedModel, _ = model.Update(msg)
-	model = updatedModel.(Model)
+	view := openDetail(0)
+	for _, name := range []string{"alpha", "beta", "gamma", "delta", "epsilon"} {
+		if !strings.Contains(view, name) {
+			t.Errorf("detail view should list %s", name)
+		}
+	}
+	if strings.Contains(view, "zeta") {
+		t.Errorf("detail view should list at most %d plugins", marketplaceTopPluginCount)
+	}
+	if !strings.Contains(view, "and 5 more") || !strings.Contains(view, "'f' to filter") {
+		t.Errorf("detail view should show the remaining count and filter hint:\n%s", view)
+	}
 
-	if model.displayMode != initialMode {
-		t.Error("Display mode should toggle back to original")
+	// test-marketplace-2 has no cached manifest
+	view = openDetail(1)
+	if !strings.Contains(view, "Not cached yet") || strings.Contains(view, "alpha") {
+		t.Errorf("uncached marketplace should say it isn't cached:\n%s", view)
 	}
 }
 
-// TestQuitBehavior verifies quit and escape handling
-func TestQuitBehavior(t *testing.T) {
-	t.Run("quit from list view", func(t *testing.T) {
-		model := NewModel()
-		model.viewState = ViewList
-		model.loading = false
-
-		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}
-		_, cmd := model.Update(msg)
+// TestMarketplacePluginsDrillIn verifies enter in the marketplace detail
+// lists the marketplace's plugins and opens plugin detail from there
+func TestMarketplacePluginsDrillIn(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
 
-		if cmd == nil {
-			t.Error("Expected quit command, got nil")
-		}
-	})
+	manifest := &marketplace.MarketplaceManifest{Name: "test-marketplace-1"}
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		manifest.Plugins = append(manifest.Plugins, marketplace.MarketplacePlugin{Name: name, Version: "1.0.0"})
+	}
+	if err := marketplace.SaveToCache("test-marketplace-1", manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	model := NewModel()
+	model.loading = false
+	model.allPlugins = []plugin.Plugin{
+		{Name: "beta", Marketplace: "test-marketplace-1", Version: "2.0.0", Installed: true},
+		{Name: "other", Marketplace: "elsewhere", Version: "1.0.0"},
+	}
+	model.results = model.filteredSearch("")
+	updatedModel, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	model = updatedModel.(Model)
+	model.marketplaceItems = createTestMarketplaceItems()
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updatedModel, _ := model.Update(msg)
+		model = updatedModel.(Model)
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+
+	// Marketplace list -> marketplace detail -> plugin list
+	model.viewState = ViewMarketplaceList
+	press(enter)
+	press(enter)
+	if !model.marketplacePluginsOpen || len(model.marketplacePlugins) != 3 {
+		t.Fatalf("enter should open the plugin list, got open=%v plugins=%d", model.marketplacePluginsOpen, len(model.marketplacePlugins))
+	}
+	view := model.marketplaceDetailView()
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		if !strings.Contains(view, name) {
+			t.Errorf("plugin list should show %s", name)
+		}
+	}
+	if strings.Contains(view, "other") {
+		t.Error("plugin list should only show this marketplace's plugins")
+	}
+
+	// Loaded plugins keep their install state
+	press(down)
+	if p := model.SelectedMarketplacePlugin(); p == nil || p.Name != "beta" || !p.Installed || p.Version != "2.0.0" {
+		t.Fatalf("selected plugin = %+v, want the loaded beta", p)
+	}
+
+	// Drill into plugin detail and back
+	press(enter)
+	if model.viewState != ViewDetail {
+		t.Fatalf("enter should open plugin detail, got view %v", model.viewState)
+	}
+	if p := model.SelectedPlugin(); p == nil || p.Name != "beta" {
+		t.Fatalf("detail should show beta, got %+v", p)
+	}
+	if !strings.Contains(model.detailView(), "beta") {
+		t.Error("detail view should render the selected marketplace plugin")
+	}
+	press(esc)
+	if model.viewState != ViewMarketplaceDetail || !model.marketplacePluginsOpen || model.marketplacePluginCursor != 1 {
+		t.Fatalf("esc should return to the plugin list, got view %v open=%v cursor=%d",
+			model.viewState, model.marketplacePluginsOpen, model.marketplacePluginCursor)
+	}
+	if model.detailFromMarketplace {
+		t.Error("leaving detail should hand selection back to the plugin list")
+	}
+
+	// esc closes the plugin list, then returns to the marketplace list
+	press(esc)
+	if model.marketplacePluginsOpen || model.viewState != ViewMarketplaceDetail {
+		t.Fatalf("esc should close the plugin list first")
+	}
+	press(esc)
+	if model.viewState != ViewMarketplaceList {
+		t.Fatalf("esc should return to the marketplace list, got %v", model.viewState)
+	}
+
+	// test-marketplace-2 has no cached manifest
+	model.marketplaceCursor = 1
+	press(enter)
+	press(enter)
+	if !strings.Contains(model.marketplaceDetailView(), "Not cached yet") {
+		t.Error("uncached marketplace should prompt for a refresh")
+	}
+	press(enter)
+	if model.viewState != ViewMarketplaceDetail {
+		t.Error("enter should do nothing without plugins")
+	}
+}
+
+// TestMarketplaceFilter verifies the browser's installed-status filter
+func TestMarketplaceFilter(t *testing.T) {
+	model := NewModel()
+	model.windowWidth = 100
+	model.windowHeight = 30
+	model.viewState = ViewMarketplaceList
+	model.marketplaceItems = append(createTestMarketplaceItems(),
+		MarketplaceItem{Name: "test-marketplace-3", DisplayName: "Test Marketplace 3", Status: MarketplaceCached},
+		MarketplaceItem{Name: "test-marketplace-4", DisplayName: "Test Marketplace 4", Status: MarketplaceNew},
+	)
+
+	counts := model.MarketplaceFilterCounts()
+	if counts[MarketplaceFilterAll] != 4 || counts[MarketplaceFilterInstalled] != 1 || counts[MarketplaceFilterAvailable] != 3 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+
+	tests := []struct {
+		mode  MarketplaceFilterMode
+		names []string
+	}{
+		{MarketplaceFilterInstalled, []string{"test-marketplace-1"}},
+		{MarketplaceFilterAvailable, []string{"test-marketplace-2", "test-marketplace-3", "test-marketplace-4"}},
+		{MarketplaceFilterAll, []string{"test-marketplace-1", "test-marketplace-2", "test-marketplace-3", "test-marketplace-4"}},
+	}
+
+	for _, tt := range tests {
+		name := MarketplaceFilterModeNames[tt.mode]
+		t.Run(name, func(t *testing.T) {
+			model.marketplaceCursor = 1
+
+			// Press f to cycle to the next filter
+			updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+			model = updatedModel.(Model)
+
+			if model.marketplaceFilterMode != tt.mode {
+				t.Fatalf("filter mode = %v, want %v", model.marketplaceFilterMode, tt.mode)
+			}
+			if model.marketplaceCursor != 0 {
+				t.Errorf("cursor should reset when the filter changes, got %d", model.marketplaceCursor)
+			}
+
+			var got []string
+			for _, item := range model.FilteredMarketplaceItems() {
+				got = append(got, item.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.names, ",") {
+				t.Errorf("filtered items = %v, want %v", got, tt.names)
+			}
+
+			label := fmt.Sprintf("%s (%d)", name, counts[tt.mode])
+			if tabs := model.renderMarketplaceFilterTabs(); !strings.Contains(tabs, label) {
+				t.Errorf("filter tabs should show %q, got %q", label, tabs)
+			}
+		})
+	}
+}
+
+// TestMarketplaceStatsLoaded verifies fetched GitHub stats land on the right
+// marketplace and re-sort the list when sorting by stars
+func TestMarketplaceStatsLoaded(t *testing.T) {
+	model := NewModel()
+	model.windowWidth = 100
+	model.windowHeight = 30
+	model.viewState = ViewMarketplaceList
+	model.marketplaceItems = []MarketplaceItem{
+		{Name: "alpha", DisplayName: "Alpha", StatsLoading: true, GitHubStats: &marketplace.GitHubStats{Stars: 10}},
+		{Name: "beta", DisplayName: "Beta", StatsLoading: true, GitHubStats: &marketplace.GitHubStats{Stars: 5}},
+		{Name: "gamma", DisplayName: "Gamma", StatsLoading: true},
+	}
+	model.marketplaceSortMode = SortByStars
+	model.ApplyMarketplaceSort()
+	model.marketplaceCursor = 0 // alpha
+
+	send := func(msg statsLoadedMsg) {
+		t.Helper()
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+	item := func(name string) MarketplaceItem {
+		t.Helper()
+		for _, it := range model.marketplaceItems {
+			if it.Name == name {
+				return it
+			}
+		}
+		t.Fatalf("no marketplace %q", name)
+		return MarketplaceItem{}
+	}
+
+	send(statsLoadedMsg{name: "gamma", stats: &marketplace.GitHubStats{Stars: 50, Forks: 7}})
+	gamma := item("gamma")
+	if gamma.StatsLoading || gamma.GitHubStats == nil || gamma.GitHubStats.Stars != 50 {
+		t.Fatalf("gamma should have its fetched stats, got %+v", gamma)
+	}
+	if !item("alpha").StatsLoading || item("alpha").GitHubStats.Stars != 10 {
+		t.Error("other marketplaces should be untouched")
+	}
+	if model.marketplaceItems[0].Name != "gamma" {
+		t.Errorf("sorting by stars should move gamma first, got %s", model.marketplaceItems[0].Name)
+	}
+	if model.marketplaceItems[model.marketplaceCursor].Name != "alpha" {
+		t.Errorf("cursor should stay on alpha, got %s", model.marketplaceItems[model.marketplaceCursor].Name)
+	}
+
+	// A failed fetch keeps the previous stats and records the error
+	send(statsLoadedMsg{name: "beta", err: errors.New("rate limited")})
+	beta := item("beta")
+	if beta.StatsLoading || beta.StatsError == nil || beta.GitHubStats == nil || beta.GitHubStats.Stars != 5 {
+		t.Errorf("beta should keep its stats and record the error, got %+v", beta)
+	}
+
+	// Stats for a marketplace that isn't listed are ignored
+	before := fmt.Sprint(model.marketplaceItems)
+	send(statsLoadedMsg{name: "unknown", stats: &marketplace.GitHubStats{Stars: 99}})
+	if fmt.Sprint(model.marketplaceItems) != before {
+		t.Error("stats for an unknown marketplace should change nothing")
+	}
+}
+
+// TestLoadMarketplaceStats verifies only marketplaces without cached stats
+// are fetched, with bounded concurrency
+func TestLoadMarketplaceStats(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []string
+	inFlight, maxInFlight := 0, 0
+	original := fetchMarketplaceStats
+	fetchMarketplaceStats = func(name, repo string) (*marketplace.GitHubStats, error) {
+		mu.Lock()
+		fetched = append(fetched, name)
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return &marketplace.GitHubStats{Stars: len(name)}, nil
+	}
+	defer func() { fetchMarketplaceStats = original }()
+
+	model := NewModel()
+	model.marketplaceItems = []MarketplaceItem{{Name: "cached", Repo: "owner/cached", statsCached: true}}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("mkt-%d", i)
+		model.marketplaceItems = append(model.marketplaceItems, MarketplaceItem{Name: name, Repo: "owner/" + name})
+	}
+
+	cmd := model.loadMarketplaceStats()
+	if model.marketplaceItems[0].StatsLoading {
+		t.Error("a marketplace with cached stats should not be marked loading")
+	}
+	if !model.marketplaceItems[1].StatsLoading {
+		t.Error("marketplaces without cached stats should be marked loading")
+	}
+
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 10 {
+		t.Fatalf("expected a batch of 10 fetches, got %T", cmd())
+	}
+	msgs := make(chan tea.Msg, len(batch))
+	for _, c := range batch {
+		go func(c tea.Cmd) { msgs <- c() }(c)
+	}
+	for range batch {
+		msg := (<-msgs).(statsLoadedMsg)
+		if msg.err != nil || msg.stats == nil || msg.stats.Stars != len(msg.name) {
+			t.Errorf("unexpected message %+v", msg)
+		}
+	}
+
+	if len(fetched) != 10 {
+		t.Errorf("fetched %d marketplaces, want 10: %v", len(fetched), fetched)
+	}
+	if maxInFlight > marketplaceStatsConcurrency {
+		t.Errorf("%d fetches ran at once, want at most %d", maxInFlight, marketplaceStatsConcurrency)
+	}
+}
+
+// TestCountInstalledByMarketplace verifies installs are attributed to the
+// marketplace after the last '@'
+func TestCountInstalledByMarketplace(t *testing.T) {
+	installed := &config.InstalledPluginsV2{Plugins: map[string][]config.PluginInstall{
+		"alpha@mkt":              nil,
+		"beta@mkt":               nil,
+		"gamma@team@other":       nil, // plugin name containing '@'
+		"@scope/delta@other":     nil, // leading '@' in the plugin name
+		"@mkt":                   nil, // no plugin name
+		"orphan@":                nil, // no marketplace
+		"no-separator":           nil,
+		"email@example.com@mail": nil,
+	}}
+
+	got := countInstalledByMarketplace(installed)
+	want := map[string]int{"mkt": 2, "other": 2, "mail": 1}
+	if len(got) != len(want) {
+		t.Errorf("counts = %v, want %v", got, want)
+	}
+	for name, count := range want {
+		if got[name] != count {
+			t.Errorf("counts[%q] = %d, want %d", name, got[name], count)
+		}
+	}
+
+	if counts := countInstalledByMarketplace(nil); len(counts) != 0 {
+		t.Errorf("nil registry should give no counts, got %v", counts)
+	}
+}
+
+// TestDisplayModeToggle verifies view mode switching
+func TestDisplayModeToggle(t *testing.T) {
+	model := NewModel()
+	model.allPlugins = createTestPlugins()
+	model.loading = false
+	model.applyFilter()
+
+	initialMode := model.displayMode
+
+	// Press Shift+V to toggle display mode
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}}
+	updatedModel, _ := model.Update(msg)
+	model = updatedModel.(Model)
+
+	if model.displayMode == initialMode {
+		t.Error("Display mode should toggle after Shift+V")
+	}
+
+	// Toggle again should return to original
+	updatedModel, _ = model.Update(msg)
+	model = updatedModel.(Model)
+
+	if model.displayMode != initialMode {
+		t.Error("Display mode should toggle back to original")
+	}
+}
+
+// TestSlimDescriptionToggle verifies the selected slim row can show its description
+func TestSlimDescriptionToggle(t *testing.T) {
+	model := NewModel()
+	model.allPlugins = createTestPlugins()
+	model.loading = false
+	model.windowHeight = 40
+	model.applyFilter()
+
+	if model.displayMode != DisplaySlim {
+		t.Fatal("Expected slim display mode by default")
+	}
+
+	first := model.SelectedPlugin().Description
+	if strings.Contains(model.listView(), first) {
+		t.Fatalf("Description %q should be hidden before toggling", first)
+	}
+
+	maxBefore := model.maxVisibleItems()
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	model = updatedModel.(Model)
+
+	if !model.slimExpanded {
+		t.Fatal("Ctrl+O should enable slim description expansion")
+	}
+	if model.maxVisibleItems() != maxBefore-1 {
+		t.Errorf("Expected maxVisibleItems to drop by one, got %d (was %d)", model.maxVisibleItems(), maxBefore)
+	}
+	if !strings.Contains(model.listView(), first) {
+		t.Errorf("Selected row should render description %q", first)
+	}
+
+	// Moving the cursor collapses the previous row and expands the new one
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = updatedModel.(Model)
+
+	second := model.SelectedPlugin().Description
+	view := model.listView()
+	if !strings.Contains(view, second) {
+		t.Errorf("Newly selected row should render description %q", second)
+	}
+	if strings.Contains(view, first) {
+		t.Errorf("Previous row description %q should collapse", first)
+	}
+
+	// Toggling again hides descriptions
+	updatedModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	model = updatedModel.(Model)
+	if strings.Contains(model.listView(), second) {
+		t.Error("Description should be hidden after toggling off")
+	}
+}
+
+// TestQuitBehavior verifies quit and escape handling
+func TestQuitBehavior(t *testing.T) {
+	t.Run("quit from list view", func(t *testing.T) {
+		model := NewModel()
+		model.viewState = ViewList
+		model.loading = false
+
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}
+		_, cmd := model.Update(msg)
+
+		if cmd == nil {
+			t.Error("Expected quit command, got nil")
+		}
+	})
 
 	t.Run("escape clears search before quitting", func(t *testing.T) {
 		model := NewModel()
@@ -843,3 +1866,1561 @@ func createTestMarketplaceItems() []MarketplaceItem {
 		},
 	}
 }
+
+// TestRegistryCheckTimeout verifies a stalled registry check doesn't block startup
+func TestRegistryCheckTimeout(t *testing.T) {
+	origCheck := checkForNewMarketplaces
+	origTimeout := RegistryCheckTimeout
+	defer func() {
+		checkForNewMarketplaces = origCheck
+		RegistryCheckTimeout = origTimeout
+	}()
+	t.Setenv("PLUM_REGISTRY_TIMEOUT", "")
+
+	var fetchErr error
+	checkForNewMarketplaces = func(ctx context.Context) ([]PopularMarketplace, int, error) {
+		// A stalled fetch that only ends when its context is cancelled
+		<-ctx.Done()
+		fetchErr = ctx.Err()
+		return nil, 3, fetchErr
+	}
+	RegistryCheckTimeout = 20 * time.Millisecond
+
+	start := time.Now()
+	msg := checkRegistryForUpdates()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("registry check should time out quickly, took %v", elapsed)
+	}
+	if !errors.Is(fetchErr, context.DeadlineExceeded) {
+		t.Errorf("fetch should be cancelled by the timeout, got %v", fetchErr)
+	}
+
+	checked, ok := msg.(registryCheckedMsg)
+	if !ok {
+		t.Fatalf("expected registryCheckedMsg, got %T", msg)
+	}
+	if checked.newCount != 0 {
+		t.Errorf("expected zero new marketplaces on timeout, got %d", checked.newCount)
+	}
+
+	t.Run("fast check returns count", func(t *testing.T) {
+		checkForNewMarketplaces = func(context.Context) ([]PopularMarketplace, int, error) {
+			return nil, 2, nil
+		}
+		RegistryCheckTimeout = time.Second
+
+		if got := checkRegistryForUpdates().(registryCheckedMsg); got.newCount != 2 {
+			t.Errorf("expected 2 new marketplaces, got %d", got.newCount)
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		t.Setenv("PLUM_REGISTRY_TIMEOUT", "250ms")
+		if got := registryCheckTimeout(); got != 250*time.Millisecond {
+			t.Errorf("expected 250ms from env, got %v", got)
+		}
+		t.Setenv("PLUM_REGISTRY_TIMEOUT", "garbage")
+		if got := registryCheckTimeout(); got != RegistryCheckTimeout {
+			t.Errorf("expected default on invalid env, got %v", got)
+		}
+	})
+}
+
+func TestPluginSortByMarketplace(t *testing.T) {
+	model := NewModel()
+	model.allPlugins = []plugin.Plugin{
+		{Name: "zeta", Marketplace: "beta-market", Installed: true},
+		{Name: "alpha", Marketplace: "gamma-market"},
+		{Name: "mango", Marketplace: "alpha-market"},
+		{Name: "apple", Marketplace: "beta-market"},
+		{Name: "kiwi", Marketplace: "alpha-market", Installed: true},
+	}
+	model.loading = false
+	model.applyFilter()
+
+	if model.pluginSortMode != PluginSortRelevance {
+		t.Fatal("Expected relevance sort by default")
+	}
+
+	// Relevance -> Name A-Z -> Marketplace
+	for i := 0; i < 2; i++ {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+		model = updatedModel.(Model)
+	}
+
+	if model.pluginSortMode != PluginSortMarketplace {
+		t.Fatalf("Ctrl+S should switch to marketplace sort, got %s", model.PluginSortModeName())
+	}
+
+	want := []string{
+		"kiwi@alpha-market",
+		"mango@alpha-market",
+		"apple@beta-market",
+		"zeta@beta-market",
+		"alpha@gamma-market",
+	}
+	if len(model.results) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(model.results))
+	}
+	for i, r := range model.results {
+		if r.Plugin.FullName() != want[i] {
+			t.Errorf("results[%d] = %s, want %s", i, r.Plugin.FullName(), want[i])
+		}
+	}
+
+	// Cycling past Recently Updated restores relevance order (installed first)
+	for i := 0; i < 2; i++ {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+		model = updatedModel.(Model)
+	}
+
+	if model.pluginSortMode != PluginSortRelevance {
+		t.Errorf("Ctrl+S should cycle back to relevance, got %s", model.PluginSortModeName())
+	}
+	if !model.results[0].Plugin.Installed {
+		t.Error("Relevance sort should list installed plugins first")
+	}
+}
+
+func TestPluginSortModes(t *testing.T) {
+	now := time.Now()
+	model := NewModel()
+	model.allPlugins = []plugin.Plugin{
+		{Name: "zeta", Marketplace: "beta-market", Installed: true, UpdatedAt: now.Add(-48 * time.Hour)},
+		{Name: "alpha", Marketplace: "gamma-market"},
+		{Name: "mango", Marketplace: "alpha-market", Installed: true, UpdatedAt: now},
+		{Name: "apple", Marketplace: "beta-market"},
+		{Name: "kiwi", Marketplace: "alpha-market", Installed: true, UpdatedAt: now.Add(-time.Hour)},
+	}
+	model.loading = false
+	model.applyFilter()
+
+	tests := []struct {
+		mode PluginSortMode
+		want []string
+	}{
+		{PluginSortName, []string{"alpha", "apple", "kiwi", "mango", "zeta"}},
+		{PluginSortMarketplace, []string{"kiwi", "mango", "apple", "zeta", "alpha"}},
+		{PluginSortRecent, []string{"mango", "kiwi", "zeta", "alpha", "apple"}},
+		{PluginSortRelevance, []string{"kiwi", "mango", "zeta", "alpha", "apple"}},
+	}
+	for _, tt := range tests {
+		updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+		model = updatedModel.(Model)
+
+		if model.pluginSortMode != tt.mode {
+			t.Fatalf("Shift+S should switch to %s, got %s", PluginSortModeNames[tt.mode], model.PluginSortModeName())
+		}
+		if model.textInput.Value() != "" {
+			t.Fatalf("Shift+S should not type into search, got %q", model.textInput.Value())
+		}
+		var got []string
+		for _, r := range model.results {
+			got = append(got, r.Plugin.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s order = %v, want %v", model.PluginSortModeName(), got, tt.want)
+		}
+	}
+
+	// Sorting applies to the filtered results
+	model.pluginSortMode = PluginSortName
+	model.textInput.SetValue("@beta-market")
+	model.applyFilter()
+	if len(model.results) != 2 || model.results[0].Plugin.Name != "apple" {
+		t.Errorf("expected filtered results sorted by name, got %v", model.results)
+	}
+}
+
+func TestMultiSelectCopy(t *testing.T) {
+	var copied []string
+	copyErr := error(nil)
+	origWrite := clipboardWrite
+	clipboardWrite = func(text string) error {
+		copied = append(copied, text)
+		return copyErr
+	}
+	defer func() { clipboardWrite = origWrite }()
+
+	model := NewModel()
+	model.allPlugins = []plugin.Plugin{
+		{Name: "alpha", Marketplace: "mkt"},
+		{Name: "beta", Marketplace: "mkt"},
+		{Name: "gamma", Marketplace: "other"},
+		{Name: "lsp", Marketplace: "mkt", HasLSPServers: true},
+	}
+	model.loading = false
+	model.applyFilter()
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updatedModel, _ := model.Update(msg)
+		model = updatedModel.(Model)
+	}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	y := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+
+	// Mark alpha, gamma, and the LSP plugin; beta is marked then unmarked
+	press(space) // alpha
+	press(down)
+	press(space) // beta
+	press(space) // beta again
+	press(down)
+	press(space) // gamma
+	press(down)
+	press(space) // lsp
+
+	if model.SelectionCount() != 3 {
+		t.Fatalf("expected 3 marked plugins, got %d", model.SelectionCount())
+	}
+	if model.textInput.Value() != "" {
+		t.Errorf("space should mark, not type; search is %q", model.textInput.Value())
+	}
+	if !strings.Contains(model.renderPluginItemSlim(model.allPlugins[0], nil, false), "✓") {
+		t.Error("marked plugin should render a check mark")
+	}
+
+	press(y)
+	want := "/plugin install alpha@mkt\n/plugin install gamma@other"
+	if len(copied) != 1 || copied[0] != want {
+		t.Fatalf("copied %q, want %q", copied, want)
+	}
+	if !model.copiedFlash || model.clipboardErrorFlash {
+		t.Error("expected copied flash after y")
+	}
+
+	// A failed write shows the clipboard error
+	model.copiedFlash = false
+	copyErr = fmt.Errorf("no clipboard")
+	press(y)
+	if !model.clipboardErrorFlash || model.copiedFlash {
+		t.Error("expected clipboard error flash when the write fails")
+	}
+
+	// Esc clears the selection before the search
+	model.textInput.SetValue("a")
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.SelectionCount() != 0 {
+		t.Errorf("esc should clear the selection, %d still marked", model.SelectionCount())
+	}
+	if model.textInput.Value() != "a" {
+		t.Errorf("esc should keep the search while clearing the selection, got %q", model.textInput.Value())
+	}
+
+	// Without a selection, space and y type into a non-empty search
+	press(space)
+	press(y)
+	if model.textInput.Value() != "a y" {
+		t.Errorf("expected space and y to be typed, got %q", model.textInput.Value())
+	}
+	if len(copied) != 2 {
+		t.Errorf("y without a selection should not copy, got %d copies", len(copied))
+	}
+}
+
+func TestExportResults(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	model := NewModel()
+	model.loading = false
+	model.allPlugins = []plugin.Plugin{
+		{Name: "alpha", Marketplace: "mkt", Version: "1.0.0", Description: "First"},
+		{Name: "beta", Marketplace: "mkt", Version: "2.0.0", Description: "Second"},
+		{Name: "gamma", Marketplace: "other", Version: "3.0.0", Description: "Third"},
+	}
+	model.textInput.SetValue("@mkt")
+	model.applyFilter()
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updatedModel, _ := model.Update(msg)
+		model = updatedModel.(Model)
+	}
+	readExport := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("export not written: %v", err)
+		}
+		return string(data)
+	}
+
+	// Markdown by default, only the filtered results
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	md := readExport("plum-export.md")
+	if !strings.Contains(md, "| alpha | 1.0.0 | mkt | First | `/plugin install alpha@mkt` |") || strings.Contains(md, "gamma") {
+		t.Errorf("unexpected markdown export:\n%s", md)
+	}
+	if !strings.Contains(model.toggleFlash, "Exported 2 to "+filepath.Join(dir, "plum-export.md")) {
+		t.Errorf("flash should name the written path, got %q", model.toggleFlash)
+	}
+	if model.textInput.Value() != "@mkt" {
+		t.Errorf("export keys should not type into the search, got %q", model.textInput.Value())
+	}
+
+	// Ctrl+X switches to JSON
+	press(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if model.exportFormat != export.FormatJSON {
+		t.Fatalf("Ctrl+X should switch to JSON, got %s", model.exportFormat)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	if js := readExport("plum-export.json"); !strings.Contains(js, `"installCommand": "/plugin install beta@mkt"`) {
+		t.Errorf("unexpected JSON export:\n%s", js)
+	}
+
+	// A failed write is reported; a directory is in the export's way
+	if err := os.Remove(filepath.Join(dir, "plum-export.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "plum-export.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	if model.toggleError == nil || !strings.Contains(model.toggleError.Error(), "export failed") {
+		t.Errorf("expected an export error, got %v", model.toggleError)
+	}
+}
+
+func TestListMouse(t *testing.T) {
+	newModel := func(mode ListDisplayMode) Model {
+		m := NewModel()
+		m.loading = false
+		m.allPlugins = createTestPlugins()
+		m.displayMode = mode
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+		m = updated.(Model)
+		m.applyFilter()
+		return m
+	}
+	click := func(m Model, y int) (Model, tea.Cmd) {
+		updated, cmd := m.Update(tea.MouseMsg{X: 10, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+		return updated.(Model), cmd
+	}
+	// rowOf finds the screen line showing a plugin's name
+	rowOf := func(t *testing.T, m Model, name string) int {
+		t.Helper()
+		for i, line := range strings.Split(m.View(), "\n") {
+			if strings.Contains(line, name+" ") {
+				return i
+			}
+		}
+		t.Fatalf("%s not on screen", name)
+		return -1
+	}
+	indexOf := func(m Model, name string) int {
+		for i, rp := range m.results {
+			if rp.Plugin.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, mode := range []ListDisplayMode{DisplaySlim, DisplayCard} {
+		m := newModel(mode)
+		for _, name := range []string{"testing-tool", "sample-plugin", "another-tool"} {
+			m, _ = click(m, rowOf(t, m, name))
+			if m.cursor != indexOf(m, name) {
+				t.Errorf("mode %d: clicking %s selected %d, want %d", mode, name, m.cursor, indexOf(m, name))
+			}
+		}
+	}
+
+	t.Run("clicks outside the results are ignored", func(t *testing.T) {
+		m := newModel(DisplaySlim)
+		m.cursor = 2
+		m, _ = click(m, 0)
+		if m.cursor != 2 {
+			t.Errorf("clicking the title moved the cursor to %d", m.cursor)
+		}
+	})
+
+	t.Run("double-click opens the detail view", func(t *testing.T) {
+		m := newModel(DisplaySlim)
+		y := rowOf(t, m, "sample-plugin")
+		m, _ = click(m, y)
+		if m.viewState != ViewList {
+			t.Fatal("a single click should not open the detail view")
+		}
+		m, _ = click(m, y)
+		if m.viewState != ViewDetail || m.SelectedPlugin().Name != "sample-plugin" {
+			t.Errorf("double-click should open sample-plugin, view = %d", m.viewState)
+		}
+	})
+
+	t.Run("slow clicks don't open", func(t *testing.T) {
+		m := newModel(DisplaySlim)
+		y := rowOf(t, m, "sample-plugin")
+		m, _ = click(m, y)
+		m.lastClickAt = m.lastClickAt.Add(-time.Second)
+		m, _ = click(m, y)
+		if m.viewState != ViewList {
+			t.Error("clicks far apart should not count as a double-click")
+		}
+	})
+
+	t.Run("wheel moves the selection", func(t *testing.T) {
+		m := newModel(DisplaySlim)
+		wheel := func(button tea.MouseButton) {
+			updated, _ := m.Update(tea.MouseMsg{Button: button, Action: tea.MouseActionPress})
+			m = updated.(Model)
+		}
+		wheel(tea.MouseButtonWheelDown)
+		wheel(tea.MouseButtonWheelDown)
+		if m.cursor != 2 {
+			t.Errorf("wheel down twice: cursor = %d, want 2", m.cursor)
+		}
+		wheel(tea.MouseButtonWheelUp)
+		if m.cursor != 1 {
+			t.Errorf("wheel up: cursor = %d, want 1", m.cursor)
+		}
+	})
+}
+
+func TestDetailLastChecked(t *testing.T) {
+	model := NewModel()
+
+	checked := &plugin.Plugin{Name: "checked", Installed: true, LastCheckedAt: time.Now().Add(-2*time.Hour - time.Minute)}
+	content := model.generateDetailContent(checked, 80)
+	if !strings.Contains(content, "update-checked 2h ago") {
+		t.Errorf("Expected 'update-checked 2h ago' in detail view, got:\n%s", content)
+	}
+
+	unchecked := &plugin.Plugin{Name: "unchecked", Installed: true}
+	if content := model.generateDetailContent(unchecked, 80); !strings.Contains(content, "never checked") {
+		t.Errorf("Expected 'never checked' for a plugin never checked, got:\n%s", content)
+	}
+
+	available := &plugin.Plugin{Name: "available"}
+	if content := model.generateDetailContent(available, 80); strings.Contains(content, "Updates:") {
+		t.Error("Plugins that aren't installed should not show update check time")
+	}
+}
+
+func TestStatusBarWrapping(t *testing.T) {
+	parts := []string{"12/345", KeyStyle.Render("↑↓") + " nav", KeyStyle.Render("tab") + " next view", "Shift+M marketplaces", "? help"}
+
+	t.Run("segments are never split", func(t *testing.T) {
+		for width := 10; width <= 80; width++ {
+			lines := packStatusSegments(parts, width, 10)
+			var rejoined []string
+			for _, line := range lines {
+				// A lone segment wider than the line may overflow; packed lines may not
+				if len(strings.Split(line, statusBarSeparator)) > 1 && lipgloss.Width(line) > width {
+					t.Errorf("width %d: line %q is %d cells wide", width, line, lipgloss.Width(line))
+				}
+				rejoined = append(rejoined, strings.Split(line, statusBarSeparator)...)
+			}
+			if len(rejoined) != len(parts) {
+				t.Fatalf("width %d: expected %d segments, got %d: %q", width, len(parts), len(rejoined), rejoined)
+			}
+			for i := range parts {
+				if rejoined[i] != parts[i] {
+					t.Errorf("width %d: segment %d = %q, want %q", width, i, rejoined[i], parts[i])
+				}
+			}
+		}
+	})
+
+	t.Run("overflow beyond max lines is dropped", func(t *testing.T) {
+		lines := packStatusSegments(parts, 20, 2)
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d: %q", len(lines), lines)
+		}
+		if strings.Contains(strings.Join(lines, "\n"), "? help") {
+			t.Error("segments past the last line should be dropped")
+		}
+	})
+
+	t.Run("unknown width keeps one line", func(t *testing.T) {
+		if lines := packStatusSegments(parts, 0, 2); len(lines) != 1 {
+			t.Errorf("expected 1 line, got %d", len(lines))
+		}
+	})
+
+	t.Run("wrapped status bar reduces visible items", func(t *testing.T) {
+		model := NewModel()
+		model.windowHeight = 40
+		model.windowWidth = 70
+		model.displayMode = DisplayCard
+
+		lines := model.statusBarLines()
+		if len(lines) != 2 {
+			t.Fatalf("expected status bar to wrap at width 70, got %d line(s): %q", len(lines), lines)
+		}
+		if got, want := model.maxVisibleItems(), (40-12-1)/4; got != want {
+			t.Errorf("maxVisibleItems() = %d, want %d", got, want)
+		}
+	})
+}
+
+// TestConcurrentReloads runs reload commands on their own goroutines, as
+// Bubble Tea does, while the model handles their results and renders. Run
+// with -race to catch shared-slice mutation.
+func TestConcurrentReloads(t *testing.T) {
+	origLoad := loadAllPlugins
+	origClear := clearCacheAndReload
+	defer func() {
+		loadAllPlugins = origLoad
+		clearCacheAndReload = origClear
+	}()
+	loadAllPlugins = func() ([]plugin.Plugin, error) { return createTestPlugins(), nil }
+	clearCacheAndReload = func(ctx context.Context) error { return nil }
+
+	model := NewModel()
+	model.windowWidth = 100
+	model.windowHeight = 30
+
+	const reloads = 20
+	msgs := make(chan tea.Msg, reloads)
+	for i := 0; i < reloads; i++ {
+		cmd := loadPlugins(model.nextReload())
+		if i%2 == 1 {
+			cmd = doRefreshCache(context.Background(), model.reloadGeneration)
+		}
+		go func() { msgs <- cmd() }()
+	}
+
+	for i := 0; i < reloads; i++ {
+		updated, _ := model.Update(<-msgs)
+		model = updated.(Model)
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+		model = updated.(Model)
+		_ = model.View()
+	}
+
+	if len(model.allPlugins) != len(createTestPlugins()) {
+		t.Errorf("expected the latest reload to be applied, got %d plugins", len(model.allPlugins))
+	}
+	if model.loading {
+		t.Error("loading should be cleared once the latest reload arrives")
+	}
+}
+
+// TestEscCancelsRefresh verifies Esc cancels the marketplace fetches of a
+// refresh in flight, and the cancelled refresh's result is dropped
+func TestEscCancelsRefresh(t *testing.T) {
+	origClear := clearCacheAndReload
+	defer func() { clearCacheAndReload = origClear }()
+
+	started := make(chan struct{})
+	clearCacheAndReload = func(ctx context.Context) error {
+		close(started)
+		// Hang like a stalled download until cancelled
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	model := newModel()
+	model.allPlugins = createTestPlugins()
+	model.loading = false
+
+	updated, cmd := model.Update(refreshCacheMsg{})
+	model = updated.(Model)
+	if !model.refreshing || model.cancelRefresh == nil {
+		t.Fatal("refresh should be running with a cancel func")
+	}
+
+	// Run the batched commands as Bubble Tea would; only the refresh matters
+	msgs := make(chan tea.Msg, 4)
+	for _, c := range cmd().(tea.BatchMsg) {
+		go func(c tea.Cmd) { msgs <- c() }(c)
+	}
+	<-started
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	if model.refreshing || model.cancelRefresh != nil {
+		t.Error("Esc should stop the refresh and release its cancel func")
+	}
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case msg := <-msgs:
+			loaded, ok := msg.(pluginsLoadedMsg)
+			if !ok {
+				continue
+			}
+			if !errors.Is(loaded.err, context.Canceled) {
+				t.Fatalf("refresh returned %v, want context.Canceled", loaded.err)
+			}
+			updated, _ = model.Update(loaded)
+			model = updated.(Model)
+			if model.err != nil {
+				t.Errorf("cancelled refresh should be dropped, got error view: %v", model.err)
+			}
+			if len(model.allPlugins) != len(createTestPlugins()) {
+				t.Errorf("cancelled refresh should keep the loaded plugins, got %d", len(model.allPlugins))
+			}
+			return
+		case <-timeout:
+			t.Fatal("refresh kept running after Esc")
+		}
+	}
+}
+
+// TestRefreshCancelsPreviousRefresh verifies starting a refresh cancels one
+// still in flight instead of leaking its context
+func TestRefreshCancelsPreviousRefresh(t *testing.T) {
+	origClear := clearCacheAndReload
+	defer func() { clearCacheAndReload = origClear }()
+
+	contexts := make(chan context.Context, 2)
+	clearCacheAndReload = func(ctx context.Context) error {
+		contexts <- ctx
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	model := newModel()
+	model.loading = false
+
+	updated, cmd := model.Update(refreshCacheMsg{})
+	model = updated.(Model)
+	for _, c := range cmd().(tea.BatchMsg) {
+		go func(c tea.Cmd) { _ = c() }(c)
+	}
+	first := <-contexts
+
+	updated, _ = model.Update(refreshCacheMsg{})
+	model = updated.(Model)
+	defer model.stopRefresh()
+
+	select {
+	case <-first.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("a new refresh should cancel the one in flight")
+	}
+}
+
+// TestStaleReloadIgnored verifies an older reload finishing last doesn't
+// overwrite newer data
+func TestStaleReloadIgnored(t *testing.T) {
+	model := NewModel()
+	stale := model.nextReload()
+	latest := model.nextReload()
+
+	fresh := []plugin.Plugin{{Name: "fresh"}}
+	updated, _ := model.Update(pluginsLoadedMsg{plugins: fresh, generation: latest})
+	model = updated.(Model)
+
+	updated, _ = model.Update(pluginsLoadedMsg{plugins: []plugin.Plugin{{Name: "old"}}, generation: stale})
+	model = updated.(Model)
+
+	if len(model.allPlugins) != 1 || model.allPlugins[0].Name != "fresh" {
+		t.Errorf("stale reload replaced newer plugins: %+v", model.allPlugins)
+	}
+}
+
+// TestLazyLoad verifies the list renders from the cached load first and is
+// then enriched with the full load, keeping the cursor on the same plugin
+func TestLazyLoad(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	origCached, origLoad := loadCachedPlugins, loadAllPlugins
+	defer func() {
+		loadCachedPlugins, loadAllPlugins = origCached, origLoad
+	}()
+
+	cached := []plugin.Plugin{
+		{Name: "beta", Marketplace: "local"},
+		{Name: "delta", Marketplace: "local"},
+	}
+	full := append([]plugin.Plugin{
+		{Name: "alpha", Marketplace: "popular", IsDiscoverable: true},
+		{Name: "gamma", Marketplace: "popular", IsDiscoverable: true},
+	}, cached...)
+	fullLoads := 0
+	loadCachedPlugins = func() ([]plugin.Plugin, error) { return cached, nil }
+	loadAllPlugins = func() ([]plugin.Plugin, error) {
+		fullLoads++
+		return full, nil
+	}
+
+	model := newModel()
+	model.windowWidth = 100
+	model.windowHeight = 30
+
+	// First render comes from the cache alone
+	updated, enrich := model.Update(loadCachedPluginsCmd(model.reloadGeneration)())
+	model = updated.(Model)
+	if fullLoads != 0 {
+		t.Fatal("cached load should not run the full load")
+	}
+	if model.loading || !model.enriching {
+		t.Errorf("loading = %v, enriching = %v; want the cached list shown while enriching", model.loading, model.enriching)
+	}
+	if len(model.allPlugins) != len(cached) {
+		t.Errorf("expected %d cached plugins, got %d", len(cached), len(model.allPlugins))
+	}
+	if !strings.Contains(model.View(), "Checking marketplaces...") {
+		t.Error("view should show that marketplaces are still being checked")
+	}
+	if enrich == nil {
+		t.Fatal("cached load should be followed by an enrichment command")
+	}
+
+	// The user moves while the full load runs
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = updated.(Model)
+	selected := model.SelectedPlugin().FullName()
+
+	updated, _ = model.Update(enrich())
+	model = updated.(Model)
+	if fullLoads != 1 {
+		t.Errorf("expected one full load, got %d", fullLoads)
+	}
+	if model.enriching {
+		t.Error("enriching should be cleared once the full list arrives")
+	}
+	if len(model.allPlugins) != len(full) {
+		t.Errorf("expected %d plugins after enrichment, got %d", len(full), len(model.allPlugins))
+	}
+	if got := model.SelectedPlugin().FullName(); got != selected {
+		t.Errorf("cursor moved from %s to %s during enrichment", selected, got)
+	}
+	if strings.Contains(model.View(), "Checking marketplaces...") {
+		t.Error("view should drop the checking notice after enrichment")
+	}
+}
+
+// TestLazyLoad_EnrichmentEdgeCases covers an empty cache, a failed full load,
+// and an enrichment overtaken by a newer reload
+func TestLazyLoad_EnrichmentEdgeCases(t *testing.T) {
+	cached := []plugin.Plugin{{Name: "beta", Marketplace: "local"}}
+
+	t.Run("empty cache keeps loading", func(t *testing.T) {
+		model := newModel()
+		updated, _ := model.Update(pluginsLoadedMsg{generation: model.reloadGeneration, cached: true})
+		model = updated.(Model)
+		if !model.loading {
+			t.Error("loading should stay on until enrichment when the cache is empty")
+		}
+
+		updated, _ = model.Update(pluginsEnrichedMsg{plugins: cached, generation: model.reloadGeneration})
+		model = updated.(Model)
+		if model.loading || len(model.allPlugins) != 1 {
+			t.Errorf("loading = %v, plugins = %d; want the enriched list", model.loading, len(model.allPlugins))
+		}
+	})
+
+	t.Run("failed enrichment keeps the cached list", func(t *testing.T) {
+		model := newModel()
+		updated, _ := model.Update(pluginsLoadedMsg{plugins: cached, generation: model.reloadGeneration, cached: true})
+		model = updated.(Model)
+
+		updated, _ = model.Update(pluginsEnrichedMsg{err: errors.New("offline"), generation: model.reloadGeneration})
+		model = updated.(Model)
+		if model.err != nil {
+			t.Errorf("err = %v, want the cached list kept", model.err)
+		}
+		if model.enriching || len(model.allPlugins) != 1 {
+			t.Errorf("enriching = %v, plugins = %d; want the cached list", model.enriching, len(model.allPlugins))
+		}
+	})
+
+	t.Run("failed enrichment with nothing cached shows the error", func(t *testing.T) {
+		model := newModel()
+		updated, _ := model.Update(pluginsLoadedMsg{generation: model.reloadGeneration, cached: true})
+		model = updated.(Model)
+
+		updated, _ = model.Update(pluginsEnrichedMsg{err: errors.New("offline"), generation: model.reloadGeneration})
+		model = updated.(Model)
+		if model.err == nil {
+			t.Error("expected the load error when there is nothing to show")
+		}
+	})
+
+	t.Run("stale enrichment is dropped", func(t *testing.T) {
+		model := newModel()
+		first := model.reloadGeneration
+		updated, _ := model.Update(pluginsLoadedMsg{plugins: cached, generation: first, cached: true})
+		model = updated.(Model)
+
+		fresh := []plugin.Plugin{{Name: "fresh", Marketplace: "local"}}
+		latest := model.nextReload()
+		updated, _ = model.Update(pluginsLoadedMsg{plugins: fresh, generation: latest})
+		model = updated.(Model)
+		if model.enriching {
+			t.Error("a full reload should end enrichment")
+		}
+
+		updated, _ = model.Update(pluginsEnrichedMsg{plugins: []plugin.Plugin{{Name: "old"}}, generation: first})
+		model = updated.(Model)
+		if len(model.allPlugins) != 1 || model.allPlugins[0].Name != "fresh" {
+			t.Errorf("stale enrichment replaced newer plugins: %+v", model.allPlugins)
+		}
+	})
+}
+
+func TestTogglePreferInstalled(t *testing.T) {
+	model := NewModel()
+	model.allPlugins = []plugin.Plugin{
+		{Name: "zeta", Marketplace: "market", Installed: true},
+		{Name: "alpha", Marketplace: "market"},
+	}
+	model.loading = false
+	model.applyFilter()
+
+	if !model.preferInstalled {
+		t.Fatal("Expected installed-first ranking by default")
+	}
+	if model.results[0].Plugin.Name != "zeta" {
+		t.Errorf("Installed plugin should be first by default, got %s", model.results[0].Plugin.Name)
+	}
+
+	updatedModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	model = updatedModel.(Model)
+
+	if model.preferInstalled {
+		t.Fatal("Ctrl+B should turn off installed-first ranking")
+	}
+	if model.results[0].Plugin.Name != "alpha" {
+		t.Errorf("Without installed-first ranking results should be by name, got %s first", model.results[0].Plugin.Name)
+	}
+}
+
+func TestPluginJSONURL(t *testing.T) {
+	base := plugin.Plugin{
+		Name:            "alpha",
+		MarketplaceRepo: "https://github.com/owner/repo",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *plugin.Plugin)
+		want   string
+	}{
+		{"string path", func(p *plugin.Plugin) { p.Source = "./tools/alpha" },
+			"https://raw.githubusercontent.com/owner/repo/main/tools/alpha/.claude-plugin/plugin.json"},
+		{"empty source", func(p *plugin.Plugin) {},
+			"https://raw.githubusercontent.com/owner/repo/main/plugins/alpha/.claude-plugin/plugin.json"},
+		{"dot source", func(p *plugin.Plugin) { p.Source = "." },
+			"https://raw.githubusercontent.com/owner/repo/main/plugins/alpha/.claude-plugin/plugin.json"},
+		{"default branch", func(p *plugin.Plugin) { p.MarketplaceBranch = "master" },
+			"https://raw.githubusercontent.com/owner/repo/master/plugins/alpha/.claude-plugin/plugin.json"},
+		{"external plugin", func(p *plugin.Plugin) { p.Source = "https://github.com/other/repo"; p.IsExternalURL = true }, ""},
+		{"GitLab marketplace", func(p *plugin.Plugin) { p.MarketplaceRepo = "https://gitlab.com/owner/repo" },
+			"https://gitlab.com/owner/repo/-/raw/main/plugins/alpha/.claude-plugin/plugin.json"},
+		{"unsupported marketplace host", func(p *plugin.Plugin) { p.MarketplaceRepo = "https://codeberg.org/owner/repo" }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := base
+			tt.modify(&p)
+			if got := pluginJSONURL(p); got != tt.want {
+				t.Errorf("pluginJSONURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetailViewFitsShortContent(t *testing.T) {
+	newModel := func() Model {
+		m := NewModel()
+		m.loading = false
+		m.allPlugins = []plugin.Plugin{{Name: "tiny", Marketplace: "mkt", Description: "Short"}}
+		m.results = m.filteredSearch("")
+		return m
+	}
+	const height = 60
+
+	// boxHeight is the header, content, and footer plus the box's border and padding
+	boxHeight := func(m Model) int {
+		p := m.SelectedPlugin()
+		width := m.detailViewport.Width
+		return lipgloss.Height(m.generateDetailHeader(p, width)) +
+			lipgloss.Height(m.generateDetailContent(p, width)) +
+			lipgloss.Height(m.generateDetailFooter(p, width)) + 4
+	}
+
+	t.Run("opened from list", func(t *testing.T) {
+		m := newModel()
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: height})
+		m = updated.(Model)
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+
+		got := lipgloss.Height(m.detailView())
+		if got != boxHeight(m) {
+			t.Errorf("detail box height = %d, want %d to fit content", got, boxHeight(m))
+		}
+		if got >= height-detailViewportOverhead {
+			t.Errorf("short plugin's detail box should not fill the screen (height %d)", got)
+		}
+	})
+
+	t.Run("first sized in detail view", func(t *testing.T) {
+		m := newModel()
+		m.viewState = ViewDetail
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: height})
+		m = updated.(Model)
+
+		if got := lipgloss.Height(m.detailView()); got != boxHeight(m) {
+			t.Errorf("detail box height = %d, want %d to fit content", got, boxHeight(m))
+		}
+	})
+}
+
+// TestWrapText verifies wrapping measures display width and never splits
+// a multi-byte rune
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth int
+		want     string
+	}{
+		{"ascii", "hello world again", 11, "hello world\nagain"},
+		{"cjk counts double width", "日本語 テキスト です", 8, "日本語\nテキスト\nです"},
+		{"emoji", "🚀 fast 🔥 tools", 8, "🚀 fast\n🔥 tools"},
+		{"long ascii token", "abcdefghij", 4, "abcd\nefgh\nij"},
+		{"long cjk token", "漢字漢字漢字", 5, "漢字\n漢字\n漢字"},
+		{"long token after a word", "hi 🍕🍕🍕🍕🍕", 4, "hi\n🍕🍕\n🍕🍕\n🍕"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(tt.text, tt.maxWidth)
+			if got != tt.want {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.maxWidth, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("wrapText produced invalid UTF-8: %q", got)
+			}
+			for _, line := range strings.Split(got, "\n") {
+				if w := lipgloss.Width(line); w > tt.maxWidth {
+					t.Errorf("line %q is %d cells wide, max %d", line, w, tt.maxWidth)
+				}
+			}
+		})
+	}
+}
+
+// TestTruncateToWidth verifies truncation respects display width and only
+// adds an ellipsis when something was cut
+func TestTruncateToWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"ascii fits", "hello", 5, "hello"},
+		{"ascii cut", "hello world", 8, "hello..."},
+		{"emoji fits", "🚀🔥", 4, "🚀🔥"},
+		{"emoji cut", "🚀🔥🍕🎉", 7, "🚀🔥..."},
+		{"cjk cut on odd width", "日本語テキスト", 8, "日本..."},
+		{"cjk cut on even width", "日本語テキスト", 9, "日本語..."},
+		{"too narrow for ellipsis", "日本語", 3, "日"},
+		{"zero width", "hello", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateToWidth(tt.s, tt.width)
+			if got != tt.want {
+				t.Errorf("truncateToWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateToWidth produced invalid UTF-8: %q", got)
+			}
+			if w := lipgloss.Width(got); w > tt.width {
+				t.Errorf("result %q is %d cells wide, max %d", got, w, tt.width)
+			}
+		})
+	}
+}
+
+// TestCardDescriptionUnicode verifies card rows keep multi-byte
+// descriptions intact and within the card
+func TestCardDescriptionUnicode(t *testing.T) {
+	model := NewModel()
+	model.windowWidth = 60
+	model.windowHeight = 40
+
+	for _, desc := range []string{
+		strings.Repeat("日本語の説明 ", 20),
+		strings.Repeat("🚀 rocket ", 20),
+	} {
+		p := plugin.Plugin{Name: "intl", Version: "1.0.0", Marketplace: "mkt", Description: desc}
+		card := model.renderPluginItemCard(p, nil, false)
+		if !utf8.ValidString(card) {
+			t.Errorf("card for %q contains invalid UTF-8", desc)
+		}
+		if !strings.Contains(card, "...") {
+			t.Errorf("long description should be truncated with an ellipsis:\n%s", card)
+		}
+		for _, line := range strings.Split(card, "\n") {
+			if w := lipgloss.Width(line); w > model.ContentWidth() {
+				t.Errorf("card line is %d cells wide, max %d: %q", w, model.ContentWidth(), line)
+			}
+		}
+	}
+}
+
+// TestScrollbarThumb verifies the thumb covers the visible share of the
+// content and tracks the scroll position
+func TestScrollbarThumb(t *testing.T) {
+	tests := []struct {
+		name                string
+		visible, total      int
+		percent             float64
+		wantHeight, wantPos int
+	}{
+		{"half visible at top", 10, 20, 0, 5, 0},
+		{"half visible at bottom", 10, 20, 1, 5, 5},
+		{"quarter visible midway", 10, 40, 0.5, 2, 4},
+		{"long content", 10, 1000, 1, 1, 9},
+		{"content fits", 10, 8, 0, 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			height, pos := scrollbarThumb(tt.visible, tt.total, tt.percent)
+			if height != tt.wantHeight || pos != tt.wantPos {
+				t.Errorf("scrollbarThumb(%d, %d, %v) = (%d, %d), want (%d, %d)",
+					tt.visible, tt.total, tt.percent, height, pos, tt.wantHeight, tt.wantPos)
+			}
+		})
+	}
+}
+
+// TestDetailScrollbarUsesContentHeight verifies the detail scrollbar is
+// sized from the real content height rather than an estimate
+func TestDetailScrollbarUsesContentHeight(t *testing.T) {
+	m := NewModel()
+	m.loading = false
+	m.allPlugins = []plugin.Plugin{{
+		Name:        "verbose",
+		Marketplace: "mkt",
+		Description: strings.Repeat("A very long plugin description that wraps. ", 100),
+	}}
+	m.results = m.filteredSearch("")
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	p := m.SelectedPlugin()
+	want := lipgloss.Height(m.generateDetailContent(p, m.detailViewport.Width))
+	if m.detailContentLines != want {
+		t.Fatalf("detailContentLines = %d, want %d", m.detailContentLines, want)
+	}
+	if want <= m.detailViewport.Height*2 {
+		t.Fatalf("fixture content (%d lines) should be over twice the viewport (%d)", want, m.detailViewport.Height)
+	}
+
+	wantThumb, _ := scrollbarThumb(m.detailViewport.Height, want, 0)
+	if got := strings.Count(m.renderDetailScrollbar(), "█"); got != wantThumb {
+		t.Errorf("thumb is %d rows, want %d", got, wantThumb)
+	}
+
+	m.detailViewport.GotoBottom()
+	lines := strings.Split(m.renderDetailScrollbar(), "\n")
+	if !strings.Contains(lines[len(lines)-1], "█") {
+		t.Error("thumb should reach the bottom of the track when scrolled to the end")
+	}
+}
+
+// TestHelpScrollbarUsesContentHeight verifies opening help records the help
+// content height for its scrollbar
+func TestHelpScrollbarUsesContentHeight(t *testing.T) {
+	m := NewModel()
+	m.loading = false
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	m = updated.(Model)
+
+	if want := lipgloss.Height(m.generateHelpSections()); m.helpContentLines != want {
+		t.Fatalf("helpContentLines = %d, want %d", m.helpContentLines, want)
+	}
+	wantThumb, _ := scrollbarThumb(m.helpViewport.Height, m.helpContentLines, 0)
+	if got := strings.Count(m.renderHelpScrollbar(), "█"); got != wantThumb {
+		t.Errorf("thumb is %d rows, want %d", got, wantThumb)
+	}
+}
+
+// TestInstallFromDetailView verifies 'i' runs the installer off the UI
+// goroutine and applies the result when installDoneMsg arrives
+func TestInstallFromDetailView(t *testing.T) {
+	original := installPlugin
+	defer func() { installPlugin = original }()
+
+	// newDetailModel opens the detail view for the named plugin
+	newDetailModel := func(name string) Model {
+		m := NewModel()
+		m.loading = false
+		m.allPlugins = []plugin.Plugin{
+			{Name: "alpha", Marketplace: "mkt", Version: "1.0.0"},
+			{Name: "beta", Marketplace: "mkt", Version: "1.0.0", Installed: true},
+		}
+		m.results = m.filteredSearch("")
+		for i, rp := range m.results {
+			if rp.Plugin.Name == name {
+				m.cursor = i
+			}
+		}
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+		m = updated.(Model)
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+		m.viewState = ViewDetail
+		return m
+	}
+	// pressI presses 'i' and confirms the install dialog if it opens
+	pressI := func(m Model) (Model, tea.Cmd) {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+		m = updated.(Model)
+		if m.viewState != ViewConfirm {
+			return m, cmd
+		}
+		updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		m = updated.(Model)
+		if cmd == nil {
+			return m, nil
+		}
+		updated, cmd = m.Update(cmd())
+		return updated.(Model), cmd
+	}
+	// runInstall runs the batched command and returns the installDoneMsg
+	runInstall := func(t *testing.T, cmd tea.Cmd) installDoneMsg {
+		t.Helper()
+		batch, ok := cmd().(tea.BatchMsg)
+		if !ok {
+			t.Fatal("install should batch the spinner with the install command")
+		}
+		for _, c := range batch {
+			if msg, ok := c().(installDoneMsg); ok {
+				return msg
+			}
+		}
+		t.Fatal("no installDoneMsg from install command")
+		return installDoneMsg{}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var got []string
+		installPlugin = func(_ context.Context, p plugin.Plugin) (string, error) {
+			got = append(got, p.FullName())
+			return "/cache/mkt/alpha", nil
+		}
+
+		m := newDetailModel("alpha")
+		readyBefore := m.ReadyCount()
+
+		m, cmd := pressI(m)
+		if m.installing != "alpha@mkt" || cmd == nil {
+			t.Fatalf("expected install in progress, installing = %q", m.installing)
+		}
+		if !strings.Contains(m.detailView(), "Installing...") {
+			t.Error("detail view should show install progress")
+		}
+
+		// A second press while installing is ignored
+		if _, cmd := pressI(m); cmd != nil {
+			t.Error("'i' should be ignored while an install is running")
+		}
+
+		msg := runInstall(t, cmd)
+		if len(got) != 1 || got[0] != "alpha@mkt" {
+			t.Fatalf("installer calls = %v, want [alpha@mkt]", got)
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+
+		if m.installing != "" {
+			t.Error("install should no longer be in progress")
+		}
+		p := m.SelectedPlugin()
+		if !p.Installed || p.InstallPath != "/cache/mkt/alpha" {
+			t.Errorf("selected plugin should be installed at the cache path, got %+v", p)
+		}
+		if m.ReadyCount() != readyBefore-1 || m.InstalledCount() != 2 {
+			t.Errorf("counts not refreshed: ready %d, installed %d", m.ReadyCount(), m.InstalledCount())
+		}
+		if !m.installedFlash || !strings.Contains(m.detailView(), "Installed!") {
+			t.Error("expected an Installed! flash")
+		}
+
+		updated, _ = m.Update(clearInstallFlashMsg{})
+		if updated.(Model).installedFlash {
+			t.Error("flash should clear")
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		installPlugin = func(_ context.Context, p plugin.Plugin) (string, error) {
+			return "", fmt.Errorf("network down")
+		}
+
+		m, cmd := pressI(newDetailModel("alpha"))
+		updated, _ := m.Update(runInstall(t, cmd))
+		m = updated.(Model)
+
+		if m.SelectedPlugin().Installed {
+			t.Error("failed install should leave the plugin uninstalled")
+		}
+		if m.installError == nil || !strings.Contains(m.detailView(), "Install failed: network down") {
+			t.Errorf("expected an install failure flash, got %v", m.installError)
+		}
+	})
+
+	t.Run("esc cancels", func(t *testing.T) {
+		installPlugin = func(ctx context.Context, p plugin.Plugin) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+
+		m, cmd := pressI(newDetailModel("alpha"))
+		m.viewState = ViewList
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		m = updated.(Model)
+		if m.cancelInstall != nil {
+			t.Fatal("esc should cancel the install")
+		}
+
+		updated, _ = m.Update(runInstall(t, cmd))
+		m = updated.(Model)
+		if m.installing != "" || m.installError != nil {
+			t.Errorf("a cancelled install should clear quietly, installing = %q, err = %v", m.installing, m.installError)
+		}
+		if m.SelectedPlugin().Installed {
+			t.Error("cancelled install should leave the plugin uninstalled")
+		}
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		installPlugin = func(_ context.Context, p plugin.Plugin) (string, error) {
+			t.Fatal("installer should not run")
+			return "", nil
+		}
+
+		if m, cmd := pressI(newDetailModel("beta")); cmd != nil || m.installing != "" {
+			t.Error("'i' should do nothing for an installed plugin")
+		}
+	})
+}
+
+func TestConfirmDialog(t *testing.T) {
+	newModel := func() Model {
+		m := NewModel()
+		m.loading = false
+		m.windowWidth, m.windowHeight = 100, 30
+		m.viewState = ViewDetail
+		return m
+	}
+	press := func(m Model, key tea.KeyMsg) (Model, tea.Cmd) {
+		updated, cmd := m.Update(key)
+		return updated.(Model), cmd
+	}
+	yes := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}
+	no := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}
+
+	ran := 0
+	pending := func() tea.Msg {
+		ran++
+		return nil
+	}
+
+	for _, cancel := range []tea.KeyMsg{no, {Type: tea.KeyEsc}} {
+		m := newModel()
+		m.requestConfirm("Do the risky thing?", pending)
+		if m.viewState != ViewConfirm || !strings.Contains(m.View(), "Do the risky thing?") {
+			t.Fatal("expected the confirmation dialog to show the prompt")
+		}
+
+		m, cmd := press(m, cancel)
+		if cmd != nil {
+			cmd()
+		}
+		if ran != 0 {
+			t.Errorf("%s should not run the pending command", cancel)
+		}
+		if m.viewState != ViewDetail || m.confirmCmd != nil {
+			t.Errorf("%s should close the dialog, view = %d", cancel, m.viewState)
+		}
+	}
+
+	for _, confirm := range []tea.KeyMsg{yes, {Type: tea.KeyEnter}} {
+		ran = 0
+		m := newModel()
+		m.requestConfirm("Do the risky thing?", pending)
+
+		m, cmd := press(m, confirm)
+		if cmd == nil {
+			t.Fatalf("%s should return the pending command", confirm)
+		}
+		cmd()
+		if ran != 1 {
+			t.Errorf("%s should run the pending command once, ran %d times", confirm, ran)
+		}
+		if m.viewState != ViewDetail {
+			t.Errorf("%s should return to the detail view, view = %d", confirm, m.viewState)
+		}
+	}
+
+	t.Run("install asks first", func(t *testing.T) {
+		original := installPlugin
+		defer func() { installPlugin = original }()
+		installs := 0
+		installPlugin = func(_ context.Context, p plugin.Plugin) (string, error) {
+			installs++
+			return "/cache/mkt/alpha", nil
+		}
+
+		m := newModel()
+		m.allPlugins = []plugin.Plugin{{Name: "alpha", Marketplace: "mkt"}}
+		m.results = m.filteredSearch("")
+
+		m, cmd := press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+		if cmd != nil || m.viewState != ViewConfirm || m.installing != "" {
+			t.Fatal("'i' should ask for confirmation before installing")
+		}
+		if !strings.Contains(m.View(), "Install alpha@mkt") {
+			t.Error("dialog should name the plugin")
+		}
+		m, cmd = press(m, no)
+		if cmd != nil || m.installing != "" || installs != 0 {
+			t.Error("declining should not install")
+		}
+	})
+}
+
+func TestTogglePluginEnabled(t *testing.T) {
+	original := setPluginEnabled
+	defer func() { setPluginEnabled = original }()
+
+	type call struct {
+		fullName string
+		enabled  bool
+		scope    settings.Scope
+		project  string
+	}
+	var calls []call
+	setPluginEnabled = func(fullName string, enabled bool, scope settings.Scope, projectPath string) error {
+		calls = append(calls, call{fullName, enabled, scope, projectPath})
+		return nil
+	}
+
+	// newModel selects the named plugin with the given states loaded
+	newModel := func(name string, view ViewState, states map[string]settings.PluginState) Model {
+		m := NewModel()
+		m.loading = false
+		m.allPlugins = []plugin.Plugin{
+			{Name: "alpha", Marketplace: "mkt", Version: "1.0.0"},
+			{Name: "beta", Marketplace: "mkt", Version: "1.0.0", Installed: true},
+		}
+		m.pluginStates = states
+		m.results = m.filteredSearch("")
+		for i, rp := range m.results {
+			if rp.Plugin.Name == name {
+				m.cursor = i
+			}
+		}
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+		m = updated.(Model)
+		m.viewState = view
+		return m
+	}
+	press := func(m Model, key string) (Model, tea.Cmd) {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(Model), cmd
+	}
+
+	tests := []struct {
+		name   string
+		view   ViewState
+		key    string
+		states map[string]settings.PluginState
+		want   call
+		flash  string
+	}{
+		{
+			name:  "no state enables in user scope",
+			view:  ViewDetail,
+			key:   "e",
+			want:  call{"beta@mkt", true, settings.ScopeUser, ""},
+			flash: "Enabled in user scope",
+		},
+		{
+			name: "writes to the deciding scope",
+			view: ViewDetail,
+			key:  "e",
+			states: map[string]settings.PluginState{
+				"beta@mkt": {FullName: "beta@mkt", Enabled: true, Scope: settings.ScopeProject},
+			},
+			want:  call{"beta@mkt", false, settings.ScopeProject, ""},
+			flash: "Disabled in project scope",
+		},
+		{
+			name: "shift+e in the list",
+			view: ViewList,
+			key:  "E",
+			states: map[string]settings.PluginState{
+				"beta@mkt": {FullName: "beta@mkt", Enabled: false, Scope: settings.ScopeLocal},
+			},
+			want:  call{"beta@mkt", true, settings.ScopeLocal, ""},
+			flash: "Enabled in local scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			m, cmd := press(newModel("beta", tt.view, tt.states), tt.key)
+			if len(calls) != 1 || calls[0] != tt.want {
+				t.Fatalf("setPluginEnabled calls = %+v, want [%+v]", calls, tt.want)
+			}
+			if cmd == nil || m.toggleFlash != tt.flash {
+				t.Errorf("toggleFlash = %q, want %q", m.toggleFlash, tt.flash)
+			}
+			if got := m.pluginStates["beta@mkt"]; got.Enabled != tt.want.enabled || got.Scope != tt.want.scope {
+				t.Errorf("state not updated: %+v", got)
+			}
+			if tt.states != nil && tt.states["beta@mkt"].Enabled == tt.want.enabled {
+				t.Error("toggle should not modify the previous model's states")
+			}
+
+			var rendered string
+			if tt.view == ViewDetail {
+				rendered = m.detailView()
+			} else {
+				rendered = strings.Join(m.statusBarParts(), " ")
+			}
+			if !strings.Contains(rendered, tt.flash) {
+				t.Errorf("view should show %q", tt.flash)
+			}
+
+			updated, _ := m.Update(clearToggleFlashMsg{})
+			m = updated.(Model)
+			if m.toggleFlash != "" {
+				t.Error("flash should clear")
+			}
+			if tt.view == ViewDetail {
+				wantHint := "enable"
+				if tt.want.enabled {
+					wantHint = "disable"
+				}
+				if !strings.Contains(m.detailView(), "e "+wantHint) {
+					t.Errorf("footer should offer to %s", wantHint)
+				}
+			}
+		})
+	}
+
+	t.Run("managed scope is refused", func(t *testing.T) {
+		calls = nil
+		states := map[string]settings.PluginState{
+			"beta@mkt": {FullName: "beta@mkt", Enabled: true, Scope: settings.ScopeManaged},
+		}
+		m, _ := press(newModel("beta", ViewDetail, states), "e")
+		if len(calls) != 0 {
+			t.Fatalf("managed plugin should not be written, got %+v", calls)
+		}
+		if m.toggleError == nil || !strings.Contains(m.detailView(), "managed settings") {
+			t.Errorf("expected a managed settings error flash, got %v", m.toggleError)
+		}
+	})
+
+	t.Run("write failure", func(t *testing.T) {
+		setPluginEnabled = func(string, bool, settings.Scope, string) error {
+			return fmt.Errorf("permission denied")
+		}
+		m, _ := press(newModel("beta", ViewDetail, nil), "e")
+		if m.toggleError == nil || m.pluginStates["beta@mkt"].Enabled {
+			t.Errorf("failed write should leave state alone and flash an error, got %v", m.toggleError)
+		}
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		calls = nil
+		if m, cmd := press(newModel("alpha", ViewDetail, nil), "e"); cmd != nil || len(calls) != 0 || m.toggleFlash != "" {
+			t.Error("'e' should do nothing for a plugin that isn't installed")
+		}
+	})
+}
+
+func TestDisabledPluginsShownDistinctly(t *testing.T) {
+	m := NewModel()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	plugins := []plugin.Plugin{
+		{Name: "alpha", Marketplace: "mkt", Version: "1.0.0", Installed: true},
+		{Name: "beta", Marketplace: "mkt", Version: "1.0.0", Installed: true},
+		{Name: "gamma", Marketplace: "mkt", Version: "1.0.0", Installed: true},
+		{Name: "delta", Marketplace: "mkt", Version: "1.0.0"},
+	}
+	states := map[string]settings.PluginState{
+		"alpha@mkt": {FullName: "alpha@mkt", Enabled: true, Scope: settings.ScopeUser},
+		"beta@mkt":  {FullName: "beta@mkt", Enabled: false, Scope: settings.ScopeProject},
+	}
+	updated, _ = m.Update(pluginsLoadedMsg{plugins: plugins, states: states, generation: m.reloadGeneration})
+	m = updated.(Model)
+
+	// gamma has no settings entry, so it is disabled too
+	if got := m.DisabledCount(); got != 2 {
+		t.Errorf("DisabledCount() = %d, want 2", got)
+	}
+	if bar := strings.Join(m.statusBarParts(), " "); !strings.Contains(bar, "2 disabled") {
+		t.Errorf("status bar should show the disabled count, got %q", bar)
+	}
+
+	for _, p := range plugins {
+		wantDisabled := p.Name == "beta" || p.Name == "gamma"
+		for mode, row := range map[string]string{
+			"slim": m.renderPluginItemSlim(p, nil, false),
+			"card": m.renderPluginItemCard(p, nil, false),
+		} {
+			if got := strings.Contains(row, "[Disabled]"); got != wantDisabled {
+				t.Errorf("%s %s row shows [Disabled] = %v, want %v", mode, p.Name, got, wantDisabled)
+			}
+			if got := strings.Contains(row, DisabledIndicator.String()); got != wantDisabled {
+				t.Errorf("%s %s row uses the disabled indicator = %v, want %v", mode, p.Name, got, wantDisabled)
+			}
+		}
+	}
+	// Before states load, installed plugins aren't marked disabled
+	m.pluginStates = nil
+	if m.DisabledCount() != 0 || strings.Contains(m.renderPluginItemSlim(plugins[1], nil, false), "[Disabled]") {
+		t.Error("plugins should not show as disabled without loaded states")
+	}
+}
+
+// TestLoadErrorView verifies a failed load shows setup guidance for a missing
+// or corrupt Claude Code config, and the raw error otherwise
+func TestLoadErrorView(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+
+	tests := []struct {
+		name    string
+		err     error
+		want    string
+		notWant string
+	}{
+		{"not installed", fmt.Errorf("%w at /nowhere", config.ErrClaudeNotInstalled), "Install Claude Code", "Error loading plugins"},
+		{"corrupt file", &config.CorruptFileError{Path: "/x/installed_plugins.json", Err: errors.New("bad")}, "isn't valid JSON", "Install Claude Code"},
+		{"other error", errors.New("disk on fire"), "Error loading plugins: disk on fire", "Install Claude Code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModel()
+			updated, _ := m.Update(pluginsLoadedMsg{err: tt.err})
+			view := updated.(Model).View()
+
+			if !strings.Contains(view, tt.want) {
+				t.Errorf("view missing %q:\n%s", tt.want, view)
+			}
+			if strings.Contains(view, tt.notWant) {
+				t.Errorf("view should not contain %q:\n%s", tt.notWant, view)
+			}
+			if !strings.Contains(view, "Press q to quit.") {
+				t.Errorf("view missing quit hint:\n%s", view)
+			}
+		})
+	}
+}
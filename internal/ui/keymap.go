@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// KeyBinding is the list of keys that trigger a command, as reported by
+// tea.KeyMsg.String() (e.g. "down", "ctrl+j", "J", " ")
+type KeyBinding []string
+
+// Has reports whether key triggers the binding
+func (b KeyBinding) Has(key string) bool {
+	for _, k := range b {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyMap holds the keys for every remappable command. The top-level
+// bindings are shared by the views that support them; the nested groups
+// apply to one view each. ctrl+c always quits and can't be remapped.
+type KeyMap struct {
+	Up          KeyBinding `json:"up"`
+	Down        KeyBinding `json:"down"`
+	PageUp      KeyBinding `json:"pageUp"`
+	PageDown    KeyBinding `json:"pageDown"`
+	Top         KeyBinding `json:"top"`
+	Bottom      KeyBinding `json:"bottom"`
+	GoTop       KeyBinding `json:"goTop"` // Pressed twice, like vim's gg
+	Select      KeyBinding `json:"select"`
+	Back        KeyBinding `json:"back"`
+	Quit        KeyBinding `json:"quit"`
+	Help        KeyBinding `json:"help"`
+	NextTab     KeyBinding `json:"nextTab"`
+	PrevTab     KeyBinding `json:"prevTab"`
+	Marketplace KeyBinding `json:"marketplace"`
+
+	List         ListKeyMap        `json:"list"`
+	Detail       DetailKeyMap      `json:"detail"`
+	HelpView     HelpKeyMap        `json:"helpView"`
+	Marketplaces MarketplaceKeyMap `json:"marketplaces"`
+	Confirm      ConfirmKeyMap     `json:"confirm"`
+}
+
+// ListKeyMap holds the plugin list's commands. Letters type into the
+// search there, so the defaults use shifted or ctrl keys.
+type ListKeyMap struct {
+	ClearSearch     KeyBinding `json:"clearSearch"`
+	ToggleView      KeyBinding `json:"toggleView"`
+	ExpandRow       KeyBinding `json:"expandRow"`
+	Sort            KeyBinding `json:"sort"`
+	PreferInstalled KeyBinding `json:"preferInstalled"`
+	Transition      KeyBinding `json:"transition"`
+	ToggleEnabled   KeyBinding `json:"toggleEnabled"`
+	Favorite        KeyBinding `json:"favorite"`
+	Star            KeyBinding `json:"star"` // Favorite while the search is empty
+	Export          KeyBinding `json:"export"`
+	ExportFormat    KeyBinding `json:"exportFormat"`
+	Mark            KeyBinding `json:"mark"`
+	CopyMarked      KeyBinding `json:"copyMarked"`
+	Refresh         KeyBinding `json:"refresh"`
+}
+
+// DetailKeyMap holds the plugin detail view's commands
+type DetailKeyMap struct {
+	Copy          KeyBinding `json:"copy"`
+	CopyCommand   KeyBinding `json:"copyCommand"`
+	CopyLink      KeyBinding `json:"copyLink"`
+	CopyJSONURL   KeyBinding `json:"copyJsonUrl"`
+	CopyPath      KeyBinding `json:"copyPath"`
+	OpenGitHub    KeyBinding `json:"openGitHub"`
+	OpenLocal     KeyBinding `json:"openLocal"`
+	Install       KeyBinding `json:"install"`
+	ToggleEnabled KeyBinding `json:"toggleEnabled"`
+	Favorite      KeyBinding `json:"favorite"`
+}
+
+// HelpKeyMap holds the help view's commands
+type HelpKeyMap struct {
+	EditSettings KeyBinding `json:"editSettings"`
+}
+
+// MarketplaceKeyMap holds the marketplace browser's commands
+type MarketplaceKeyMap struct {
+	Close      KeyBinding `json:"close"`      // Leave the browser from its list
+	Filter     KeyBinding `json:"filter"`     // Cycle the list's status filter
+	Copy       KeyBinding `json:"copy"`       // Copy the add command in the detail view
+	ShowInList KeyBinding `json:"showInList"` // Filter the plugin list to the marketplace
+	OpenGitHub KeyBinding `json:"openGitHub"`
+}
+
+// ConfirmKeyMap holds the confirmation dialog's answers
+type ConfirmKeyMap struct {
+	Yes KeyBinding `json:"yes"`
+	No  KeyBinding `json:"no"`
+}
+
+// DefaultKeyMap returns plum's built-in key bindings
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:          KeyBinding{"up", "ctrl+k", "ctrl+p"},
+		Down:        KeyBinding{"down", "ctrl+j", "ctrl+n"},
+		PageUp:      KeyBinding{"pgup", "ctrl+u"},
+		PageDown:    KeyBinding{"pgdown", "ctrl+d"},
+		Top:         KeyBinding{"home"},
+		Bottom:      KeyBinding{"end", "shift+g", "G"},
+		GoTop:       KeyBinding{"g"},
+		Select:      KeyBinding{"enter"},
+		Back:        KeyBinding{"esc", "backspace"},
+		Quit:        KeyBinding{"q"},
+		Help:        KeyBinding{"?"},
+		NextTab:     KeyBinding{"tab", "right"},
+		PrevTab:     KeyBinding{"shift+tab", "left"},
+		Marketplace: KeyBinding{"shift+m", "M"},
+		List: ListKeyMap{
+			ClearSearch:     KeyBinding{"esc", "ctrl+g"},
+			ToggleView:      KeyBinding{"shift+v", "V"},
+			ExpandRow:       KeyBinding{"ctrl+o"},
+			Sort:            KeyBinding{"ctrl+s", "shift+s", "S"},
+			PreferInstalled: KeyBinding{"ctrl+b"},
+			Transition:      KeyBinding{"ctrl+t"},
+			ToggleEnabled:   KeyBinding{"shift+e", "E"},
+			Favorite:        KeyBinding{"shift+f", "F"},
+			Star:            KeyBinding{"*"},
+			Export:          KeyBinding{"shift+x", "X"},
+			ExportFormat:    KeyBinding{"ctrl+x"},
+			Mark:            KeyBinding{" "},
+			CopyMarked:      KeyBinding{"y"},
+			Refresh:         KeyBinding{"shift+u", "U"},
+		},
+		Detail: DetailKeyMap{
+			Copy:          KeyBinding{"c"},
+			CopyCommand:   KeyBinding{"y"},
+			CopyLink:      KeyBinding{"l"},
+			CopyJSONURL:   KeyBinding{"r"},
+			CopyPath:      KeyBinding{"p"},
+			OpenGitHub:    KeyBinding{"g"},
+			OpenLocal:     KeyBinding{"o"},
+			Install:       KeyBinding{"i"},
+			ToggleEnabled: KeyBinding{"e"},
+			Favorite:      KeyBinding{"f", "*"},
+		},
+		HelpView: HelpKeyMap{
+			EditSettings: KeyBinding{"e"},
+		},
+		Marketplaces: MarketplaceKeyMap{
+			Close:      KeyBinding{"esc", "ctrl+g"},
+			Filter:     KeyBinding{"f"},
+			Copy:       KeyBinding{"c"},
+			ShowInList: KeyBinding{"f"},
+			OpenGitHub: KeyBinding{"g"},
+		},
+		Confirm: ConfirmKeyMap{
+			Yes: KeyBinding{"y", "Y", "enter"},
+			No:  KeyBinding{"n", "N", "esc"},
+		},
+	}
+}
+
+// namedBinding is a binding with its keys.json name, for validation
+type namedBinding struct {
+	name string
+	keys KeyBinding
+}
+
+// viewBindings groups the bindings each view responds to. Within a view a
+// key may trigger only one command.
+func (k KeyMap) viewBindings() map[string][]namedBinding {
+	return map[string][]namedBinding{
+		"list": {
+			{"up", k.Up}, {"down", k.Down}, {"pageUp", k.PageUp}, {"pageDown", k.PageDown},
+			{"top", k.Top}, {"bottom", k.Bottom}, {"goTop", k.GoTop}, {"select", k.Select}, {"help", k.Help},
+			{"nextTab", k.NextTab}, {"prevTab", k.PrevTab}, {"marketplace", k.Marketplace},
+			{"list.clearSearch", k.List.ClearSearch}, {"list.toggleView", k.List.ToggleView},
+			{"list.expandRow", k.List.ExpandRow}, {"list.sort", k.List.Sort},
+			{"list.preferInstalled", k.List.PreferInstalled}, {"list.transition", k.List.Transition},
+			{"list.toggleEnabled", k.List.ToggleEnabled}, {"list.favorite", k.List.Favorite}, {"list.star", k.List.Star},
+			{"list.export", k.List.Export}, {"list.exportFormat", k.List.ExportFormat},
+			{"list.mark", k.List.Mark}, {"list.copyMarked", k.List.CopyMarked},
+			{"list.refresh", k.List.Refresh},
+		},
+		"detail": {
+			{"back", k.Back}, {"quit", k.Quit}, {"help", k.Help}, {"marketplace", k.Marketplace},
+			{"detail.copy", k.Detail.Copy}, {"detail.copyCommand", k.Detail.CopyCommand},
+			{"detail.copyLink", k.Detail.CopyLink}, {"detail.copyJsonUrl", k.Detail.CopyJSONURL},
+			{"detail.copyPath", k.Detail.CopyPath}, {"detail.openGitHub", k.Detail.OpenGitHub},
+			{"detail.openLocal", k.Detail.OpenLocal}, {"detail.install", k.Detail.Install},
+			{"detail.toggleEnabled", k.Detail.ToggleEnabled}, {"detail.favorite", k.Detail.Favorite},
+		},
+		"help": {
+			{"back", k.Back}, {"quit", k.Quit}, {"help", k.Help}, {"select", k.Select},
+			{"marketplace", k.Marketplace}, {"helpView.editSettings", k.HelpView.EditSettings},
+		},
+		"marketplace list": {
+			{"up", k.Up}, {"down", k.Down}, {"top", k.Top}, {"bottom", k.Bottom}, {"goTop", k.GoTop},
+			{"select", k.Select}, {"nextTab", k.NextTab}, {"prevTab", k.PrevTab},
+			{"help", k.Help}, {"quit", k.Quit},
+			{"marketplaces.close", k.Marketplaces.Close}, {"marketplaces.filter", k.Marketplaces.Filter},
+		},
+		"marketplace detail": {
+			{"back", k.Back}, {"select", k.Select}, {"help", k.Help}, {"quit", k.Quit},
+			{"marketplaces.copy", k.Marketplaces.Copy}, {"marketplaces.showInList", k.Marketplaces.ShowInList},
+			{"marketplaces.openGitHub", k.Marketplaces.OpenGitHub},
+		},
+		"confirm": {
+			{"confirm.yes", k.Confirm.Yes}, {"confirm.no", k.Confirm.No},
+		},
+	}
+}
+
+// Validate reports empty keys and keys bound to two commands in one view
+func (k KeyMap) Validate() error {
+	views := k.viewBindings()
+	names := make([]string, 0, len(views))
+	for view := range views {
+		names = append(names, view)
+	}
+	sort.Strings(names)
+
+	for _, view := range names {
+		owner := map[string]string{}
+		for _, b := range views[view] {
+			for _, key := range b.keys {
+				if key == "" {
+					return fmt.Errorf("%s: empty key", b.name)
+				}
+				if other, ok := owner[key]; ok && other != b.name {
+					return fmt.Errorf("%q is bound to both %s and %s in the %s view", key, other, b.name, view)
+				}
+				owner[key] = b.name
+			}
+		}
+	}
+	return nil
+}
+
+// keysPath returns the user's key bindings file, keys.json in plumConfigDir
+// (variable for testing)
+var keysPath = func() (string, error) {
+	dir, err := plumConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "keys.json"), nil
+}
+
+// readKeyMap reads a key bindings file over the defaults. Bindings left out
+// keep their default keys; a missing file is the default key map.
+func readKeyMap(path string) (KeyMap, error) {
+	keys := DefaultKeyMap()
+
+	// #nosec G304 -- path is the user's own key bindings file
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return keys, nil
+	}
+	if err != nil {
+		return keys, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&keys); err != nil {
+		if strings.HasPrefix(err.Error(), "json: unknown field") {
+			return DefaultKeyMap(), fmt.Errorf("unknown binding %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+		}
+		return DefaultKeyMap(), fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := keys.Validate(); err != nil {
+		return DefaultKeyMap(), err
+	}
+	return keys, nil
+}
+
+// loadKeyMap returns the key map at path. If the file can't be used, a
+// warning is written to warn and the default bindings are returned.
+func loadKeyMap(path string, warn io.Writer) KeyMap {
+	keys, err := readKeyMap(path)
+	if err != nil {
+		_, _ = fmt.Fprintf(warn, "Warning: ignoring key bindings %s: %v\n", path, err)
+		return DefaultKeyMap()
+	}
+	return keys
+}
+
+// userKeyMap is the user's key map, read the first time a model is created
+var (
+	userKeyMapOnce sync.Once
+	userKeyMap     KeyMap
+)
+
+func loadUserKeyMap() KeyMap {
+	userKeyMapOnce.Do(func() {
+		userKeyMap = DefaultKeyMap()
+		if path, err := keysPath(); err == nil {
+			userKeyMap = loadKeyMap(path, os.Stderr)
+		}
+	})
+	return userKeyMap
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// writeKeys writes a key bindings file in a temp dir
+func writeKeys(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultKeyMapIsValid(t *testing.T) {
+	if err := DefaultKeyMap().Validate(); err != nil {
+		t.Errorf("default key map should be valid: %v", err)
+	}
+}
+
+func TestLoadKeyMap_RemapDown(t *testing.T) {
+	path := writeKeys(t, `{"down": ["J"], "detail": {"install": ["I"]}}`)
+
+	var warn bytes.Buffer
+	keys := loadKeyMap(path, &warn)
+	if warn.Len() > 0 {
+		t.Fatalf("unexpected warning: %s", warn.String())
+	}
+	if !keys.Down.Has("J") || keys.Down.Has("down") {
+		t.Errorf("down = %v, want [J]", keys.Down)
+	}
+	if !keys.Detail.Install.Has("I") || keys.Detail.Install.Has("i") {
+		t.Errorf("detail.install = %v, want [I]", keys.Detail.Install)
+	}
+	if !keys.Up.Has("up") || !keys.Detail.Copy.Has("c") {
+		t.Error("bindings left out of the file should keep their defaults")
+	}
+
+	m := NewModel()
+	m.keys = keys
+	m.loading = false
+	m.allPlugins = []plugin.Plugin{
+		{Name: "alpha", Marketplace: "mkt"},
+		{Name: "beta", Marketplace: "mkt"},
+	}
+	m.applyFilter()
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	if m.cursor != 1 {
+		t.Errorf("J should move down, cursor = %d", m.cursor)
+	}
+	if m.textInput.Value() != "" {
+		t.Errorf("J should not type into the search, got %q", m.textInput.Value())
+	}
+
+	// The old key no longer navigates
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	if m.cursor != 0 {
+		t.Errorf("down should no longer move the cursor, cursor = %d", m.cursor)
+	}
+}
+
+// TestLoadKeyMap_RemappedListKeys verifies search history, gg and '*' follow
+// their bindings rather than fixed keys
+func TestLoadKeyMap_RemappedListKeys(t *testing.T) {
+	path := writeKeys(t, `{"up": ["ctrl+k"], "down": ["ctrl+j"], "goTop": ["ctrl+t"], "list": {"star": ["ctrl+f"], "transition": ["ctrl+y"]}}`)
+	var warn bytes.Buffer
+	keys := loadKeyMap(path, &warn)
+	if warn.Len() > 0 {
+		t.Fatalf("unexpected warning: %s", warn.String())
+	}
+
+	m := NewModel()
+	m.keys = keys
+	m.loading = false
+	m.windowHeight = 40
+	m.allPlugins = []plugin.Plugin{
+		{Name: "alpha", Marketplace: "mkt"},
+		{Name: "beta", Marketplace: "mkt"},
+	}
+	m.applyFilter()
+	m.searchHistory = []string{"alpha", "beta"}
+	saveFavorites = func(map[string]bool) error { return nil }
+	defer func() { saveFavorites = writeFavorites }()
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if got := m.textInput.Value(); got != "beta" {
+		t.Fatalf("remapped up should recall the last search, got %q", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	if got := m.textInput.Value(); got != "" {
+		t.Fatalf("remapped down should step forward to the empty search, got %q", got)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	if m.cursor != 1 {
+		t.Fatalf("remapped down should navigate once history is done, cursor = %d", m.cursor)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlT})
+	press(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.cursor != 0 {
+		t.Errorf("remapped goTop pressed twice should jump to the top, cursor = %d", m.cursor)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if !m.favorites["alpha@mkt"] {
+		t.Error("remapped star should favorite the selected plugin")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	if m.textInput.Value() != "*" {
+		t.Errorf("'*' should type into the search once unbound, got %q", m.textInput.Value())
+	}
+}
+
+func TestLoadKeyMap_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown binding", `{"jump": ["x"]}`, `unknown binding "jump"`},
+		{"unknown nested binding", `{"list": {"fly": ["x"]}}`, `unknown binding "fly"`},
+		{"duplicate key", `{"down": ["?"]}`, `"?" is bound to both`},
+		{"empty key", `{"quit": [""]}`, "quit: empty key"},
+		{"invalid JSON", `{"down": "J"`, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeKeys(t, tt.content)
+			if _, err := readKeyMap(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("readKeyMap error = %v, want %q", err, tt.wantErr)
+			}
+
+			var warn bytes.Buffer
+			keys := loadKeyMap(path, &warn)
+			if !strings.Contains(warn.String(), "Warning: ignoring key bindings") {
+				t.Errorf("expected a warning, got %q", warn.String())
+			}
+			if !keys.Down.Has("down") || !keys.Quit.Has("q") {
+				t.Error("a rejected file should fall back to the default bindings")
+			}
+		})
+	}
+}
+
+func TestLoadKeyMap_SameKeyInDifferentViews(t *testing.T) {
+	// 'x' in the detail view and the help view never collide
+	path := writeKeys(t, `{"detail": {"copy": ["x"]}, "helpView": {"editSettings": ["x"]}}`)
+	if _, err := readKeyMap(path); err != nil {
+		t.Errorf("a key reused across views should be allowed: %v", err)
+	}
+}
+
+func TestLoadKeyMap_MissingFile(t *testing.T) {
+	var warn bytes.Buffer
+	keys := loadKeyMap(filepath.Join(t.TempDir(), "keys.json"), &warn)
+	if warn.Len() > 0 {
+		t.Errorf("a missing file should not warn: %s", warn.String())
+	}
+	if !keys.Down.Has("down") {
+		t.Error("a missing file should give the default bindings")
+	}
+}
@@ -0,0 +1,336 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// maxReadmeSize caps how much of an installed plugin's README is shown
+const maxReadmeSize = 64 << 10
+
+// detailText returns the text shown in a plugin's detail view: the README
+// from its install directory when there is one, else its description
+func detailText(p *plugin.Plugin) string {
+	if p.Installed && p.InstallPath != "" {
+		// #nosec G304 -- InstallPath comes from the install registry
+		if f, err := os.Open(filepath.Join(p.InstallPath, "README.md")); err == nil {
+			defer func() { _ = f.Close() }()
+			data, err := io.ReadAll(io.LimitReader(f, maxReadmeSize))
+			if readme := strings.TrimSpace(string(data)); err == nil && readme != "" && utf8.ValidString(readme) {
+				return readme
+			}
+		}
+	}
+	return p.Description
+}
+
+// renderMarkdown renders the basic markdown found in plugin READMEs and
+// descriptions: headings, paragraphs, bullet and numbered lists, block
+// quotes, fenced code, and inline bold, italic, code, and links. Text is
+// wrapped to width. The renderer is best effort; if it panics on odd input
+// the text is shown plain-wrapped instead.
+func renderMarkdown(src string, width int) (out string) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = wrapText(src, width)
+		}
+	}()
+
+	if width < 10 {
+		width = 10
+	}
+
+	var lines []string
+	var para []string
+	prevKind := ""
+
+	// gap separates blocks with a single blank line
+	gap := func() {
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+	}
+	flushPara := func() {
+		if len(para) > 0 {
+			gap()
+			lines = append(lines, wrapMarkdown(strings.Join(para, " "), width, newStyle(), "", "")...)
+			para = nil
+		}
+	}
+
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flushPara()
+			if !inCode {
+				gap()
+			}
+			inCode = !inCode
+			prevKind = "code"
+			continue
+		}
+		if inCode {
+			lines = append(lines, "  "+MarkdownCodeStyle.Render(truncateToWidth(line, width-2)))
+			continue
+		}
+
+		kind := ""
+		switch {
+		case trimmed == "":
+			flushPara()
+
+		case isMarkdownRule(trimmed):
+			flushPara()
+			gap()
+			lines = append(lines, HelpStyle.Render(strings.Repeat(ruleChar, width)))
+
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			text := strings.TrimSpace(strings.TrimRight(trimmed[level:], "#"))
+			if level > 6 || !strings.HasPrefix(trimmed[level:], " ") {
+				para = append(para, trimmed)
+				kind = "para"
+				break
+			}
+			flushPara()
+			gap()
+			lines = append(lines, wrapMarkdown(text, width, MarkdownHeadingStyle, "", "")...)
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushPara()
+			if prevKind != "quote" {
+				gap()
+			}
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			lines = append(lines, wrapMarkdown(text, width, MarkdownQuoteStyle, separatorBar+" ", separatorBar+" ")...)
+			kind = "quote"
+
+		default:
+			if marker, text, ok := listItem(trimmed); ok {
+				flushPara()
+				if prevKind != "list" {
+					gap()
+				}
+				indent := strings.Repeat("  ", (len(line)-len(strings.TrimLeft(line, " ")))/2)
+				lines = append(lines, wrapMarkdown(text, width, newStyle(),
+					indent+marker, indent+strings.Repeat(" ", lipgloss.Width(marker)))...)
+				kind = "list"
+				break
+			}
+			para = append(para, trimmed)
+			kind = "para"
+		}
+		prevKind = kind
+	}
+	flushPara()
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isMarkdownRule reports whether a trimmed line is a horizontal rule
+func isMarkdownRule(line string) bool {
+	if len(line) < 3 {
+		return false
+	}
+	compact := strings.ReplaceAll(line, " ", "")
+	for _, c := range []string{"-", "*", "_"} {
+		if strings.Trim(compact, c) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// listItem splits a trimmed bullet ("- x", "* x", "+ x") or numbered
+// ("1. x") list line into its display marker and text
+func listItem(line string) (marker, text string, ok bool) {
+	if len(line) >= 2 && strings.ContainsRune("-*+", rune(line[0])) && line[1] == ' ' {
+		return "• ", strings.TrimSpace(line[2:]), true
+	}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	if digits > 0 && digits < len(line)-1 && line[digits] == '.' && line[digits+1] == ' ' {
+		return line[:digits+1] + " ", strings.TrimSpace(line[digits+2:]), true
+	}
+	return "", "", false
+}
+
+// mdPiece is a run of text with one inline style
+type mdPiece struct {
+	text  string
+	style lipgloss.Style
+}
+
+// mdWord is a word made of one or more differently styled pieces, e.g. a
+// bold word followed by a plain comma
+type mdWord []mdPiece
+
+func (w mdWord) width() int {
+	n := 0
+	for _, p := range w {
+		n += lipgloss.Width(p.text)
+	}
+	return n
+}
+
+func (w mdWord) render() string {
+	var b strings.Builder
+	for _, p := range w {
+		b.WriteString(p.style.Render(p.text))
+	}
+	return b.String()
+}
+
+// wrapMarkdown renders inline markdown in text and wraps it to width. The
+// first line starts with first and later lines with rest.
+func wrapMarkdown(text string, width int, style lipgloss.Style, first, rest string) []string {
+	var lines []string
+	var line strings.Builder
+	line.WriteString(first)
+	lineWidth := lipgloss.Width(first)
+	empty := true
+
+	for _, word := range splitMarkdownWords(parseInline(text, style)) {
+		for _, chunk := range splitLongWord(word, width-lipgloss.Width(rest)) {
+			chunkWidth := chunk.width()
+			if !empty && lineWidth+1+chunkWidth > width {
+				lines = append(lines, line.String())
+				line.Reset()
+				line.WriteString(rest)
+				lineWidth = lipgloss.Width(rest)
+				empty = true
+			}
+			if !empty {
+				line.WriteString(" ")
+				lineWidth++
+			}
+			line.WriteString(chunk.render())
+			lineWidth += chunkWidth
+			empty = false
+		}
+	}
+	return append(lines, line.String())
+}
+
+// splitLongWord breaks a word wider than maxWidth into pieces that fit
+func splitLongWord(word mdWord, maxWidth int) []mdWord {
+	if maxWidth < 1 || word.width() <= maxWidth {
+		return []mdWord{word}
+	}
+
+	var chunks []mdWord
+	var chunk mdWord
+	chunkWidth := 0
+	for _, p := range word {
+		var run strings.Builder
+		for _, r := range p.text {
+			rw := lipgloss.Width(string(r))
+			if chunkWidth+rw > maxWidth && chunkWidth > 0 {
+				if run.Len() > 0 {
+					chunk = append(chunk, mdPiece{run.String(), p.style})
+					run.Reset()
+				}
+				chunks = append(chunks, chunk)
+				chunk, chunkWidth = nil, 0
+			}
+			run.WriteRune(r)
+			chunkWidth += rw
+		}
+		if run.Len() > 0 {
+			chunk = append(chunk, mdPiece{run.String(), p.style})
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// splitMarkdownWords splits styled pieces into words at spaces
+func splitMarkdownWords(pieces []mdPiece) []mdWord {
+	var words []mdWord
+	var word mdWord
+	for _, p := range pieces {
+		for i, part := range strings.Split(p.text, " ") {
+			if i > 0 && len(word) > 0 {
+				words = append(words, word)
+				word = nil
+			}
+			if part != "" {
+				word = append(word, mdPiece{part, p.style})
+			}
+		}
+	}
+	if len(word) > 0 {
+		words = append(words, word)
+	}
+	return words
+}
+
+// parseInline splits text into styled pieces for `code`, **bold**,
+// *italic*, and [links](url). Unmatched markers are kept as written.
+func parseInline(s string, style lipgloss.Style) []mdPiece {
+	var pieces []mdPiece
+	var plain strings.Builder
+	emit := func() {
+		if plain.Len() > 0 {
+			pieces = append(pieces, mdPiece{plain.String(), style})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				emit()
+				pieces = append(pieces, mdPiece{rest[1 : 1+end], MarkdownCodeStyle})
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				emit()
+				pieces = append(pieces, parseInline(rest[2:2+end], style.Bold(true))...)
+				i += end + 4
+				continue
+			}
+
+		case (rest[0] == '*' || (rest[0] == '_' && (i == 0 || s[i-1] == ' '))) && len(rest) > 1 && rest[1] != ' ':
+			if end := strings.IndexByte(rest[1:], rest[0]); end > 0 {
+				emit()
+				pieces = append(pieces, parseInline(rest[1:1+end], style.Italic(true))...)
+				i += end + 2
+				continue
+			}
+
+		case rest[0] == '[' || strings.HasPrefix(rest, "!["):
+			open := strings.IndexByte(rest, '[')
+			if mid := strings.Index(rest, "]("); mid > open {
+				if end := strings.IndexByte(rest[mid+2:], ')'); end >= 0 {
+					emit()
+					pieces = append(pieces, parseInline(rest[open+1:mid], style.Underline(true))...)
+					i += mid + 2 + end + 1
+					continue
+				}
+			}
+		}
+		plain.WriteByte(s[i])
+		i++
+	}
+	emit()
+	return pieces
+}
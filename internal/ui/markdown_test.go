@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []string // substrings that must appear
+		notWant []string // raw markdown that must not appear
+	}{
+		{
+			name:    "heading",
+			src:     "# Docker Tools\n\nManage containers.",
+			want:    []string{"Docker Tools", "Manage containers."},
+			notWant: []string{"#"},
+		},
+		{
+			name:    "bold and italic",
+			src:     "Use **fast** mode or *careful* mode or __strict__ mode.",
+			want:    []string{"Use fast mode or careful mode or strict mode."},
+			notWant: []string{"**", "__", "*careful*"},
+		},
+		{
+			name:    "inline code",
+			src:     "Run `plum install docker` to start.",
+			want:    []string{"Run plum install docker to start."},
+			notWant: []string{"`"},
+		},
+		{
+			name:    "bullet list",
+			src:     "Features:\n\n- one\n* two\n+ three",
+			want:    []string{"• one", "• two", "• three"},
+			notWant: []string{"- one", "* two", "+ three"},
+		},
+		{
+			name: "numbered list",
+			src:  "1. first\n2. second",
+			want: []string{"1. first", "2. second"},
+		},
+		{
+			name:    "link",
+			src:     "See [the docs](https://example.com/docs) and ![logo](logo.png).",
+			want:    []string{"See the docs and logo."},
+			notWant: []string{"](", "https://example.com"},
+		},
+		{
+			name:    "code block kept verbatim",
+			src:     "```bash\n**not bold** `x`\n```",
+			want:    []string{"**not bold** `x`"},
+			notWant: []string{"```"},
+		},
+		{
+			name: "snake_case is not italic",
+			src:  "Set max_retry_count in config.",
+			want: []string{"max_retry_count"},
+		},
+		{
+			name: "unmatched markers stay",
+			src:  "2 * 3 and a lone ` tick",
+			want: []string{"2 * 3 and a lone ` tick"},
+		},
+		{
+			name: "plain text",
+			src:  "Just a plain description.",
+			want: []string{"Just a plain description."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderMarkdown(tt.src, 60)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("output missing %q:\n%s", w, got)
+				}
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(got, nw) {
+					t.Errorf("output contains raw markdown %q:\n%s", nw, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_Wraps(t *testing.T) {
+	src := "# A heading long enough to need wrapping at this width\n\n" +
+		"A **paragraph** of text with `code` that goes on well past the width.\n\n" +
+		"- a bullet item that also runs past the width and wraps\n" +
+		"- averyveryveryveryveryverylongtokenwithoutanyspaces"
+
+	const width = 24
+	got := renderMarkdown(src, width)
+	for _, line := range strings.Split(got, "\n") {
+		if w := lipgloss.Width(line); w > width {
+			t.Errorf("line %q is %d wide, want <= %d", line, w, width)
+		}
+	}
+	if !strings.Contains(got, "• a bullet item") || !strings.Contains(got, "\n  ") {
+		t.Errorf("bullet continuation lines should be indented:\n%s", got)
+	}
+}
+
+func TestDetailText(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Alpha\n\n**Readme** body"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	installed := &plugin.Plugin{Name: "alpha", Description: "desc", Installed: true, InstallPath: dir}
+	if got := detailText(installed); !strings.Contains(got, "Readme") {
+		t.Errorf("installed plugin with a README should show it, got %q", got)
+	}
+
+	noReadme := &plugin.Plugin{Name: "beta", Description: "desc", Installed: true, InstallPath: t.TempDir()}
+	if got := detailText(noReadme); got != "desc" {
+		t.Errorf("missing README should fall back to the description, got %q", got)
+	}
+
+	available := &plugin.Plugin{Name: "gamma", Description: "desc", InstallPath: dir}
+	if got := detailText(available); got != "desc" {
+		t.Errorf("uninstalled plugin should show its description, got %q", got)
+	}
+
+	m := NewModel()
+	content := m.generateDetailContent(installed, 60)
+	if !strings.Contains(content, "Readme body") || strings.Contains(content, "**") || strings.Contains(content, "# Alpha") {
+		t.Errorf("detail content should render the README as markdown:\n%s", content)
+	}
+}
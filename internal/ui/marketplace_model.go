@@ -1,7 +1,9 @@
 package ui
 
 import (
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 )
 
 // MarketplaceStatus represents the installation status of a marketplace
@@ -26,6 +28,174 @@ type MarketplaceItem struct {
 	GitHubStats          *marketplace.GitHubStats // GitHub repo stats (may be nil)
 	StatsLoading         bool                     // True while fetching stats
 	StatsError           error                    // Stats fetch error if any
+	TopPlugins           []string                 // First plugins in the cached manifest (nil if uncached)
+	statsCached          bool                     // GitHubStats came from the 24h stats cache
+}
+
+// marketplaceStatsConcurrency caps how many GitHub stats requests run at once
+const marketplaceStatsConcurrency = 4
+
+// statsLoadedMsg carries freshly fetched GitHub stats for one marketplace
+type statsLoadedMsg struct {
+	name  string
+	stats *marketplace.GitHubStats
+	err   error
+}
+
+// fetchMarketplaceStats fetches a repo's GitHub stats and caches them
+// (variable for testing)
+var fetchMarketplaceStats = func(name, repo string) (*marketplace.GitHubStats, error) {
+	stats, err := marketplace.FetchGitHubStats(repo)
+	if err != nil {
+		return nil, err
+	}
+	_ = marketplace.SaveStatsToCache(name, stats) // Best effort; a failed write just refetches next time
+	return stats, nil
+}
+
+// loadMarketplaceStats marks every marketplace without cached stats as
+// loading and returns a command that fetches their stats in the background,
+// a few at a time, sending a statsLoadedMsg for each
+func (m *Model) loadMarketplaceStats() tea.Cmd {
+	sem := make(chan struct{}, marketplaceStatsConcurrency)
+	var cmds []tea.Cmd
+	for i := range m.marketplaceItems {
+		item := &m.marketplaceItems[i]
+		if item.statsCached || item.Repo == "" {
+			continue
+		}
+		item.StatsLoading = true
+		item.StatsError = nil
+		name, repo := item.Name, item.Repo
+		cmds = append(cmds, func() tea.Msg {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			stats, err := fetchMarketplaceStats(name, repo)
+			return statsLoadedMsg{name: name, stats: stats, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// applyMarketplaceStats stores fetched stats on the named marketplace. A
+// failed fetch keeps the stats it had (the static snapshot, if any). When
+// the list is sorted by stats it is re-sorted, keeping the cursor on the
+// same marketplace.
+func (m *Model) applyMarketplaceStats(msg statsLoadedMsg) {
+	found := false
+	for i := range m.marketplaceItems {
+		item := &m.marketplaceItems[i]
+		if item.Name != msg.name {
+			continue
+		}
+		found = true
+		item.StatsLoading = false
+		item.StatsError = msg.err
+		if msg.err == nil && msg.stats != nil {
+			item.GitHubStats = msg.stats
+			item.statsCached = true
+		}
+		if m.selectedMarketplace != nil && m.selectedMarketplace.Name == item.Name {
+			m.selectedMarketplace.GitHubStats = item.GitHubStats
+			m.selectedMarketplace.StatsLoading = false
+			m.selectedMarketplace.StatsError = item.StatsError
+		}
+	}
+	if !found || (m.marketplaceSortMode != SortByStars && m.marketplaceSortMode != SortByLastUpdated) {
+		return
+	}
+
+	var current string
+	if items := m.FilteredMarketplaceItems(); m.marketplaceCursor < len(items) {
+		current = items[m.marketplaceCursor].Name
+	}
+	m.ApplyMarketplaceSort()
+	for i, item := range m.FilteredMarketplaceItems() {
+		if item.Name == current {
+			m.marketplaceCursor = i
+			break
+		}
+	}
+	m.UpdateMarketplaceScroll()
+}
+
+// openMarketplaceBrowser loads the marketplaces, starts fetching stats that
+// aren't cached, and switches to the marketplace list
+func (m Model) openMarketplaceBrowser(from ViewState) (tea.Model, tea.Cmd) {
+	_ = m.LoadMarketplaceItems()
+	statsCmd := m.loadMarketplaceStats()
+	m.previousViewBeforeMarketplace = from
+	m.StartViewTransition(ViewMarketplaceList, 1)
+	return m, tea.Batch(animationTick(), statsCmd)
+}
+
+// marketplaceTopPluginCount is how many plugins the marketplace detail lists
+const marketplaceTopPluginCount = 5
+
+// loadTopPlugins fills TopPlugins from the marketplace's cached manifest.
+// Uncached marketplaces are left with no list.
+func (item *MarketplaceItem) loadTopPlugins() {
+	item.TopPlugins = nil
+
+	manifest, err := marketplace.LoadFromCache(item.Name)
+	if err != nil || manifest == nil {
+		return
+	}
+
+	names := make([]string, 0, marketplaceTopPluginCount)
+	for _, p := range manifest.Plugins {
+		if len(names) == marketplaceTopPluginCount {
+			break
+		}
+		names = append(names, p.Name)
+	}
+	item.TopPlugins = names
+}
+
+// cachedMarketplacePlugins lists a marketplace's plugins in manifest order
+// from its cached manifest. Plugins already loaded are used as-is so install
+// state carries over. Returns nil when the marketplace isn't cached.
+func (m Model) cachedMarketplacePlugins(item MarketplaceItem) []plugin.Plugin {
+	manifest, err := marketplace.LoadFromCache(item.Name)
+	if err != nil || manifest == nil {
+		return nil
+	}
+
+	loaded := make(map[string]plugin.Plugin)
+	for _, p := range m.allPlugins {
+		if p.Marketplace == item.Name {
+			loaded[p.Name] = p
+		}
+	}
+
+	plugins := make([]plugin.Plugin, 0, len(manifest.Plugins))
+	for _, mp := range manifest.Plugins {
+		if p, ok := loaded[mp.Name]; ok {
+			plugins = append(plugins, p)
+			continue
+		}
+		plugins = append(plugins, plugin.Plugin{
+			Name:             mp.Name,
+			Description:      mp.Description,
+			Version:          mp.Version,
+			Keywords:         mp.Keywords,
+			Category:         mp.Category,
+			Author:           plugin.Author{Name: mp.Author.Name, Email: mp.Author.Email, URL: mp.Author.URL, Company: mp.Author.Company},
+			Marketplace:      item.Name,
+			MarketplaceRepo:  item.Repo,
+			IsDiscoverable:   item.Status != MarketplaceInstalled,
+			Source:           mp.Source,
+			Homepage:         mp.Homepage,
+			Repository:       mp.Repository,
+			License:          mp.License,
+			Tags:             mp.Tags,
+			PluginJSONSHA256: mp.SHA256,
+			HasLSPServers:    mp.HasLSPServers,
+			IsExternalURL:    mp.IsExternalURL,
+			IsIncomplete:     mp.IsIncomplete,
+		})
+	}
+	return plugins
 }
 
 // MarketplaceSortMode represents sorting options for marketplaces
@@ -41,6 +211,30 @@ const (
 // MarketplaceSortModeNames for display
 var MarketplaceSortModeNames = []string{"Plugins", "Stars", "Name", "Updated"}
 
+// MarketplaceFilterMode represents which marketplaces the browser shows
+type MarketplaceFilterMode int
+
+const (
+	MarketplaceFilterAll       MarketplaceFilterMode = iota // Show every marketplace
+	MarketplaceFilterInstalled                              // Show only marketplaces added to Claude Code
+	MarketplaceFilterAvailable                              // Show only marketplaces not yet added
+)
+
+// MarketplaceFilterModeNames for display
+var MarketplaceFilterModeNames = []string{"All", "Installed", "Available"}
+
+// Matches reports whether a marketplace with the given status passes the filter
+func (f MarketplaceFilterMode) Matches(status MarketplaceStatus) bool {
+	switch f {
+	case MarketplaceFilterInstalled:
+		return status == MarketplaceInstalled
+	case MarketplaceFilterAvailable:
+		return status != MarketplaceInstalled
+	default:
+		return true
+	}
+}
+
 // StatusBadge returns a display badge for marketplace status
 func (m MarketplaceItem) StatusBadge() string {
 	switch m.Status {
@@ -18,13 +18,17 @@ func (m Model) marketplaceListView() string {
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
-	// Sort tabs
+	// Filter and sort tabs
+	b.WriteString(m.renderMarketplaceFilterTabs())
+	b.WriteString("\n")
 	b.WriteString(m.renderMarketplaceSortTabs())
 	b.WriteString("\n\n")
 
 	// Marketplace list
 	if len(m.marketplaceItems) == 0 {
 		b.WriteString(DescriptionStyle.Render("No marketplaces found. Press Shift+U to refresh."))
+	} else if len(m.FilteredMarketplaceItems()) == 0 {
+		b.WriteString(DescriptionStyle.Render("No marketplaces match this filter. Press f to change it."))
 	} else {
 		visible := m.VisibleMarketplaceItems()
 		offset := m.marketplaceScrollOffset
@@ -54,8 +58,8 @@ func (m Model) renderMarketplaceItem(item MarketplaceItem, selected bool) string
 	pluginCountStr := formatPluginCount(item.InstalledPluginCount, item.TotalPluginCount)
 	statsStr := formatGitHubStats(item.GitHubStats, item.StatsLoading, item.StatsError)
 
-	tertiaryStyle := lipgloss.NewStyle().Foreground(TextTertiary)
-	mutedStyle := lipgloss.NewStyle().Foreground(TextMuted)
+	tertiaryStyle := newStyle().Foreground(TextTertiary)
+	mutedStyle := newStyle().Foreground(TextMuted)
 
 	return fmt.Sprintf("%s%s %s  %s  %s",
 		prefix, indicator, name,
@@ -116,15 +120,44 @@ func formatGitHubStats(stats *marketplace.GitHubStats, loading bool, err error)
 	return ""
 }
 
+// renderMarketplaceFilterTabs renders the installed-status filter tabs with counts
+func (m Model) renderMarketplaceFilterTabs() string {
+	// Tab styles (same as renderFilterTabs)
+	activeTab := newStyle().
+		Foreground(PlumBright).
+		Bold(true).
+		Padding(0, 1)
+
+	inactiveTab := newStyle().
+		Foreground(TextTertiary).
+		Padding(0, 1)
+
+	counts := m.MarketplaceFilterCounts()
+
+	var parts []string
+	for i, name := range MarketplaceFilterModeNames {
+		mode := MarketplaceFilterMode(i)
+		label := fmt.Sprintf("%s (%d)", name, counts[mode])
+		if mode == m.marketplaceFilterMode {
+			parts = append(parts, activeTab.Render(label))
+		} else {
+			parts = append(parts, inactiveTab.Render(label))
+		}
+	}
+
+	hint := HelpStyle.Render("  (f to filter)")
+	return strings.Join(parts, DimSeparator.Render(separatorBar)) + hint
+}
+
 // renderMarketplaceSortTabs renders sort mode tabs
 func (m Model) renderMarketplaceSortTabs() string {
 	// Tab styles (inline like renderFilterTabs)
-	activeTab := lipgloss.NewStyle().
+	activeTab := newStyle().
 		Foreground(PlumBright).
 		Bold(true).
 		Padding(0, 1)
 
-	inactiveTab := lipgloss.NewStyle().
+	inactiveTab := newStyle().
 		Foreground(TextTertiary).
 		Padding(0, 1)
 
@@ -173,7 +206,7 @@ func (m Model) marketplaceStatusBar() string {
 	parts = append(parts, KeyStyle.Render("esc")+" return to plugins")
 	parts = append(parts, KeyStyle.Render("?")+" help")
 
-	return StatusBarStyle.Render(strings.Join(parts, "  │  "))
+	return StatusBarStyle.Render(strings.Join(parts, statusBarSeparator))
 }
 
 // marketplaceDetailView renders detailed view of a marketplace
@@ -188,15 +221,12 @@ func (m Model) marketplaceDetailView() string {
 		contentWidth = 40
 	}
 
-	var b strings.Builder
+	if m.marketplacePluginsOpen {
+		return m.marketplacePluginsView(item, contentWidth)
+	}
 
-	// Header with name and status badge
-	badge := item.StatusBadge()
-	header := DetailTitleStyle.Render(item.DisplayName) + "  " + badge
-	b.WriteString(header)
-	b.WriteString("\n")
-	b.WriteString(strings.Repeat("─", contentWidth))
-	b.WriteString("\n\n")
+	var b strings.Builder
+	b.WriteString(marketplaceDetailHeader(item, contentWidth))
 
 	// Details
 	details := []struct {
@@ -255,10 +285,34 @@ func (m Model) marketplaceDetailView() string {
 	b.WriteString(wrapText(item.Description, contentWidth))
 	b.WriteString("\n")
 
+	// Top plugins from the cached manifest
+	b.WriteString("\n")
+	b.WriteString(DetailLabelStyle.Render("Top plugins:"))
+	b.WriteString("\n")
+	switch {
+	case item.TopPlugins == nil:
+		b.WriteString("  " + HelpStyle.Render("Not cached yet. Press Shift+U to refresh."))
+		b.WriteString("\n")
+	case len(item.TopPlugins) == 0:
+		b.WriteString("  " + HelpStyle.Render("No plugins listed"))
+		b.WriteString("\n")
+	default:
+		for _, name := range item.TopPlugins {
+			b.WriteString("  • " + DetailValueStyle.Render(name))
+			b.WriteString("\n")
+		}
+		if more := item.TotalPluginCount - len(item.TopPlugins); more > 0 {
+			b.WriteString("  " + HelpStyle.Render(fmt.Sprintf("…and %d more", more)))
+			b.WriteString("\n")
+		}
+		b.WriteString("  " + HelpStyle.Render("press 'enter' to browse them, 'f' to filter the plugin list to them"))
+		b.WriteString("\n")
+	}
+
 	// Actions section
 	if item.Status != MarketplaceInstalled {
 		b.WriteString("\n")
-		b.WriteString(strings.Repeat("─", contentWidth))
+		b.WriteString(strings.Repeat(ruleChar, contentWidth))
 		b.WriteString("\n")
 		b.WriteString(DetailLabelStyle.Render("Install:"))
 		b.WriteString("\n")
@@ -275,21 +329,64 @@ func (m Model) marketplaceDetailView() string {
 
 	// Flash messages
 	if m.copiedFlash {
-		successStyle := lipgloss.NewStyle().Foreground(Success).Bold(true)
+		successStyle := newStyle().Foreground(Success).Bold(true)
 		footerParts = append(footerParts, successStyle.Render("✓ Copied!"))
 	} else if m.githubOpenedFlash {
-		openedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9500")).Bold(true)
+		openedStyle := newStyle().Foreground(lipgloss.Color("#FF9500")).Bold(true)
 		footerParts = append(footerParts, openedStyle.Render("✓ Opened!"))
 	} else {
 		if item.Status != MarketplaceInstalled {
 			footerParts = append(footerParts, KeyStyle.Render("c")+" copy install")
 		}
+		footerParts = append(footerParts, KeyStyle.Render("enter")+" plugins")
 		footerParts = append(footerParts, KeyStyle.Render("f")+" filter plugins")
 		footerParts = append(footerParts, KeyStyle.Render("g")+" github")
 	}
 
 	footerParts = append(footerParts, KeyStyle.Render("q")+" quit")
-	b.WriteString(HelpStyle.Render(strings.Join(footerParts, "  │  ")))
+	b.WriteString(HelpStyle.Render(strings.Join(footerParts, statusBarSeparator)))
+
+	boxStyle := DetailBoxStyle.Width(contentWidth + 4)
+	return AppStyle.Render(boxStyle.Render(b.String()))
+}
+
+// marketplaceDetailHeader renders the marketplace name, status badge, and rule
+func marketplaceDetailHeader(item *MarketplaceItem, contentWidth int) string {
+	header := DetailTitleStyle.Render(item.DisplayName) + "  " + item.StatusBadge()
+	return header + "\n" + strings.Repeat(ruleChar, contentWidth) + "\n\n"
+}
+
+// marketplacePluginsView renders the scrollable list of a marketplace's plugins
+func (m Model) marketplacePluginsView(item *MarketplaceItem, contentWidth int) string {
+	var b strings.Builder
+	b.WriteString(marketplaceDetailHeader(item, contentWidth))
+
+	switch {
+	case m.marketplacePlugins == nil:
+		b.WriteString(HelpStyle.Render("Not cached yet. Press Shift+U in the plugin list to refresh."))
+		b.WriteString("\n")
+	case len(m.marketplacePlugins) == 0:
+		b.WriteString(HelpStyle.Render("No plugins listed"))
+		b.WriteString("\n")
+	default:
+		for i, p := range m.VisibleMarketplacePlugins() {
+			selected := m.marketplacePluginScroll+i == m.marketplacePluginCursor
+			b.WriteString(m.renderPluginItemSlim(p, nil, selected))
+			b.WriteString("\n")
+		}
+	}
+
+	// Footer
+	b.WriteString("\n")
+	var footerParts []string
+	if n := len(m.marketplacePlugins); n > 0 {
+		footerParts = append(footerParts, fmt.Sprintf("%d/%d", m.marketplacePluginCursor+1, n))
+		footerParts = append(footerParts, KeyStyle.Render("↑↓")+" nav")
+		footerParts = append(footerParts, KeyStyle.Render("enter")+" details")
+	}
+	footerParts = append(footerParts, KeyStyle.Render("esc")+" back")
+	footerParts = append(footerParts, KeyStyle.Render("q")+" quit")
+	b.WriteString(HelpStyle.Render(strings.Join(footerParts, statusBarSeparator)))
 
 	boxStyle := DetailBoxStyle.Width(contentWidth + 4)
 	return AppStyle.Render(boxStyle.Render(b.String()))
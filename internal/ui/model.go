@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -10,11 +13,13 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/harmonica"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/itsdevcoffee/plum/internal/config"
+	"github.com/itsdevcoffee/plum/internal/export"
+	"github.com/itsdevcoffee/plum/internal/install"
 	"github.com/itsdevcoffee/plum/internal/marketplace"
 	"github.com/itsdevcoffee/plum/internal/plugin"
 	"github.com/itsdevcoffee/plum/internal/search"
+	"github.com/itsdevcoffee/plum/internal/settings"
 )
 
 // ViewState represents the current view
@@ -26,6 +31,7 @@ const (
 	ViewHelp
 	ViewMarketplaceList   // Marketplace browser view
 	ViewMarketplaceDetail // Marketplace detail view
+	ViewConfirm           // Yes/no dialog before a destructive action
 )
 
 // TransitionStyle represents the animation style for view transitions
@@ -53,10 +59,24 @@ const (
 	FilterDiscover                    // Show only discoverable (from uninstalled marketplaces)
 	FilterReady                       // Show only ready to install (marketplace installed, plugin not)
 	FilterInstalled                   // Show only installed
+	FilterFavorites                   // Show only starred plugins
 )
 
 // FilterModeNames for display
-var FilterModeNames = []string{"All", "Discover", "Ready", "Installed"}
+var FilterModeNames = []string{"All", "Discover", "Ready", "Installed", "Favorites"}
+
+// PluginSortMode represents ordering options for the plugin list
+type PluginSortMode int
+
+const (
+	PluginSortRelevance   PluginSortMode = iota // Search ranking (installed first, then name)
+	PluginSortName                              // Alphabetical by name
+	PluginSortMarketplace                       // Clustered by marketplace, then name
+	PluginSortRecent                            // Most recently installed or updated first
+)
+
+// PluginSortModeNames for display
+var PluginSortModeNames = []string{"Relevance", "Name A-Z", "Marketplace", "Recently Updated"}
 
 // TransitionStyleNames for display
 var TransitionStyleNames = []string{"Instant", "Zoom", "Slide V"}
@@ -81,40 +101,100 @@ type Model struct {
 	// Data
 	allPlugins           []plugin.Plugin
 	results              []search.RankedPlugin
+	searcher             *search.Searcher // Reusable index over allPlugins
 	loading              bool
 	refreshing           bool   // True when manually refreshing cache
+	enriching            bool   // Showing cached plugins while discovery fetches the rest
 	refreshProgress      int    // Number of marketplaces refreshed
 	refreshTotal         int    // Total marketplaces to refresh
 	refreshCurrent       string // Current marketplace being fetched
+	reloadGeneration     int    // Latest reload requested; stale pluginsLoadedMsg results are dropped
+	searchSeq            int    // Latest keystroke's search; stale searchDebounceMsgs are dropped
+	searchPending        bool   // The latest keystroke's search has not run yet
 	newMarketplacesCount int    // Number of new marketplaces available in registry
+	// cancelRefresh cancels the refresh in flight, nil when none
+	cancelRefresh context.CancelFunc
 
 	// UI state
 	textInput           textinput.Model
 	spinner             spinner.Model
 	helpViewport        viewport.Model
 	detailViewport      viewport.Model
+	helpContentLines    int // Lines of content in helpViewport, for the scrollbar
+	detailContentLines  int // Lines of content in detailViewport, for the scrollbar
 	cursor              int
 	scrollOffset        int
 	viewState           ViewState
 	displayMode         ListDisplayMode
+	slimExpanded        bool // Show the selected slim row's description on a second line
 	filterMode          FilterMode
+	pluginSortMode      PluginSortMode
+	preferInstalled     bool // Rank installed plugins first in search results
 	windowWidth         int
 	windowHeight        int
 	copiedFlash         bool // Brief "Copied!" indicator (for 'c')
 	linkCopiedFlash     bool // Brief "Link Copied!" indicator (for 'l')
+	jsonURLCopiedFlash  bool // Brief "URL Copied!" indicator (for 'r')
 	pathCopiedFlash     bool // Brief "Path Copied!" indicator (for 'p')
 	githubOpenedFlash   bool // Brief "Opened!" indicator (for 'g')
 	localOpenedFlash    bool // Brief "Opened!" indicator (for 'o')
 	clipboardErrorFlash bool // Brief "Clipboard error!" indicator
+	editorErrorFlash    bool // Brief "Editor failed" indicator (for 'e' in help)
+
+	// In-TUI install state (for 'i' in the detail view)
+	installing     string // Full name of the plugin being installed
+	installedFlash bool   // Brief "Installed!" indicator
+	installError   error  // Brief install failure indicator
+	// cancelInstall cancels the install in flight, nil when none
+	cancelInstall context.CancelFunc
+
+	// Confirmation dialog (ViewConfirm)
+	confirmPrompt string
+	confirmCmd    tea.Cmd   // Runs only when confirmed
+	confirmReturn ViewState // View to go back to
+
+	// Enable/disable state (for 'e' in the detail view, 'E' in the list)
+	pluginStates map[string]settings.PluginState // Effective enabled state by full name
+	toggleFlash  string                          // Brief enable/disable, favorite, or export confirmation
+	toggleError  error                           // Brief toggle failure indicator
+
+	// Favorites (for 'f' in the detail view, '*' or 'F' in the list)
+	favorites map[string]bool // Starred plugins by full name
+
+	// List export (Shift+X writes, Ctrl+X switches format)
+	exportFormat export.Format
 
 	// Marketplace view state
 	marketplaceItems              []MarketplaceItem
 	marketplaceCursor             int
 	marketplaceScrollOffset       int
 	marketplaceSortMode           MarketplaceSortMode
+	marketplaceFilterMode         MarketplaceFilterMode
 	selectedMarketplace           *MarketplaceItem
 	previousViewBeforeMarketplace ViewState
 
+	// Marketplace plugin list (enter in the marketplace detail view)
+	marketplacePluginsOpen  bool            // Showing the selected marketplace's plugins
+	marketplacePlugins      []plugin.Plugin // nil when the marketplace isn't cached
+	marketplacePluginCursor int
+	marketplacePluginScroll int
+	detailFromMarketplace   bool // Plugin detail was opened from the marketplace plugin list
+
+	// Vim-style gg: the first goTop key, waiting for its second (list views)
+	pendingTop string
+
+	// Multi-select in the list (space marks, 'y' copies install commands)
+	selected map[string]bool // Marked plugins by full name
+
+	// Last left-click in the list, to spot double-clicks
+	lastClickIndex int
+	lastClickAt    time.Time
+
+	// Search history for this session (Up at the top of the list recalls it,
+	// Down steps forward again)
+	searchHistory []string // Submitted queries, oldest first
+	historyBack   int      // How far back the recalled query is; 0 when not recalling
+
 	// Marketplace autocomplete state (for @marketplace-name filtering)
 	marketplaceAutocompleteActive bool                // True when showing marketplace picker
 	marketplaceAutocompleteList   []MarketplaceItem   // Filtered marketplaces for autocomplete
@@ -134,37 +214,52 @@ type Model struct {
 	transitionDirection int             // 1 = forward (right to left), -1 = back (left to right)
 	transitionStyle     TransitionStyle // Current animation style
 
+	// Key bindings, the defaults or the user's keys.json
+	keys KeyMap
+
 	// Error state
 	err error
 }
 
-// NewModel creates a new Model with initial state
+// NewModel creates a new Model with initial state and the user's key
+// bindings, applying the user's color theme the first time
 func NewModel() Model {
+	loadUserTheme()
+	m := newModel()
+	m.keys = loadUserKeyMap()
+	return m
+}
+
+// newModel creates a new Model with the default key bindings, without
+// reading the user's theme
+func newModel() Model {
 	ti := textinput.New()
 	ti.Placeholder = "Search plugins (or @marketplace-name to filter)..."
 	ti.Focus()
 	ti.CharLimit = 100
 	ti.Width = 40
-	ti.PromptStyle = SearchPromptStyle
-	ti.TextStyle = SearchInputStyle
+	styleTextInput(&ti)
 	ti.Prompt = "> "
 
 	// Initialize spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(PeachSoft)
+	s.Style = newStyle().Foreground(PeachSoft)
 
 	// Initialize spring for animations
 	spring := harmonica.NewSpring(harmonica.FPS(animationFPS), springFrequency, springDamping)
 
 	return Model{
 		textInput:                     ti,
+		keys:                          DefaultKeyMap(),
 		spinner:                       s,
 		spring:                        spring,
 		loading:                       true,
 		viewState:                     ViewList,
 		previousView:                  ViewList,
 		displayMode:                   DisplaySlim,       // Default to slim mode
+		preferInstalled:               true,
+		searcher:                      search.NewSearcher(nil),
 		marketplaceSortMode:           SortByPluginCount, // Default marketplace sort
 		transitionProgress:            1.0,               // Start fully transitioned (no animation on init)
 		targetTransition:              1.0,
@@ -190,15 +285,34 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		textinput.Blink,
 		m.spinner.Tick,
-		loadPlugins,
-		checkRegistryForUpdates, // Check for new marketplaces
+		loadCachedPluginsCmd(m.reloadGeneration), // Render from cache first, then enrich
+		checkRegistryForUpdates,                  // Check for new marketplaces
 	)
 }
 
-// checkRegistryForUpdates checks if there are new marketplaces in the registry
+// RegistryCheckTimeout bounds how long the startup registry check may take
+// before it is abandoned. Override with PLUM_REGISTRY_TIMEOUT (e.g. "10s").
+var RegistryCheckTimeout = 5 * time.Second
+
+// registryCheckTimeout returns the effective registry check timeout
+func registryCheckTimeout() time.Duration {
+	if v := os.Getenv("PLUM_REGISTRY_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return RegistryCheckTimeout
+}
+
+// checkRegistryForUpdates checks if there are new marketplaces in the registry.
+// A stalled network must not hold up startup, so the fetch is cancelled after
+// registryCheckTimeout and reported as zero new marketplaces.
 func checkRegistryForUpdates() tea.Msg {
-	// Will be set by update.go to call marketplace.FetchRegistryWithComparison
-	_, newCount, err := checkForNewMarketplaces()
+	// The timeout cancels the fetch itself rather than abandoning it
+	ctx, cancel := context.WithTimeout(context.Background(), registryCheckTimeout())
+	defer cancel()
+
+	_, newCount, err := checkForNewMarketplaces(ctx)
 	if err != nil || newCount == 0 {
 		return registryCheckedMsg{newCount: 0}
 	}
@@ -214,20 +328,311 @@ type PopularMarketplace struct {
 }
 
 // checkForNewMarketplaces wrapper to avoid circular import
-var checkForNewMarketplaces = func() ([]PopularMarketplace, int, error) {
+var checkForNewMarketplaces = func(ctx context.Context) ([]PopularMarketplace, int, error) {
 	return nil, 0, nil // Will be set by update.go
 }
 
-// pluginsLoadedMsg is sent when plugins are loaded
+// pluginsLoadedMsg is sent when plugins are loaded. The plugins slice is
+// built fresh by the command and owned by the model once Update swaps it in.
 type pluginsLoadedMsg struct {
-	plugins []plugin.Plugin
-	err     error
+	plugins    []plugin.Plugin
+	states     map[string]settings.PluginState
+	favorites  map[string]bool
+	err        error
+	generation int  // Reload that produced this message
+	cached     bool // Loaded from plum's cache only; an enrichment load follows
+}
+
+// pluginsEnrichedMsg carries the full plugin list, including freshly
+// discovered marketplaces, that replaces a cached first load
+type pluginsEnrichedMsg struct {
+	plugins    []plugin.Plugin
+	err        error
+	generation int // Reload that produced this message
+}
+
+// loadAllPlugins loads every plugin from config (variable for testing)
+var loadAllPlugins = config.LoadAllPlugins
+
+// loadPlugins returns a command that loads all plugins from config. It runs
+// off the UI goroutine, so it must not read or write the model.
+func loadPlugins(generation int) tea.Cmd {
+	return func() tea.Msg {
+		plugins, err := loadAllPlugins()
+		return pluginsLoadedMsg{plugins: plugins, states: loadPluginStates(), favorites: loadFavorites(), err: err, generation: generation}
+	}
+}
+
+// loadCachedPlugins loads plugins without network access (variable for testing)
+var loadCachedPlugins = config.LoadCachedPlugins
+
+// loadCachedPluginsCmd returns a command that loads plugins from local
+// manifests and plum's cache only, so the list renders before discovery
+// reaches the network. Update follows it up with enrichPlugins.
+func loadCachedPluginsCmd(generation int) tea.Cmd {
+	return func() tea.Msg {
+		plugins, err := loadCachedPlugins()
+		return pluginsLoadedMsg{plugins: plugins, states: loadPluginStates(), favorites: loadFavorites(), err: err, generation: generation, cached: true}
+	}
+}
+
+// enrichPlugins returns a command that runs the full load, fetching
+// discovered marketplaces that are missing from or expired in the cache
+func enrichPlugins(generation int) tea.Cmd {
+	return func() tea.Msg {
+		plugins, err := loadAllPlugins()
+		return pluginsEnrichedMsg{plugins: plugins, err: err, generation: generation}
+	}
+}
+
+// loadPluginStates loads each plugin's effective enabled state across the
+// settings scopes, keyed by full name (variable for testing)
+var loadPluginStates = func() map[string]settings.PluginState {
+	states, err := settings.MergedPluginStates("")
+	if err != nil {
+		return nil
+	}
+	byName := make(map[string]settings.PluginState, len(states))
+	for _, state := range states {
+		byName[state.FullName] = state
+	}
+	return byName
+}
+
+// setPluginEnabled writes a plugin's enabled state (variable for testing)
+var setPluginEnabled = settings.SetPluginEnabled
+
+// installPlugin installs a plugin in user scope and returns its install path
+// (variable for testing)
+var installPlugin = func(ctx context.Context, p plugin.Plugin) (string, error) {
+	if err := install.CheckWritable(settings.ScopeUser, ""); err != nil {
+		return "", err
+	}
+	result, err := install.Install(ctx, install.NewTarget(p), install.Options{Scope: settings.ScopeUser})
+	if err != nil {
+		return "", err
+	}
+	return result.CacheDir, nil
+}
+
+// installDoneMsg is sent when an in-TUI install finishes
+type installDoneMsg struct {
+	fullName    string
+	installPath string
+	err         error
+}
+
+// doInstall returns a command that installs p off the UI goroutine.
+// Cancelling ctx stops its downloads.
+func doInstall(ctx context.Context, p plugin.Plugin) tea.Cmd {
+	return func() tea.Msg {
+		installPath, err := installPlugin(ctx, p)
+		return installDoneMsg{fullName: p.FullName(), installPath: installPath, err: err}
+	}
+}
+
+// applyEnrichedPlugins swaps in the full plugin list after a cached first
+// load. The user may already be browsing, so the cursor stays on the plugin
+// it was on when that plugin is still in the results.
+func (m *Model) applyEnrichedPlugins(plugins []plugin.Plugin) {
+	selected := ""
+	if m.cursor < len(m.results) {
+		selected = m.results[m.cursor].Plugin.FullName()
+	}
+
+	m.allPlugins = plugins
+	m.searcher.Update(plugins)
+	m.results = m.filteredSearch(m.textInput.Value())
+
+	for i := range m.results {
+		if m.results[i].Plugin.FullName() == selected {
+			m.cursor = i
+			return
+		}
+	}
+	if m.cursor >= len(m.results) {
+		m.cursor = max(len(m.results)-1, 0)
+	}
+}
+
+// markInstalled records a finished in-TUI install in the loaded plugins and
+// current results, so counts and the detail view update without a reload
+func (m *Model) markInstalled(fullName, installPath string) {
+	plugins := make([]plugin.Plugin, len(m.allPlugins))
+	copy(plugins, m.allPlugins)
+	for i := range plugins {
+		if plugins[i].FullName() == fullName {
+			plugins[i].Installed = true
+			plugins[i].InstallPath = installPath
+		}
+	}
+	m.allPlugins = plugins
+
+	// Install enables the plugin in user scope, which decides its state
+	// unless a higher-precedence scope already does
+	if state, ok := m.pluginStates[fullName]; !ok || state.Scope == settings.ScopeUser {
+		m.setPluginState(settings.PluginState{FullName: fullName, Enabled: true, Scope: settings.ScopeUser})
+	}
+
+	for i := range m.results {
+		if m.results[i].Plugin.FullName() == fullName {
+			m.results[i].Plugin.Installed = true
+			m.results[i].Plugin.InstallPath = installPath
+		}
+	}
+
+	for i := range m.marketplacePlugins {
+		if m.marketplacePlugins[i].FullName() == fullName {
+			m.marketplacePlugins[i].Installed = true
+			m.marketplacePlugins[i].InstallPath = installPath
+		}
+	}
+
+	if m.viewState == ViewDetail && m.detailViewport.Width > 0 {
+		m.fitDetailViewport(m.windowHeight)
+	}
 }
 
-// loadPlugins loads all plugins from config
-func loadPlugins() tea.Msg {
-	plugins, err := config.LoadAllPlugins()
-	return pluginsLoadedMsg{plugins: plugins, err: err}
+// togglePluginEnabled flips an installed plugin's enabled state in the scope
+// that currently decides it, or user scope if no settings mention it.
+// Plugins set by managed settings can't be changed.
+func (m *Model) togglePluginEnabled(p plugin.Plugin) {
+	m.toggleFlash = ""
+	m.toggleError = nil
+
+	fullName := p.FullName()
+	state, ok := m.pluginStates[fullName]
+	if !ok {
+		state = settings.PluginState{FullName: fullName, Scope: settings.ScopeUser}
+	}
+	if !state.Scope.IsWritable() {
+		m.toggleError = fmt.Errorf("%s is set by %s settings", fullName, state.Scope)
+		return
+	}
+
+	enabled := !state.Enabled
+	if err := setPluginEnabled(fullName, enabled, state.Scope, ""); err != nil {
+		m.toggleError = err
+		return
+	}
+
+	state.Enabled = enabled
+	m.setPluginState(state)
+
+	if enabled {
+		m.toggleFlash = "Enabled in " + state.Scope.String() + " scope"
+	} else {
+		m.toggleFlash = "Disabled in " + state.Scope.String() + " scope"
+	}
+}
+
+// setPluginState records a plugin's new effective state. The map is copied
+// rather than written in place, since earlier model values share it.
+func (m *Model) setPluginState(state settings.PluginState) {
+	states := make(map[string]settings.PluginState, len(m.pluginStates)+1)
+	for name, s := range m.pluginStates {
+		states[name] = s
+	}
+	states[state.FullName] = state
+	m.pluginStates = states
+}
+
+// pluginEnabled reports whether the settings enable an installed plugin
+func (m Model) pluginEnabled(p plugin.Plugin) bool {
+	return m.pluginStates[p.FullName()].Enabled
+}
+
+// pluginDisabled reports whether p is installed but not enabled. Nothing
+// counts as disabled until plugin states have loaded.
+func (m Model) pluginDisabled(p plugin.Plugin) bool {
+	return p.Installed && m.pluginStates != nil && !m.pluginEnabled(p)
+}
+
+// searchHistoryLimit bounds how many submitted queries are remembered
+const searchHistoryLimit = 20
+
+// recordSearch adds a submitted query to the search history, moving it to
+// the newest spot if it was already there
+func (m *Model) recordSearch(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+	history := make([]string, 0, len(m.searchHistory)+1)
+	for _, q := range m.searchHistory {
+		if q != query {
+			history = append(history, q)
+		}
+	}
+	history = append(history, query)
+	if len(history) > searchHistoryLimit {
+		history = history[len(history)-searchHistoryLimit:]
+	}
+	m.searchHistory = history
+	m.historyBack = 0
+}
+
+// recallingSearch reports whether the search box still shows the last
+// recalled history entry
+func (m Model) recallingSearch() bool {
+	n := len(m.searchHistory)
+	return m.historyBack > 0 && m.historyBack <= n && m.textInput.Value() == m.searchHistory[n-m.historyBack]
+}
+
+// recallSearch puts the next older history entry in the search box. It
+// starts from an empty search, or continues while the box still shows the
+// last recalled query; otherwise it returns false and changes nothing.
+func (m *Model) recallSearch() bool {
+	n := len(m.searchHistory)
+	recalling := m.recallingSearch()
+
+	switch {
+	case recalling && m.historyBack == n:
+		return true // Already at the oldest query
+	case recalling:
+		m.historyBack++
+	case m.textInput.Value() == "" && n > 0:
+		m.historyBack = 1
+	default:
+		return false
+	}
+
+	m.showSearch(m.searchHistory[n-m.historyBack])
+	return true
+}
+
+// forwardSearch puts the next newer history entry in the search box while
+// recalling, or the empty search the recall started from after the newest
+// one. Returns false and changes nothing when not recalling.
+func (m *Model) forwardSearch() bool {
+	if !m.recallingSearch() {
+		return false
+	}
+
+	m.historyBack--
+	query := ""
+	if m.historyBack > 0 {
+		query = m.searchHistory[len(m.searchHistory)-m.historyBack]
+	}
+	m.showSearch(query)
+	return true
+}
+
+// showSearch runs query as if it were typed, with the cursor at the top
+func (m *Model) showSearch(query string) {
+	m.textInput.SetValue(query)
+	m.textInput.CursorEnd()
+	m.UpdateMarketplaceAutocomplete(query)
+	m.results = m.filteredSearch(query)
+	m.cursor = 0
+	m.scrollOffset = 0
+	m.SnapCursorToTarget()
+}
+
+// readyToInstall reports whether 'i' can install p: its marketplace is
+// installed, it isn't yet, and plum knows how to install it
+func readyToInstall(p plugin.Plugin) bool {
+	return !p.Installed && !p.IsDiscoverable && p.Installable()
 }
 
 // refreshCacheMsg is sent to initiate cache refresh
@@ -245,33 +650,64 @@ type refreshProgressMsg struct {
 	total     int    // Total to fetch
 }
 
-// doRefreshCache performs the actual cache refresh
+// doRefreshCache returns a command that performs the actual cache refresh
 // This runs in a goroutine automatically by Bubble Tea
-func doRefreshCache() tea.Msg {
-	// TODO: Add progress updates here once we refactor clearCacheAndReload
-	// to accept a progress callback
+// Cancelling ctx stops the marketplace fetches in flight.
+func doRefreshCache(ctx context.Context, generation int) tea.Cmd {
+	return func() tea.Msg {
+		// TODO: Add progress updates here once we refactor clearCacheAndReload
+		// to accept a progress callback
+
+		// Clear cache and reload
+		if err := clearCacheAndReload(ctx); err != nil {
+			return pluginsLoadedMsg{plugins: nil, err: err, generation: generation}
+		}
 
-	// Clear cache and reload
-	if err := clearCacheAndReload(); err != nil {
-		return pluginsLoadedMsg{plugins: nil, err: err}
+		// Reload plugins after cache clear
+		plugins, err := loadAllPlugins()
+		if err != nil {
+			return pluginsLoadedMsg{plugins: nil, err: err, generation: generation}
+		}
+
+		return pluginsLoadedMsg{plugins: plugins, states: loadPluginStates(), favorites: loadFavorites(), err: nil, generation: generation}
 	}
+}
 
-	// Reload plugins after cache clear
-	plugins, err := config.LoadAllPlugins()
-	if err != nil {
-		return pluginsLoadedMsg{plugins: nil, err: err}
+// stopRefresh cancels the refresh in flight, if any. Also called once a
+// refresh finishes, to release its context.
+func (m *Model) stopRefresh() {
+	if m.cancelRefresh != nil {
+		m.cancelRefresh()
+		m.cancelRefresh = nil
 	}
+}
+
+// stopInstall cancels the install in flight, if any. Also called once an
+// install finishes, to release its context.
+func (m *Model) stopInstall() {
+	if m.cancelInstall != nil {
+		m.cancelInstall()
+		m.cancelInstall = nil
+	}
+}
 
-	return pluginsLoadedMsg{plugins: plugins, err: nil}
+// nextReload starts a new reload generation; results from earlier reloads
+// that finish later are dropped instead of overwriting newer data
+func (m *Model) nextReload() int {
+	m.reloadGeneration++
+	return m.reloadGeneration
 }
 
 // clearCacheAndReload is set by update.go to avoid circular import
-var clearCacheAndReload = func() error {
+var clearCacheAndReload = func(ctx context.Context) error {
 	return nil // Will be set by update.go
 }
 
 // SelectedPlugin returns the currently selected plugin, if any
 func (m Model) SelectedPlugin() *plugin.Plugin {
+	if m.detailFromMarketplace {
+		return m.SelectedMarketplacePlugin()
+	}
 	if len(m.results) == 0 || m.cursor >= len(m.results) {
 		return nil
 	}
@@ -301,29 +737,38 @@ func (m Model) ScrollOffset() int {
 
 // UpdateScroll adjusts scroll offset to keep cursor visible with buffer
 func (m *Model) UpdateScroll() {
-	maxVisible := m.maxVisibleItems()
-	if len(m.results) <= maxVisible {
-		m.scrollOffset = 0
-		return
+	m.scrollOffset = scrollOffsetFor(m.cursor, m.scrollOffset, len(m.results), m.maxVisibleItems())
+}
+
+// scrollOffsetFor returns the scroll offset that keeps cursor visible with a
+// buffer of rows around it. The buffer shrinks for very short lists, and the
+// offset is clamped so the list never scrolls past its end.
+func scrollOffsetFor(cursor, offset, total, maxVisible int) int {
+	if maxVisible < 1 {
+		maxVisible = 1
+	}
+	if total <= maxVisible {
+		return 0
 	}
 
-	if m.cursor < m.scrollOffset+scrollBuffer {
-		m.scrollOffset = m.cursor - scrollBuffer
-		if m.scrollOffset < 0 {
-			m.scrollOffset = 0
-		}
-		return
+	buffer := scrollBuffer
+	if limit := (maxVisible - 1) / 2; buffer > limit {
+		buffer = limit
 	}
 
-	if m.cursor >= m.scrollOffset+maxVisible-scrollBuffer {
-		m.scrollOffset = m.cursor - maxVisible + scrollBuffer + 1
-		if m.scrollOffset > len(m.results)-maxVisible {
-			m.scrollOffset = len(m.results) - maxVisible
-		}
-		if m.scrollOffset < 0 {
-			m.scrollOffset = 0
-		}
+	if cursor < offset+buffer {
+		offset = cursor - buffer
+	} else if cursor >= offset+maxVisible-buffer {
+		offset = cursor - maxVisible + buffer + 1
+	}
+
+	if offset > total-maxVisible {
+		offset = total - maxVisible
 	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
 }
 
 // maxVisibleItems returns the maximum number of items that can be displayed
@@ -331,8 +776,13 @@ func (m Model) maxVisibleItems() int {
 	// Account for title (1) + blanks (2) + search (1) + blank (1) + filters (1) + blanks (2)
 	// + blank before status (1) + status (1) + AppStyle padding top/bottom (2) = 12 lines
 	available := m.windowHeight - 12
+	// A wrapped status bar takes extra lines
+	available -= len(m.statusBarLines()) - 1
 	if m.displayMode == DisplaySlim {
-		// Slim view: 1 line per item
+		// Slim view: 1 line per item, plus 1 for the expanded selected row
+		if m.slimExpanded {
+			available--
+		}
 		return available
 	}
 	// Card view: 4 lines per item (2 content rows + 2 border rows)
@@ -349,6 +799,12 @@ func (m *Model) ToggleDisplayMode() {
 	m.UpdateScroll()
 }
 
+// ToggleSlimExpanded toggles the description line under the selected slim row
+func (m *Model) ToggleSlimExpanded() {
+	m.slimExpanded = !m.slimExpanded
+	m.UpdateScroll()
+}
+
 // DisplayModeName returns the current display mode name
 func (m Model) DisplayModeName() string {
 	if m.displayMode == DisplaySlim {
@@ -367,16 +823,71 @@ func (m Model) ContentWidth() int {
 
 // NextFilter cycles to the next filter mode
 func (m *Model) NextFilter() {
-	m.filterMode = (m.filterMode + 1) % 4
+	m.filterMode = (m.filterMode + 1) % FilterMode(len(FilterModeNames))
 	m.applyFilter()
 }
 
 // PrevFilter cycles to the previous filter mode
 func (m *Model) PrevFilter() {
-	m.filterMode = (m.filterMode + 3) % 4 // +3 is same as -1 mod 4
+	n := FilterMode(len(FilterModeNames))
+	m.filterMode = (m.filterMode + n - 1) % n
+	m.applyFilter()
+}
+
+// NextPluginSort cycles to the next plugin list sort mode
+func (m *Model) NextPluginSort() {
+	m.pluginSortMode = (m.pluginSortMode + 1) % PluginSortMode(len(PluginSortModeNames))
 	m.applyFilter()
 }
 
+// TogglePreferInstalled switches installed-first ranking in search results
+func (m *Model) TogglePreferInstalled() {
+	m.preferInstalled = !m.preferInstalled
+	m.applyFilter()
+}
+
+// searchOptions returns the search ranking options for the current preferences
+func (m Model) searchOptions() search.Options {
+	return search.Options{PreferInstalled: m.preferInstalled}
+}
+
+// PluginSortModeName returns the current plugin sort mode name
+func (m Model) PluginSortModeName() string {
+	return PluginSortModeNames[m.pluginSortMode]
+}
+
+// sortPluginsByMarketplace orders results by marketplace, then plugin name,
+// keeping the list flat. Stable so equal keys keep their search ranking.
+func sortPluginsByMarketplace(results []search.RankedPlugin) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Plugin, results[j].Plugin
+		if a.Marketplace != b.Marketplace {
+			return a.Marketplace < b.Marketplace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// sortPluginsByName orders results alphabetically by name, then marketplace
+func sortPluginsByName(results []search.RankedPlugin) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Plugin, results[j].Plugin
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Marketplace < b.Marketplace
+	})
+}
+
+// sortPluginsByRecent orders results by when they were last installed or
+// updated, newest first. Plugins without that metadata keep their search
+// ranking after the rest.
+func sortPluginsByRecent(results []search.RankedPlugin) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Plugin.UpdatedAt.After(results[j].Plugin.UpdatedAt)
+	})
+}
+
 // applyFilter re-runs search with current filter and resets cursor
 func (m *Model) applyFilter() {
 	m.results = m.filteredSearch(m.textInput.Value())
@@ -385,76 +896,89 @@ func (m *Model) applyFilter() {
 	m.SnapCursorToTarget()
 }
 
-// filteredSearch runs search and applies the current filter
+// filteredSearch runs search, applies the current filter, then the sort mode
 func (m Model) filteredSearch(query string) []search.RankedPlugin {
-	// Check for marketplace filter (starts with @)
-	if strings.HasPrefix(query, "@") {
-		// Parse: @marketplace-name [optional search terms]
-		parts := strings.SplitN(query[1:], " ", 2)
-		marketplaceName := parts[0]
-		searchTerms := ""
-		if len(parts) > 1 {
-			searchTerms = parts[1]
-		}
+	results := m.searchWithFilter(query)
+	switch m.pluginSortMode {
+	case PluginSortName:
+		sortPluginsByName(results)
+	case PluginSortMarketplace:
+		sortPluginsByMarketplace(results)
+	case PluginSortRecent:
+		sortPluginsByRecent(results)
+	}
+	return results
+}
+
+// pluginSearcher returns the searcher over allPlugins, reindexing only when
+// the plugins have changed
+func (m Model) pluginSearcher() *search.Searcher {
+	if m.searcher == nil {
+		return search.NewSearcher(m.allPlugins)
+	}
+	m.searcher.Update(m.allPlugins)
+	return m.searcher
+}
 
-		// Filter plugins by marketplace
-		var marketplacePlugins []plugin.Plugin
+// searchWithFilter runs search and applies the current filter. Filter tokens
+// (@marketplace, #category, +tag) first narrow the candidates; the filter
+// mode applies either way.
+func (m Model) searchWithFilter(query string) []search.RankedPlugin {
+	var allResults []search.RankedPlugin
+
+	filters := parseQueryFilters(query)
+	if filters.active() {
+		var candidates []plugin.Plugin
 		for _, p := range m.allPlugins {
-			if p.Marketplace == marketplaceName {
-				marketplacePlugins = append(marketplacePlugins, p)
+			if filters.matches(p) {
+				candidates = append(candidates, p)
 			}
 		}
 
-		// If there are search terms, fuzzy search within the marketplace
-		if searchTerms != "" {
-			return search.Search(searchTerms, marketplacePlugins)
+		if filters.Text != "" {
+			// Fuzzy search within the candidates
+			allResults = search.SearchWithOptions(filters.Text, candidates, m.searchOptions())
+		} else {
+			// Otherwise keep every candidate
+			for _, p := range candidates {
+				allResults = append(allResults, search.RankedPlugin{
+					Plugin: p,
+					Score:  1.0,
+				})
+			}
 		}
+	} else {
+		allResults = m.pluginSearcher().QueryWithOptions(query, m.searchOptions())
+	}
 
-		// Otherwise return all plugins from this marketplace
-		var filtered []search.RankedPlugin
-		for _, p := range marketplacePlugins {
-			filtered = append(filtered, search.RankedPlugin{
-				Plugin: p,
-				Score:  1.0,
-			})
-		}
-		return filtered
+	if m.filterMode == FilterAll {
+		return allResults
 	}
 
-	// First get all search results
-	allResults := search.Search(query, m.allPlugins)
+	filtered := make([]search.RankedPlugin, 0)
+	for _, rp := range allResults {
+		if m.matchesFilter(rp.Plugin) {
+			filtered = append(filtered, rp)
+		}
+	}
+	return filtered
+}
 
-	// Apply filter
+// matchesFilter reports whether a plugin belongs in the current filter tab
+func (m Model) matchesFilter(p plugin.Plugin) bool {
 	switch m.filterMode {
 	case FilterDiscover:
-		// Show only discoverable (from uninstalled marketplaces)
-		filtered := make([]search.RankedPlugin, 0)
-		for _, r := range allResults {
-			if r.Plugin.IsDiscoverable {
-				filtered = append(filtered, r)
-			}
-		}
-		return filtered
-
+		// Only discoverable (from uninstalled marketplaces)
+		return p.IsDiscoverable
 	case FilterReady:
-		// Show only ready to install (not installed, marketplace IS installed)
-		filtered := make([]search.RankedPlugin, 0)
-		for _, rp := range allResults {
-			if !rp.Plugin.Installed && !rp.Plugin.IsDiscoverable {
-				filtered = append(filtered, rp)
-			}
-		}
-		return filtered
+		// Only ready to install (not installed, marketplace IS installed)
+		return !p.Installed && !p.IsDiscoverable
 	case FilterInstalled:
-		filtered := make([]search.RankedPlugin, 0)
-		for _, rp := range allResults {
-			if rp.Plugin.Installed {
-				filtered = append(filtered, rp)
-			}
-		}
-		return filtered
+		return p.Installed
+	case FilterFavorites:
+		return m.favorites[p.FullName()]
 	default:
-		return allResults
+		return true
 	}
 }
 
@@ -468,7 +992,7 @@ func (m Model) getDynamicFilterCounts(query string) map[FilterMode]int {
 	counts := make(map[FilterMode]int)
 
 	// For each filter mode, calculate how many results we'd get
-	for _, mode := range []FilterMode{FilterAll, FilterDiscover, FilterReady, FilterInstalled} {
+	for _, mode := range []FilterMode{FilterAll, FilterDiscover, FilterReady, FilterInstalled, FilterFavorites} {
 		// Temporarily set filter mode and get results
 		tempModel := m
 		tempModel.filterMode = mode
@@ -486,6 +1010,52 @@ func (m Model) ReadyCount() int {
 	})
 }
 
+// ToggleSelected marks or unmarks the plugin under the cursor
+func (m *Model) ToggleSelected() {
+	p := m.SelectedPlugin()
+	if p == nil {
+		return
+	}
+	// Copy before writing so earlier Model values keep their selection
+	selected := make(map[string]bool, len(m.selected)+1)
+	for name := range m.selected {
+		selected[name] = true
+	}
+	if selected[p.FullName()] {
+		delete(selected, p.FullName())
+	} else {
+		selected[p.FullName()] = true
+	}
+	m.selected = selected
+}
+
+// SelectionCount returns how many plugins are marked
+func (m Model) SelectionCount() int {
+	return len(m.selected)
+}
+
+// selectedInstallCommands returns the install commands of the marked
+// plugins that can be installed, one per line, sorted by full name
+func (m Model) selectedInstallCommands() string {
+	var names []string
+	commands := make(map[string]string)
+	for _, p := range m.allPlugins {
+		if name := p.FullName(); m.selected[name] && p.Installable() {
+			if _, seen := commands[name]; !seen {
+				names = append(names, name)
+			}
+			commands[name] = p.InstallCommand()
+		}
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = commands[name]
+	}
+	return strings.Join(lines, "\n")
+}
+
 // DiscoverableCount returns count of discoverable plugins
 func (m Model) DiscoverableCount() int {
 	return m.countPlugins(func(p plugin.Plugin) bool {
@@ -500,6 +1070,11 @@ func (m Model) InstalledCount() int {
 	})
 }
 
+// DisabledCount returns count of installed plugins the settings disable
+func (m Model) DisabledCount() int {
+	return m.countPlugins(m.pluginDisabled)
+}
+
 // TotalPlugins returns total plugin count
 func (m Model) TotalPlugins() int {
 	return len(m.allPlugins)
@@ -556,6 +1131,10 @@ func (m *Model) StartViewTransition(newView ViewState, direction int) {
 	if m.viewState == newView {
 		return
 	}
+	if newView != ViewDetail {
+		// Leaving plugin detail; the list selection applies again
+		m.detailFromMarketplace = false
+	}
 	m.previousView = m.viewState
 	m.viewState = newView
 	m.transitionProgress = 0.0
@@ -593,6 +1172,20 @@ func (m Model) TransitionOffset() int {
 
 // Marketplace View Functions
 
+// countInstalledByMarketplace counts registered plugins per marketplace
+func countInstalledByMarketplace(installed *config.InstalledPluginsV2) map[string]int {
+	counts := make(map[string]int)
+	if installed == nil {
+		return counts
+	}
+	for fullName := range installed.Plugins {
+		if _, marketplaceName, ok := plugin.ParseFullName(fullName); ok {
+			counts[marketplaceName]++
+		}
+	}
+	return counts
+}
+
 // LoadMarketplaceItems loads and processes all marketplaces with status and stats
 func (m *Model) LoadMarketplaceItems() error {
 	// 1. Load known marketplaces (installed)
@@ -609,16 +1202,7 @@ func (m *Model) LoadMarketplaceItems() error {
 
 	// 3. Count installed plugins per marketplace
 	installed, _ := config.LoadInstalledPlugins()
-	installedByMarketplace := make(map[string]int)
-	if installed != nil {
-		for fullName := range installed.Plugins {
-			// fullName format: "plugin@marketplace"
-			parts := strings.SplitN(fullName, "@", 2)
-			if len(parts) == 2 {
-				installedByMarketplace[parts[1]]++
-			}
-		}
-	}
+	installedByMarketplace := countInstalledByMarketplace(installed)
 
 	// 4. Build MarketplaceItem array
 	var items []MarketplaceItem
@@ -654,6 +1238,7 @@ func (m *Model) LoadMarketplaceItems() error {
 		// Load GitHub stats: prefer cache, fallback to static stats
 		if stats, err := marketplace.LoadStatsFromCache(pm.Name); err == nil && stats != nil {
 			item.GitHubStats = stats
+			item.statsCached = true
 		} else {
 			// Fallback to static stats from PopularMarketplaces (by name lookup)
 			item.GitHubStats = getStaticStatsByName(pm.Name)
@@ -739,44 +1324,102 @@ func getStaticStatsByName(name string) *marketplace.GitHubStats {
 	return nil
 }
 
+// FilteredMarketplaceItems returns the marketplace items that pass the
+// current filter mode, in sort order
+func (m Model) FilteredMarketplaceItems() []MarketplaceItem {
+	if m.marketplaceFilterMode == MarketplaceFilterAll {
+		return m.marketplaceItems
+	}
+	var items []MarketplaceItem
+	for _, item := range m.marketplaceItems {
+		if m.marketplaceFilterMode.Matches(item.Status) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// MarketplaceFilterCounts returns how many marketplaces each filter mode shows
+func (m Model) MarketplaceFilterCounts() map[MarketplaceFilterMode]int {
+	counts := make(map[MarketplaceFilterMode]int)
+	for _, item := range m.marketplaceItems {
+		for _, mode := range []MarketplaceFilterMode{MarketplaceFilterAll, MarketplaceFilterInstalled, MarketplaceFilterAvailable} {
+			if mode.Matches(item.Status) {
+				counts[mode]++
+			}
+		}
+	}
+	return counts
+}
+
 // VisibleMarketplaceItems returns visible marketplace items based on scroll
 func (m Model) VisibleMarketplaceItems() []MarketplaceItem {
+	items := m.FilteredMarketplaceItems()
 	maxVisible := m.maxVisibleItems()
-	if len(m.marketplaceItems) <= maxVisible {
-		return m.marketplaceItems
+	if len(items) <= maxVisible {
+		return items
 	}
 
 	start := m.marketplaceScrollOffset
 	end := start + maxVisible
-	if end > len(m.marketplaceItems) {
-		end = len(m.marketplaceItems)
+	if end > len(items) {
+		end = len(items)
 	}
 
-	return m.marketplaceItems[start:end]
+	return items[start:end]
 }
 
 // UpdateMarketplaceScroll adjusts scroll offset for marketplace view
 func (m *Model) UpdateMarketplaceScroll() {
-	maxVisible := m.maxVisibleItems()
-	if len(m.marketplaceItems) <= maxVisible {
-		m.marketplaceScrollOffset = 0
+	m.marketplaceScrollOffset = scrollOffsetFor(m.marketplaceCursor, m.marketplaceScrollOffset,
+		len(m.FilteredMarketplaceItems()), m.maxVisibleItems())
+}
+
+// OpenMarketplacePlugins shows the selected marketplace's plugin list
+func (m *Model) OpenMarketplacePlugins() {
+	if m.selectedMarketplace == nil {
 		return
 	}
+	m.marketplacePlugins = m.cachedMarketplacePlugins(*m.selectedMarketplace)
+	m.marketplacePluginsOpen = true
+	m.marketplacePluginCursor = 0
+	m.marketplacePluginScroll = 0
+}
 
-	if m.marketplaceCursor < m.marketplaceScrollOffset+scrollBuffer {
-		m.marketplaceScrollOffset = m.marketplaceCursor - scrollBuffer
-		if m.marketplaceScrollOffset < 0 {
-			m.marketplaceScrollOffset = 0
-		}
-		return
+// SelectedMarketplacePlugin returns the plugin under the cursor in the
+// marketplace plugin list
+func (m Model) SelectedMarketplacePlugin() *plugin.Plugin {
+	if m.marketplacePluginCursor >= len(m.marketplacePlugins) {
+		return nil
 	}
+	return &m.marketplacePlugins[m.marketplacePluginCursor]
+}
 
-	if m.marketplaceCursor >= m.marketplaceScrollOffset+maxVisible-scrollBuffer {
-		m.marketplaceScrollOffset = m.marketplaceCursor - maxVisible + scrollBuffer + 1
-		if m.marketplaceScrollOffset > len(m.marketplaceItems)-maxVisible {
-			m.marketplaceScrollOffset = len(m.marketplaceItems) - maxVisible
-		}
+// VisibleMarketplacePlugins returns the marketplace plugins that fit on screen
+func (m Model) VisibleMarketplacePlugins() []plugin.Plugin {
+	end := m.marketplacePluginScroll + m.maxVisibleMarketplacePlugins()
+	if end > len(m.marketplacePlugins) {
+		end = len(m.marketplacePlugins)
+	}
+	return m.marketplacePlugins[m.marketplacePluginScroll:end]
+}
+
+// maxVisibleMarketplacePlugins is how many plugin rows fit in the
+// marketplace detail box
+func (m Model) maxVisibleMarketplacePlugins() int {
+	// AppStyle padding (2) + box border and padding (4) + header (3)
+	// + blank before footer (1) + footer (1) = 11 lines
+	available := m.windowHeight - 11
+	if available < 3 {
+		available = 3
 	}
+	return available
+}
+
+// UpdateMarketplacePluginScroll keeps the plugin list cursor on screen
+func (m *Model) UpdateMarketplacePluginScroll() {
+	m.marketplacePluginScroll = scrollOffsetFor(m.marketplacePluginCursor, m.marketplacePluginScroll,
+		len(m.marketplacePlugins), m.maxVisibleMarketplacePlugins())
 }
 
 // NextMarketplaceSort cycles to next sort mode
@@ -795,6 +1438,13 @@ func (m *Model) PrevMarketplaceSort() {
 	m.marketplaceScrollOffset = 0
 }
 
+// NextMarketplaceFilter cycles to the next marketplace filter mode
+func (m *Model) NextMarketplaceFilter() {
+	m.marketplaceFilterMode = (m.marketplaceFilterMode + 1) % MarketplaceFilterMode(len(MarketplaceFilterModeNames))
+	m.marketplaceCursor = 0
+	m.marketplaceScrollOffset = 0
+}
+
 // UpdateMarketplaceAutocomplete updates the marketplace autocomplete list based on query
 func (m *Model) UpdateMarketplaceAutocomplete(query string) {
 	// Extract marketplace filter part (everything after @ until first space)
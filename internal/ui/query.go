@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/search"
+)
+
+// queryFilters are the prefix tokens pulled out of a search query before the
+// remaining text is fuzzy searched:
+//
+//	@marketplace  leading token; only plugins from that marketplace
+//	#category     only plugins in that category
+//	+tag          only plugins with that keyword or tag
+//	author:name   only plugins whose author contains name
+//
+// The remaining text may be a /regex/ (see search.RegexQuery).
+//
+// Matching ignores case, and every token must match.
+type queryFilters struct {
+	Marketplace string
+	Categories  []string
+	Tags        []string
+	Authors     []string
+	Text        string
+}
+
+// authorPrefix introduces an author filter token
+const authorPrefix = "author:"
+
+// parseQueryFilters splits a query into its filter tokens and free text
+func parseQueryFilters(query string) queryFilters {
+	var f queryFilters
+
+	if strings.HasPrefix(query, "@") {
+		parts := strings.SplitN(query[1:], " ", 2)
+		f.Marketplace = parts[0]
+		query = ""
+		if len(parts) > 1 {
+			query = parts[1]
+		}
+	}
+
+	var text []string
+	for _, token := range strings.Fields(query) {
+		switch {
+		case len(token) > 1 && token[0] == '#':
+			f.Categories = append(f.Categories, strings.ToLower(token[1:]))
+		case len(token) > 1 && token[0] == '+':
+			f.Tags = append(f.Tags, strings.ToLower(token[1:]))
+		case len(token) > len(authorPrefix) && strings.HasPrefix(strings.ToLower(token), authorPrefix):
+			f.Authors = append(f.Authors, strings.ToLower(token[len(authorPrefix):]))
+		default:
+			text = append(text, token)
+		}
+	}
+
+	if len(f.Categories) == 0 && len(f.Tags) == 0 && len(f.Authors) == 0 {
+		// Leave the text untouched when there's nothing to strip
+		f.Text = query
+	} else {
+		f.Text = strings.Join(text, " ")
+	}
+	return f
+}
+
+// active reports whether any filter token was given
+func (f queryFilters) active() bool {
+	return f.Marketplace != "" || len(f.Categories) > 0 || len(f.Tags) > 0 || len(f.Authors) > 0
+}
+
+// matches reports whether a plugin passes every filter token
+func (f queryFilters) matches(p plugin.Plugin) bool {
+	if f.Marketplace != "" && p.Marketplace != f.Marketplace {
+		return false
+	}
+	for _, category := range f.Categories {
+		if strings.ToLower(p.Category) != category {
+			return false
+		}
+	}
+	for _, tag := range f.Tags {
+		if !hasTag(p, tag) {
+			return false
+		}
+	}
+	for _, author := range f.Authors {
+		if !strings.Contains(strings.ToLower(p.AuthorName()), author) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTag reports whether a plugin lists tag (lowercase) as a keyword or tag
+func hasTag(p plugin.Plugin, tag string) bool {
+	for _, list := range [][]string{p.Keywords, p.Tags} {
+		for _, t := range list {
+			if strings.ToLower(t) == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queryError explains why the current search query can't run, e.g. an
+// invalid /regex/. Returns nil for a valid query.
+func (m Model) queryError() error {
+	text := parseQueryFilters(m.textInput.Value()).Text
+	if pattern, ok := search.RegexQuery(text); ok {
+		if _, err := search.CompileRegex(pattern); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
+	return nil
+}
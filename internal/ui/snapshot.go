@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+)
+
+// Snapshot view names accepted by RenderSnapshot
+const (
+	SnapshotViewList        = "list"
+	SnapshotViewDetail      = "detail"
+	SnapshotViewMarketplace = "marketplace"
+)
+
+// SnapshotOptions configures a static, non-interactive render of the TUI
+type SnapshotOptions struct {
+	Width   int
+	Height  int
+	View    string // list, detail, or marketplace
+	Query   string // Search query applied before rendering
+	Plugins []plugin.Plugin
+}
+
+// RenderSnapshot builds a Model from the given plugins, sizes it, applies the
+// query and view, and returns the rendered frame. It never touches the network
+// or the user's config, so output is reproducible for docs and golden tests.
+func RenderSnapshot(opts SnapshotOptions) (string, error) {
+	if opts.Width <= 0 || opts.Height <= 0 {
+		return "", fmt.Errorf("invalid snapshot size %dx%d", opts.Width, opts.Height)
+	}
+
+	m := newModel()
+	m.loading = false
+	m.allPlugins = opts.Plugins
+	m.textInput.SetValue(opts.Query)
+	m.results = m.filteredSearch(opts.Query)
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: opts.Width, Height: opts.Height})
+	m = updated.(Model)
+
+	switch opts.View {
+	case "", SnapshotViewList:
+		m.viewState = ViewList
+	case SnapshotViewDetail:
+		if m.SelectedPlugin() == nil {
+			return "", fmt.Errorf("no plugins match query %q", opts.Query)
+		}
+		m.viewState = ViewDetail
+		m.initOrUpdateDetailViewport(opts.Height)
+	case SnapshotViewMarketplace:
+		m.marketplaceItems = snapshotMarketplaceItems(opts.Plugins)
+		m.ApplyMarketplaceSort()
+		m.viewState = ViewMarketplaceList
+	default:
+		return "", fmt.Errorf("unknown view %q (use list, detail, or marketplace)", opts.View)
+	}
+	m.previousView = m.viewState
+
+	return m.View(), nil
+}
+
+// snapshotMarketplaceItems derives marketplace entries from the plugins
+// themselves instead of the registry and cache used by LoadMarketplaceItems.
+func snapshotMarketplaceItems(plugins []plugin.Plugin) []MarketplaceItem {
+	byName := make(map[string]*MarketplaceItem)
+	var names []string
+
+	for _, p := range plugins {
+		item, ok := byName[p.Marketplace]
+		if !ok {
+			item = &MarketplaceItem{
+				Name:        p.Marketplace,
+				DisplayName: p.Marketplace,
+				Repo:        p.MarketplaceRepo,
+				Status:      MarketplaceAvailable,
+			}
+			byName[p.Marketplace] = item
+			names = append(names, p.Marketplace)
+		}
+		item.TotalPluginCount++
+		if p.Installed {
+			item.InstalledPluginCount++
+			item.Status = MarketplaceInstalled
+		}
+	}
+
+	sort.Strings(names)
+	items := make([]MarketplaceItem, 0, len(names))
+	for _, name := range names {
+		items = append(items, *byName[name])
+	}
+	return items
+}
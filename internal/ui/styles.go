@@ -1,6 +1,13 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"io"
+	"os"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
 
 // Colors - Orange/Peach themed semantic palette
 var (
@@ -26,168 +33,306 @@ var (
 	BorderSubtle = lipgloss.Color("#5C4033") // Warm brown for borders
 )
 
-// Styles
+// Styles, built from the colors by buildStyles
+var (
+	// Layout and list
+	AppStyle, TitleStyle, UpdateNotificationStyle              lipgloss.Style
+	SearchPromptStyle, SearchInputStyle                        lipgloss.Style
+	InstalledIndicator, DisabledIndicator                      lipgloss.Style
+	SelectedIndicator, FavoriteIndicator, AvailableIndicator   lipgloss.Style
+	DiscoverBadge                                              lipgloss.Style
+	PluginNameStyle, PluginNameSelectedStyle                   lipgloss.Style
+	MatchHighlightStyle, MarketplaceStyle, VersionStyle        lipgloss.Style
+	DescriptionStyle, PluginCardStyle, PluginCardSelectedStyle lipgloss.Style
+	StatusBarStyle, DimSeparator, HelpStyle                    lipgloss.Style
+
+	// Detail view and dialogs
+	DetailBoxStyle, ConfirmBoxStyle, DetailTitleStyle           lipgloss.Style
+	DetailLabelStyle, DetailValueStyle, DetailDescStyle         lipgloss.Style
+	InstallCommandStyle, DiscoverMessageStyle, KeyStyle         lipgloss.Style
+	InstalledBadge, DisabledBadge, AvailableBadge               lipgloss.Style
+	NotInstallableBadge, HelpSectionStyle, HelpTextStyle        lipgloss.Style
+	MarkdownHeadingStyle, MarkdownCodeStyle, MarkdownQuoteStyle lipgloss.Style
+
+	// Animation highlight bars
+	HighlightBarFull, HighlightBarMedium, HighlightBarLight lipgloss.Style
+)
+
+// Separators drawn between items and as rules, ASCII in plain mode
 var (
+	separatorBar       string // Between tabs and in the highlight bar, "│"
+	statusBarSeparator string // Joins status bar segments and footer hints, "  │  "
+	ruleChar           string // Repeated for horizontal rules, "─"
+)
+
+// plainStyles turns off colors, text attributes, and box drawing (for
+// NO_COLOR and --plain)
+var plainStyles bool
+
+// styleRenderer renders every plum style; in plain mode it has the ASCII
+// color profile, so styles emit no escape sequences
+var styleRenderer = lipgloss.DefaultRenderer()
+
+func init() {
+	buildStyles()
+}
+
+// SetPlain switches every style to plain text and ASCII separators, or
+// back to the themed styles
+func SetPlain(plain bool) {
+	plainStyles = plain
+	styleRenderer = lipgloss.DefaultRenderer()
+	if plain {
+		styleRenderer = lipgloss.NewRenderer(io.Discard)
+		styleRenderer.SetColorProfile(termenv.Ascii)
+	}
+	buildStyles()
+}
+
+// NoColorRequested reports whether the NO_COLOR environment variable is
+// set (see https://no-color.org)
+func NoColorRequested() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// newStyle starts a style on plum's renderer. Build every style with it
+// rather than lipgloss.NewStyle so plain mode reaches all of them.
+func newStyle() lipgloss.Style {
+	return styleRenderer.NewStyle()
+}
+
+// boxBorder is the border for cards, boxes, and dialogs
+func boxBorder() lipgloss.Border {
+	if plainStyles {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.RoundedBorder()
+}
+
+// styleTextInput applies plum's styles to a text input. In plain mode the
+// input's own placeholder and cursor styles are cleared too.
+func styleTextInput(ti *textinput.Model) {
+	ti.PromptStyle = SearchPromptStyle
+	ti.TextStyle = SearchInputStyle
+	if plainStyles {
+		ti.PlaceholderStyle = newStyle()
+		ti.CompletionStyle = newStyle()
+		ti.Cursor.Style = newStyle()
+		ti.Cursor.TextStyle = newStyle()
+	}
+}
+
+// buildStyles (re)creates the styles from the current colors
+func buildStyles() {
+	separatorBar, statusBarSeparator, ruleChar = "│", "  │  ", "─"
+	if plainStyles {
+		separatorBar, statusBarSeparator, ruleChar = "|", "  |  ", "-"
+	}
+
 	// App container
-	AppStyle = lipgloss.NewStyle().
-			Padding(1, 2)
+	AppStyle = newStyle().
+		Padding(1, 2)
 
 	// Title
-	TitleStyle = lipgloss.NewStyle().
-			Foreground(PeachSoft).
-			Bold(true).
-			MarginBottom(1)
+	TitleStyle = newStyle().
+		Foreground(PeachSoft).
+		Bold(true).
+		MarginBottom(1)
 
 	// Update notification box with gradient border
-	UpdateNotificationStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(PeachSoft).
-				Foreground(PeachSoft).
-				Bold(true).
-				Padding(0, 1)
+	UpdateNotificationStyle = newStyle().
+		Border(boxBorder()).
+		BorderForeground(PeachSoft).
+		Foreground(PeachSoft).
+		Bold(true).
+		Padding(0, 1)
 
 	// Search input
-	SearchPromptStyle = lipgloss.NewStyle().
-				Foreground(PlumBright).
-				Bold(true)
+	SearchPromptStyle = newStyle().
+		Foreground(PlumBright).
+		Bold(true)
 
-	SearchInputStyle = lipgloss.NewStyle().
-				Foreground(TextPrimary)
+	SearchInputStyle = newStyle().
+		Foreground(TextPrimary)
 
 	// Plugin list item - installed
-	InstalledIndicator = lipgloss.NewStyle().
-				Foreground(Success).
-				SetString("●")
+	InstalledIndicator = newStyle().
+		Foreground(Success).
+		SetString("●")
+
+	// Plugin list item - installed but disabled in settings
+	DisabledIndicator = newStyle().
+		Foreground(TextMuted).
+		SetString("◐")
+
+	// Plugin list item - marked for a batch copy (space)
+	SelectedIndicator = newStyle().
+		Foreground(PlumBright).
+		Bold(true).
+		SetString("✓")
+
+	// Plugin list item - starred as a favorite
+	FavoriteIndicator = newStyle().
+		Foreground(PeachSoft).
+		SetString("★")
 
 	// Plugin list item - available
-	AvailableIndicator = lipgloss.NewStyle().
-				Foreground(TextTertiary).
-				SetString("○")
+	AvailableIndicator = newStyle().
+		Foreground(TextTertiary).
+		SetString("○")
 
 	// Discover badge for plugins from uninstalled marketplaces
-	DiscoverBadge = lipgloss.NewStyle().
-			Foreground(PeachSoft).
-			Bold(true).
-			SetString("[Discover]")
+	DiscoverBadge = newStyle().
+		Foreground(PeachSoft).
+		Bold(true).
+		SetString("[Discover]")
 
 	// Plugin name
-	PluginNameStyle = lipgloss.NewStyle().
-			Foreground(TextPrimary).
-			Bold(true)
+	PluginNameStyle = newStyle().
+		Foreground(TextPrimary).
+		Bold(true)
 
 	// Plugin name when selected/highlighted
-	PluginNameSelectedStyle = lipgloss.NewStyle().
-				Foreground(PlumGlow).
-				Bold(true)
+	PluginNameSelectedStyle = newStyle().
+		Foreground(PlumGlow).
+		Bold(true)
+
+	// Characters of a plugin name that matched the search query
+	MatchHighlightStyle = newStyle().
+		Foreground(PlumBright).
+		Bold(true).
+		Underline(true)
 
 	// Plugin marketplace tag
-	MarketplaceStyle = lipgloss.NewStyle().
-				Foreground(TextTertiary)
+	MarketplaceStyle = newStyle().
+		Foreground(TextTertiary)
 
 	// Plugin version
-	VersionStyle = lipgloss.NewStyle().
-			Foreground(TextMuted)
+	VersionStyle = newStyle().
+		Foreground(TextMuted)
 
 	// Plugin description
-	DescriptionStyle = lipgloss.NewStyle().
-				Foreground(TextSecondary)
+	DescriptionStyle = newStyle().
+		Foreground(TextSecondary)
 
 	// Plugin card - normal state
-	PluginCardStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(BorderSubtle).
-			Padding(0, 1)
+	PluginCardStyle = newStyle().
+		Border(boxBorder()).
+		BorderForeground(BorderSubtle).
+		Padding(0, 1)
 
 	// Plugin card - selected state
-	PluginCardSelectedStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(PlumMedium). // Richer plum for selected cards
-				Padding(0, 1)
+	PluginCardSelectedStyle = newStyle().
+		Border(boxBorder()).
+		BorderForeground(PlumMedium). // Richer plum for selected cards
+		Padding(0, 1)
 
 	// Status bar
-	StatusBarStyle = lipgloss.NewStyle().
-			Foreground(TextTertiary).
-			MarginTop(1)
+	StatusBarStyle = newStyle().
+		Foreground(TextTertiary).
+		MarginTop(1)
 
 	// Dim separator for tabs/status bar
-	DimSeparator = lipgloss.NewStyle().
-			Foreground(TextMuted)
+	DimSeparator = newStyle().
+		Foreground(TextMuted)
 
 	// Help text
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(TextMuted)
+	HelpStyle = newStyle().
+		Foreground(TextMuted)
 
 	// Detail view styles
-	DetailBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(PlumBright).
-			Padding(1, 2)
-
-	DetailTitleStyle = lipgloss.NewStyle().
-				Foreground(TextPrimary).
-				Bold(true).
-				MarginBottom(1)
-
-	DetailLabelStyle = lipgloss.NewStyle().
-				Foreground(TextTertiary).
-				Width(12)
-
-	DetailValueStyle = lipgloss.NewStyle().
-				Foreground(TextPrimary)
-
-	DetailDescStyle = lipgloss.NewStyle().
-			Foreground(TextSecondary).
-			MarginTop(1).
-			MarginBottom(1)
-
-	InstallCommandStyle = lipgloss.NewStyle().
-				Foreground(Success).
-				Background(TextMuted).
-				Padding(0, 1)
+	DetailBoxStyle = newStyle().
+		Border(boxBorder()).
+		BorderForeground(PlumBright).
+		Padding(1, 2)
+
+	// Confirmation dialog
+	ConfirmBoxStyle = newStyle().
+		Border(boxBorder()).
+		BorderForeground(PeachSoft).
+		Padding(1, 3)
+
+	DetailTitleStyle = newStyle().
+		Foreground(TextPrimary).
+		Bold(true).
+		MarginBottom(1)
+
+	DetailLabelStyle = newStyle().
+		Foreground(TextTertiary).
+		Width(12)
+
+	DetailValueStyle = newStyle().
+		Foreground(TextPrimary)
+
+	DetailDescStyle = newStyle().
+		Foreground(TextSecondary).
+		MarginTop(1).
+		MarginBottom(1)
+
+	InstallCommandStyle = newStyle().
+		Foreground(Success).
+		Background(TextMuted).
+		Padding(0, 1)
 
 	// Discover message style for marketplace install instructions
-	DiscoverMessageStyle = lipgloss.NewStyle().
-				Foreground(PeachSoft).
-				Italic(true)
+	DiscoverMessageStyle = newStyle().
+		Foreground(PeachSoft).
+		Italic(true)
 
-	KeyStyle = lipgloss.NewStyle().
-			Foreground(PlumBright).
-			Bold(true)
+	KeyStyle = newStyle().
+		Foreground(PlumBright).
+		Bold(true)
 
 	// Badge styles
-	InstalledBadge = lipgloss.NewStyle().
-			Foreground(Success).
-			Bold(true).
-			SetString("[Installed]")
+	InstalledBadge = newStyle().
+		Foreground(Success).
+		Bold(true).
+		SetString("[Installed]")
+
+	DisabledBadge = newStyle().
+		Foreground(TextMuted).
+		SetString("[Disabled]")
 
-	AvailableBadge = lipgloss.NewStyle().
-			Foreground(TextTertiary).
-			SetString("[Available]")
+	AvailableBadge = newStyle().
+		Foreground(TextTertiary).
+		SetString("[Available]")
 
 	// Not installable badge (for LSP/external plugins)
-	NotInstallableBadge = lipgloss.NewStyle().
-				Foreground(TextMuted).
-				Italic(true)
+	NotInstallableBadge = newStyle().
+		Foreground(TextMuted).
+		Italic(true)
 
 	// Help view styles
-	HelpSectionStyle = lipgloss.NewStyle().
-				Foreground(PeachSoft).
-				Bold(true)
+	HelpSectionStyle = newStyle().
+		Foreground(PeachSoft).
+		Bold(true)
 
-	HelpTextStyle = lipgloss.NewStyle().
-			Foreground(TextSecondary)
+	HelpTextStyle = newStyle().
+		Foreground(TextSecondary)
+
+	// Markdown styles for READMEs and descriptions in the detail view
+	MarkdownHeadingStyle = newStyle().
+		Foreground(PlumBright).
+		Bold(true)
+
+	MarkdownCodeStyle = newStyle().
+		Foreground(PeachSoft)
+
+	MarkdownQuoteStyle = newStyle().
+		Foreground(TextTertiary).
+		Italic(true)
 
 	// Animation highlight bars - sliding selection indicator
-	HighlightBarFull = lipgloss.NewStyle().
-				Foreground(PlumBright).
-				Bold(true).
-				SetString("▌ ")
-
-	HighlightBarMedium = lipgloss.NewStyle().
-				Foreground(PlumGlow).
-				SetString("▌ ")
-
-	HighlightBarLight = lipgloss.NewStyle().
-				Foreground(TextTertiary).
-				SetString("│ ")
-)
+	HighlightBarFull = newStyle().
+		Foreground(PlumBright).
+		Bold(true).
+		SetString("▌ ")
+
+	HighlightBarMedium = newStyle().
+		Foreground(PlumGlow).
+		SetString("▌ ")
+
+	HighlightBarLight = newStyle().
+		Foreground(TextTertiary).
+		SetString(separatorBar + " ")
+}
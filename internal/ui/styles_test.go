@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/muesli/termenv"
+)
+
+func TestPlainMode(t *testing.T) {
+	// Force colors on so the styled renders have something to strip
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(profile)
+		SetPlain(false)
+	})
+
+	plugins := []plugin.Plugin{
+		{Name: "alpha", Marketplace: "mkt", Description: "First plugin", Version: "1.0.0", Installed: true, InstallPath: "/tmp/alpha"},
+		{Name: "beta", Marketplace: "mkt", Description: "Second plugin", Version: "2.0.0"},
+	}
+	render := func(t *testing.T) map[string]string {
+		t.Helper()
+		frames := map[string]string{}
+		for _, view := range []string{SnapshotViewList, SnapshotViewDetail, SnapshotViewMarketplace} {
+			frame, err := RenderSnapshot(SnapshotOptions{Width: 100, Height: 30, View: view, Plugins: plugins})
+			if err != nil {
+				t.Fatalf("RenderSnapshot(%s) failed: %v", view, err)
+			}
+			frames[view] = frame
+		}
+		m := newModel()
+		m.requestConfirm("Install alpha@mkt in user scope?", nil)
+		frames["confirm"] = m.View()
+		frames["markdown"] = renderMarkdown("# Title\n\n> quoted\n\n---\n\nSome `code`.", 40)
+		return frames
+	}
+
+	SetPlain(false)
+	for view, frame := range render(t) {
+		if !strings.Contains(frame, "\x1b[") {
+			t.Errorf("%s: styled output should contain ANSI escapes", view)
+		}
+	}
+
+	SetPlain(true)
+	for view, frame := range render(t) {
+		if strings.Contains(frame, "\x1b") {
+			t.Errorf("%s: plain output contains ANSI escapes:\n%q", view, frame)
+		}
+		for _, box := range []string{"│", "─", "╭", "╮", "╰", "╯"} {
+			if strings.Contains(frame, box) {
+				t.Errorf("%s: plain output contains box drawing %q:\n%s", view, box, frame)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme maps color role names (see themeRoles) to hex colors, e.g.
+//
+//	{"plumBright": "#5FAFFF", "success": "#00AF87"}
+//
+// Roles left out keep their default color.
+type Theme map[string]string
+
+// themeRoles are the colors a theme file can override, by role name
+var themeRoles = map[string]*lipgloss.Color{
+	"plumMedium":    &PlumMedium,
+	"plumBright":    &PlumBright,
+	"plumGlow":      &PlumGlow,
+	"peachSoft":     &PeachSoft,
+	"success":       &Success,
+	"error":         &Error,
+	"textPrimary":   &TextPrimary,
+	"textSecondary": &TextSecondary,
+	"textTertiary":  &TextTertiary,
+	"textMuted":     &TextMuted,
+	"borderSubtle":  &BorderSubtle,
+}
+
+// defaultColors holds the built-in palette, so a theme can be undone
+var defaultColors = func() map[string]lipgloss.Color {
+	colors := make(map[string]lipgloss.Color, len(themeRoles))
+	for role, color := range themeRoles {
+		colors[role] = *color
+	}
+	return colors
+}()
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// plumConfigDir returns plum's own config directory, $XDG_CONFIG_HOME/plum
+// or ~/.config/plum
+func plumConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "plum"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "plum"), nil
+}
+
+// themePath returns the user's theme file, theme.json in plumConfigDir
+// (variable for testing)
+var themePath = func() (string, error) {
+	dir, err := plumConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "theme.json"), nil
+}
+
+// readTheme reads and validates a theme file. A missing file is no theme.
+func readTheme(path string) (Theme, error) {
+	// #nosec G304 -- path is the user's own theme file
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var theme Theme
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	for role, hex := range theme {
+		if _, ok := themeRoles[role]; !ok {
+			return nil, fmt.Errorf("unknown color role %q (known: %s)", role, strings.Join(themeRoleNames(), ", "))
+		}
+		if !hexColorPattern.MatchString(hex) {
+			return nil, fmt.Errorf("%s: %q is not a hex color like #FF8C42", role, hex)
+		}
+	}
+	return theme, nil
+}
+
+// themeRoleNames returns the known role names, sorted
+func themeRoleNames() []string {
+	names := make([]string, 0, len(themeRoles))
+	for role := range themeRoles {
+		names = append(names, role)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyTheme resets the palette to the defaults, applies the theme's
+// overrides, and rebuilds the styles. A nil theme restores the defaults.
+func applyTheme(theme Theme) {
+	for role, color := range themeRoles {
+		*color = defaultColors[role]
+		if hex, ok := theme[role]; ok {
+			*color = lipgloss.Color(hex)
+		}
+	}
+	buildStyles()
+}
+
+// loadTheme applies the theme at path. If the file can't be used, a
+// warning is written to warn and the default colors are kept.
+func loadTheme(path string, warn io.Writer) {
+	theme, err := readTheme(path)
+	if err != nil {
+		_, _ = fmt.Fprintf(warn, "Warning: ignoring theme %s: %v\n", path, err)
+		theme = nil
+	}
+	applyTheme(theme)
+}
+
+// loadUserThemeOnce applies the user's theme file the first time a model
+// is created
+var loadUserThemeOnce sync.Once
+
+func loadUserTheme() {
+	loadUserThemeOnce.Do(func() {
+		if path, err := themePath(); err == nil {
+			loadTheme(path, os.Stderr)
+		}
+	})
+}
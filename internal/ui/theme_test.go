@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// writeTheme writes a theme file and restores the default colors after the test
+func writeTheme(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { applyTheme(nil) })
+	return path
+}
+
+func TestLoadTheme_FullOverride(t *testing.T) {
+	theme := map[string]string{}
+	var entries []string
+	for i, role := range themeRoleNames() {
+		hex := fmt.Sprintf("#%06X", i+1)
+		theme[role] = hex
+		entries = append(entries, `"`+role+`": "`+hex+`"`)
+	}
+	path := writeTheme(t, "{"+strings.Join(entries, ",")+"}")
+
+	var warn bytes.Buffer
+	loadTheme(path, &warn)
+	if warn.Len() > 0 {
+		t.Errorf("unexpected warning: %s", warn.String())
+	}
+	for role, color := range themeRoles {
+		if string(*color) != theme[role] {
+			t.Errorf("%s = %s, want %s", role, *color, theme[role])
+		}
+	}
+	if KeyStyle.GetForeground() != lipgloss.Color(theme["plumBright"]) {
+		t.Errorf("styles should be rebuilt from the theme, KeyStyle foreground = %v", KeyStyle.GetForeground())
+	}
+}
+
+func TestLoadTheme_PartialOverride(t *testing.T) {
+	path := writeTheme(t, `{"success": "#00AF87", "textMuted": "#888"}`)
+
+	loadTheme(path, &bytes.Buffer{})
+	if Success != "#00AF87" || TextMuted != "#888" {
+		t.Errorf("overrides not applied: success %s, textMuted %s", Success, TextMuted)
+	}
+	if PlumBright != defaultColors["plumBright"] {
+		t.Errorf("unspecified roles should keep their defaults, plumBright = %s", PlumBright)
+	}
+	if InstalledBadge.GetForeground() != lipgloss.Color("#00AF87") {
+		t.Error("InstalledBadge should use the themed success color")
+	}
+}
+
+func TestLoadTheme_InvalidFallsBack(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON": `{"success": `,
+		"bad color":    `{"success": "green"}`,
+		"unknown role": `{"background": "#000000"}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Start from a themed palette to check it is reset
+			applyTheme(Theme{"plumBright": "#123456"})
+			path := writeTheme(t, content)
+
+			var warn bytes.Buffer
+			loadTheme(path, &warn)
+			if !strings.Contains(warn.String(), "Warning: ignoring theme "+path) {
+				t.Errorf("expected a warning naming the file, got %q", warn.String())
+			}
+			for role, color := range themeRoles {
+				if *color != defaultColors[role] {
+					t.Errorf("%s = %s, want default %s", role, *color, defaultColors[role])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTheme_MissingFile(t *testing.T) {
+	t.Cleanup(func() { applyTheme(nil) })
+	var warn bytes.Buffer
+	loadTheme(filepath.Join(t.TempDir(), "theme.json"), &warn)
+	if warn.Len() > 0 {
+		t.Errorf("a missing theme file should be silent, got %q", warn.String())
+	}
+	if PeachSoft != defaultColors["peachSoft"] {
+		t.Error("expected default colors")
+	}
+}
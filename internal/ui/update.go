@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -13,13 +15,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/itsdevcoffee/plum/internal/marketplace"
+	"github.com/itsdevcoffee/plum/internal/plugin"
 )
 
 func init() {
 	// Set functions to avoid circular import
 	clearCacheAndReload = marketplace.RefreshAll // Use RefreshAll to fetch from registry
-	checkForNewMarketplaces = func() ([]PopularMarketplace, int, error) {
-		updated, newCount, err := marketplace.FetchRegistryWithComparison(marketplace.PopularMarketplaces)
+	checkForNewMarketplaces = func(ctx context.Context) ([]PopularMarketplace, int, error) {
+		updated, newCount, err := marketplace.FetchRegistryWithComparison(ctx, marketplace.PopularMarketplaces)
 		// Convert marketplace.PopularMarketplace to ui.PopularMarketplace
 		result := make([]PopularMarketplace, len(updated))
 		for i, m := range updated {
@@ -34,6 +37,9 @@ func init() {
 	}
 }
 
+// clipboardWrite copies text to the system clipboard (variable for testing)
+var clipboardWrite = clipboard.WriteAll
+
 // animationTickMsg is sent to update animations
 type animationTickMsg time.Time
 
@@ -43,6 +49,9 @@ type clearCopiedFlashMsg struct{}
 // clearLinkCopiedFlashMsg clears the "Link Copied!" indicator
 type clearLinkCopiedFlashMsg struct{}
 
+// clearJSONURLCopiedFlashMsg clears the "URL Copied!" indicator
+type clearJSONURLCopiedFlashMsg struct{}
+
 // clearPathCopiedFlashMsg clears the "Path Copied!" indicator
 type clearPathCopiedFlashMsg struct{}
 
@@ -55,6 +64,12 @@ type clearLocalOpenedFlashMsg struct{}
 // clearClipboardErrorMsg clears the "Clipboard error!" indicator
 type clearClipboardErrorMsg struct{}
 
+// clearInstallFlashMsg clears the "Installed!" or install failure indicator
+type clearInstallFlashMsg struct{}
+
+// clearToggleFlashMsg clears the enable/disable confirmation or failure
+type clearToggleFlashMsg struct{}
+
 func clearCopiedFlash() tea.Cmd {
 	return clearFlashAfter(2*time.Second, clearCopiedFlashMsg{})
 }
@@ -63,6 +78,10 @@ func clearLinkCopiedFlash() tea.Cmd {
 	return clearFlashAfter(2*time.Second, clearLinkCopiedFlashMsg{})
 }
 
+func clearJSONURLCopiedFlash() tea.Cmd {
+	return clearFlashAfter(2*time.Second, clearJSONURLCopiedFlashMsg{})
+}
+
 func clearPathCopiedFlash() tea.Cmd {
 	return clearFlashAfter(2*time.Second, clearPathCopiedFlashMsg{})
 }
@@ -79,6 +98,44 @@ func clearClipboardError() tea.Cmd {
 	return clearFlashAfter(3*time.Second, clearClipboardErrorMsg{})
 }
 
+func clearInstallFlash(duration time.Duration) tea.Cmd {
+	return clearFlashAfter(duration, clearInstallFlashMsg{})
+}
+
+func clearToggleFlash(duration time.Duration) tea.Cmd {
+	return clearFlashAfter(duration, clearToggleFlashMsg{})
+}
+
+// searchDebounce is how long typing must pause before the list is re-filtered
+const searchDebounce = 80 * time.Millisecond
+
+// searchDebounceMsg asks for the search to run if no newer keystroke arrived
+type searchDebounceMsg struct {
+	seq int
+}
+
+// runPendingSearch re-filters the list for the current input right away,
+// keeping the cursor in range
+func (m *Model) runPendingSearch() {
+	m.searchPending = false
+	m.results = m.filteredSearch(m.textInput.Value())
+	if m.cursor >= len(m.results) {
+		m.cursor = len(m.results) - 1
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+	}
+	m.UpdateScroll()
+	m.SnapCursorToTarget()
+}
+
+// debounceSearch schedules the search for the keystroke numbered seq
+func debounceSearch(seq int) tea.Cmd {
+	return tea.Tick(searchDebounce, func(t time.Time) tea.Msg {
+		return searchDebounceMsg{seq: seq}
+	})
+}
+
 func clearFlashAfter(duration time.Duration, msg tea.Msg) tea.Cmd {
 	return tea.Tick(duration, func(t time.Time) tea.Msg {
 		return msg
@@ -109,6 +166,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.detailViewport, cmd = m.detailViewport.Update(msg)
 			return m, cmd
 		}
+		if m.viewState == ViewList {
+			return m.handleListMouse(msg)
+		}
 		return m, nil
 
 	case tea.WindowSizeMsg:
@@ -119,9 +179,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		(&m).initOrUpdateHelpViewport(msg.Height)
 		(&m).initOrUpdateDetailViewport(msg.Height)
 
+		// Keep the selection on screen at the new size
+		m.UpdateScroll()
+		m.UpdateMarketplaceScroll()
+		m.SnapCursorToTarget()
+
 		return m, nil
 
 	case pluginsLoadedMsg:
+		if msg.generation != m.reloadGeneration {
+			// A newer reload is in flight; its result will arrive later
+			return m, nil
+		}
+		m.stopRefresh()
+		m.enriching = false
 		if msg.err != nil {
 			m.err = msg.err
 			m.loading = false
@@ -129,23 +200,61 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.allPlugins = msg.plugins
+		m.pluginStates = msg.states
+		m.favorites = msg.favorites
+		m.searcher.Update(msg.plugins)
 		m.results = m.filteredSearch(m.textInput.Value())
 		m.loading = false
 		m.refreshing = false
 		// Initialize cursor animation to current position
 		m.cursorY = 0
 		m.targetCursorY = 0
+		if msg.cached {
+			// Keep the spinner up if the cache had nothing to show yet
+			m.enriching = true
+			m.loading = len(msg.plugins) == 0
+			return m, enrichPlugins(msg.generation)
+		}
+		return m, nil
+
+	case pluginsEnrichedMsg:
+		if msg.generation != m.reloadGeneration {
+			// A newer reload replaced the cached list already
+			return m, nil
+		}
+		m.enriching = false
+		m.loading = false
+		if msg.err != nil {
+			// The cached list is still usable; only fail if there is none
+			if len(m.allPlugins) == 0 {
+				m.err = msg.err
+			}
+			return m, nil
+		}
+		m.applyEnrichedPlugins(msg.plugins)
 		return m, nil
 
 	case refreshCacheMsg:
-		// Start refresh process
+		// Start refresh process, cancelling any refresh still in flight
+		m.stopRefresh()
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancelRefresh = cancel
 		m.refreshing = true
 		m.newMarketplacesCount = 0 // Clear notification during refresh
 		return m, tea.Batch(
 			m.spinner.Tick,
-			doRefreshCache,
+			doRefreshCache(ctx, m.nextReload()),
 		)
 
+	case searchDebounceMsg:
+		if msg.seq != m.searchSeq || !m.searchPending || m.marketplaceAutocompleteActive {
+			// A newer keystroke will search, Enter already ran it, or the
+			// picker is showing
+			return m, nil
+		}
+		m.runPendingSearch()
+		return m, nil
+
 	case registryCheckedMsg:
 		// Registry check completed - store new marketplace count and force re-render
 		m.newMarketplacesCount = msg.newCount
@@ -160,7 +269,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case spinner.TickMsg:
-		if m.loading || m.refreshing {
+		if m.loading || m.refreshing || m.installing != "" {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
@@ -194,6 +303,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.linkCopiedFlash = false
 		return m, nil
 
+	case clearJSONURLCopiedFlashMsg:
+		m.jsonURLCopiedFlash = false
+		return m, nil
+
 	case clearPathCopiedFlashMsg:
 		m.pathCopiedFlash = false
 		return m, nil
@@ -209,6 +322,57 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case clearClipboardErrorMsg:
 		m.clipboardErrorFlash = false
 		return m, nil
+
+	case settingsEditedMsg:
+		if msg.err != nil {
+			m.editorErrorFlash = true
+			return m, clearEditorError()
+		}
+		// Reload to pick up manual edits
+		return m, loadPlugins(m.nextReload())
+
+	case clearEditorErrorMsg:
+		m.editorErrorFlash = false
+		return m, nil
+
+	case statsLoadedMsg:
+		m.applyMarketplaceStats(msg)
+		return m, nil
+
+	case installConfirmedMsg:
+		if m.installing != "" {
+			return m, nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancelInstall = cancel
+		m.installing = msg.plugin.FullName()
+		m.installError = nil
+		return m, tea.Batch(m.spinner.Tick, doInstall(ctx, msg.plugin))
+
+	case installDoneMsg:
+		m.stopInstall()
+		m.installing = ""
+		if errors.Is(msg.err, context.Canceled) {
+			// Cancelled with Esc; nothing to report
+			return m, nil
+		}
+		if msg.err != nil {
+			m.installError = msg.err
+			return m, clearInstallFlash(5 * time.Second)
+		}
+		m.markInstalled(msg.fullName, msg.installPath)
+		m.installedFlash = true
+		return m, clearInstallFlash(2 * time.Second)
+
+	case clearInstallFlashMsg:
+		m.installedFlash = false
+		m.installError = nil
+		return m, nil
+
+	case clearToggleFlashMsg:
+		m.toggleFlash = ""
+		m.toggleError = nil
+		return m, nil
 	}
 
 	return m, nil
@@ -247,11 +411,14 @@ func (m *Model) initOrUpdateHelpViewport(terminalHeight int) {
 		}
 
 		m.helpViewport.SetContent(sectionsContent)
+		m.helpContentLines = contentHeight
 	}
 }
 
+// detailViewportOverhead is the detail view's height outside the viewport
+const detailViewportOverhead = 9
+
 func (m *Model) initOrUpdateDetailViewport(terminalHeight int) {
-	const overhead = 9
 	const minWidth = 40
 
 	detailViewportWidth := m.ContentWidth() - 10
@@ -260,34 +427,44 @@ func (m *Model) initOrUpdateDetailViewport(terminalHeight int) {
 	}
 
 	if m.detailViewport.Width == 0 {
-		viewportHeight := terminalHeight - overhead
+		viewportHeight := terminalHeight - detailViewportOverhead
 		if viewportHeight < 5 {
 			viewportHeight = 5
 		}
 		m.detailViewport = viewport.New(detailViewportWidth, viewportHeight)
-		return
 	}
 
 	m.detailViewport.Width = detailViewportWidth
 
 	if m.viewState == ViewDetail {
-		if p := m.SelectedPlugin(); p != nil {
-			detailContent := m.generateDetailContent(p, detailViewportWidth)
-			contentHeight := lipgloss.Height(detailContent)
-			maxHeight := terminalHeight - overhead
-			if maxHeight < 3 {
-				maxHeight = 3
-			}
+		m.fitDetailViewport(terminalHeight)
+	}
+}
 
-			if contentHeight < maxHeight {
-				m.detailViewport.Height = contentHeight
-			} else {
-				m.detailViewport.Height = maxHeight
-			}
+// fitDetailViewport loads the selected plugin into the detail viewport and
+// sizes it to the content, so short plugins get a short box instead of one
+// padded to the full screen. Taller content is capped and scrolls.
+func (m *Model) fitDetailViewport(terminalHeight int) {
+	p := m.SelectedPlugin()
+	if p == nil {
+		return
+	}
 
-			m.detailViewport.SetContent(detailContent)
-		}
+	detailContent := m.generateDetailContent(p, m.detailViewport.Width)
+	contentHeight := lipgloss.Height(detailContent)
+	maxHeight := terminalHeight - detailViewportOverhead
+	if maxHeight < 3 {
+		maxHeight = 3
+	}
+
+	if contentHeight < maxHeight {
+		m.detailViewport.Height = contentHeight
+	} else {
+		m.detailViewport.Height = maxHeight
 	}
+
+	m.detailViewport.SetContent(detailContent)
+	m.detailContentLines = contentHeight
 }
 
 // handleKeyMsg handles keyboard input
@@ -310,6 +487,8 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleMarketplaceListKeys(msg)
 	case ViewMarketplaceDetail:
 		return m.handleMarketplaceDetailKeys(msg)
+	case ViewConfirm:
+		return m.handleConfirmKeys(msg)
 	}
 
 	return m, nil
@@ -318,42 +497,49 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // handleListKeys handles keys in the list view
 // Uses telescope/fzf pattern: Ctrl+key for navigation, typing goes to search
 func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	// Navigation: Ctrl + j/k/n/p or arrow keys
-	case "up", "ctrl+k", "ctrl+p":
-		// Handle marketplace autocomplete navigation
-		if m.marketplaceAutocompleteActive {
-			if m.marketplaceAutocompleteCursor > 0 {
-				m.marketplaceAutocompleteCursor--
-			}
-			return m, nil
+	// Vim-style gg jumps to the top. With an empty search the first press
+	// is held back; if the next key isn't the same, the held key is typed
+	// into the search after all, ahead of that key.
+	if held := m.pendingTop; held != "" {
+		m.pendingTop = ""
+		if msg.String() == held {
+			return m.listTop()
 		}
-
-		if m.cursor > 0 {
-			m.cursor--
+		var typed tea.Cmd
+		if runes := []rune(held); len(runes) == 1 {
+			m, typed = m.typeIntoSearch(tea.KeyMsg{Type: tea.KeyRunes, Runes: runes})
 		}
-		m.UpdateScroll()
-		m.SetCursorTarget()
-		return m, animationTick()
+		updated, cmd := m.handleListKeys(msg)
+		return updated, tea.Batch(typed, cmd)
+	}
+	if key := msg.String(); m.keys.GoTop.Has(key) && m.textInput.Value() == "" && !m.marketplaceAutocompleteActive {
+		m.pendingTop = key
+		return m, nil
+	}
+	// '*' can't start a useful search, so with an empty search it stars
+	if m.keys.List.Star.Has(msg.String()) && m.textInput.Value() == "" {
+		return m.toggleFavorite()
+	}
 
-	case "down", "ctrl+j", "ctrl+n":
-		// Handle marketplace autocomplete navigation
-		if m.marketplaceAutocompleteActive {
-			if m.marketplaceAutocompleteCursor < len(m.marketplaceAutocompleteList)-1 {
-				m.marketplaceAutocompleteCursor++
-			}
+	switch key := msg.String(); {
+	// Navigation: Ctrl + j/k/n/p or arrow keys
+	case m.keys.Up.Has(key):
+		// Up at the top of the list recalls earlier searches when the
+		// search box is empty or still shows a recalled query
+		if !m.marketplaceAutocompleteActive && m.cursor == 0 && m.recallSearch() {
 			return m, nil
 		}
+		return m.listUp()
 
-		if m.cursor < len(m.results)-1 {
-			m.cursor++
+	case m.keys.Down.Has(key):
+		// While recalling, Down steps back toward newer searches
+		if !m.marketplaceAutocompleteActive && m.forwardSearch() {
+			return m, nil
 		}
-		m.UpdateScroll()
-		m.SetCursorTarget()
-		return m, animationTick()
+		return m.listDown()
 
 	// Page navigation
-	case "pgup", "ctrl+u":
+	case m.keys.PageUp.Has(key):
 		m.cursor -= m.maxVisibleItems()
 		if m.cursor < 0 {
 			m.cursor = 0
@@ -362,7 +548,7 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.SetCursorTarget()
 		return m, animationTick()
 
-	case "pgdown", "ctrl+d":
+	case m.keys.PageDown.Has(key):
 		m.cursor += m.maxVisibleItems()
 		if m.cursor >= len(m.results) {
 			m.cursor = len(m.results) - 1
@@ -375,13 +561,10 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, animationTick()
 
 	// Jump to start/end
-	case "home":
-		m.cursor = 0
-		m.scrollOffset = 0
-		m.SetCursorTarget()
-		return m, animationTick()
+	case m.keys.Top.Has(key):
+		return m.listTop()
 
-	case "end":
+	case m.keys.Bottom.Has(key):
 		if len(m.results) > 0 {
 			m.cursor = len(m.results) - 1
 		}
@@ -390,47 +573,10 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, animationTick()
 
 	// Actions
-	case "enter":
-		// Handle marketplace autocomplete selection
-		if m.marketplaceAutocompleteActive {
-			m.SelectMarketplaceAutocomplete()
-			m.results = m.filteredSearch(m.textInput.Value())
-			return m, nil
-		}
+	case m.keys.Select.Has(key):
+		return m.openSelectedResult()
 
-		if len(m.results) > 0 {
-			// Set detail viewport content before transition (like help menu)
-			if m.detailViewport.Width > 0 {
-				if p := m.SelectedPlugin(); p != nil {
-					contentWidth := m.ContentWidth() - 10
-					if contentWidth < 40 {
-						contentWidth = 40
-					}
-					detailContent := m.generateDetailContent(p, contentWidth)
-
-					// Calculate viewport height (match WindowSizeMsg overhead)
-					contentHeight := lipgloss.Height(detailContent)
-					maxHeight := m.windowHeight - 9
-					if maxHeight < 3 {
-						maxHeight = 3
-					}
-
-					if contentHeight < maxHeight {
-						m.detailViewport.Height = contentHeight
-					} else {
-						m.detailViewport.Height = maxHeight
-					}
-
-					m.detailViewport.SetContent(detailContent)
-					m.detailViewport.GotoTop() // Reset scroll position
-				}
-			}
-			m.StartViewTransition(ViewDetail, 1) // Forward transition
-			return m, animationTick()
-		}
-		return m, nil
-
-	case "?":
+	case m.keys.Help.Has(key):
 		// Set help SECTIONS content in viewport (not header/footer)
 		if m.helpViewport.Width > 0 {
 			sectionsContent := m.generateHelpSections()
@@ -458,52 +604,101 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 
 			m.helpViewport.SetContent(sectionsContent)
+			m.helpContentLines = contentHeight
 			m.helpViewport.GotoTop()
 		}
 		m.StartViewTransition(ViewHelp, 1)
 		return m, animationTick()
 
-	case "tab", "right":
+	case m.keys.NextTab.Has(key):
 		m.NextFilter()
 		return m, nil
 
-	case "shift+tab", "left":
+	case m.keys.PrevTab.Has(key):
 		m.PrevFilter()
 		return m, nil
 
-	case "shift+v", "V":
+	case m.keys.List.ToggleView.Has(key):
 		m.ToggleDisplayMode()
 		return m, nil
 
-	case "ctrl+t":
+	case m.keys.List.ExpandRow.Has(key):
+		m.ToggleSlimExpanded()
+		return m, nil
+
+	case m.keys.List.Sort.Has(key):
+		m.NextPluginSort()
+		return m, nil
+
+	case m.keys.List.PreferInstalled.Has(key):
+		m.TogglePreferInstalled()
+		return m, nil
+
+	case m.keys.List.Transition.Has(key):
 		m.CycleTransitionStyle()
 		return m, nil
 
-	case "shift+u", "U":
+	case m.keys.List.ToggleEnabled.Has(key):
+		return m.toggleSelectedPlugin()
+
+	case m.keys.List.Favorite.Has(key):
+		return m.toggleFavorite()
+
+	case m.keys.List.Export.Has(key):
+		m.ExportResults()
+		if m.toggleError != nil {
+			return m, clearToggleFlash(3 * time.Second)
+		}
+		return m, clearToggleFlash(4 * time.Second)
+
+	case m.keys.List.ExportFormat.Has(key):
+		m.NextExportFormat()
+		return m, clearToggleFlash(2 * time.Second)
+
+	// Multi-select. Space only marks while the search is empty or a
+	// selection is already under way, so multi-word searches still work.
+	case m.keys.List.Mark.Has(key):
+		if m.textInput.Value() == "" || m.SelectionCount() > 0 {
+			m.ToggleSelected()
+			return m, nil
+		}
+
+	case m.keys.List.CopyMarked.Has(key):
+		if m.SelectionCount() > 0 {
+			return m.copySelectedCommands()
+		}
+
+	case m.keys.List.Refresh.Has(key):
 		// Refresh cache - clear and re-fetch all marketplace data
 		return m, func() tea.Msg {
 			return refreshCacheMsg{}
 		}
 
-	case "shift+m", "M":
-		// Open marketplace browser
-		_ = m.LoadMarketplaceItems()
-		m.previousViewBeforeMarketplace = ViewList
-		m.StartViewTransition(ViewMarketplaceList, 1)
-		return m, animationTick()
+	case m.keys.Marketplace.Has(key):
+		return m.openMarketplaceBrowser(ViewList)
 
-	// Clear search, cancel refresh, or quit
-	case "esc", "ctrl+g":
+	// Clear search, cancel refresh or install, or quit
+	case m.keys.List.ClearSearch.Has(key):
 		// If refreshing, cancel the refresh
 		if m.refreshing {
+			m.stopRefresh()
 			m.refreshing = false
 			m.refreshProgress = 0
 			m.refreshTotal = 0
 			m.refreshCurrent = ""
+			// The cancelled refresh still reports back; drop its result
+			m.nextReload()
+			return m, nil
+		}
+		// If installing, cancel the install; its installDoneMsg clears the state
+		if m.cancelInstall != nil {
+			m.stopInstall()
 			return m, nil
 		}
-		// Otherwise clear search or quit
-		if m.textInput.Value() != "" {
+		// Otherwise clear the selection, then the search, then quit
+		if m.SelectionCount() > 0 {
+			m.selected = nil
+		} else if m.textInput.Value() != "" {
 			m.textInput.SetValue("")
 			m.results = m.filteredSearch("")
 			m.cursor = 0
@@ -516,6 +711,119 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// All other keys go to text input (typing)
+	return m.typeIntoSearch(msg)
+}
+
+// listUp moves the list selection, or the marketplace suggestion, up
+func (m Model) listUp() (tea.Model, tea.Cmd) {
+	if m.marketplaceAutocompleteActive {
+		if m.marketplaceAutocompleteCursor > 0 {
+			m.marketplaceAutocompleteCursor--
+		}
+		return m, nil
+	}
+
+	if m.cursor > 0 {
+		m.cursor--
+	}
+	m.UpdateScroll()
+	m.SetCursorTarget()
+	return m, animationTick()
+}
+
+// listDown moves the list selection, or the marketplace suggestion, down
+func (m Model) listDown() (tea.Model, tea.Cmd) {
+	if m.marketplaceAutocompleteActive {
+		if m.marketplaceAutocompleteCursor < len(m.marketplaceAutocompleteList)-1 {
+			m.marketplaceAutocompleteCursor++
+		}
+		return m, nil
+	}
+
+	if m.cursor < len(m.results)-1 {
+		m.cursor++
+	}
+	m.UpdateScroll()
+	m.SetCursorTarget()
+	return m, animationTick()
+}
+
+// listTop jumps to the first result
+func (m Model) listTop() (tea.Model, tea.Cmd) {
+	m.cursor = 0
+	m.scrollOffset = 0
+	m.SetCursorTarget()
+	return m, animationTick()
+}
+
+// openSelectedResult opens the selected result's details, or accepts the
+// highlighted marketplace suggestion
+func (m Model) openSelectedResult() (tea.Model, tea.Cmd) {
+	// Handle marketplace autocomplete selection
+	if m.marketplaceAutocompleteActive {
+		m.SelectMarketplaceAutocomplete()
+		m.results = m.filteredSearch(m.textInput.Value())
+		return m, nil
+	}
+
+	// Enter during the debounce opens a result for what was typed
+	if m.searchPending {
+		m.runPendingSearch()
+	}
+
+	if len(m.results) > 0 {
+		m.recordSearch(m.textInput.Value())
+		// Set detail viewport content before transition (like help menu)
+		if m.detailViewport.Width > 0 {
+			m.fitDetailViewport(m.windowHeight)
+			m.detailViewport.GotoTop() // Reset scroll position
+		}
+		m.StartViewTransition(ViewDetail, 1) // Forward transition
+		return m, animationTick()
+	}
+	return m, nil
+}
+
+// doubleClickInterval is the longest gap between two clicks on the same row
+// that still opens it
+const doubleClickInterval = 400 * time.Millisecond
+
+// handleListMouse selects the clicked result, opens it on a double-click,
+// and moves the selection with the scroll wheel
+func (m Model) handleListMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return m.listUp()
+
+	case tea.MouseButtonWheelDown:
+		return m.listDown()
+
+	case tea.MouseButtonLeft:
+		idx := m.resultIndexAt(msg.Y)
+		if idx < 0 {
+			return m, nil
+		}
+		now := time.Now()
+		double := idx == m.lastClickIndex && now.Sub(m.lastClickAt) <= doubleClickInterval
+		m.lastClickIndex, m.lastClickAt = idx, now
+		m.cursor = idx
+		m.UpdateScroll()
+		m.SetCursorTarget()
+		if double {
+			m.lastClickAt = time.Time{}
+			return m.openSelectedResult()
+		}
+		return m, animationTick()
+	}
+	return m, nil
+}
+
+// typeIntoSearch passes a key to the search input and schedules the search
+func (m Model) typeIntoSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	oldValue := m.textInput.Value()
 	m.textInput, cmd = m.textInput.Update(msg)
@@ -524,43 +832,78 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Update marketplace autocomplete state
 	m.UpdateMarketplaceAutocomplete(newValue)
 
-	// Re-run search on input change (with filter)
-	if !m.marketplaceAutocompleteActive {
-		m.results = m.filteredSearch(newValue)
-	}
-
-	// Reset cursor to top on any search input change
+	// Reset cursor to top on any search input change. The input updates
+	// right away; the search itself waits for typing to pause.
 	if newValue != oldValue {
 		m.cursor = 0
 		m.scrollOffset = 0
 		m.marketplaceAutocompleteCursor = 0
 		m.SnapCursorToTarget()
-	} else if m.cursor >= len(m.results) {
-		// Clamp cursor if somehow out of bounds
-		m.cursor = len(m.results) - 1
-		if m.cursor < 0 {
-			m.cursor = 0
-		}
+
+		m.searchSeq++
+		m.searchPending = true
+		cmd = tea.Batch(cmd, debounceSearch(m.searchSeq))
 	}
 
 	return m, cmd
 }
 
+// copySelectedCommands copies the marked plugins' install commands
+func (m Model) copySelectedCommands() (tea.Model, tea.Cmd) {
+	commands := m.selectedInstallCommands()
+	if commands == "" {
+		return m, nil
+	}
+	if err := clipboardWrite(commands); err == nil {
+		m.copiedFlash = true
+		return m, clearCopiedFlash()
+	}
+	m.clipboardErrorFlash = true
+	return m, clearClipboardError()
+}
+
+// toggleSelectedPlugin enables or disables the selected plugin if installed
+func (m Model) toggleSelectedPlugin() (tea.Model, tea.Cmd) {
+	p := m.SelectedPlugin()
+	if p == nil || !p.Installed {
+		return m, nil
+	}
+	m.togglePluginEnabled(*p)
+	if m.toggleError != nil {
+		return m, clearToggleFlash(3 * time.Second)
+	}
+	return m, clearToggleFlash(2 * time.Second)
+}
+
+// toggleFavorite stars or unstars the selected plugin
+func (m Model) toggleFavorite() (tea.Model, tea.Cmd) {
+	m.ToggleFavorite()
+	if m.toggleError != nil {
+		return m, clearToggleFlash(3 * time.Second)
+	}
+	return m, clearToggleFlash(2 * time.Second)
+}
+
 // handleDetailKeys handles keys in the detail view
 // TODO(Phase 4.2): Split into sub-handlers to reduce complexity (currently 35)
 //   - handleDetailCopyActions() for c, y, l, p keys
 //   - handleDetailNavigationActions() for open, back, transitions
 //   - See keybindings.go for centralized key definitions
 func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q":
+	switch key := msg.String(); {
+	case m.keys.Quit.Has(key):
 		return m, tea.Quit
 
-	case "esc", "backspace":
+	case m.keys.Back.Has(key):
+		if m.detailFromMarketplace {
+			// Back to the marketplace plugin list it was opened from
+			m.StartViewTransition(ViewMarketplaceDetail, -1)
+			return m, animationTick()
+		}
 		m.StartViewTransition(ViewList, -1) // Back transition
 		return m, animationTick()
 
-	case "c":
+	case m.keys.Detail.Copy.Has(key):
 		if p := m.SelectedPlugin(); p != nil && !p.Installed {
 			var copyText string
 			if p.IsDiscoverable {
@@ -569,7 +912,7 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				copyText = p.InstallCommand()
 			}
 
-			if err := clipboard.WriteAll(copyText); err == nil {
+			if err := clipboardWrite(copyText); err == nil {
 				m.copiedFlash = true
 				return m, clearCopiedFlash()
 			}
@@ -578,9 +921,22 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "y":
+	case m.keys.Detail.Install.Has(key):
+		// Install in user scope without leaving the TUI
+		if p := m.SelectedPlugin(); p != nil && readyToInstall(*p) && m.installing == "" {
+			m.requestConfirm("Install "+p.FullName()+" in user scope?", confirmInstall(*p))
+		}
+		return m, nil
+
+	case m.keys.Detail.ToggleEnabled.Has(key):
+		return m.toggleSelectedPlugin()
+
+	case m.keys.Detail.Favorite.Has(key):
+		return m.toggleFavorite()
+
+	case m.keys.Detail.CopyCommand.Has(key):
 		if p := m.SelectedPlugin(); p != nil && !p.Installed && p.IsDiscoverable {
-			if err := clipboard.WriteAll(p.InstallCommand()); err == nil {
+			if err := clipboardWrite(p.InstallCommand()); err == nil {
 				m.copiedFlash = true
 				return m, clearCopiedFlash()
 			}
@@ -589,10 +945,10 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "g":
+	case m.keys.Detail.OpenGitHub.Has(key):
 		if p := m.SelectedPlugin(); p != nil {
-			url := p.GitHubURL()
-			if url != "" && strings.HasPrefix(url, "https://github.com/") {
+			url := p.SourceURL()
+			if strings.HasPrefix(url, "https://github.com/") || strings.HasPrefix(url, "https://gitlab.com/") {
 				openURL(url)
 				m.githubOpenedFlash = true
 				return m, clearGithubOpenedFlash()
@@ -600,12 +956,12 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "l":
+	case m.keys.Detail.CopyLink.Has(key):
 		// Copy plugin GitHub URL to clipboard
 		if p := m.SelectedPlugin(); p != nil {
-			url := p.GitHubURL()
+			url := p.SourceURL()
 			if url != "" {
-				if err := clipboard.WriteAll(url); err == nil {
+				if err := clipboardWrite(url); err == nil {
 					m.linkCopiedFlash = true
 					return m, clearLinkCopiedFlash()
 				} else {
@@ -616,7 +972,21 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "o":
+	case m.keys.Detail.CopyJSONURL.Has(key):
+		// Copy the raw plugin.json URL to clipboard
+		if p := m.SelectedPlugin(); p != nil {
+			if url := pluginJSONURL(*p); url != "" {
+				if err := clipboardWrite(url); err != nil {
+					m.clipboardErrorFlash = true
+					return m, clearClipboardError()
+				}
+				m.jsonURLCopiedFlash = true
+				return m, clearJSONURLCopiedFlash()
+			}
+		}
+		return m, nil
+
+	case m.keys.Detail.OpenLocal.Has(key):
 		if p := m.SelectedPlugin(); p != nil && p.Installed && p.InstallPath != "" {
 			openPath(p.InstallPath)
 			m.localOpenedFlash = true
@@ -624,10 +994,10 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "p":
+	case m.keys.Detail.CopyPath.Has(key):
 		// Copy local install path to clipboard (only for installed plugins)
 		if p := m.SelectedPlugin(); p != nil && p.Installed && p.InstallPath != "" {
-			if err := clipboard.WriteAll(p.InstallPath); err == nil {
+			if err := clipboardWrite(p.InstallPath); err == nil {
 				m.pathCopiedFlash = true
 				return m, clearPathCopiedFlash()
 			} else {
@@ -637,14 +1007,10 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "shift+m", "M":
-		// Open marketplace browser
-		_ = m.LoadMarketplaceItems()
-		m.previousViewBeforeMarketplace = ViewDetail
-		m.StartViewTransition(ViewMarketplaceList, 1)
-		return m, animationTick()
+	case m.keys.Marketplace.Has(key):
+		return m.openMarketplaceBrowser(ViewDetail)
 
-	case "?":
+	case m.keys.Help.Has(key):
 		m.StartViewTransition(ViewHelp, 1) // Forward transition
 		return m, animationTick()
 
@@ -660,18 +1026,18 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
-	switch msg.String() {
-	case "q":
+	switch key := msg.String(); {
+	case m.keys.Quit.Has(key):
 		return m, tea.Quit
 
-	case "shift+m", "M":
-		// Open marketplace browser
-		_ = m.LoadMarketplaceItems()
-		m.previousViewBeforeMarketplace = ViewHelp
-		m.StartViewTransition(ViewMarketplaceList, 1)
-		return m, animationTick()
+	case m.keys.Marketplace.Has(key):
+		return m.openMarketplaceBrowser(ViewHelp)
+
+	case m.keys.HelpView.EditSettings.Has(key):
+		// Suspend the TUI and edit the active scope's settings.json
+		return m, editSettings(m.editSettingsScope())
 
-	case "esc", "?", "backspace", "enter":
+	case m.keys.Back.Has(key) || m.keys.Help.Has(key) || m.keys.Select.Has(key):
 		m.StartViewTransition(ViewList, -1) // Back transition
 		return m, animationTick()
 
@@ -684,49 +1050,82 @@ func (m Model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleMarketplaceListKeys handles keys in the marketplace list view
 func (m Model) handleMarketplaceListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "ctrl+k", "ctrl+p":
+	// Vim-style gg: the first press waits for a second one; any other key
+	// cancels it
+	held := m.pendingTop
+	m.pendingTop = ""
+
+	switch key := msg.String(); {
+	case m.keys.GoTop.Has(key):
+		if held != key {
+			m.pendingTop = key
+			return m, nil
+		}
+		m.marketplaceCursor = 0
+		m.UpdateMarketplaceScroll()
+		return m, nil
+
+	case m.keys.Top.Has(key):
+		m.marketplaceCursor = 0
+		m.UpdateMarketplaceScroll()
+		return m, nil
+
+	case m.keys.Bottom.Has(key):
+		if n := len(m.FilteredMarketplaceItems()); n > 0 {
+			m.marketplaceCursor = n - 1
+		}
+		m.UpdateMarketplaceScroll()
+		return m, nil
+
+	case m.keys.Up.Has(key):
 		if m.marketplaceCursor > 0 {
 			m.marketplaceCursor--
 		}
 		m.UpdateMarketplaceScroll()
 		return m, nil
 
-	case "down", "ctrl+j", "ctrl+n":
-		if m.marketplaceCursor < len(m.marketplaceItems)-1 {
+	case m.keys.Down.Has(key):
+		if m.marketplaceCursor < len(m.FilteredMarketplaceItems())-1 {
 			m.marketplaceCursor++
 		}
 		m.UpdateMarketplaceScroll()
 		return m, nil
 
-	case "enter":
-		if len(m.marketplaceItems) > 0 && m.marketplaceCursor < len(m.marketplaceItems) {
+	case m.keys.Select.Has(key):
+		items := m.FilteredMarketplaceItems()
+		if len(items) > 0 && m.marketplaceCursor < len(items) {
 			// Create a copy to avoid holding a pointer to a slice element
-			item := m.marketplaceItems[m.marketplaceCursor]
+			item := items[m.marketplaceCursor]
+			item.loadTopPlugins()
 			m.selectedMarketplace = &item
+			m.marketplacePluginsOpen = false
 			m.StartViewTransition(ViewMarketplaceDetail, 1)
 			return m, animationTick()
 		}
 		return m, nil
 
-	case "tab", "right":
+	case m.keys.NextTab.Has(key):
 		m.NextMarketplaceSort()
 		return m, nil
 
-	case "shift+tab", "left":
+	case m.keys.PrevTab.Has(key):
 		m.PrevMarketplaceSort()
 		return m, nil
 
-	case "esc", "ctrl+g":
+	case m.keys.Marketplaces.Filter.Has(key):
+		m.NextMarketplaceFilter()
+		return m, nil
+
+	case m.keys.Marketplaces.Close.Has(key):
 		// Return to plugin list view
 		m.StartViewTransition(ViewList, -1)
 		return m, animationTick()
 
-	case "?":
+	case m.keys.Help.Has(key):
 		m.StartViewTransition(ViewHelp, 1)
 		return m, animationTick()
 
-	case "q":
+	case m.keys.Quit.Has(key):
 		return m, tea.Quit
 	}
 
@@ -735,16 +1134,24 @@ func (m Model) handleMarketplaceListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleMarketplaceDetailKeys handles keys in the marketplace detail view
 func (m Model) handleMarketplaceDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc", "backspace":
+	if m.marketplacePluginsOpen {
+		return m.handleMarketplacePluginsKeys(msg)
+	}
+
+	switch key := msg.String(); {
+	case m.keys.Back.Has(key):
 		m.StartViewTransition(ViewMarketplaceList, -1)
 		return m, animationTick()
 
-	case "c":
+	case m.keys.Select.Has(key):
+		m.OpenMarketplacePlugins()
+		return m, nil
+
+	case m.keys.Marketplaces.Copy.Has(key):
 		if m.selectedMarketplace != nil && m.selectedMarketplace.Status != MarketplaceInstalled {
 			installCmd := fmt.Sprintf("/plugin marketplace add %s",
 				extractMarketplaceSource(m.selectedMarketplace.Repo))
-			if err := clipboard.WriteAll(installCmd); err == nil {
+			if err := clipboardWrite(installCmd); err == nil {
 				m.copiedFlash = true
 				return m, clearCopiedFlash()
 			}
@@ -753,7 +1160,7 @@ func (m Model) handleMarketplaceDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		}
 		return m, nil
 
-	case "f":
+	case m.keys.Marketplaces.ShowInList.Has(key):
 		m.previousViewBeforeMarketplace = ViewList
 		m.StartViewTransition(ViewList, -1)
 		m.textInput.SetValue("@" + m.selectedMarketplace.Name)
@@ -762,7 +1169,7 @@ func (m Model) handleMarketplaceDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		m.scrollOffset = 0
 		return m, animationTick()
 
-	case "g":
+	case m.keys.Marketplaces.OpenGitHub.Has(key):
 		if m.selectedMarketplace != nil {
 			url := m.selectedMarketplace.Repo
 			if strings.HasPrefix(url, "https://github.com/") {
@@ -773,17 +1180,77 @@ func (m Model) handleMarketplaceDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		}
 		return m, nil
 
-	case "?":
+	case m.keys.Help.Has(key):
 		m.StartViewTransition(ViewHelp, 1)
 		return m, animationTick()
 
-	case "q":
+	case m.keys.Quit.Has(key):
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// handleMarketplacePluginsKeys handles keys in the marketplace plugin list
+// shown inside the marketplace detail view
+func (m Model) handleMarketplacePluginsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); {
+	case m.keys.Up.Has(key):
+		if m.marketplacePluginCursor > 0 {
+			m.marketplacePluginCursor--
+		}
+		m.UpdateMarketplacePluginScroll()
+		return m, nil
+
+	case m.keys.Down.Has(key):
+		if m.marketplacePluginCursor < len(m.marketplacePlugins)-1 {
+			m.marketplacePluginCursor++
+		}
+		m.UpdateMarketplacePluginScroll()
+		return m, nil
+
+	case m.keys.Select.Has(key):
+		if m.SelectedMarketplacePlugin() == nil {
+			return m, nil
+		}
+		m.StartViewTransition(ViewDetail, 1)
+		m.detailFromMarketplace = true
+		if m.detailViewport.Width > 0 {
+			m.fitDetailViewport(m.windowHeight)
+			m.detailViewport.GotoTop()
+		}
+		return m, animationTick()
+
+	case m.keys.Back.Has(key):
+		m.marketplacePluginsOpen = false
+		return m, nil
+
+	case m.keys.Quit.Has(key):
 		return m, tea.Quit
 	}
 
 	return m, nil
 }
 
+// pluginJSONURL returns the raw URL of a plugin's plugin.json on its
+// marketplace's default branch, built the same way the installer fetches it.
+// Returns "" for external plugins and unsupported marketplace hosts.
+func pluginJSONURL(p plugin.Plugin) string {
+	if p.IsExternalURL {
+		return ""
+	}
+	repo, err := marketplace.ParseRepoLocation(p.MarketplaceRepo)
+	if err != nil {
+		return ""
+	}
+
+	branch := p.MarketplaceBranch
+	if branch == "" {
+		branch = "main"
+	}
+	return marketplace.PluginJSONURL(repo, branch, marketplace.PluginSourcePath(p.Source, p.Name))
+}
+
 func openURL(url string) {
 	var cmd string
 	var args []string
@@ -805,20 +1272,7 @@ func openURL(url string) {
 }
 
 func openPath(path string) {
-	var cmd string
-	var args []string
-
-	switch runtime.GOOS {
-	case "darwin":
-		cmd = "open"
-		args = []string{path}
-	case "windows":
-		cmd = "explorer"
-		args = []string{path}
-	default:
-		cmd = "xdg-open"
-		args = []string{path}
-	}
+	cmd, args := openerCommand(path)
 
 	// #nosec G204 -- cmd is determined by runtime.GOOS (trusted), args is install path from config
 	_ = exec.Command(cmd, args...).Start()
@@ -5,13 +5,24 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/itsdevcoffee/plum/internal/config"
 	"github.com/itsdevcoffee/plum/internal/plugin"
+	"github.com/itsdevcoffee/plum/internal/search"
 )
 
+// loadErrorMessage explains a failure to load plugins, with setup guidance
+// when Claude Code's configuration is missing or corrupt
+func loadErrorMessage(err error) string {
+	if guidance := config.SetupGuidance(err); guidance != "" {
+		return guidance
+	}
+	return fmt.Sprintf("Error loading plugins: %v", err)
+}
+
 // View renders the current view
 func (m Model) View() string {
 	if m.err != nil {
-		return AppStyle.Render(fmt.Sprintf("Error loading plugins: %v\n\nPress q to quit.", m.err))
+		return AppStyle.Render(loadErrorMessage(m.err) + "\n\nPress q to quit.")
 	}
 
 	// Get the current view content
@@ -25,6 +36,8 @@ func (m Model) View() string {
 		content = m.marketplaceListView()
 	case ViewMarketplaceDetail:
 		content = m.marketplaceDetailView()
+	case ViewConfirm:
+		content = m.confirmView()
 	default:
 		content = m.listView()
 	}
@@ -143,12 +156,12 @@ func (m Model) applySlideVTransition(content string) string {
 // renderFilterTabs renders the filter tab bar
 func (m Model) renderFilterTabs() string {
 	// Tab styles
-	activeTab := lipgloss.NewStyle().
+	activeTab := newStyle().
 		Foreground(PlumBright).
 		Bold(true).
 		Padding(0, 1)
 
-	inactiveTab := lipgloss.NewStyle().
+	inactiveTab := newStyle().
 		Foreground(TextTertiary).
 		Padding(0, 1)
 
@@ -165,6 +178,7 @@ func (m Model) renderFilterTabs() string {
 		{"Discover", counts[FilterDiscover], m.filterMode == FilterDiscover},
 		{"Ready", counts[FilterReady], m.filterMode == FilterReady},
 		{"Installed", counts[FilterInstalled], m.filterMode == FilterInstalled},
+		{"★ Favorites", counts[FilterFavorites], m.filterMode == FilterFavorites},
 	}
 
 	var parts []string
@@ -177,34 +191,13 @@ func (m Model) renderFilterTabs() string {
 		}
 	}
 
-	return strings.Join(parts, DimSeparator.Render("│"))
+	return strings.Join(parts, DimSeparator.Render(separatorBar))
 }
 
 // listView renders the main list view
 func (m Model) listView() string {
 	var b strings.Builder
-
-	// Header - Title with optional inline notification
-	title := "🍑 plum - Claude Plugin Manager"
-
-	if m.newMarketplacesCount > 0 {
-		plural := ""
-		if m.newMarketplacesCount > 1 {
-			plural = "s"
-		}
-		title = fmt.Sprintf("%s | ⚡ %d new marketplace%s - Shift+U", title, m.newMarketplacesCount, plural)
-	}
-
-	b.WriteString(TitleStyle.Render(title))
-	b.WriteString("\n\n")
-
-	// Search input with custom styling for @marketplace syntax
-	b.WriteString(m.renderSearchInput())
-	b.WriteString("\n")
-
-	// Filter tabs
-	b.WriteString(m.renderFilterTabs())
-	b.WriteString("\n\n")
+	b.WriteString(m.listHeader())
 
 	// Results
 	if m.loading {
@@ -214,7 +207,7 @@ func (m Model) listView() string {
 	} else if m.refreshing {
 		b.WriteString(m.spinner.View())
 		b.WriteString(" ")
-		refreshStyle := lipgloss.NewStyle().Foreground(PeachSoft).Bold(true)
+		refreshStyle := newStyle().Foreground(PeachSoft).Bold(true)
 		if m.refreshTotal > 0 {
 			progressText := fmt.Sprintf("Refreshing marketplaces (%d/%d)", m.refreshProgress, m.refreshTotal)
 			if m.refreshCurrent != "" {
@@ -229,6 +222,8 @@ func (m Model) listView() string {
 	} else if m.marketplaceAutocompleteActive {
 		// Show marketplace picker for autocomplete
 		b.WriteString(m.renderMarketplaceAutocomplete())
+	} else if err := m.queryError(); err != nil {
+		b.WriteString(newStyle().Foreground(Error).Render(err.Error()))
 	} else if len(m.results) == 0 {
 		b.WriteString(DescriptionStyle.Render("No plugins found matching your search."))
 	} else {
@@ -238,8 +233,12 @@ func (m Model) listView() string {
 		for i, rp := range visible {
 			actualIdx := offset + i
 			isSelected := actualIdx == m.cursor
-			b.WriteString(m.renderPluginItem(rp.Plugin, isSelected))
+			b.WriteString(m.renderPluginItem(rp, isSelected))
 			b.WriteString("\n")
+			if isSelected && m.slimExpanded && m.displayMode == DisplaySlim {
+				b.WriteString(m.renderSlimDescription(rp.Plugin))
+				b.WriteString("\n")
+			}
 		}
 	}
 
@@ -250,6 +249,69 @@ func (m Model) listView() string {
 	return AppStyle.Render(b.String())
 }
 
+// listHeader renders the list view above the results: title, search
+// input, and filter tabs, ending with the blank line before the results
+func (m Model) listHeader() string {
+	var b strings.Builder
+
+	// Header - Title with optional inline notification
+	title := "🍑 plum - Claude Plugin Manager"
+
+	if m.newMarketplacesCount > 0 {
+		plural := ""
+		if m.newMarketplacesCount > 1 {
+			plural = "s"
+		}
+		title = fmt.Sprintf("%s | ⚡ %d new marketplace%s - Shift+U", title, m.newMarketplacesCount, plural)
+	} else if m.enriching && !m.loading {
+		title += " | Checking marketplaces..."
+	}
+
+	b.WriteString(TitleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	// Search input with custom styling for @marketplace syntax
+	b.WriteString(m.renderSearchInput())
+	b.WriteString("\n")
+
+	// Filter tabs
+	b.WriteString(m.renderFilterTabs())
+	b.WriteString("\n\n")
+
+	return b.String()
+}
+
+// resultsShown reports whether the list view is showing plugin rows rather
+// than a loading, empty, or autocomplete state
+func (m Model) resultsShown() bool {
+	return !m.loading && !m.refreshing && len(m.allPlugins) > 0 &&
+		!m.marketplaceAutocompleteActive && m.queryError() == nil && len(m.results) > 0
+}
+
+// resultIndexAt returns the index into m.results of the row at screen line
+// y in the list view, or -1 when y isn't on a result
+func (m Model) resultIndexAt(y int) int {
+	if !m.resultsShown() {
+		return -1
+	}
+
+	// Results start below the app padding and the header
+	top := AppStyle.GetPaddingTop() + strings.Count(m.listHeader(), "\n")
+	offset := m.ScrollOffset()
+	for i, rp := range m.VisibleResults() {
+		idx := offset + i
+		height := lipgloss.Height(m.renderPluginItem(rp, idx == m.cursor))
+		if idx == m.cursor && m.slimExpanded && m.displayMode == DisplaySlim {
+			height++
+		}
+		if y >= top && y < top+height {
+			return idx
+		}
+		top += height
+	}
+	return -1
+}
+
 // renderPluginItem renders a single plugin item based on display mode
 // renderSearchInput renders the search input with custom styling for @marketplace syntax
 func (m Model) renderSearchInput() string {
@@ -270,7 +332,7 @@ func (m Model) renderSearchInput() string {
 		}
 
 		// Style marketplace part with contrasting background
-		marketplaceStyle := lipgloss.NewStyle().
+		marketplaceStyle := newStyle().
 			Foreground(TextPrimary).
 			Background(PlumMedium).
 			Bold(true).
@@ -283,7 +345,7 @@ func (m Model) renderSearchInput() string {
 		// Add cursor indicator at end if focused
 		cursorIndicator := ""
 		if m.textInput.Focused() {
-			cursorIndicator = lipgloss.NewStyle().Foreground(PlumBright).Render("│")
+			cursorIndicator = newStyle().Foreground(PlumBright).Render(separatorBar)
 		}
 
 		return promptStyled + marketplaceStyled + searchPart + cursorIndicator
@@ -298,7 +360,7 @@ func (m Model) renderMarketplaceAutocomplete() string {
 	var b strings.Builder
 
 	// Header
-	headerStyle := lipgloss.NewStyle().Foreground(PeachSoft).Bold(true)
+	headerStyle := newStyle().Foreground(PeachSoft).Bold(true)
 	b.WriteString(headerStyle.Render("Select marketplace:"))
 	b.WriteString("\n\n")
 
@@ -328,7 +390,7 @@ func (m Model) renderMarketplaceAutocomplete() string {
 			name := nameStyle.Render(item.DisplayName)
 
 			// Plugin count
-			pluginCount := lipgloss.NewStyle().Foreground(TextTertiary).Render(
+			pluginCount := newStyle().Foreground(TextTertiary).Render(
 				fmt.Sprintf("(%d plugins)", item.TotalPluginCount))
 
 			b.WriteString(fmt.Sprintf("%s%s  %s\n", prefix, name, pluginCount))
@@ -341,27 +403,89 @@ func (m Model) renderMarketplaceAutocomplete() string {
 	return b.String()
 }
 
-func (m Model) renderPluginItem(p plugin.Plugin, selected bool) string {
+func (m Model) renderPluginItem(rp search.RankedPlugin, selected bool) string {
 	if m.displayMode == DisplaySlim {
-		return m.renderPluginItemSlim(p, selected)
+		return m.renderPluginItemSlim(rp.Plugin, rp.MatchedIndices, selected)
 	}
-	return m.renderPluginItemCard(p, selected)
+	return m.renderPluginItemCard(rp.Plugin, rp.MatchedIndices, selected)
 }
 
-// renderPluginItemSlim renders a compact one-line plugin item
-func (m Model) renderPluginItemSlim(p plugin.Plugin, selected bool) string {
-	// Indicator
-	var indicator string
-	if p.Installed {
-		indicator = InstalledIndicator.String()
-	} else {
-		indicator = AvailableIndicator.String()
-		// Add [Discover] badge for plugins from uninstalled marketplaces
-		if p.IsDiscoverable {
-			indicator += " " + DiscoverBadge.String()
+// renderHighlightedName renders a plugin name, emphasizing the runes at the
+// matched positions
+func renderHighlightedName(name string, matched []int, style lipgloss.Style) string {
+	if len(matched) == 0 {
+		return style.Render(name)
+	}
+
+	isMatched := make(map[int]bool, len(matched))
+	for _, i := range matched {
+		isMatched[i] = true
+	}
+	matchStyle := MatchHighlightStyle.Inherit(style)
+
+	// Render runs of matched and unmatched runes as single segments
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(matchStyle.Render(string(run)))
+		} else {
+			b.WriteString(style.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(name) {
+		if isMatched[i] != runMatched {
+			flush()
+			runMatched = isMatched[i]
 		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
+
+// pluginIndicator renders the installed, disabled, available, or marked
+// indicator
+// shown before a plugin's name
+func (m Model) pluginIndicator(p plugin.Plugin) string {
+	var indicator lipgloss.Style
+	var badge string
+	switch {
+	case m.pluginDisabled(p):
+		indicator, badge = DisabledIndicator, DisabledBadge.String()
+	case p.Installed:
+		indicator = InstalledIndicator
+	case p.IsDiscoverable:
+		// Plugins from uninstalled marketplaces get a [Discover] badge
+		indicator, badge = AvailableIndicator, DiscoverBadge.String()
+	default:
+		indicator = AvailableIndicator
+	}
+
+	// Marked plugins (space) swap the marker for a check
+	if m.selected[p.FullName()] {
+		indicator = SelectedIndicator
 	}
 
+	out := indicator.String()
+	if m.favorites[p.FullName()] {
+		out += FavoriteIndicator.String()
+	}
+	if badge != "" {
+		out += " " + badge
+	}
+	return out
+}
+
+// renderPluginItemSlim renders a compact one-line plugin item
+func (m Model) renderPluginItemSlim(p plugin.Plugin, matched []int, selected bool) string {
+	indicator := m.pluginIndicator(p)
+
 	// Name style based on selection
 	var nameStyle lipgloss.Style
 	if selected {
@@ -379,7 +503,7 @@ func (m Model) renderPluginItemSlim(p plugin.Plugin, selected bool) string {
 	}
 
 	// Format: [prefix][indicator] name v[version] [installability-tag]
-	name := nameStyle.Render(p.Name)
+	name := renderHighlightedName(p.Name, matched, nameStyle)
 	version := VersionStyle.Render("v" + p.Version)
 
 	// Add installability tag if not installable
@@ -391,8 +515,32 @@ func (m Model) renderPluginItemSlim(p plugin.Plugin, selected bool) string {
 	return fmt.Sprintf("%s%s %s %s%s", prefix, indicator, name, version, installTag)
 }
 
+// renderSlimDescription renders the description line shown under the selected
+// slim row when expanded. Long descriptions are cut at a word boundary.
+func (m Model) renderSlimDescription(p plugin.Plugin) string {
+	const indent = "      "
+
+	desc := p.Description
+	if desc == "" {
+		desc = "No description"
+	}
+
+	maxWidth := m.ContentWidth() - len(indent) - 4
+	if maxWidth < 20 {
+		maxWidth = 20
+	}
+
+	lines := strings.SplitN(wrapText(desc, maxWidth-3), "\n", 2)
+	line := lines[0]
+	if len(lines) > 1 {
+		line += "..."
+	}
+
+	return indent + DescriptionStyle.Render(truncateToWidth(line, maxWidth))
+}
+
 // renderPluginItemCard renders a plugin item as a card with border
-func (m Model) renderPluginItemCard(p plugin.Plugin, selected bool) string {
+func (m Model) renderPluginItemCard(p plugin.Plugin, matched []int, selected bool) string {
 	// Card width (account for app padding and card border)
 	cardWidth := m.ContentWidth() - 6
 	if cardWidth < 40 {
@@ -400,17 +548,7 @@ func (m Model) renderPluginItemCard(p plugin.Plugin, selected bool) string {
 	}
 	innerWidth := cardWidth - 4 // Account for card padding and border
 
-	// Indicator
-	var indicator string
-	if p.Installed {
-		indicator = InstalledIndicator.String()
-	} else {
-		indicator = AvailableIndicator.String()
-		// Add [Discover] badge for plugins from uninstalled marketplaces
-		if p.IsDiscoverable {
-			indicator += " " + DiscoverBadge.String()
-		}
-	}
+	indicator := m.pluginIndicator(p)
 
 	// Name style based on selection
 	var nameStyle lipgloss.Style
@@ -421,7 +559,7 @@ func (m Model) renderPluginItemCard(p plugin.Plugin, selected bool) string {
 	}
 
 	// Row 1: [indicator] Name v[version] [installability-tag]    @marketplace
-	name := nameStyle.Render(p.Name)
+	name := renderHighlightedName(p.Name, matched, nameStyle)
 	version := VersionStyle.Render("v" + p.Version)
 	marketplace := MarketplaceStyle.Render("@" + p.Marketplace)
 
@@ -447,11 +585,7 @@ func (m Model) renderPluginItemCard(p plugin.Plugin, selected bool) string {
 	if maxDescLen < 20 {
 		maxDescLen = 20
 	}
-	truncDesc := p.Description
-	if len(truncDesc) > maxDescLen {
-		truncDesc = truncDesc[:maxDescLen-3] + "..."
-	}
-	row2 := "  " + DescriptionStyle.Render(truncDesc)
+	row2 := "  " + DescriptionStyle.Render(truncateToWidth(p.Description, maxDescLen))
 
 	// Combine rows (2 rows now)
 	content := row1 + "\n" + row2
@@ -469,6 +603,55 @@ func (m Model) renderPluginItemCard(p plugin.Plugin, selected bool) string {
 
 // statusBar renders the status bar (responsive to terminal width)
 func (m Model) statusBar() string {
+	return StatusBarStyle.Render(strings.Join(m.statusBarLines(), "\n"))
+}
+
+// maxStatusBarLines caps how far the status bar may wrap; further segments are dropped
+const maxStatusBarLines = 2
+
+// statusBarLines packs the status bar segments into lines that fit the
+// content width, never splitting a segment across lines
+func (m Model) statusBarLines() []string {
+	width := m.ContentWidth() - AppStyle.GetHorizontalPadding()
+	return packStatusSegments(m.statusBarParts(), width, maxStatusBarLines)
+}
+
+// packStatusSegments greedily fills lines of at most width cells with whole
+// segments. Segments that don't fit within maxLines are dropped. A width of
+// zero or less (window size unknown) keeps everything on one line.
+func packStatusSegments(parts []string, width, maxLines int) []string {
+	if width <= 0 {
+		return []string{strings.Join(parts, statusBarSeparator)}
+	}
+
+	sepWidth := lipgloss.Width(statusBarSeparator)
+	var lines []string
+	var line []string
+	lineWidth := 0
+
+	for _, part := range parts {
+		partWidth := lipgloss.Width(part)
+		if len(line) > 0 && lineWidth+sepWidth+partWidth > width {
+			lines = append(lines, strings.Join(line, statusBarSeparator))
+			if len(lines) == maxLines {
+				return lines
+			}
+			line, lineWidth = nil, 0
+		}
+		if len(line) > 0 {
+			lineWidth += sepWidth
+		}
+		line = append(line, part)
+		lineWidth += partWidth
+	}
+	if len(line) > 0 {
+		lines = append(lines, strings.Join(line, statusBarSeparator))
+	}
+	return lines
+}
+
+// statusBarParts returns the status bar segments for the current width
+func (m Model) statusBarParts() []string {
 	var parts []string
 
 	// Position in current filtered results
@@ -510,6 +693,15 @@ func (m Model) statusBar() string {
 		} else {
 			parts = append(parts, position+" "+m.FilterModeName())
 		}
+		if m.pluginSortMode != PluginSortRelevance {
+			parts = append(parts, "by "+strings.ToLower(m.PluginSortModeName()))
+		}
+		if !m.preferInstalled {
+			parts = append(parts, "installed not first")
+		}
+		if n := m.DisabledCount(); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d disabled", n))
+		}
 		parts = append(parts, KeyStyle.Render("↑↓/ctrl+jk")+" navigate")
 		parts = append(parts, KeyStyle.Render("tab")+" next view")
 		parts = append(parts, KeyStyle.Render("Shift+V")+" "+oppositeView)
@@ -523,6 +715,12 @@ func (m Model) statusBar() string {
 		} else {
 			parts = append(parts, position)
 		}
+		if n := m.DisabledCount(); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d disabled", n))
+		}
+		if m.pluginSortMode != PluginSortRelevance {
+			parts = append(parts, "by "+strings.ToLower(m.PluginSortModeName()))
+		}
 		parts = append(parts, KeyStyle.Render("↑↓")+" nav")
 		parts = append(parts, KeyStyle.Render("tab")+" next view")
 		parts = append(parts, KeyStyle.Render("Shift+M")+" marketplaces")
@@ -550,7 +748,41 @@ func (m Model) statusBar() string {
 		parts = append(parts, KeyStyle.Render("?")+"=help")
 	}
 
-	return StatusBarStyle.Render(strings.Join(parts, "  │  "))
+	// Enable/disable result for 'E' and the multi-select state go right
+	// after the position
+	var extra []string
+	if flash := m.toggleFlashView(width / 2); flash != "" {
+		extra = append(extra, flash)
+	}
+	if n := m.SelectionCount(); n > 0 {
+		switch {
+		case m.copiedFlash:
+			extra = append(extra, newStyle().Foreground(Success).Bold(true).Render(fmt.Sprintf("✓ Copied %d!", n)))
+		case m.clipboardErrorFlash:
+			extra = append(extra, newStyle().Foreground(Error).Bold(true).Render("✗ Clipboard error"))
+		default:
+			extra = append(extra, fmt.Sprintf("%d selected", n)+" "+KeyStyle.Render("y")+" copy")
+		}
+	}
+	if len(extra) > 0 {
+		parts = append(parts[:1], append(extra, parts[1:]...)...)
+	}
+
+	return parts
+}
+
+// toggleFlashView renders the enable/disable confirmation or failure, or ""
+// when there's nothing to show
+func (m Model) toggleFlashView(maxWidth int) string {
+	switch {
+	case m.toggleError != nil:
+		errorStyle := newStyle().Foreground(Error).Bold(true)
+		return errorStyle.Render(truncateToWidth("✗ "+m.toggleError.Error(), maxWidth))
+	case m.toggleFlash != "":
+		successStyle := newStyle().Foreground(Success).Bold(true)
+		return successStyle.Render(truncateToWidth("✓ "+m.toggleFlash, maxWidth))
+	}
+	return ""
 }
 
 // detailView renders the detail view for the selected plugin
@@ -562,6 +794,9 @@ func (m Model) generateDetailHeader(p *plugin.Plugin, contentWidth int) string {
 	var badge string
 	if p.Installed {
 		badge = InstalledBadge.String()
+		if m.pluginDisabled(*p) {
+			badge += " " + DisabledBadge.String()
+		}
 	} else {
 		badge = AvailableBadge.String()
 	}
@@ -571,10 +806,10 @@ func (m Model) generateDetailHeader(p *plugin.Plugin, contentWidth int) string {
 		badge += " " + NotInstallableBadge.Render(p.InstallabilityTag())
 	}
 
-	header := DetailTitleStyle.Render(p.Name) + "  " + badge
+	header := DetailTitleStyle.Render(p.Title()) + "  " + badge
 	b.WriteString(header)
 	b.WriteString("\n")
-	b.WriteString(strings.Repeat("─", contentWidth))
+	b.WriteString(strings.Repeat(ruleChar, contentWidth))
 
 	return b.String()
 }
@@ -609,9 +844,19 @@ func (m Model) generateDetailContent(p *plugin.Plugin, contentWidth int) string
 		b.WriteString("\n")
 	}
 
-	// Description (word-wrapped)
+	// When `plum update` last compared this install to its marketplace
+	if p.Installed {
+		checked := "never checked"
+		if !p.LastCheckedAt.IsZero() {
+			checked = "update-checked " + formatRelativeTime(p.LastCheckedAt)
+		}
+		b.WriteString(DetailLabelStyle.Render("Updates:") + " " + DetailValueStyle.Render(checked))
+		b.WriteString("\n")
+	}
+
+	// README or description, rendered as basic markdown
 	b.WriteString("\n")
-	b.WriteString(wrapText(p.Description, contentWidth))
+	b.WriteString(renderMarkdown(detailText(p), contentWidth))
 	b.WriteString("\n")
 
 	// Keywords (word-wrapped)
@@ -626,13 +871,13 @@ func (m Model) generateDetailContent(p *plugin.Plugin, contentWidth int) string
 	// Install instructions (move from footer to scrollable content)
 	if !p.Installed {
 		b.WriteString("\n")
-		b.WriteString(strings.Repeat("─", contentWidth))
+		b.WriteString(strings.Repeat(ruleChar, contentWidth))
 		b.WriteString("\n")
 
 		switch {
 		case !p.Installable():
 			// Plugin requires different installation method
-			notInstallableStyle := lipgloss.NewStyle().Foreground(TextMuted).Italic(true)
+			notInstallableStyle := newStyle().Foreground(TextMuted).Italic(true)
 			b.WriteString(notInstallableStyle.Render("ℹ " + p.InstallabilityReason()))
 			b.WriteString("\n\n")
 			if p.HasLSPServers {
@@ -679,13 +924,43 @@ func (m Model) generateDetailFooter(p *plugin.Plugin, contentWidth int) string {
 	var footerParts []string
 
 	// Define styles for flash messages
-	successStyle := lipgloss.NewStyle().Foreground(Success).Bold(true)
-	openedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF9500")).Bold(true)
-	errorStyle := lipgloss.NewStyle().Foreground(Error).Bold(true)
+	successStyle := newStyle().Foreground(Success).Bold(true)
+	openedStyle := newStyle().Foreground(lipgloss.Color("#FF9500")).Bold(true)
+	errorStyle := newStyle().Foreground(Error).Bold(true)
 
 	// Always show esc
 	footerParts = append(footerParts, KeyStyle.Render("esc")+" back")
 
+	// In-TUI install progress and result
+	switch {
+	case m.installing == p.FullName():
+		footerParts = append(footerParts, m.spinner.View()+" Installing...")
+	case m.installedFlash && p.Installed:
+		footerParts = append(footerParts, successStyle.Render("✓ Installed!"))
+	case m.installError != nil:
+		footerParts = append(footerParts, errorStyle.Render(truncateToWidth("✗ Install failed: "+m.installError.Error(), contentWidth/2)))
+	case readyToInstall(*p):
+		footerParts = append(footerParts, KeyStyle.Render("i")+" install")
+	}
+
+	// Enable/disable for installed plugins and favorite (with flash replacement)
+	if flash := m.toggleFlashView(contentWidth / 2); flash != "" {
+		footerParts = append(footerParts, flash)
+	} else {
+		if p.Installed {
+			if m.pluginEnabled(*p) {
+				footerParts = append(footerParts, KeyStyle.Render("e")+" disable")
+			} else {
+				footerParts = append(footerParts, KeyStyle.Render("e")+" enable")
+			}
+		}
+		if m.favorites[p.FullName()] {
+			footerParts = append(footerParts, KeyStyle.Render("f")+" unstar")
+		} else {
+			footerParts = append(footerParts, KeyStyle.Render("f")+" star")
+		}
+	}
+
 	// Show install commands for non-installed plugins (or flash message)
 	// Skip for non-installable plugins (LSP, external URL)
 	if !p.Installed && p.Installable() {
@@ -718,6 +993,9 @@ func (m Model) generateDetailFooter(p *plugin.Plugin, contentWidth int) string {
 	} else {
 		footerParts = append(footerParts, KeyStyle.Render("l")+" copy link")
 	}
+	if m.jsonURLCopiedFlash {
+		footerParts = append(footerParts, successStyle.Render("✓ plugin.json URL Copied!"))
+	}
 
 	// Local directory actions (only for installed)
 	if p.Installed && p.InstallPath != "" {
@@ -740,7 +1018,7 @@ func (m Model) generateDetailFooter(p *plugin.Plugin, contentWidth int) string {
 
 	// Always show quit
 	footerParts = append(footerParts, KeyStyle.Render("q")+" quit")
-	b.WriteString(HelpStyle.Render(strings.Join(footerParts, "  │  ")))
+	b.WriteString(HelpStyle.Render(strings.Join(footerParts, statusBarSeparator)))
 
 	return b.String()
 }
@@ -759,7 +1037,7 @@ func (m Model) detailView() string {
 	}
 
 	// Wrapper with left/right margin (match help menu pattern)
-	detailWrapperStyle := lipgloss.NewStyle().
+	detailWrapperStyle := newStyle().
 		Padding(0, 2, 0, 2)
 
 	header := m.generateDetailHeader(p, contentWidth)
@@ -781,8 +1059,8 @@ func (m Model) detailView() string {
 		)
 
 		// Wrap in box (match help menu pattern)
-		detailBoxStyle := lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
+		detailBoxStyle := newStyle().
+			Border(boxBorder()).
 			BorderForeground(PlumBright).
 			Padding(1, 2)
 
@@ -795,43 +1073,39 @@ func (m Model) detailView() string {
 	return AppStyle.Render(boxStyle.Render(header + "\n\n" + content + "\n" + footer))
 }
 
-// renderDetailScrollbar renders the scrollbar for detail view (copy of renderHelpScrollbar)
+// renderDetailScrollbar renders the scrollbar for detail view
 func (m Model) renderDetailScrollbar() string {
-	if m.detailViewport.Height <= 0 {
-		return ""
-	}
-
-	// Check if content is scrollable
-	if m.detailViewport.AtTop() && m.detailViewport.AtBottom() {
+	if m.detailViewport.Height <= 0 || (m.detailViewport.AtTop() && m.detailViewport.AtBottom()) {
 		return "" // Content fits, no scrollbar needed
 	}
+	return renderScrollbar(m.detailViewport.Height, m.detailContentLines, m.detailViewport.ScrollPercent())
+}
 
-	// Get dimensions
-	visibleHeight := m.detailViewport.Height
-	scrollPercent := m.detailViewport.ScrollPercent()
-
-	// Estimate total content height (heuristic)
-	totalHeight := visibleHeight * 2
-
-	// Calculate thumb size (proportional)
-	thumbHeight := (visibleHeight * visibleHeight) / totalHeight
-	if thumbHeight < 1 {
-		thumbHeight = 1
+// scrollbarThumb sizes and positions a scrollbar thumb so it covers the
+// visible share of totalLines
+func scrollbarThumb(visibleHeight, totalLines int, scrollPercent float64) (height, pos int) {
+	if totalLines < visibleHeight {
+		totalLines = visibleHeight
 	}
-	if thumbHeight > visibleHeight {
-		thumbHeight = visibleHeight
+
+	height = (visibleHeight * visibleHeight) / totalLines
+	if height < 1 {
+		height = 1
 	}
 
-	// Calculate thumb position
-	trackHeight := visibleHeight - thumbHeight
-	thumbPos := int(float64(trackHeight) * scrollPercent)
+	trackHeight := visibleHeight - height
+	pos = int(float64(trackHeight) * scrollPercent)
+	return height, pos
+}
 
-	// Render scrollbar with plum theme
-	var scrollbar strings.Builder
+// renderScrollbar renders a plum-themed scrollbar visibleHeight rows tall
+func renderScrollbar(visibleHeight, totalLines int, scrollPercent float64) string {
+	thumbHeight, thumbPos := scrollbarThumb(visibleHeight, totalLines, scrollPercent)
 
-	thumbStyle := lipgloss.NewStyle().Foreground(PlumBright)   // Orange thumb
-	trackStyle := lipgloss.NewStyle().Foreground(BorderSubtle) // Brown track
+	thumbStyle := newStyle().Foreground(PlumBright)   // Orange thumb
+	trackStyle := newStyle().Foreground(BorderSubtle) // Brown track
 
+	var scrollbar strings.Builder
 	for i := 0; i < visibleHeight; i++ {
 		if i >= thumbPos && i < thumbPos+thumbHeight {
 			scrollbar.WriteString(thumbStyle.Render("█"))
@@ -846,18 +1120,49 @@ func (m Model) renderDetailScrollbar() string {
 	return " " + scrollbar.String()
 }
 
-// wrapText wraps text to fit within maxWidth characters
+// truncateToWidth cuts s to at most w terminal cells, ending it with "..."
+// when anything was cut. Wide characters count as two cells.
+func truncateToWidth(s string, w int) string {
+	if lipgloss.Width(s) <= w {
+		return s
+	}
+	if w <= 0 {
+		return ""
+	}
+
+	const ellipsis = "..."
+	tail := ellipsis
+	if w <= len(ellipsis) {
+		tail = ""
+	}
+	limit := w - lipgloss.Width(tail)
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		rw := lipgloss.Width(string(r))
+		if width+rw > limit {
+			break
+		}
+		b.WriteRune(r)
+		width += rw
+	}
+	return b.String() + tail
+}
+
+// wrapText wraps text to fit within maxWidth terminal cells. Widths are
+// measured per rune, so wide (CJK, emoji) characters count as two cells and
+// long words are split without breaking a rune.
 func wrapText(text string, maxWidth int) string {
 	if maxWidth <= 0 {
 		return text
 	}
 
 	var result strings.Builder
-	words := strings.Fields(text)
 	lineLen := 0
 
-	for i, word := range words {
-		wordLen := len(word)
+	for _, word := range strings.Fields(text) {
+		wordLen := lipgloss.Width(word)
 
 		if lineLen+wordLen+1 > maxWidth && lineLen > 0 {
 			result.WriteString("\n")
@@ -869,27 +1174,22 @@ func wrapText(text string, maxWidth int) string {
 			lineLen++
 		}
 
-		// Handle words longer than maxWidth
-		if wordLen > maxWidth {
-			for len(word) > maxWidth {
-				if lineLen > 0 {
-					result.WriteString("\n")
-					lineLen = 0
-				}
-				result.WriteString(word[:maxWidth])
-				word = word[maxWidth:]
-				result.WriteString("\n")
-			}
-			if len(word) > 0 {
-				result.WriteString(word)
-				lineLen = len(word)
-			}
-		} else {
+		if wordLen <= maxWidth {
 			result.WriteString(word)
 			lineLen += wordLen
+			continue
 		}
 
-		_ = i // suppress unused warning
+		// Split words longer than maxWidth across lines
+		for _, r := range word {
+			w := lipgloss.Width(string(r))
+			if lineLen+w > maxWidth && lineLen > 0 {
+				result.WriteString("\n")
+				lineLen = 0
+			}
+			result.WriteRune(r)
+			lineLen += w
+		}
 	}
 
 	return result.String()